@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	gotoken "go/token"
+	"math/big"
 	"strings"
 
 	"github.com/goruby/goruby/token"
@@ -23,6 +24,22 @@ type Node interface {
 	String() string
 }
 
+// Position converts the position pos of a node, as returned by Pos or End,
+// into line and column information within file. It returns an invalid
+// position if pos does not belong to file.
+func Position(file *gotoken.File, pos int) gotoken.Position {
+	if file == nil || pos < 0 || pos > file.Size() {
+		return gotoken.Position{}
+	}
+	return file.Position(file.Pos(pos))
+}
+
+// Span returns the line and column information of the start of node and of
+// the position immediately after it within file
+func Span(file *gotoken.File, node Node) (start, end gotoken.Position) {
+	return Position(file, node.Pos()), Position(file, node.End())
+}
+
 // A Statement represents a statement within the AST
 //
 // All statement nodes implement the Statement interface.
@@ -110,7 +127,12 @@ func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Literal }
 func (rs *ReturnStatement) Pos() int { return rs.Token.Pos }
 
 // End returns the position of first character immediately after the node
-func (rs *ReturnStatement) End() int { return rs.ReturnValue.End() }
+func (rs *ReturnStatement) End() int {
+	if rs.ReturnValue == nil {
+		return rs.Token.Pos + len(rs.Token.Literal)
+	}
+	return rs.ReturnValue.End()
+}
 
 // An ExpressionStatement is a Statement wrapping an Expression
 type ExpressionStatement struct {
@@ -146,10 +168,20 @@ type BlockStatement struct {
 func (bs *BlockStatement) statementNode() {}
 
 // Pos returns the position of first character belonging to the node
-func (bs *BlockStatement) Pos() int { return bs.Token.Pos }
+func (bs *BlockStatement) Pos() int {
+	if bs.Token.Type == token.ILLEGAL && len(bs.Statements) != 0 {
+		return bs.Statements[0].Pos()
+	}
+	return bs.Token.Pos
+}
 
 // End returns the position of first character immediately after the node
-func (bs *BlockStatement) End() int { return bs.EndToken.Pos }
+func (bs *BlockStatement) End() int {
+	if bs.EndToken.Type == token.ILLEGAL && len(bs.Statements) != 0 {
+		return bs.Statements[len(bs.Statements)-1].End()
+	}
+	return bs.EndToken.Pos + len(bs.EndToken.Literal)
+}
 
 // TokenLiteral returns '{' or the first token from the first statement
 func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
@@ -169,20 +201,43 @@ type ExceptionHandlingBlock struct {
 	EndToken   token.Token
 	TryBody    *BlockStatement
 	Rescues    []*RescueBlock
+	Else       *BlockStatement
+	Ensure     *BlockStatement
+}
+
+// IsModifier reports whether the block is written as modifier, i.e. `x rescue y`
+func (eh *ExceptionHandlingBlock) IsModifier() bool {
+	return eh.EndToken.Type == token.ILLEGAL
 }
 
 func (eh *ExceptionHandlingBlock) expressionNode() {}
 
 // Pos returns the position of first character belonging to the node
-func (eh *ExceptionHandlingBlock) Pos() int { return eh.BeginToken.Pos }
+func (eh *ExceptionHandlingBlock) Pos() int {
+	if eh.IsModifier() {
+		return eh.TryBody.Pos()
+	}
+	return eh.BeginToken.Pos
+}
 
 // End returns the position of first character immediately after the node
-func (eh *ExceptionHandlingBlock) End() int { return eh.EndToken.Pos }
+func (eh *ExceptionHandlingBlock) End() int {
+	if eh.IsModifier() {
+		return eh.Rescues[0].End()
+	}
+	return eh.EndToken.Pos + len(eh.EndToken.Literal)
+}
 
 // TokenLiteral returns the token literal from 'begin'
 func (eh *ExceptionHandlingBlock) TokenLiteral() string { return eh.BeginToken.Literal }
 func (eh *ExceptionHandlingBlock) String() string {
 	var out bytes.Buffer
+	if eh.IsModifier() {
+		out.WriteString(eh.TryBody.String())
+		out.WriteString(" rescue ")
+		out.WriteString(eh.Rescues[0].Body.String())
+		return out.String()
+	}
 	out.WriteString(eh.BeginToken.Literal)
 	out.WriteString("\n")
 	out.WriteString(eh.TryBody.String())
@@ -190,10 +245,24 @@ func (eh *ExceptionHandlingBlock) String() string {
 	for _, r := range eh.Rescues {
 		out.WriteString(r.String())
 	}
+	writeElseEnsure(&out, eh.Else, eh.Ensure)
 	out.WriteString("end")
 	return out.String()
 }
 
+func writeElseEnsure(out *bytes.Buffer, elseBody, ensure *BlockStatement) {
+	if elseBody != nil {
+		out.WriteString("else\n")
+		out.WriteString(elseBody.String())
+		out.WriteString("\n")
+	}
+	if ensure != nil {
+		out.WriteString("ensure\n")
+		out.WriteString(ensure.String())
+		out.WriteString("\n")
+	}
+}
+
 // A RescueBlock represents a rescue block
 type RescueBlock struct {
 	Token            token.Token
@@ -453,10 +522,12 @@ func (i *ScopedIdentifier) End() int { return i.Inner.End() }
 // TokenLiteral returns the literal of the token.SCOPE token
 func (i *ScopedIdentifier) TokenLiteral() string { return i.Token.Literal }
 
-// IntegerLiteral represents an integer in the AST
+// IntegerLiteral represents an integer in the AST. BigValue is only set if
+// the literal does not fit into an int64.
 type IntegerLiteral struct {
-	Token token.Token
-	Value int64
+	Token    token.Token
+	Value    int64
+	BigValue *big.Int
 }
 
 func (il *IntegerLiteral) expressionNode() {}
@@ -466,11 +537,35 @@ func (il *IntegerLiteral) literalNode()    {}
 func (il *IntegerLiteral) Pos() int { return il.Token.Pos }
 
 // End returns the position of first character immediately after the node
-func (il *IntegerLiteral) End() int { return il.Token.Pos + len(fmt.Sprintf("%d", il.Value)) }
+func (il *IntegerLiteral) End() int { return il.Token.Pos + len(il.Token.Literal) }
 
 // TokenLiteral returns the literal from the token.INT token
 func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
-func (il *IntegerLiteral) String() string       { return fmt.Sprintf("%d", il.Value) }
+func (il *IntegerLiteral) String() string {
+	if il.BigValue != nil {
+		return il.BigValue.String()
+	}
+	return fmt.Sprintf("%d", il.Value)
+}
+
+// FloatLiteral represents a float in the AST
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (fl *FloatLiteral) expressionNode() {}
+func (fl *FloatLiteral) literalNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (fl *FloatLiteral) Pos() int { return fl.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (fl *FloatLiteral) End() int { return fl.Token.Pos + len(fl.Token.Literal) }
+
+// TokenLiteral returns the literal from the token.FLOAT token
+func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FloatLiteral) String() string       { return fl.Token.Literal }
 
 // Nil represents the 'nil' keyword
 type Nil struct {
@@ -503,7 +598,7 @@ func (b *Boolean) literalNode()    {}
 func (b *Boolean) Pos() int { return b.Token.Pos }
 
 // End returns the position of first character immediately after the node
-func (b *Boolean) End() int { return b.Token.Pos + len(fmt.Sprintf("%t", b.Value)) }
+func (b *Boolean) End() int { return b.Token.Pos + len(b.Token.Literal) }
 
 // TokenLiteral returns the literal from the token token.BOOLEAN
 func (b *Boolean) TokenLiteral() string { return b.Token.Literal }
@@ -522,12 +617,47 @@ func (sl *StringLiteral) literalNode()    {}
 func (sl *StringLiteral) Pos() int { return sl.Token.Pos }
 
 // End returns the position of first character immediately after the node
-func (sl *StringLiteral) End() int { return sl.Token.Pos + len(sl.Value) }
+func (sl *StringLiteral) End() int { return sl.Token.Pos + len(sl.Token.Literal) }
 
 // TokenLiteral returns the literal from token token.STRING
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
 func (sl *StringLiteral) String() string       { return sl.Value }
 
+// InterpolatedStringLiteral represents a double quoted string containing
+// interpolated code, e.g. "foo #{bar}", in the AST. Parts holds the literal
+// parts of the string as *StringLiteral and the interpolated code as
+// *BlockStatement in order of appearance.
+type InterpolatedStringLiteral struct {
+	Token token.Token // the token.STRING
+	Parts []Node
+}
+
+func (isl *InterpolatedStringLiteral) expressionNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (isl *InterpolatedStringLiteral) Pos() int { return isl.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (isl *InterpolatedStringLiteral) End() int {
+	return isl.Token.Pos + len(isl.Token.Literal)
+}
+
+// TokenLiteral returns the literal from token token.STRING
+func (isl *InterpolatedStringLiteral) TokenLiteral() string { return isl.Token.Literal }
+func (isl *InterpolatedStringLiteral) String() string {
+	var out bytes.Buffer
+	for _, part := range isl.Parts {
+		if lit, ok := part.(*StringLiteral); ok {
+			out.WriteString(lit.Value)
+			continue
+		}
+		out.WriteString("#{")
+		out.WriteString(part.String())
+		out.WriteString("}")
+	}
+	return out.String()
+}
+
 // Comment represents a double quoted string in the AST
 type Comment struct {
 	Token token.Token // the #
@@ -541,7 +671,7 @@ func (c *Comment) literalNode()   {}
 func (c *Comment) Pos() int { return c.Token.Pos }
 
 // End returns the position of first character immediately after the node
-func (c *Comment) End() int { return c.Token.Pos + len(c.Value) }
+func (c *Comment) End() int { return c.Token.Pos + len(c.Token.Literal) + len(c.Value) }
 
 // TokenLiteral returns the literal from token token.STRING
 func (c *Comment) TokenLiteral() string { return c.Token.Literal }
@@ -568,7 +698,7 @@ func (s *SymbolLiteral) String() string       { return ":" + s.Value.String() }
 
 // ConditionalExpression represents an if expression within the AST
 type ConditionalExpression struct {
-	Token       token.Token // The 'if' or 'unless' token
+	Token       token.Token // The 'if', 'elsif' or 'unless' token
 	EndToken    token.Token // The 'end' token
 	Condition   Expression
 	Consequence *BlockStatement
@@ -584,61 +714,117 @@ func (ce *ConditionalExpression) expressionNode() {}
 
 // Pos returns the position of first character belonging to the node
 func (ce *ConditionalExpression) Pos() int {
-	if ce.EndToken.Type == token.ILLEGAL {
+	switch {
+	case ce.Token.Type == token.QMARK:
+		return ce.Condition.Pos()
+	case ce.EndToken.Type == token.ILLEGAL:
 		return ce.Consequence.Pos()
+	default:
+		return ce.Token.Pos
 	}
-	return ce.Token.Pos
 }
 
 // End returns the position of first character immediately after the node
 func (ce *ConditionalExpression) End() int {
-	if ce.EndToken.Type == token.ILLEGAL {
-		return ce.Consequence.Pos()
+	switch {
+	case ce.Token.Type == token.QMARK:
+		return ce.Alternative.End()
+	case ce.EndToken.Type == token.ILLEGAL:
+		return ce.Condition.End()
+	default:
+		return ce.EndToken.Pos + len(ce.EndToken.Literal)
+	}
+}
+
+// Elsif returns the conditional expression of an elsif branch, if
+// ce.Alternative consists of one
+func (ce *ConditionalExpression) Elsif() (*ConditionalExpression, bool) {
+	if ce.Alternative == nil || len(ce.Alternative.Statements) != 1 {
+		return nil, false
+	}
+	stmt, ok := ce.Alternative.Statements[0].(*ExpressionStatement)
+	if !ok {
+		return nil, false
+	}
+	elsif, ok := stmt.Expression.(*ConditionalExpression)
+	if !ok || elsif.Token.Type != token.ELSIF {
+		return nil, false
 	}
-	return ce.EndToken.Pos
+	return elsif, true
 }
 
-// TokenLiteral returns the literal from token token.IF or token.UNLESS
+// TokenLiteral returns the literal from token token.IF, token.ELSIF or token.UNLESS
 func (ce *ConditionalExpression) TokenLiteral() string { return ce.Token.Literal }
 func (ce *ConditionalExpression) String() string {
 	var out bytes.Buffer
+	ce.writeBranches(&out)
+	out.WriteString(" end")
+	return out.String()
+}
+
+// writeBranches writes ce and its elsif and else branches without the
+// closing end
+func (ce *ConditionalExpression) writeBranches(out *bytes.Buffer) {
 	out.WriteString(ce.Token.Literal)
 	out.WriteString(ce.Condition.String())
 	out.WriteString(" ")
 	out.WriteString(ce.Consequence.String())
+	if elsif, ok := ce.Elsif(); ok {
+		out.WriteString(" ")
+		elsif.writeBranches(out)
+		return
+	}
 	if ce.Alternative != nil {
 		out.WriteString("else ")
 		out.WriteString(ce.Alternative.String())
 	}
-	out.WriteString(" end")
-	return out.String()
 }
 
-// A LoopExpression represents a loop
+// A LoopExpression represents a while or until loop
 type LoopExpression struct {
-	Token     token.Token // while
+	Token     token.Token // while or until
 	EndToken  token.Token // end
 	Condition Expression
 	Block     *BlockStatement
+	// PostCondition is true for `begin ... end while cond`, where Block is
+	// evaluated once before the condition is checked for the first time
+	PostCondition bool
+}
+
+// IsNegated indicates if the loop uses until, i.e. the condition is negated
+func (ce *LoopExpression) IsNegated() bool {
+	return ce.Token.Type == token.UNTIL
+}
+
+// IsModifier reports whether the loop is written as modifier, i.e. `x while cond`
+func (ce *LoopExpression) IsModifier() bool {
+	return ce.EndToken.Type == token.ILLEGAL
 }
 
 func (ce *LoopExpression) expressionNode() {}
 
 // Pos returns the position of first character belonging to the node
 func (ce *LoopExpression) Pos() int {
+	if ce.IsModifier() {
+		return ce.Block.Pos()
+	}
 	return ce.Token.Pos
 }
 
 // End returns the position of first character immediately after the node
 func (ce *LoopExpression) End() int {
-	return ce.EndToken.Pos
+	if ce.IsModifier() {
+		return ce.Condition.End()
+	}
+	return ce.EndToken.Pos + len(ce.EndToken.Literal)
 }
 
-// TokenLiteral returns the literal from token token.WHILE
+// TokenLiteral returns the literal from token token.WHILE or token.UNTIL
 func (ce *LoopExpression) TokenLiteral() string { return ce.Token.Literal }
 func (ce *LoopExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString(ce.Token.Literal)
+	out.WriteString(" ")
 	out.WriteString(ce.Condition.String())
 	out.WriteString(" do ")
 	out.WriteString(ce.Block.String())
@@ -646,6 +832,580 @@ func (ce *LoopExpression) String() string {
 	return out.String()
 }
 
+// A BreakExpression represents a break out of a loop or a block
+type BreakExpression struct {
+	Token token.Token // the token.BREAK token
+	Value Expression  // the optional value to break with
+}
+
+func (b *BreakExpression) expressionNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (b *BreakExpression) Pos() int { return b.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (b *BreakExpression) End() int {
+	if b.Value == nil {
+		return b.Token.Pos + len(b.Token.Literal)
+	}
+	return b.Value.End()
+}
+
+// TokenLiteral returns the literal of the token.BREAK token
+func (b *BreakExpression) TokenLiteral() string { return b.Token.Literal }
+func (b *BreakExpression) String() string {
+	if b.Value == nil {
+		return b.Token.Literal
+	}
+	return b.Token.Literal + " " + b.Value.String()
+}
+
+// A NextExpression represents a jump to the next iteration of a loop or the
+// end of a block
+type NextExpression struct {
+	Token token.Token // the token.NEXT token
+	Value Expression  // the optional value returned from a block
+}
+
+func (n *NextExpression) expressionNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (n *NextExpression) Pos() int { return n.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (n *NextExpression) End() int {
+	if n.Value == nil {
+		return n.Token.Pos + len(n.Token.Literal)
+	}
+	return n.Value.End()
+}
+
+// TokenLiteral returns the literal of the token.NEXT token
+func (n *NextExpression) TokenLiteral() string { return n.Token.Literal }
+func (n *NextExpression) String() string {
+	if n.Value == nil {
+		return n.Token.Literal
+	}
+	return n.Token.Literal + " " + n.Value.String()
+}
+
+// A RedoExpression represents a restart of the current loop iteration or
+// block call without checking the loop condition again
+type RedoExpression struct {
+	Token token.Token // the token.REDO token
+}
+
+func (r *RedoExpression) expressionNode() {}
+func (r *RedoExpression) literalNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (r *RedoExpression) Pos() int { return r.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (r *RedoExpression) End() int { return r.Token.Pos + len(r.Token.Literal) }
+
+// TokenLiteral returns the literal of the token.REDO token
+func (r *RedoExpression) TokenLiteral() string { return r.Token.Literal }
+func (r *RedoExpression) String() string       { return r.Token.Literal }
+
+// A RetryExpression represents a restart of the begin block whose rescue
+// clause it is located in
+type RetryExpression struct {
+	Token token.Token // the token.RETRY token
+}
+
+func (r *RetryExpression) expressionNode() {}
+func (r *RetryExpression) literalNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (r *RetryExpression) Pos() int { return r.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (r *RetryExpression) End() int { return r.Token.Pos + len(r.Token.Literal) }
+
+// TokenLiteral returns the literal of the token.RETRY token
+func (r *RetryExpression) TokenLiteral() string { return r.Token.Literal }
+func (r *RetryExpression) String() string       { return r.Token.Literal }
+
+// A CaseExpression represents a case expression with when clauses
+type CaseExpression struct {
+	Token    token.Token // the token.CASE token
+	EndToken token.Token // the token.END token
+	Subject  Expression  // the optional value compared against the when clauses
+	Whens    []*WhenClause
+	Else     *BlockStatement
+}
+
+func (c *CaseExpression) expressionNode() {}
+
+// Pos returns the position of the `case` keyword
+func (c *CaseExpression) Pos() int { return c.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (c *CaseExpression) End() int { return c.EndToken.Pos + len(c.EndToken.Literal) }
+
+// TokenLiteral returns the literal of the token.CASE token
+func (c *CaseExpression) TokenLiteral() string { return c.Token.Literal }
+func (c *CaseExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString(c.Token.Literal)
+	if c.Subject != nil {
+		out.WriteString(" ")
+		out.WriteString(c.Subject.String())
+	}
+	out.WriteString("\n")
+	for _, w := range c.Whens {
+		out.WriteString(w.String())
+	}
+	if c.Else != nil {
+		out.WriteString("else\n")
+		out.WriteString(c.Else.String())
+		out.WriteString("\n")
+	}
+	out.WriteString("end")
+	return out.String()
+}
+
+// A WhenClause represents a single when branch of a case expression
+type WhenClause struct {
+	Token  token.Token // the token.WHEN token
+	Values []Expression
+	Body   *BlockStatement
+}
+
+func (w *WhenClause) expressionNode() {}
+
+// Pos returns the position of the `when` keyword
+func (w *WhenClause) Pos() int { return w.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (w *WhenClause) End() int {
+	if len(w.Body.Statements) == 0 {
+		return w.Values[len(w.Values)-1].End()
+	}
+	return w.Body.End()
+}
+
+// TokenLiteral returns the literal of the token.WHEN token
+func (w *WhenClause) TokenLiteral() string { return w.Token.Literal }
+func (w *WhenClause) String() string {
+	var out bytes.Buffer
+	values := make([]string, len(w.Values))
+	for i, v := range w.Values {
+		values[i] = v.String()
+	}
+	out.WriteString(w.Token.Literal)
+	out.WriteString(" ")
+	out.WriteString(strings.Join(values, ", "))
+	out.WriteString("\n")
+	out.WriteString(w.Body.String())
+	out.WriteString("\n")
+	return out.String()
+}
+
+// A CaseMatchExpression represents a case expression with in clauses, i.e.
+// pattern matching
+type CaseMatchExpression struct {
+	Token    token.Token // the token.CASE token
+	EndToken token.Token // the token.END token
+	Subject  Expression  // the value matched against the patterns
+	Ins      []*InClause
+	Else     *BlockStatement
+}
+
+func (c *CaseMatchExpression) expressionNode() {}
+
+// Pos returns the position of the `case` keyword
+func (c *CaseMatchExpression) Pos() int { return c.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (c *CaseMatchExpression) End() int { return c.EndToken.Pos + len(c.EndToken.Literal) }
+
+// TokenLiteral returns the literal of the token.CASE token
+func (c *CaseMatchExpression) TokenLiteral() string { return c.Token.Literal }
+func (c *CaseMatchExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString(c.Token.Literal)
+	out.WriteString(" ")
+	out.WriteString(c.Subject.String())
+	out.WriteString("\n")
+	for _, in := range c.Ins {
+		out.WriteString(in.String())
+	}
+	if c.Else != nil {
+		out.WriteString("else\n")
+		out.WriteString(c.Else.String())
+		out.WriteString("\n")
+	}
+	out.WriteString("end")
+	return out.String()
+}
+
+// An InClause represents a single in branch of a case expression
+type InClause struct {
+	Token      token.Token // the token.IN token
+	Pattern    Pattern
+	GuardToken token.Token // the token.IF or token.UNLESS token of the guard
+	Guard      Expression  // the optional guard condition
+	Body       *BlockStatement
+}
+
+// IsGuardNegated indicates if the guard uses unless, i.e. is negated
+func (in *InClause) IsGuardNegated() bool {
+	return in.GuardToken.Type == token.UNLESS
+}
+
+func (in *InClause) expressionNode() {}
+
+// Pos returns the position of the `in` keyword
+func (in *InClause) Pos() int { return in.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (in *InClause) End() int {
+	switch {
+	case len(in.Body.Statements) != 0:
+		return in.Body.End()
+	case in.Guard != nil:
+		return in.Guard.End()
+	default:
+		return in.Pattern.End()
+	}
+}
+
+// TokenLiteral returns the literal of the token.IN token
+func (in *InClause) TokenLiteral() string { return in.Token.Literal }
+func (in *InClause) String() string {
+	var out bytes.Buffer
+	out.WriteString(in.Token.Literal)
+	out.WriteString(" ")
+	out.WriteString(in.Pattern.String())
+	if in.Guard != nil {
+		out.WriteString(" ")
+		out.WriteString(in.GuardToken.Literal)
+		out.WriteString(" ")
+		out.WriteString(in.Guard.String())
+	}
+	out.WriteString("\n")
+	out.WriteString(in.Body.String())
+	out.WriteString("\n")
+	return out.String()
+}
+
+// A Pattern represents a pattern within an in clause
+//
+// All pattern nodes implement the Pattern interface.
+type Pattern interface {
+	Expression
+	patternNode()
+}
+
+// A ValuePattern matches any object for which `Value === object` is truthy
+type ValuePattern struct {
+	Value Expression
+}
+
+func (v *ValuePattern) expressionNode() {}
+func (v *ValuePattern) patternNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (v *ValuePattern) Pos() int { return v.Value.Pos() }
+
+// End returns the position of first character immediately after the node
+func (v *ValuePattern) End() int { return v.Value.End() }
+
+// TokenLiteral returns the literal of the value
+func (v *ValuePattern) TokenLiteral() string { return v.Value.TokenLiteral() }
+func (v *ValuePattern) String() string       { return v.Value.String() }
+
+// A VariablePattern matches any object and binds it to Name
+type VariablePattern struct {
+	Name *Identifier
+}
+
+func (v *VariablePattern) expressionNode() {}
+func (v *VariablePattern) patternNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (v *VariablePattern) Pos() int { return v.Name.Pos() }
+
+// End returns the position of first character immediately after the node
+func (v *VariablePattern) End() int { return v.Name.End() }
+
+// TokenLiteral returns the literal of the variable name
+func (v *VariablePattern) TokenLiteral() string { return v.Name.TokenLiteral() }
+func (v *VariablePattern) String() string       { return v.Name.String() }
+
+// A PinPattern matches the value of an expression instead of binding a
+// variable, i.e. `^x` or `^(expr)`
+type PinPattern struct {
+	Token  token.Token // the token.CARET token
+	Value  Expression
+	Rparen token.Token // the closing paren of `^(expr)`
+}
+
+func (pp *PinPattern) expressionNode() {}
+func (pp *PinPattern) patternNode()    {}
+
+// Pos returns the position of the `^`
+func (pp *PinPattern) Pos() int { return pp.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (pp *PinPattern) End() int {
+	if pp.Rparen.Type == token.RPAREN {
+		return pp.Rparen.Pos + 1
+	}
+	return pp.Value.End()
+}
+
+// TokenLiteral returns the literal of the token.CARET token
+func (pp *PinPattern) TokenLiteral() string { return pp.Token.Literal }
+func (pp *PinPattern) String() string {
+	if pp.Rparen.Type == token.RPAREN {
+		return pp.Token.Literal + "(" + pp.Value.String() + ")"
+	}
+	return pp.Token.Literal + pp.Value.String()
+}
+
+// An AlternativePattern matches if any of its alternatives matches, i.e.
+// `a | b`
+type AlternativePattern struct {
+	Alternatives []Pattern
+}
+
+func (a *AlternativePattern) expressionNode() {}
+func (a *AlternativePattern) patternNode()    {}
+
+// Pos returns the position of the first alternative
+func (a *AlternativePattern) Pos() int { return a.Alternatives[0].Pos() }
+
+// End returns the end of the last alternative
+func (a *AlternativePattern) End() int { return a.Alternatives[len(a.Alternatives)-1].End() }
+
+// TokenLiteral returns the literal of the first alternative
+func (a *AlternativePattern) TokenLiteral() string { return a.Alternatives[0].TokenLiteral() }
+func (a *AlternativePattern) String() string {
+	alternatives := make([]string, len(a.Alternatives))
+	for i, alt := range a.Alternatives {
+		alternatives[i] = alt.String()
+	}
+	return strings.Join(alternatives, " | ")
+}
+
+// A CapturePattern binds the object matched by Pattern to Name, i.e.
+// `pattern => name`
+type CapturePattern struct {
+	Pattern Pattern
+	Name    *Identifier
+}
+
+func (c *CapturePattern) expressionNode() {}
+func (c *CapturePattern) patternNode()    {}
+
+// Pos returns the position of the pattern
+func (c *CapturePattern) Pos() int { return c.Pattern.Pos() }
+
+// End returns the end of the bound name
+func (c *CapturePattern) End() int { return c.Name.End() }
+
+// TokenLiteral returns the literal of the pattern
+func (c *CapturePattern) TokenLiteral() string { return c.Pattern.TokenLiteral() }
+func (c *CapturePattern) String() string {
+	return c.Pattern.String() + " => " + c.Name.String()
+}
+
+// A SplatPattern matches the remaining elements of an array pattern, i.e.
+// `*rest`, or the remaining pairs of a hash pattern, i.e. `**rest`
+type SplatPattern struct {
+	Token token.Token // the token.ASTERISK or token.POW token
+	Name  *Identifier // the optional name the rest is bound to
+}
+
+func (s *SplatPattern) expressionNode() {}
+func (s *SplatPattern) patternNode()    {}
+
+// Pos returns the position of the splat operator
+func (s *SplatPattern) Pos() int { return s.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (s *SplatPattern) End() int {
+	if s.Name == nil {
+		return s.Token.Pos + len(s.Token.Literal)
+	}
+	return s.Name.End()
+}
+
+// TokenLiteral returns the literal of the splat operator
+func (s *SplatPattern) TokenLiteral() string { return s.Token.Literal }
+func (s *SplatPattern) String() string {
+	if s.Name == nil {
+		return s.Token.Literal
+	}
+	return s.Token.Literal + s.Name.String()
+}
+
+// An ArrayPattern matches arrays element by element, i.e. `[a, *rest]` or
+// `Const(a, b)`. At most one element is a SplatPattern.
+type ArrayPattern struct {
+	Token    token.Token // the opening bracket or paren, ILLEGAL for bare patterns
+	Constant Expression  // the optional constant the object must match
+	Elements []Pattern
+	Rbracket token.Token // the closing bracket or paren
+}
+
+func (a *ArrayPattern) expressionNode() {}
+func (a *ArrayPattern) patternNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (a *ArrayPattern) Pos() int {
+	switch {
+	case a.Constant != nil:
+		return a.Constant.Pos()
+	case a.Token.Type == token.ILLEGAL:
+		return a.Elements[0].Pos()
+	default:
+		return a.Token.Pos
+	}
+}
+
+// End returns the position of first character immediately after the node
+func (a *ArrayPattern) End() int {
+	if a.Token.Type == token.ILLEGAL {
+		return a.Elements[len(a.Elements)-1].End()
+	}
+	return a.Rbracket.Pos + 1
+}
+
+// TokenLiteral returns the literal of the opening bracket
+func (a *ArrayPattern) TokenLiteral() string { return a.Token.Literal }
+func (a *ArrayPattern) String() string {
+	elements := make([]string, len(a.Elements))
+	for i, e := range a.Elements {
+		elements[i] = e.String()
+	}
+	return patternString(a.Constant, a.Token, elements, a.Rbracket)
+}
+
+// A FindPattern searches an array for the elements in between two splats,
+// i.e. `[*, x, *post]`
+type FindPattern struct {
+	Token    token.Token // the opening bracket or paren, ILLEGAL for bare patterns
+	Constant Expression  // the optional constant the object must match
+	Pre      *SplatPattern
+	Elements []Pattern
+	Post     *SplatPattern
+	Rbracket token.Token // the closing bracket or paren
+}
+
+func (f *FindPattern) expressionNode() {}
+func (f *FindPattern) patternNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (f *FindPattern) Pos() int {
+	switch {
+	case f.Constant != nil:
+		return f.Constant.Pos()
+	case f.Token.Type == token.ILLEGAL:
+		return f.Pre.Pos()
+	default:
+		return f.Token.Pos
+	}
+}
+
+// End returns the position of first character immediately after the node
+func (f *FindPattern) End() int {
+	if f.Token.Type == token.ILLEGAL {
+		return f.Post.End()
+	}
+	return f.Rbracket.Pos + 1
+}
+
+// TokenLiteral returns the literal of the opening bracket
+func (f *FindPattern) TokenLiteral() string { return f.Token.Literal }
+func (f *FindPattern) String() string {
+	elements := []string{f.Pre.String()}
+	for _, e := range f.Elements {
+		elements = append(elements, e.String())
+	}
+	elements = append(elements, f.Post.String())
+	return patternString(f.Constant, f.Token, elements, f.Rbracket)
+}
+
+// A HashPattern matches hashes by their symbol keys, i.e. `{name: String}`
+// or `Const(x:, y:)`. A key without pattern binds its value to a variable
+// of the same name.
+type HashPattern struct {
+	Token    token.Token // the opening brace or paren, ILLEGAL for bare patterns
+	Constant Expression  // the optional constant the object must match
+	Keys     []*Identifier
+	Values   []Pattern     // the patterns for Keys, nil if the key has none
+	Rest     *SplatPattern // the optional `**rest`
+	NoRest   bool          // true for `**nil`, i.e. no other keys are allowed
+	Rbrace   token.Token   // the closing brace or paren
+}
+
+func (h *HashPattern) expressionNode() {}
+func (h *HashPattern) patternNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (h *HashPattern) Pos() int {
+	switch {
+	case h.Constant != nil:
+		return h.Constant.Pos()
+	case h.Token.Type != token.ILLEGAL:
+		return h.Token.Pos
+	case len(h.Keys) != 0:
+		return h.Keys[0].Pos()
+	default:
+		return h.Rest.Pos()
+	}
+}
+
+// End returns the position of first character immediately after the node
+func (h *HashPattern) End() int {
+	if h.Token.Type != token.ILLEGAL {
+		return h.Rbrace.Pos + 1
+	}
+	if h.Rest != nil {
+		return h.Rest.End()
+	}
+	last := len(h.Keys) - 1
+	if h.Values[last] != nil {
+		return h.Values[last].End()
+	}
+	return h.Keys[last].End() + 1
+}
+
+// TokenLiteral returns the literal of the opening brace
+func (h *HashPattern) TokenLiteral() string { return h.Token.Literal }
+func (h *HashPattern) String() string {
+	var pairs []string
+	for i, k := range h.Keys {
+		pair := k.String() + ":"
+		if h.Values[i] != nil {
+			pair += " " + h.Values[i].String()
+		}
+		pairs = append(pairs, pair)
+	}
+	if h.Rest != nil {
+		pairs = append(pairs, h.Rest.String())
+	}
+	if h.NoRest {
+		pairs = append(pairs, "**nil")
+	}
+	return patternString(h.Constant, h.Token, pairs, h.Rbrace)
+}
+
+func patternString(constant Expression, open token.Token, elements []string, close token.Token) string {
+	var out bytes.Buffer
+	if constant != nil {
+		out.WriteString(constant.String())
+	}
+	out.WriteString(open.Literal)
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString(close.Literal)
+	return out.String()
+}
+
 // ExpressionList represents a list of expressions within the AST divided by commas
 type ExpressionList []Expression
 
@@ -657,7 +1417,7 @@ func (el ExpressionList) Pos() int {
 	if len(el) == 0 {
 		return 0
 	}
-	return el[0].End()
+	return el[0].Pos()
 }
 
 // End returns End of the last element
@@ -679,12 +1439,124 @@ func (el ExpressionList) String() string {
 	var out bytes.Buffer
 	elements := []string{}
 	for _, e := range el {
+		// nested lists destructure on the left side of assignments
+		if list, ok := e.(ExpressionList); ok {
+			elements = append(elements, "("+list.String()+")")
+			continue
+		}
 		elements = append(elements, e.String())
 	}
 	out.WriteString(strings.Join(elements, ", "))
 	return out.String()
 }
 
+// A Splat represents a splat within call arguments, array literals and
+// assignments, i.e. `*list`, or a double splat within call arguments, i.e.
+// `**hash`. Value is nil for anonymous splats on the left side of
+// assignments.
+type Splat struct {
+	Token token.Token // the '*' or '**'
+	Value Expression
+}
+
+func (s *Splat) expressionNode() {}
+
+// IsDouble reports whether s is a double splat, i.e. `**hash`
+func (s *Splat) IsDouble() bool { return s.Token.Type == token.POW }
+
+// Pos returns the position of the splat operator
+func (s *Splat) Pos() int { return s.Token.Pos }
+
+// End returns the end of the value, or of the operator for anonymous splats
+func (s *Splat) End() int {
+	if s.Value == nil {
+		return s.Token.Pos + len(s.Token.Literal)
+	}
+	return s.Value.End()
+}
+
+// TokenLiteral returns the literal of the splat operator
+func (s *Splat) TokenLiteral() string { return s.Token.Literal }
+func (s *Splat) String() string {
+	if s.Value == nil {
+		return s.Token.Literal
+	}
+	return s.Token.Literal + s.Value.String()
+}
+
+// A RangeLiteral represents a range within the AST, e.g. `1..5`. Low is nil
+// for beginless ranges and High is nil for endless ranges.
+type RangeLiteral struct {
+	Token token.Token // the '..' or '...'
+	Low   Expression
+	High  Expression
+}
+
+// IsExclusive reports whether the range excludes its upper bound, i.e. is
+// written with three dots
+func (rl *RangeLiteral) IsExclusive() bool {
+	return rl.Token.Type == token.DOT3
+}
+
+func (rl *RangeLiteral) expressionNode() {}
+func (rl *RangeLiteral) literalNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (rl *RangeLiteral) Pos() int {
+	if rl.Low != nil {
+		return rl.Low.Pos()
+	}
+	return rl.Token.Pos
+}
+
+// End returns the position of first character immediately after the node
+func (rl *RangeLiteral) End() int {
+	if rl.High != nil {
+		return rl.High.End()
+	}
+	return rl.Token.Pos + len(rl.Token.Literal)
+}
+
+// TokenLiteral returns the literal of the token token.DOT2 or token.DOT3
+func (rl *RangeLiteral) TokenLiteral() string { return rl.Token.Literal }
+func (rl *RangeLiteral) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	if rl.Low != nil {
+		out.WriteString(rl.Low.String())
+	}
+	out.WriteString(rl.Token.Literal)
+	if rl.High != nil {
+		out.WriteString(rl.High.String())
+	}
+	out.WriteString(")")
+	return out.String()
+}
+
+// A RegexLiteral represents a regular expression literal within the AST,
+// e.g. `/foo/i` or `%r{foo}`. Value holds the pattern as *StringLiteral or,
+// if it contains interpolated code, as *InterpolatedStringLiteral.
+type RegexLiteral struct {
+	Token token.Token // the token.REGEX
+	Value Expression
+	Flags string
+}
+
+func (rl *RegexLiteral) expressionNode() {}
+func (rl *RegexLiteral) literalNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (rl *RegexLiteral) Pos() int { return rl.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (rl *RegexLiteral) End() int { return rl.Token.Pos + len(rl.Token.Literal) }
+
+// TokenLiteral returns the literal from token token.REGEX
+func (rl *RegexLiteral) TokenLiteral() string { return rl.Token.Literal }
+func (rl *RegexLiteral) String() string {
+	return "/" + rl.Value.String() + "/" + rl.Flags
+}
+
 // ArrayLiteral represents an Array literal within the AST
 type ArrayLiteral struct {
 	Token    token.Token // the '['
@@ -700,7 +1572,11 @@ func (al *ArrayLiteral) Pos() int { return al.Token.Pos }
 
 // End returns the position of first character immediately after the node
 func (al *ArrayLiteral) End() int {
-	return al.Rbracket.Pos
+	if al.Rbracket.Type != token.RBRACKET && len(al.Elements) != 0 {
+		// an implicit array, e.g. `return 1, 2`
+		return al.Elements[len(al.Elements)-1].End()
+	}
+	return al.Rbracket.Pos + len(al.Rbracket.Literal)
 }
 
 // TokenLiteral returns the literal of the token token.LBRACKET
@@ -719,27 +1595,40 @@ func (al *ArrayLiteral) String() string {
 
 // HashLiteral represents an Hash literal within the AST
 type HashLiteral struct {
-	Token  token.Token // the '{'
-	Rbrace token.Token // the '{'
-	Map    map[Expression]Expression
+	Token  token.Token // the '{', ILLEGAL for hashes without braces
+	Rbrace token.Token // the '}'
+	Keys   []Expression
+	Values []Expression // the values for Keys, in the same order
 }
 
 func (hl *HashLiteral) expressionNode() {}
 func (hl *HashLiteral) literalNode()    {}
 
-// Pos returns the position of the left brace
-func (hl *HashLiteral) Pos() int { return hl.Token.Pos }
+// Pos returns the position of the left brace, or of the first key if the
+// hash has no braces
+func (hl *HashLiteral) Pos() int {
+	if hl.Token.Type == token.ILLEGAL && len(hl.Keys) != 0 {
+		return hl.Keys[0].Pos()
+	}
+	return hl.Token.Pos
+}
 
-// End returns the position of the right brace
-func (hl *HashLiteral) End() int { return hl.Rbrace.Pos }
+// End returns the position of the right brace, or the end of the last value
+// if the hash has no braces
+func (hl *HashLiteral) End() int {
+	if hl.Token.Type == token.ILLEGAL && len(hl.Values) != 0 {
+		return hl.Values[len(hl.Values)-1].End()
+	}
+	return hl.Rbrace.Pos + len(hl.Rbrace.Literal)
+}
 
 // TokenLiteral returns the literal of the token token.LBRACE
 func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
 func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 	elements := []string{}
-	for key, val := range hl.Map {
-		elements = append(elements, fmt.Sprintf("%q => %q", key.String(), val.String()))
+	for i, key := range hl.Keys {
+		elements = append(elements, fmt.Sprintf("%q => %q", key.String(), hl.Values[i].String()))
 	}
 	out.WriteString("{")
 	out.WriteString(strings.Join(elements, ", "))
@@ -747,10 +1636,13 @@ func (hl *HashLiteral) String() string {
 	return out.String()
 }
 
-// A BlockCapture represents a function scoped variable capturing a block
+// A BlockCapture represents a function scoped variable capturing a block.
+// When passing a block argument to a method call the block can also be given
+// as an arbitrary expression, e.g. `&:upcase`, which is held in Value.
 type BlockCapture struct {
 	Token token.Token // the `&`
 	Name  *Identifier
+	Value Expression
 }
 
 func (b *BlockCapture) expressionNode() {}
@@ -759,9 +1651,17 @@ func (b *BlockCapture) literalNode()    {}
 // Pos returns the position of the ampersand
 func (b *BlockCapture) Pos() int { return b.Token.Pos }
 
-// End returns the position of the last character of Name
-func (b *BlockCapture) End() int { return b.Name.End() }
+// End returns the position of the last character of Name or Value
+func (b *BlockCapture) End() int {
+	if b.Value != nil {
+		return b.Value.End()
+	}
+	return b.Name.End()
+}
 func (b *BlockCapture) String() string {
+	if b.Value != nil {
+		return "&" + b.Value.String()
+	}
 	return "&" + b.Name.Value
 }
 
@@ -778,6 +1678,8 @@ type FunctionLiteral struct {
 	CapturedBlock *BlockCapture
 	Body          *BlockStatement
 	Rescues       []*RescueBlock
+	Else          *BlockStatement
+	Ensure        *BlockStatement
 }
 
 func (fl *FunctionLiteral) expressionNode() {}
@@ -787,7 +1689,7 @@ func (fl *FunctionLiteral) literalNode()    {}
 func (fl *FunctionLiteral) Pos() int { return fl.Token.Pos }
 
 // End returns the position of the `end` keyword
-func (fl *FunctionLiteral) End() int { return fl.EndToken.Pos }
+func (fl *FunctionLiteral) End() int { return fl.EndToken.Pos + len(fl.EndToken.Literal) }
 
 // TokenLiteral returns the literal from token.DEF
 func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
@@ -815,35 +1717,94 @@ func (fl *FunctionLiteral) String() string {
 	for _, r := range fl.Rescues {
 		out.WriteString(r.String())
 	}
+	writeElseEnsure(&out, fl.Else, fl.Ensure)
 	out.WriteString(" end")
 	return out.String()
 }
 
+// ParameterKind describes how a FunctionParameter gets its value
+type ParameterKind int
+
+const (
+	// PositionalParameter is a required or optional positional parameter, i.e. `a` or `a = 1`
+	PositionalParameter ParameterKind = iota
+	// RestParameter collects remaining positional arguments, i.e. `*a`
+	RestParameter
+	// KeywordParameter is a required or optional keyword parameter, i.e. `a:` or `a: 1`
+	KeywordParameter
+	// KeywordRestParameter collects remaining keyword arguments, i.e. `**a`
+	KeywordRestParameter
+	// BlockParameter captures the block passed to a block, i.e. `&a`
+	BlockParameter
+)
+
 // A FunctionParameter represents a parameter in a function literal
 type FunctionParameter struct {
-	Name    *Identifier
+	Token   token.Token // the '*', '**', '&' or '(', ILLEGAL for other parameters
+	Kind    ParameterKind
+	Name    *Identifier // nil for anonymous rest and destructuring parameters
 	Default Expression
+	// Elements are the parameters a destructuring block parameter like
+	// `(a, b)` spreads its argument over
+	Elements []*FunctionParameter
+	Rparen   token.Token // the ')' of destructuring parameters
 }
 
 func (f *FunctionParameter) expressionNode() {}
 
 // Pos returns the position of first character belonging to the node
-func (f *FunctionParameter) Pos() int { return f.Name.Pos() }
+func (f *FunctionParameter) Pos() int {
+	if f.Token.Type != token.ILLEGAL {
+		return f.Token.Pos
+	}
+	return f.Name.Pos()
+}
 
 // End returns the position of the default end if it exists, otherwise the end position of Name
 func (f *FunctionParameter) End() int {
 	if f.Default != nil {
 		return f.Default.End()
 	}
+	if f.Elements != nil {
+		return f.Rparen.Pos + len(f.Rparen.Literal)
+	}
+	if f.Name == nil {
+		return f.Token.Pos + len(f.Token.Literal)
+	}
+	if f.Kind == KeywordParameter {
+		return f.Name.End() + 1
+	}
 	return f.Name.End()
 }
 
 // TokenLiteral returns the token of the parameter name
-func (f *FunctionParameter) TokenLiteral() string { return f.Name.TokenLiteral() }
+func (f *FunctionParameter) TokenLiteral() string {
+	if f.Name == nil {
+		return f.Token.Literal
+	}
+	return f.Name.TokenLiteral()
+}
 func (f *FunctionParameter) String() string {
 	var out bytes.Buffer
-	out.WriteString(f.Name.String())
-	if f.Default != nil {
+	if f.Elements != nil {
+		params := []string{}
+		for _, p := range f.Elements {
+			params = append(params, p.String())
+		}
+		return "(" + strings.Join(params, ", ") + ")"
+	}
+	out.WriteString(f.Token.Literal)
+	if f.Name != nil {
+		out.WriteString(f.Name.String())
+	}
+	switch {
+	case f.Kind == KeywordParameter:
+		out.WriteString(":")
+		if f.Default != nil {
+			out.WriteString(" ")
+			out.WriteString(encloseInParensIfNeeded(f.Default))
+		}
+	case f.Default != nil:
 		out.WriteString(" = ")
 		out.WriteString(encloseInParensIfNeeded(f.Default))
 	}
@@ -852,19 +1813,20 @@ func (f *FunctionParameter) String() string {
 
 // An IndexExpression represents an array or hash access in the AST
 type IndexExpression struct {
-	Token  token.Token // The [ token
-	Left   Expression
-	Index  Expression
-	Length Expression
+	Token    token.Token // The [ token
+	Rbracket token.Token // The ] token
+	Left     Expression
+	Index    Expression
+	Length   Expression
 }
 
 func (ie *IndexExpression) expressionNode() {}
 
 // Pos returns the position of first character belonging to the node
-func (ie *IndexExpression) Pos() int { return ie.Token.Pos }
+func (ie *IndexExpression) Pos() int { return ie.Left.Pos() }
 
-// End returns the position of the last character belonging to the node
-func (ie *IndexExpression) End() int { return ie.Index.End() }
+// End returns the position of first character immediately after the node
+func (ie *IndexExpression) End() int { return ie.Rbracket.Pos + len(ie.Rbracket.Literal) }
 
 // TokenLiteral returns the literal from token.LBRACKET
 func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
@@ -888,6 +1850,7 @@ type ContextCallExpression struct {
 	Context   Expression       // The lefthandside expression
 	Function  *Identifier      // The function to call
 	Arguments []Expression     // The function arguments
+	Rparen    token.Token      // The ')' token if the arguments are parenthesized
 	Block     *BlockExpression // The function block
 }
 
@@ -908,6 +1871,9 @@ func (ce *ContextCallExpression) End() int {
 	if ce.Block != nil {
 		return ce.Block.End()
 	}
+	if ce.Rparen.Type == token.RPAREN {
+		return ce.Rparen.Pos + len(ce.Rparen.Literal)
+	}
 	if len(ce.Arguments) == 0 {
 		return ce.Function.End()
 	}
@@ -951,7 +1917,7 @@ func (b *BlockExpression) expressionNode() {}
 func (b *BlockExpression) Pos() int { return b.Token.Pos }
 
 // End returns the position of the end token
-func (b *BlockExpression) End() int { return b.EndToken.Pos }
+func (b *BlockExpression) End() int { return b.EndToken.Pos + len(b.EndToken.Literal) }
 
 // TokenLiteral returns the literal from the Token
 func (b *BlockExpression) TokenLiteral() string { return b.Token.Literal }
@@ -994,7 +1960,7 @@ func (m *ModuleExpression) expressionNode() {}
 func (m *ModuleExpression) Pos() int { return m.Token.Pos }
 
 // End returns the position of the `end` token
-func (m *ModuleExpression) End() int { return m.EndToken.Pos }
+func (m *ModuleExpression) End() int { return m.EndToken.Pos + len(m.EndToken.Literal) }
 
 // TokenLiteral returns the literal from token.MODULE
 func (m *ModuleExpression) TokenLiteral() string { return m.Token.Literal }
@@ -1025,7 +1991,7 @@ func (m *ClassExpression) expressionNode() {}
 func (m *ClassExpression) Pos() int { return m.Token.Pos }
 
 // End returns the position of the `end` token
-func (m *ClassExpression) End() int { return m.EndToken.Pos }
+func (m *ClassExpression) End() int { return m.EndToken.Pos + len(m.EndToken.Literal) }
 
 // TokenLiteral returns the literal from token.CLASS
 func (m *ClassExpression) TokenLiteral() string { return m.Token.Literal }
@@ -127,6 +127,12 @@ func walkExprList(v Visitor, list []Expression) {
 	}
 }
 
+func walkPatternList(v Visitor, list []Pattern) {
+	for _, x := range list {
+		Walk(v, x)
+	}
+}
+
 func walkStmtList(v Visitor, list []Statement) {
 	for _, x := range list {
 		Walk(v, x)
@@ -152,6 +158,7 @@ func Walk(v Visitor, node Node) {
 	case *Identifier,
 		*Global,
 		*IntegerLiteral,
+		*FloatLiteral,
 		*StringLiteral,
 		*SymbolLiteral,
 		*Boolean,
@@ -159,6 +166,8 @@ func Walk(v Visitor, node Node) {
 		*Self,
 		*BlockCapture,
 		*Keyword__FILE__,
+		*RedoExpression,
+		*RetryExpression,
 		*Comment:
 		// nothing to do
 
@@ -171,6 +180,12 @@ func Walk(v Visitor, node Node) {
 		for _, r := range n.Rescues {
 			Walk(v, r)
 		}
+		if n.Else != nil {
+			Walk(v, n.Else)
+		}
+		if n.Ensure != nil {
+			Walk(v, n.Ensure)
+		}
 
 	case *RescueBlock:
 		if len(n.ExceptionClasses) != 0 {
@@ -196,10 +211,21 @@ func Walk(v Visitor, node Node) {
 		for _, r := range n.Rescues {
 			Walk(v, r)
 		}
+		if n.Else != nil {
+			Walk(v, n.Else)
+		}
+		if n.Ensure != nil {
+			Walk(v, n.Ensure)
+		}
 
 	case *FunctionParameter:
-		Walk(v, n.Name)
-		Walk(v, n.Default)
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		if n.Default != nil {
+			Walk(v, n.Default)
+		}
+		walkParameterList(v, n.Elements)
 
 	case *IndexExpression:
 		Walk(v, n.Left)
@@ -243,14 +269,35 @@ func Walk(v Visitor, node Node) {
 	case ExpressionList:
 		walkExprList(v, n)
 
+	case *Splat:
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+
 	// Types
+	case *InterpolatedStringLiteral:
+		for _, part := range n.Parts {
+			Walk(v, part)
+		}
+
 	case *ArrayLiteral:
 		walkExprList(v, n.Elements)
 
+	case *RangeLiteral:
+		if n.Low != nil {
+			Walk(v, n.Low)
+		}
+		if n.High != nil {
+			Walk(v, n.High)
+		}
+
+	case *RegexLiteral:
+		Walk(v, n.Value)
+
 	case *HashLiteral:
-		for k, val := range n.Map {
+		for i, k := range n.Keys {
 			Walk(v, k)
-			Walk(v, val)
+			Walk(v, n.Values[i])
 		}
 
 	case *ExpressionStatement:
@@ -284,6 +331,96 @@ func Walk(v Visitor, node Node) {
 		Walk(v, n.Condition)
 		Walk(v, n.Block)
 
+	case *BreakExpression:
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+
+	case *NextExpression:
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+
+	case *CaseExpression:
+		if n.Subject != nil {
+			Walk(v, n.Subject)
+		}
+		for _, w := range n.Whens {
+			Walk(v, w)
+		}
+		if n.Else != nil {
+			Walk(v, n.Else)
+		}
+
+	case *WhenClause:
+		walkExprList(v, n.Values)
+		Walk(v, n.Body)
+
+	case *CaseMatchExpression:
+		Walk(v, n.Subject)
+		for _, in := range n.Ins {
+			Walk(v, in)
+		}
+		if n.Else != nil {
+			Walk(v, n.Else)
+		}
+
+	case *InClause:
+		Walk(v, n.Pattern)
+		if n.Guard != nil {
+			Walk(v, n.Guard)
+		}
+		Walk(v, n.Body)
+
+	case *ValuePattern:
+		Walk(v, n.Value)
+
+	case *VariablePattern:
+		Walk(v, n.Name)
+
+	case *PinPattern:
+		Walk(v, n.Value)
+
+	case *AlternativePattern:
+		walkPatternList(v, n.Alternatives)
+
+	case *CapturePattern:
+		Walk(v, n.Pattern)
+		Walk(v, n.Name)
+
+	case *SplatPattern:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+
+	case *ArrayPattern:
+		if n.Constant != nil {
+			Walk(v, n.Constant)
+		}
+		walkPatternList(v, n.Elements)
+
+	case *FindPattern:
+		if n.Constant != nil {
+			Walk(v, n.Constant)
+		}
+		Walk(v, n.Pre)
+		walkPatternList(v, n.Elements)
+		Walk(v, n.Post)
+
+	case *HashPattern:
+		if n.Constant != nil {
+			Walk(v, n.Constant)
+		}
+		for i, k := range n.Keys {
+			Walk(v, k)
+			if n.Values[i] != nil {
+				Walk(v, n.Values[i])
+			}
+		}
+		if n.Rest != nil {
+			Walk(v, n.Rest)
+		}
+
 	// Program
 	case *Program:
 		walkStmtList(v, n.Statements)
@@ -38,7 +38,7 @@ func (s *stackError) buildStack() {
 	}
 	for elem := path.Front(); elem != nil; elem = elem.Next() {
 		n := elem.Value.(ast.Node)
-		pos := s.file.Position(token.Pos(n.Pos()))
+		pos := ast.Position(s.file, n.Pos())
 		fmt.Println(pos.String())
 		fmt.Printf("Node: %#v\n", n)
 		// ast.Print(nil, n)
@@ -2,63 +2,74 @@ package evaluator
 
 import (
 	"fmt"
-	"sort"
+	"math/big"
 	"strings"
 
 	"github.com/goruby/goruby/ast"
 	"github.com/goruby/goruby/object"
+	"github.com/goruby/goruby/token"
 	"github.com/pkg/errors"
 )
 
+// evaluator evaluates the nodes of one program. Each evaluator keeps
+// its own call stack, so that independent evaluations do not interfere.
+type evaluator struct {
+	// rt keeps track of the Ruby call stack to build backtraces for exceptions
+	rt runtime
+}
+
 type callContext struct {
+	evaluator *evaluator
 	object.CallContext
 }
 
 func (c *callContext) Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
-	return Eval(node, env)
-}
-
-type rubyObjects []object.RubyObject
-
-func (r rubyObjects) Inspect() string {
-	toS := make([]string, len(r))
-	for i, e := range r {
-		toS[i] = e.Inspect()
-	}
-	return strings.Join(toS, ", ")
-}
-func (r rubyObjects) Type() object.Type       { return "" }
-func (r rubyObjects) Class() object.RubyClass { return nil }
-
-func expandToArrayIfNeeded(obj object.RubyObject) object.RubyObject {
-	arr, ok := obj.(rubyObjects)
-	if !ok {
-		return obj
-	}
-	return object.NewArray(arr...)
+	return c.evaluator.eval(node, env)
 }
 
 // Eval evaluates the given node and traverses recursive over its children
 func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
+	e := &evaluator{rt: newRuntime()}
+	return e.eval(node, env)
+}
+
+func (e *evaluator) eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 	switch node := node.(type) {
 
 	// Statements
 	case *ast.Program:
-		return evalProgram(node.Statements, env)
+		context := "<main>"
+		if e.rt.context() != "" {
+			context = "<top (required)>"
+		}
+		e.rt.pushToStack(context, node.File)
+		defer e.rt.popFromStack()
+		internSymbols(node)
+		return e.withBacktrace(e.evalProgram(node.Statements, env))
 	case *ast.ExpressionStatement:
-		return Eval(node.Expression, env)
+		e.rt.setPosition(node.Pos())
+		return e.eval(node.Expression, env)
 	case *ast.ReturnStatement:
-		val, err := Eval(node.ReturnValue, env)
+		val, err := e.eval(node.ReturnValue, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval of return statement")
 		}
 		return &object.ReturnValue{Value: val}, nil
+	case *object.FrameBody:
+		e.rt.pushToStack(node.Name, node.File)
+		defer e.rt.popFromStack()
+		return e.withBacktrace(e.evalBlockStatement(node.BlockStatement, env))
 	case *ast.BlockStatement:
-		return evalBlockStatement(node, env)
+		return e.evalBlockStatement(node, env)
 
 	// Literals
 	case (*ast.IntegerLiteral):
+		if node.BigValue != nil {
+			return object.NewBigInteger(node.BigValue), nil
+		}
 		return object.NewInteger(node.Value), nil
+	case (*ast.FloatLiteral):
+		return object.NewFloat(node.Value), nil
 	case (*ast.Boolean):
 		return nativeBoolToBooleanObject(node.Value), nil
 	case (*ast.Nil):
@@ -86,8 +97,11 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		}
 		return val, nil
 	case *ast.Identifier:
-		return evalIdentifier(node, env)
+		return e.evalIdentifier(node, env)
 	case *ast.Global:
+		if val, ok := object.LastMatchReference(env, node.Value); ok {
+			return val, nil
+		}
 		val, ok := env.Get(node.Value)
 		if !ok {
 			return object.NIL, nil
@@ -95,17 +109,23 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		return val, nil
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}, nil
+	case *ast.InterpolatedStringLiteral:
+		return e.evalInterpolatedStringLiteral(node, env)
+	case *ast.RangeLiteral:
+		return e.evalRangeLiteral(node, env)
+	case *ast.RegexLiteral:
+		return e.evalRegexLiteral(node, env)
 	case *ast.SymbolLiteral:
 		switch value := node.Value.(type) {
 		case *ast.Identifier:
-			return &object.Symbol{Value: value.Value}, nil
-		case *ast.StringLiteral:
-			str, err := Eval(value, env)
+			return object.NewSymbol(value.Value), nil
+		case *ast.StringLiteral, *ast.InterpolatedStringLiteral:
+			str, err := e.eval(value, env)
 			if err != nil {
 				return nil, errors.WithMessage(err, "eval symbol literal string")
 			}
 			if str, ok := str.(*object.String); ok {
-				return &object.Symbol{Value: str.Value}, nil
+				return object.NewSymbol(str.Value), nil
 			}
 			panic(errors.WithStack(
 				fmt.Errorf("error while parsing SymbolLiteral: expected *object.String, got %T", str),
@@ -119,7 +139,7 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		context, _ := env.Get("self")
 		_, inClassOrModule := context.(*object.Self).RubyObject.(object.Environment)
 		if node.Receiver != nil {
-			rec, err := Eval(node.Receiver, env)
+			rec, err := e.eval(node.Receiver, env)
 			if err != nil {
 				return nil, errors.WithMessage(err, "eval function receiver")
 			}
@@ -132,24 +152,29 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		}
 		params := make([]*object.FunctionParameter, len(node.Parameters))
 		for i, param := range node.Parameters {
-			def, err := Eval(param.Default, env)
-			if err != nil {
-				return nil, errors.WithMessage(err, "eval function literal param")
+			params[i] = &object.FunctionParameter{Default: param.Default, Kind: param.Kind}
+			if param.Name != nil {
+				params[i].Name = param.Name.Value
 			}
-			params[i] = &object.FunctionParameter{Name: param.Name.Value, Default: def}
 		}
-		body := node.Body
+		if node.CapturedBlock != nil {
+			params = append(params, &object.FunctionParameter{
+				Name: node.CapturedBlock.Name.Value,
+				Kind: ast.BlockParameter,
+			})
+		}
 		function := &object.Function{
 			Parameters: params,
 			Env:        env,
-			Body:       body,
+			Body:       functionBody(node),
+			Frame:      e.rt.newFrame(node.Name.Value),
 		}
 		extended := object.AddMethod(context, node.Name.Value, function)
 		if node.Receiver != nil && !inClassOrModule {
 			envInfo, _ := object.EnvStat(env, context)
 			envInfo.Env().Set(node.Receiver.Value, extended)
 		}
-		return &object.Symbol{Value: node.Name.Value}, nil
+		return object.NewSymbol(node.Name.Value), nil
 	case *ast.BlockExpression:
 		params := node.Parameters
 		body := node.Body
@@ -157,125 +182,73 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 			Parameters: params,
 			Body:       body,
 			Env:        env,
+			Frame:      e.rt.newFrame(blockContext(e.rt.context())),
 		}
 		return block, nil
 	case *ast.ArrayLiteral:
-		elements, err := evalExpressions(node.Elements, env)
+		elements, err := e.evalArguments(node.Elements, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval array literal")
 		}
 		return &object.Array{Elements: elements}, nil
 	case *ast.HashLiteral:
-		var hash object.Hash
-		for k, v := range node.Map {
-			key, err := Eval(k, env)
+		hash := object.NewHash()
+		context := &callContext{e, object.NewCallContext(env, hash)}
+		for i, k := range node.Keys {
+			key, err := e.eval(k, env)
 			if err != nil {
 				return nil, errors.WithMessage(err, "eval hash key")
 			}
-			value, err := Eval(v, env)
+			value, err := e.eval(node.Values[i], env)
 			if err != nil {
 				return nil, errors.WithMessage(err, "eval hash value")
 			}
-			hash.Set(key, value)
+			if _, err := object.Send(context, "[]=", key, value); err != nil {
+				return nil, errors.WithMessage(err, "eval hash literal")
+			}
 		}
-		return &hash, nil
+		return hash, nil
 	case ast.ExpressionList:
-		var objects []object.RubyObject
-		for _, e := range node {
-			obj, err := Eval(e, env)
-			if err != nil {
-				return nil, errors.WithMessage(err, "eval expression list")
-			}
-			objects = append(objects, obj)
+		elements, err := e.evalArguments(node, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval expression list")
+		}
+		return object.NewArray(elements...), nil
+	case *ast.Splat:
+		if node.IsDouble() {
+			return nil, errors.WithStack(
+				object.NewSyntaxError(fmt.Errorf("double splat not allowed outside of arguments")),
+			)
+		}
+		value, err := e.eval(node.Value, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval splat")
 		}
-		return rubyObjects(objects), nil
+		elements, err := e.splatElements(value, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval splat")
+		}
+		return object.NewArray(elements...), nil
 
 	// Expressions
 	case *ast.Assignment:
-		right, err := Eval(node.Right, env)
+		right, err := e.eval(node.Right, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval right hand Assignment side")
 		}
 
 		switch left := node.Left.(type) {
-		case *ast.IndexExpression:
-			indexLeft, err := Eval(left.Left, env)
-			if err != nil {
-				return nil, errors.WithMessage(err, "eval left hand Assignment side: eval left side of IndexExpression")
-			}
-			index, err := Eval(left.Index, env)
-			if err != nil {
-				return nil, errors.WithMessage(err, "eval left hand Assignment side: eval right side of IndexExpression")
-			}
-			return evalIndexExpressionAssignment(indexLeft, index, expandToArrayIfNeeded(right))
-		case *ast.InstanceVariable:
-			self, _ := env.Get("self")
-			selfObj := self.(*object.Self)
-			selfAsEnv, ok := selfObj.RubyObject.(object.Environment)
-			if !ok {
-				return nil, errors.Wrap(
-					object.NewSyntaxError(fmt.Errorf("instance variable not allowed for %s", selfObj.Name)),
-					"eval left hand Assignment side",
-				)
-			}
-
-			right = expandToArrayIfNeeded(right)
-			selfAsEnv.Set(left.String(), right)
-			return right, nil
-		case *ast.Identifier:
-			right = expandToArrayIfNeeded(right)
-			env.Set(left.Value, right)
-			return right, nil
-		case *ast.Global:
-			right = expandToArrayIfNeeded(right)
-			env.SetGlobal(left.Value, right)
-			return right, nil
 		case ast.ExpressionList:
-			values := []object.RubyObject{right}
-			if list, ok := right.(rubyObjects); ok {
-				values = list
-			}
-			if len(left) > len(values) {
-				// enlarge slice
-				for len(values) <= len(left) {
-					values = append(values, object.NIL)
-				}
-			}
-			for i, exp := range left {
-				if _, ok := exp.(*ast.InstanceVariable); ok {
-					self, _ := env.Get("self")
-					selfObj := self.(*object.Self)
-					selfAsEnv, ok := selfObj.RubyObject.(object.Environment)
-					if !ok {
-						return nil, errors.Wrap(
-							object.NewSyntaxError(fmt.Errorf("instance variable not allowed for %s", selfObj.Name)),
-							"eval left hand Assignment side",
-						)
-					}
-
-					selfAsEnv.Set(exp.String(), values[i])
-					continue
-				}
-				if indexExp, ok := exp.(*ast.IndexExpression); ok {
-					indexLeft, err := Eval(indexExp.Left, env)
-					if err != nil {
-						return nil, errors.WithMessage(err, "eval left hand Assignment side: eval left side of IndexExpression")
-					}
-					index, err := Eval(indexExp.Index, env)
-					if err != nil {
-						return nil, errors.WithMessage(err, "eval left hand Assignment side: eval right side of IndexExpression")
-					}
-					evalIndexExpressionAssignment(indexLeft, index, values[i])
-					continue
-				}
-				env.Set(exp.String(), values[i])
-			}
-			return expandToArrayIfNeeded(right), nil
+			err = e.evalMultipleAssignment(left, destructure(right), env)
+		case *ast.Splat:
+			err = e.evalMultipleAssignment(ast.ExpressionList{left}, destructure(right), env)
 		default:
-			return nil, errors.WithStack(
-				object.NewSyntaxError(fmt.Errorf("Assignment not supported to %T", node.Left)),
-			)
+			err = e.evalAssignmentTarget(left, right, env)
+		}
+		if err != nil {
+			return nil, err
 		}
+		return right, nil
 	case *ast.ModuleExpression:
 		module, ok := env.Get(node.Name.Value)
 		if !ok {
@@ -283,7 +256,7 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		}
 		moduleEnv := module.(object.Environment)
 		moduleEnv.Set("self", &object.Self{RubyObject: module, Name: node.Name.Value})
-		bodyReturn, err := Eval(node.Body, moduleEnv)
+		bodyReturn, err := e.eval(node.Body, moduleEnv)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval Module body")
 		}
@@ -309,7 +282,7 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		}
 		classEnv := class.(object.Environment)
 		classEnv.Set("self", &object.Self{RubyObject: class, Name: node.Name.Value})
-		bodyReturn, err := Eval(node.Body, classEnv)
+		bodyReturn, err := e.eval(node.Body, classEnv)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval class body")
 		}
@@ -318,56 +291,93 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		env.Set(node.Name.Value, self.RubyObject)
 		return bodyReturn, nil
 	case *ast.ContextCallExpression:
-		context, err := Eval(node.Context, env)
+		context, err := e.eval(node.Context, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval method call receiver")
 		}
 		if context == nil {
 			context, _ = env.Get("self")
 		}
-		args, err := evalExpressions(node.Arguments, env)
+		arguments := node.Arguments
+		var capture *ast.BlockCapture
+		if len(arguments) != 0 {
+			if c, ok := arguments[len(arguments)-1].(*ast.BlockCapture); ok {
+				capture = c
+				arguments = arguments[:len(arguments)-1]
+			}
+		}
+		args, err := e.evalArguments(arguments, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval method call arguments")
 		}
+		var block object.RubyObject
 		if node.Block != nil {
-			block, err := Eval(node.Block, env)
+			block, err = e.eval(node.Block, env)
 			if err != nil {
 				return nil, errors.WithMessage(err, "eval method call block")
 			}
 			args = append(args, block)
+		} else if capture != nil {
+			proc, err := e.evalBlockArgument(capture, env)
+			if err != nil {
+				return nil, errors.WithMessage(err, "eval method call block argument")
+			}
+			if proc != nil {
+				block = proc
+				args = append(args, block)
+			}
+		}
+		callContext := &callContext{e, object.NewCallContext(env, context)}
+		e.rt.setPosition(node.Pos())
+		var ret object.RubyObject
+		if node.Context == nil && node.Function.Value == "super" {
+			ret, err = e.withBacktrace(e.evalSuper(callContext, args...))
+		} else {
+			ret, err = e.withBacktrace(object.Send(callContext, node.Function.Value, args...))
 		}
-		callContext := &callContext{object.NewCallContext(env, context)}
-		return object.Send(callContext, node.Function.Value, args...)
+		if jump, ok := errors.Cause(err).(*object.Jump); ok && block != nil && jump.Block == block {
+			return jump.Value, nil
+		}
+		return ret, err
 	case *ast.YieldExpression:
 		selfObject, _ := env.Get("self")
 		self := selfObject.(*object.Self)
 		if self.Block == nil {
 			return nil, errors.WithStack(object.NewNoBlockGivenLocalJumpError())
 		}
-		args, err := evalExpressions(node.Arguments, env)
+		args, err := e.evalArguments(node.Arguments, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval yield arguments")
 		}
-		callContext := &callContext{object.NewCallContext(env, self)}
-		return self.Block.Call(callContext, args...)
+		callContext := &callContext{e, object.NewCallContext(env, self)}
+		e.rt.setPosition(node.Pos())
+		return e.withBacktrace(self.Block.Call(callContext, args...))
 	case *ast.IndexExpression:
-		left, err := Eval(node.Left, env)
+		left, err := e.eval(node.Left, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval IndexExpression left side")
 		}
-		index, err := Eval(node.Index, env)
+		index, err := e.eval(node.Index, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval IndexExpression index")
 		}
-		return evalIndexExpression(left, index)
+		args := []object.RubyObject{index}
+		if node.Length != nil {
+			length, err := e.eval(node.Length, env)
+			if err != nil {
+				return nil, errors.WithMessage(err, "eval IndexExpression length")
+			}
+			args = append(args, length)
+		}
+		return e.evalIndexExpression(env, left, args...)
 	case *ast.PrefixExpression:
-		right, err := Eval(node.Right, env)
+		right, err := e.eval(node.Right, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval prefix right side")
 		}
 		return evalPrefixExpression(node.Operator, right)
 	case *ast.InfixExpression:
-		left, err := Eval(node.Left, env)
+		left, err := e.eval(node.Left, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval operator left side")
 		}
@@ -376,17 +386,31 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 			return left, nil
 		}
 
-		right, err := Eval(node.Right, env)
+		right, err := e.eval(node.Right, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval operator right side")
 		}
 		if node.IsControlExpression() {
 			return right, nil
 		}
-		context := &callContext{object.NewCallContext(env, left)}
+		context := &callContext{e, object.NewCallContext(env, left)}
 		return object.Send(context, node.Operator, right)
 	case *ast.ConditionalExpression:
-		return evalConditionalExpression(node, env)
+		return e.evalConditionalExpression(node, env)
+	case *ast.CaseExpression:
+		return e.evalCaseExpression(node, env)
+	case *ast.CaseMatchExpression:
+		return e.evalCaseMatchExpression(node, env)
+	case *ast.LoopExpression:
+		return e.evalLoopExpression(node, env)
+	case *ast.BreakExpression:
+		return e.evalJump(object.BreakJump, node.Value, env)
+	case *ast.NextExpression:
+		return e.evalJump(object.NextJump, node.Value, env)
+	case *ast.RedoExpression:
+		return nil, &object.Jump{JumpType: object.RedoJump, Value: object.NIL}
+	case *ast.RetryExpression:
+		return nil, &object.Jump{JumpType: object.RetryJump, Value: object.NIL}
 	case *ast.ScopedIdentifier:
 		self, _ := env.Get("self")
 		outer, ok := env.Get(node.Outer.Value)
@@ -403,17 +427,13 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 				"eval scope outer",
 			)
 		}
-		inner, err := Eval(node.Inner, outerEnv)
+		inner, err := e.eval(node.Inner, outerEnv)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval scope inner")
 		}
 		return inner, nil
 	case *ast.ExceptionHandlingBlock:
-		bodyReturn, err := Eval(node.TryBody, env)
-		if err == nil {
-			return bodyReturn, nil
-		}
-		return handleException(err, node.Rescues, env)
+		return e.evalExceptionHandlingBlock(node, env)
 
 	case *ast.Comment:
 		// ignore comments
@@ -428,15 +448,46 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 
 }
 
-func evalProgram(stmts []ast.Statement, env object.Environment) (object.RubyObject, error) {
+// internSymbols adds the symbol literals without interpolation within program
+// to the symbol table before it runs, as MRI does when compiling a program.
+// That way Symbol.all_symbols contains them before they are evaluated.
+func internSymbols(program *ast.Program) {
+	ast.Inspect(program, func(node ast.Node) bool {
+		symbol, ok := node.(*ast.SymbolLiteral)
+		if !ok {
+			return true
+		}
+		switch value := symbol.Value.(type) {
+		case *ast.Identifier:
+			object.NewSymbol(value.Value)
+		case *ast.StringLiteral:
+			object.NewSymbol(value.Value)
+		}
+		return true
+	})
+}
+
+// withBacktrace attaches the current backtrace to err if it is a Ruby
+// exception raised within the innermost frame
+func (e *evaluator) withBacktrace(obj object.RubyObject, err error) (object.RubyObject, error) {
+	if err != nil {
+		object.AddBacktrace(err, e.rt.backtrace())
+	}
+	return obj, err
+}
+
+func (e *evaluator) evalProgram(stmts []ast.Statement, env object.Environment) (object.RubyObject, error) {
 	var result object.RubyObject
 	var err error
 	for _, statement := range stmts {
 		if _, ok := statement.(*ast.Comment); ok {
 			continue
 		}
-		result, err = Eval(statement, env)
+		result, err = e.eval(statement, env)
 
+		if jump, ok := errors.Cause(err).(*object.Jump); ok {
+			err = errors.WithStack(object.NewJumpLocalJumpError(jump))
+		}
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval program statement")
 		}
@@ -450,19 +501,250 @@ func evalProgram(stmts []ast.Statement, env object.Environment) (object.RubyObje
 	return result, nil
 }
 
-func evalExpressions(exps []ast.Expression, env object.Environment) ([]object.RubyObject, error) {
+// evalArguments evaluates the arguments of method calls and the elements of
+// array literals. Splats are expanded in place, double splats and hashes
+// without braces are merged into a single trailing Hash of keywords.
+func (e *evaluator) evalArguments(exps []ast.Expression, env object.Environment) ([]object.RubyObject, error) {
 	var result []object.RubyObject
-
-	for _, e := range exps {
-		evaluated, err := Eval(e, env)
+	var keywords *object.Hash
+	for _, exp := range exps {
+		splat, isSplat := exp.(*ast.Splat)
+		hash, isHash := exp.(*ast.HashLiteral)
+		if isSplat && !splat.IsDouble() {
+			value, err := e.eval(splat.Value, env)
+			if err != nil {
+				return nil, err
+			}
+			elements, err := e.splatElements(value, env)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, elements...)
+			keywords = nil
+			continue
+		}
+		if !isSplat && !(isHash && hash.Token.Type == token.ILLEGAL) {
+			evaluated, err := e.eval(exp, env)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, evaluated)
+			keywords = nil
+			continue
+		}
+		var value object.RubyObject
+		var err error
+		if isSplat {
+			value, err = e.eval(splat.Value, env)
+		} else {
+			value, err = e.eval(hash, env)
+		}
 		if err != nil {
 			return nil, err
 		}
-		result = append(result, evaluated)
+		pairs, ok := value.(*object.Hash)
+		if !ok {
+			return nil, errors.WithStack(object.NewImplicitConversionTypeError(object.NewHash(), value))
+		}
+		if keywords == nil && isHash {
+			keywords = pairs
+			result = append(result, keywords)
+			continue
+		}
+		if keywords == nil {
+			keywords = object.NewHash()
+			result = append(result, keywords)
+		}
+		context := &callContext{e, object.NewCallContext(env, keywords)}
+		values := pairs.Values()
+		for i, key := range pairs.Keys() {
+			if _, err := object.Send(context, "[]=", key, values[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// like in MRI double splatting an empty hash passes no argument
+	if keywords != nil && keywords.Len() == 0 {
+		result = result[:len(result)-1]
 	}
 	return result, nil
 }
 
+// splatElements returns the elements value is expanded to by a splat. Objects
+// other than arrays and nil are converted by calling to_a on them, if they
+// respond to it.
+func (e *evaluator) splatElements(value object.RubyObject, env object.Environment) ([]object.RubyObject, error) {
+	if value == object.NIL {
+		return nil, nil
+	}
+	if array, ok := value.(*object.Array); ok {
+		return append([]object.RubyObject{}, array.Elements...), nil
+	}
+	context := &callContext{e, object.NewCallContext(env, value)}
+	converted, err := object.Send(context, "to_a")
+	if _, ok := errors.Cause(err).(*object.NoMethodError); ok {
+		return []object.RubyObject{value}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	array, ok := converted.(*object.Array)
+	if !ok {
+		return nil, errors.WithStack(object.NewTypeError(fmt.Sprintf(
+			"can't convert %s to Array (%s#to_a gives %s)",
+			value.Class().(object.RubyObject).Inspect(),
+			value.Class().(object.RubyObject).Inspect(),
+			converted.Class().(object.RubyObject).Inspect(),
+		)))
+	}
+	return append([]object.RubyObject{}, array.Elements...), nil
+}
+
+// destructure returns the values the right side of a multiple assignment
+// provides: the elements of arrays or the value itself
+func destructure(value object.RubyObject) []object.RubyObject {
+	if array, ok := value.(*object.Array); ok {
+		return array.Elements
+	}
+	return []object.RubyObject{value}
+}
+
+// evalMultipleAssignment assigns values to targets. Targets without value
+// get nil and a splat target collects all values not taken by the targets
+// before and after it.
+func (e *evaluator) evalMultipleAssignment(targets ast.ExpressionList, values []object.RubyObject, env object.Environment) error {
+	splatIdx := -1
+	for i, target := range targets {
+		if _, ok := target.(*ast.Splat); ok {
+			splatIdx = i
+			break
+		}
+	}
+	valueAt := func(i int) object.RubyObject {
+		if i < len(values) {
+			return values[i]
+		}
+		return object.NIL
+	}
+	if splatIdx == -1 {
+		for i, target := range targets {
+			if err := e.evalAssignmentTarget(target, valueAt(i), env); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	pre, post := targets[:splatIdx], targets[splatIdx+1:]
+	for i, target := range pre {
+		if err := e.evalAssignmentTarget(target, valueAt(i), env); err != nil {
+			return err
+		}
+	}
+	restEnd := len(values) - len(post)
+	if restEnd < len(pre) {
+		restEnd = len(pre)
+	}
+	rest := object.NewArray()
+	if restEnd > len(pre) {
+		rest.Elements = append(rest.Elements, values[len(pre):restEnd]...)
+	}
+	if splat := targets[splatIdx].(*ast.Splat); splat.Value != nil {
+		if err := e.evalAssignmentTarget(splat.Value, rest, env); err != nil {
+			return err
+		}
+	}
+	for i, target := range post {
+		if err := e.evalAssignmentTarget(target, valueAt(restEnd+i), env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evalAssignmentTarget assigns value to a single target on the left side of
+// an assignment. Nested lists destructure value.
+func (e *evaluator) evalAssignmentTarget(target ast.Expression, value object.RubyObject, env object.Environment) error {
+	switch target := target.(type) {
+	case *ast.Identifier:
+		env.Set(target.Value, value)
+	case *ast.Global:
+		env.SetGlobal(target.Value, value)
+	case *ast.InstanceVariable:
+		self, _ := env.Get("self")
+		selfObj := self.(*object.Self)
+		selfAsEnv, ok := selfObj.RubyObject.(object.Environment)
+		if !ok {
+			return errors.Wrap(
+				object.NewSyntaxError(fmt.Errorf("instance variable not allowed for %s", selfObj.Name)),
+				"eval left hand Assignment side",
+			)
+		}
+		selfAsEnv.Set(target.String(), value)
+	case *ast.IndexExpression:
+		indexLeft, err := e.eval(target.Left, env)
+		if err != nil {
+			return errors.WithMessage(err, "eval left hand Assignment side: eval left side of IndexExpression")
+		}
+		index, err := e.evalIndexAssignmentArguments(target, env)
+		if err != nil {
+			return errors.WithMessage(err, "eval left hand Assignment side: eval right side of IndexExpression")
+		}
+		if _, err := e.evalIndexExpressionAssignment(env, indexLeft, index, value); err != nil {
+			return err
+		}
+	case *ast.ContextCallExpression:
+		receiver, err := e.eval(target.Context, env)
+		if err != nil {
+			return errors.WithMessage(err, "eval left hand Assignment side: eval receiver of attribute")
+		}
+		e.rt.setPosition(target.Pos())
+		_, err = e.withBacktrace(object.Send(&callContext{e, object.NewCallContext(env, receiver)}, target.Function.Value+"=", value))
+		return err
+	case ast.ExpressionList:
+		return e.evalMultipleAssignment(target, destructure(value), env)
+	default:
+		return errors.WithStack(
+			object.NewSyntaxError(fmt.Errorf("Assignment not supported to %T", target)),
+		)
+	}
+	return nil
+}
+
+// evalBlockArgument evaluates the block argument `&block` of a method call.
+// Objects other than procs are converted by calling to_proc on them. The
+// returned proc is nil if the block argument evaluates to nil.
+func (e *evaluator) evalBlockArgument(capture *ast.BlockCapture, env object.Environment) (*object.Proc, error) {
+	var value ast.Expression = capture.Name
+	if capture.Value != nil {
+		value = capture.Value
+	}
+	block, err := e.eval(value, env)
+	if err != nil {
+		return nil, err
+	}
+	if block == object.NIL {
+		return nil, nil
+	}
+	if proc, ok := block.(*object.Proc); ok {
+		return proc, nil
+	}
+	converted, err := object.Send(&callContext{e, object.NewCallContext(env, block)}, "to_proc")
+	if _, ok := errors.Cause(err).(*object.NoMethodError); ok {
+		return nil, errors.WithStack(object.NewWrongArgumentTypeError(&object.Proc{}, block))
+	}
+	if err != nil {
+		return nil, err
+	}
+	proc, ok := converted.(*object.Proc)
+	if !ok {
+		return nil, errors.WithStack(object.NewTypeError(fmt.Sprintf(
+			"can't convert %s to Proc (%s#to_proc gives %s)",
+			block.Class().Name(), block.Class().Name(), converted.Class().Name(),
+		)))
+	}
+	return proc, nil
+}
+
 func evalPrefixExpression(operator string, right object.RubyObject) (object.RubyObject, error) {
 	switch operator {
 	case "!":
@@ -490,14 +772,16 @@ func evalBangOperatorExpression(right object.RubyObject) object.RubyObject {
 func evalMinusPrefixOperatorExpression(right object.RubyObject) (object.RubyObject, error) {
 	switch right := right.(type) {
 	case *object.Integer:
-		return &object.Integer{Value: -right.Value}, nil
+		return object.NewBigInteger(new(big.Int).Neg(right.BigInt())), nil
+	case *object.Float:
+		return object.NewFloat(-right.Value), nil
 	default:
 		return nil, errors.WithStack(object.NewException("unknown operator: -%s", right.Type()))
 	}
 }
 
-func evalConditionalExpression(ce *ast.ConditionalExpression, env object.Environment) (object.RubyObject, error) {
-	condition, err := Eval(ce.Condition, env)
+func (e *evaluator) evalConditionalExpression(ce *ast.ConditionalExpression, env object.Environment) (object.RubyObject, error) {
+	condition, err := e.eval(ce.Condition, env)
 	if err != nil {
 		return nil, err
 	}
@@ -506,35 +790,204 @@ func evalConditionalExpression(ce *ast.ConditionalExpression, env object.Environ
 		evaluateConsequence = !evaluateConsequence
 	}
 	if evaluateConsequence {
-		return Eval(ce.Consequence, env)
+		return e.eval(ce.Consequence, env)
 	} else if ce.Alternative != nil {
-		return Eval(ce.Alternative, env)
+		return e.eval(ce.Alternative, env)
 	} else {
 		return object.NIL, nil
 	}
 }
 
-func evalIndexExpressionAssignment(left, index, right object.RubyObject) (object.RubyObject, error) {
-	switch target := left.(type) {
-	case *object.Array:
-		integer, ok := index.(*object.Integer)
+func (e *evaluator) evalCaseExpression(ce *ast.CaseExpression, env object.Environment) (object.RubyObject, error) {
+	var subject object.RubyObject
+	if ce.Subject != nil {
+		var err error
+		subject, err = e.eval(ce.Subject, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval case subject")
+		}
+	}
+	for _, when := range ce.Whens {
+		for _, v := range when.Values {
+			value, err := e.eval(v, env)
+			if err != nil {
+				return nil, errors.WithMessage(err, "eval when value")
+			}
+			matched := isTruthy(value)
+			if subject != nil {
+				matched, err = e.caseEqual(value, subject, env)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if matched {
+				return e.eval(when.Body, env)
+			}
+		}
+	}
+	if ce.Else != nil {
+		return e.eval(ce.Else, env)
+	}
+	return object.NIL, nil
+}
+
+func (e *evaluator) evalInterpolatedStringLiteral(node *ast.InterpolatedStringLiteral, env object.Environment) (object.RubyObject, error) {
+	var out strings.Builder
+	for _, part := range node.Parts {
+		if lit, ok := part.(*ast.StringLiteral); ok {
+			out.WriteString(lit.Value)
+			continue
+		}
+		value, err := e.eval(part, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval string interpolation")
+		}
+		if value == nil {
+			continue
+		}
+		str, ok := value.(*object.String)
 		if !ok {
-			return nil, errors.Wrap(
-				object.NewImplicitConversionTypeError(integer, index),
-				"eval array index",
-			)
+			context := &callContext{e, object.NewCallContext(env, value)}
+			converted, err := object.Send(context, "to_s")
+			if err != nil {
+				return nil, errors.WithMessage(err, "eval string interpolation")
+			}
+			str, ok = converted.(*object.String)
+			if !ok {
+				str = &object.String{Value: value.Inspect()}
+			}
 		}
-		idx := int(integer.Value)
-		if idx >= len(target.Elements) {
-			// enlarge slice
-			for len(target.Elements) <= idx {
-				target.Elements = append(target.Elements, object.NIL)
+		out.WriteString(str.Value)
+	}
+	return &object.String{Value: out.String()}, nil
+}
+
+// caseEqual reports whether `pattern === obj` is truthy
+func (e *evaluator) caseEqual(pattern, obj object.RubyObject, env object.Environment) (bool, error) {
+	context := &callContext{e, object.NewCallContext(env, pattern)}
+	result, err := object.Send(context, "===", obj)
+	if err != nil {
+		return false, err
+	}
+	return isTruthy(result), nil
+}
+
+func (e *evaluator) evalLoopExpression(loop *ast.LoopExpression, env object.Environment) (object.RubyObject, error) {
+	checkCondition := !loop.PostCondition
+	for {
+		if checkCondition {
+			condition, err := e.eval(loop.Condition, env)
+			if err != nil {
+				return nil, errors.WithMessage(err, "eval loop condition")
+			}
+			if isTruthy(condition) == loop.IsNegated() {
+				return object.NIL, nil
+			}
+		}
+		checkCondition = true
+		result, err := e.eval(loop.Block, env)
+		if err != nil {
+			jump, ok := errors.Cause(err).(*object.Jump)
+			if !ok || jump.Block != nil {
+				return nil, err
 			}
+			switch jump.JumpType {
+			case object.BreakJump:
+				return jump.Value, nil
+			case object.RedoJump:
+				checkCondition = false
+			case object.RetryJump:
+				return nil, err
+			}
+			continue
+		}
+		if _, ok := result.(*object.ReturnValue); ok {
+			return result, nil
+		}
+	}
+}
+
+func (e *evaluator) evalJump(jumpType object.JumpType, value ast.Expression, env object.Environment) (object.RubyObject, error) {
+	var val object.RubyObject = object.NIL
+	if value != nil {
+		var err error
+		val, err = e.eval(value, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("eval %s value", jumpType))
+		}
+	}
+	return nil, &object.Jump{JumpType: jumpType, Value: val}
+}
+
+func (e *evaluator) evalRegexLiteral(node *ast.RegexLiteral, env object.Environment) (object.RubyObject, error) {
+	source, err := e.eval(node.Value, env)
+	if err != nil {
+		return nil, errors.WithMessage(err, "eval regex literal")
+	}
+	e.rt.setPosition(node.Pos())
+	re, err := object.NewRegexp(source.(*object.String).Value, object.RegexpOptions(node.Flags))
+	if err != nil {
+		return e.withBacktrace(nil, errors.WithStack(err))
+	}
+	return re, nil
+}
+
+func (e *evaluator) evalRangeLiteral(node *ast.RangeLiteral, env object.Environment) (object.RubyObject, error) {
+	var low, high object.RubyObject = object.NIL, object.NIL
+	var err error
+	if node.Low != nil {
+		low, err = e.eval(node.Low, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval range begin")
+		}
+	}
+	if node.High != nil {
+		high, err = e.eval(node.High, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval range end")
+		}
+	}
+	context := &callContext{e, object.NewCallContext(env, low)}
+	e.rt.setPosition(node.Pos())
+	rng, err := object.NewRange(context, low, high, node.IsExclusive())
+	if err != nil {
+		return e.withBacktrace(nil, errors.WithStack(err))
+	}
+	return rng, nil
+}
+
+// evalIndexAssignmentArguments evaluates the index and the optional length
+// of an index expression on the left side of an assignment
+func (e *evaluator) evalIndexAssignmentArguments(node *ast.IndexExpression, env object.Environment) ([]object.RubyObject, error) {
+	index, err := e.eval(node.Index, env)
+	if err != nil {
+		return nil, err
+	}
+	if node.Length == nil {
+		return []object.RubyObject{index}, nil
+	}
+	length, err := e.eval(node.Length, env)
+	if err != nil {
+		return nil, err
+	}
+	return []object.RubyObject{index, length}, nil
+}
+
+func (e *evaluator) evalIndexExpressionAssignment(env object.Environment, left object.RubyObject, index []object.RubyObject, right object.RubyObject) (object.RubyObject, error) {
+	switch target := left.(type) {
+	case *object.Array:
+		context := &callContext{e, object.NewCallContext(env, target)}
+		_, err := object.Send(context, "[]=", append(index, right)...)
+		if err != nil {
+			return nil, errors.Wrap(err, "eval array index")
 		}
-		target.Elements[idx] = right
 		return right, nil
 	case *object.Hash:
-		target.Set(index, right)
+		context := &callContext{e, object.NewCallContext(env, target)}
+		_, err := object.Send(context, "[]=", append(index, right)...)
+		if err != nil {
+			return nil, errors.Wrap(err, "eval hash index")
+		}
 		return right, nil
 	default:
 		return nil, errors.Wrap(
@@ -544,15 +997,15 @@ func evalIndexExpressionAssignment(left, index, right object.RubyObject) (object
 	}
 }
 
-func evalIndexExpression(left, index object.RubyObject) (object.RubyObject, error) {
+func (e *evaluator) evalIndexExpression(env object.Environment, left object.RubyObject, args ...object.RubyObject) (object.RubyObject, error) {
 	switch target := left.(type) {
 	case *object.Array:
-		return evalArrayIndexExpression(target, index), nil
-	case *object.Hash:
-		return evalHashIndexExpression(target, index), nil
-	default:
-		return nil, errors.WithStack(object.NewException("index operator not supported: %s", left.Type()))
+		if _, ok := args[0].(*object.Integer); ok && len(args) == 1 {
+			return evalArrayIndexExpression(target, args[0]), nil
+		}
 	}
+	context := &callContext{e, object.NewCallContext(env, left)}
+	return object.Send(context, "[]", args...)
 }
 
 func evalArrayIndexExpression(arrayObject *object.Array, index object.RubyObject) object.RubyObject {
@@ -575,19 +1028,21 @@ func evalArrayIndexExpression(arrayObject *object.Array, index object.RubyObject
 	return arrayObject.Elements[idx]
 }
 
-func evalHashIndexExpression(hash *object.Hash, index object.RubyObject) object.RubyObject {
-	result, ok := hash.Get(index)
-	if !ok {
-		return object.NIL
+// evalSuper calls the implementation of the current method within the
+// superclass. Only super with explicit arguments is supported.
+func (e *evaluator) evalSuper(context *callContext, args ...object.RubyObject) (object.RubyObject, error) {
+	self, _ := context.Receiver().(*object.Self)
+	if self == nil || self.Method == nil {
+		return nil, errors.WithStack(object.NewRuntimeError("super called outside of method"))
 	}
-	return result
+	return object.SendSuper(context, self.Method, args...)
 }
 
-func evalBlockStatement(block *ast.BlockStatement, env object.Environment) (object.RubyObject, error) {
+func (e *evaluator) evalBlockStatement(block *ast.BlockStatement, env object.Environment) (object.RubyObject, error) {
 	var result object.RubyObject
 	var err error
 	for _, statement := range block.Statements {
-		result, err = Eval(statement, env)
+		result, err = e.eval(statement, env)
 		if err != nil {
 			return nil, err
 		}
@@ -605,7 +1060,7 @@ func evalBlockStatement(block *ast.BlockStatement, env object.Environment) (obje
 	return result, nil
 }
 
-func evalIdentifier(node *ast.Identifier, env object.Environment) (object.RubyObject, error) {
+func (e *evaluator) evalIdentifier(node *ast.Identifier, env object.Environment) (object.RubyObject, error) {
 	val, ok := env.Get(node.Value)
 	if ok {
 		return val, nil
@@ -619,8 +1074,11 @@ func evalIdentifier(node *ast.Identifier, env object.Environment) (object.RubyOb
 	}
 
 	self, _ := env.Get("self")
-	context := &callContext{object.NewCallContext(env, self)}
+	context := &callContext{e, object.NewCallContext(env, self)}
 	val, err := object.Send(context, node.Value)
+	if err != nil && respondsTo(self, node.Value) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, errors.Wrap(
 			object.NewUndefinedLocalVariableOrMethodNameError(self, node.Value),
@@ -630,6 +1088,15 @@ func evalIdentifier(node *ast.Identifier, env object.Environment) (object.RubyOb
 	return val, nil
 }
 
+func respondsTo(obj object.RubyObject, method string) bool {
+	for class := obj.Class(); class != nil; class = class.SuperClass() {
+		if _, ok := class.Methods().Get(method); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func unwrapReturnValue(obj object.RubyObject) object.RubyObject {
 	if returnValue, ok := obj.(*object.ReturnValue); ok {
 		return returnValue.Value
@@ -637,62 +1104,120 @@ func unwrapReturnValue(obj object.RubyObject) object.RubyObject {
 	return obj
 }
 
-func handleException(err error, rescues []*ast.RescueBlock, env object.Environment) (object.RubyObject, error) {
+// functionBody returns the body of the method defined by function. Method
+// level rescue, else and ensure clauses get wrapped around the body the same
+// way a begin block would.
+func functionBody(function *ast.FunctionLiteral) *ast.BlockStatement {
+	if len(function.Rescues) == 0 && function.Else == nil && function.Ensure == nil {
+		return function.Body
+	}
+	block := &ast.ExceptionHandlingBlock{
+		BeginToken: function.Token,
+		EndToken:   function.EndToken,
+		TryBody:    function.Body,
+		Rescues:    function.Rescues,
+		Else:       function.Else,
+		Ensure:     function.Ensure,
+	}
+	return &ast.BlockStatement{
+		Token:      function.Body.Token,
+		EndToken:   function.EndToken,
+		Statements: []ast.Statement{&ast.ExpressionStatement{Expression: block}},
+	}
+}
+
+func (e *evaluator) evalExceptionHandlingBlock(block *ast.ExceptionHandlingBlock, env object.Environment) (object.RubyObject, error) {
+	result, err := e.evalRescuedBody(block, env)
+	if block.Ensure == nil {
+		return result, err
+	}
+	ensureResult, ensureErr := e.eval(block.Ensure, env)
+	if ensureErr != nil {
+		return nil, errors.WithMessage(ensureErr, "eval ensure")
+	}
+	if _, ok := ensureResult.(*object.ReturnValue); ok {
+		return ensureResult, nil
+	}
+	return result, err
+}
+
+// evalRescuedBody evaluates the body of block, hands raised exceptions to the
+// rescue clauses and evaluates the else clause if nothing was raised. A retry
+// within a rescue clause evaluates the body again.
+func (e *evaluator) evalRescuedBody(block *ast.ExceptionHandlingBlock, env object.Environment) (object.RubyObject, error) {
+	for {
+		result, err := e.eval(block.TryBody, env)
+		if err == nil {
+			if _, ok := result.(*object.ReturnValue); ok || block.Else == nil {
+				return result, nil
+			}
+			return e.eval(block.Else, env)
+		}
+		if _, ok := errors.Cause(err).(*object.Jump); ok {
+			return nil, err
+		}
+		result, err = e.handleException(err, block.Rescues, env)
+		if jump, ok := errors.Cause(err).(*object.Jump); ok && jump.JumpType == object.RetryJump {
+			continue
+		}
+		return result, err
+	}
+}
+
+func (e *evaluator) handleException(err error, rescues []*ast.RescueBlock, env object.Environment) (object.RubyObject, error) {
 	if err != nil && len(rescues) == 0 {
 		return nil, err
 	}
-	errorObject := err.(object.RubyObject)
-	errClass := errorObject.Class().Name()
-	rescueEnv := object.WithScopedLocalVariables(env)
+	errorObject, ok := errors.Cause(err).(object.RubyObject)
+	if !ok {
+		return nil, err
+	}
+	object.AddBacktrace(err, e.rt.backtrace())
 
-	var catchAll *ast.RescueBlock
 	for _, r := range rescues {
-		if len(r.ExceptionClasses) == 0 {
-			catchAll = r
+		rescued, matchErr := e.rescueMatches(r, errorObject, env)
+		if matchErr != nil {
+			return nil, matchErr
+		}
+		if !rescued {
 			continue
 		}
 		if r.Exception != nil {
-			rescueEnv.Set(r.Exception.Value, errorObject)
-		}
-		for _, cl := range r.ExceptionClasses {
-			if cl.Value == errClass {
-				rescueRet, err := Eval(r.Body, rescueEnv)
-				return rescueRet, err
-			}
-		}
-	}
-
-	if catchAll != nil {
-		ancestors := getAncestors(errorObject)
-		sort.Strings(ancestors)
-		if sort.SearchStrings(ancestors, "StandardError") >= len(ancestors) {
-			return nil, err
+			env.Set(r.Exception.Value, errorObject)
 		}
-
-		if catchAll.Exception != nil {
-			rescueEnv.Set(catchAll.Exception.Value, errorObject)
+		previous, ok := env.Get("$!")
+		if !ok {
+			previous = object.NIL
 		}
-		rescueRet, err := Eval(catchAll.Body, rescueEnv)
-		return rescueRet, err
+		env.SetGlobal("$!", errorObject)
+		defer env.SetGlobal("$!", previous)
+		return e.eval(r.Body, env)
 	}
 
 	return nil, err
 }
 
-func getAncestors(obj object.RubyObject) []string {
-	class := obj.Class()
-	if c, ok := obj.(object.RubyClass); ok {
-		class = c
+// rescueMatches reports whether rescue handles errorObject, i.e. whether errorObject
+// is_a? one of the rescued exception classes. A rescue clause without classes
+// handles every StandardError.
+func (e *evaluator) rescueMatches(rescue *ast.RescueBlock, errorObject object.RubyObject, env object.Environment) (bool, error) {
+	if len(rescue.ExceptionClasses) == 0 {
+		return object.IsStandardError(errorObject), nil
 	}
-	var ancestors []string
-	ancestors = append(ancestors, class.Name())
-
-	superClass := class.SuperClass()
-	if superClass != nil {
-		superAncestors := getAncestors(superClass.(object.RubyClassObject))
-		ancestors = append(ancestors, superAncestors...)
+	for _, cl := range rescue.ExceptionClasses {
+		class, err := e.eval(cl, env)
+		if err != nil {
+			return false, errors.WithMessage(err, "eval rescue class")
+		}
+		matches, err := e.caseEqual(class, errorObject, env)
+		if err != nil {
+			return false, errors.WithMessage(err, "eval rescue class match")
+		}
+		if matches {
+			return true, nil
+		}
 	}
-	return ancestors
+	return false, nil
 }
 
 func isTruthy(obj object.RubyObject) bool {
@@ -1,9 +1,11 @@
 package evaluator
 
 import (
+	"fmt"
 	"go/token"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/goruby/goruby/object"
@@ -42,6 +44,11 @@ func TestEvalIntegerExpression(t *testing.T) {
 		{"3 * (3 * 3) + 10", 37},
 		{"(5 + 10 * 2 + 15 / 3) * 2 + -10", 50},
 		{"5 % 2", 1},
+		{"2 ** 10", 1024},
+		{"2 ** 3 ** 2", 512},
+		{"-2 ** 2", -4},
+		{"2 ** 64 / 2 ** 60", 16},
+		{"9223372036854775807 + 1 - 1", 9223372036854775807},
 	}
 
 	for _, tt := range tests {
@@ -49,6 +56,66 @@ func TestEvalIntegerExpression(t *testing.T) {
 		checkError(t, err)
 		testIntegerObject(t, evaluated, tt.expected)
 	}
+
+	t.Run("exceeding int64", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected string
+		}{
+			{"2 ** 64", "18446744073709551616"},
+			{"9223372036854775807 + 1", "9223372036854775808"},
+			{"-9223372036854775808 - 1", "-9223372036854775809"},
+			{"4294967296 * 4294967296", "18446744073709551616"},
+			{"-18446744073709551616", "-18446744073709551616"},
+			{"(2 ** 64).class", "Integer"},
+			{"2 ** 64 == 18446744073709551616", "true"},
+			{"2 ** 64 > 2 ** 63", "true"},
+			{"{2 ** 64 => 1}[18446744073709551616]", "1"},
+		}
+
+		for _, tt := range tests {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("Expected %q to eval to %s, got %s", tt.input, tt.expected, evaluated.Inspect())
+			}
+		}
+	})
+}
+
+func TestEvalFloatExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"12.34", "12.34"},
+		{"-1.5", "-1.5"},
+		{"1234e-2", "12.34"},
+		{"1 + 2.5", "3.5"},
+		{"2.5 * 2", "5.0"},
+		{"10 / 4.0", "2.5"},
+		{"1.0 / 0", "Infinity"},
+		{"-1.0 / 0", "-Infinity"},
+		{"0.0 / 0", "NaN"},
+		{"Float::INFINITY", "Infinity"},
+		{"1e20", "1.0e+20"},
+		{"0.00001", "1.0e-05"},
+		{"3.14159.round(2)", "3.14"},
+		{"-7.0 % 3", "2.0"},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		float, ok := evaluated.(*object.Float)
+		if !ok {
+			t.Errorf("object is not Float. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if float.Inspect() != tt.expected {
+			t.Errorf("object has wrong value. got=%s, want=%s", float.Inspect(), tt.expected)
+		}
+	}
 }
 
 func TestEvalBooleanExpression(t *testing.T) {
@@ -127,6 +194,11 @@ func TestConditionalExpression(t *testing.T) {
 		{"unless 1 > 2; 10; end", 10},
 		{"unless 1 > 2; 10; else\n 20; end", 10},
 		{"unless 1 < 2; 10; else\n 20; end", 20},
+		{"if 1 > 2; 10; elsif 2 > 1; 20; end", 20},
+		{"if 1 > 2; 10; elsif 2 > 3; 20; end", nil},
+		{"if 1 > 2; 10; elsif 2 > 3; 20; else 30; end", 30},
+		{"if 1 > 2 then 10 elsif 2 > 3 then 20 elsif 3 > 2 then 30 else 40 end", 30},
+		{"if 1 < 2\n 10\nelsif 2 > 1\n 20\nend", 10},
 	}
 
 	for _, tt := range tests {
@@ -141,6 +213,144 @@ func TestConditionalExpression(t *testing.T) {
 	}
 }
 
+func TestLoopExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"x = 0; while x < 3; x += 1; end; x", 3},
+		{"x = 0; while x < 3 do x += 1 end", nil},
+		{"x = 0; until x == 3; x += 1; end; x", 3},
+		{"x = 0; x += 1 while x < 5; x", 5},
+		{"x = 0; x += 1 until x > 5; x", 6},
+		{"x = 5; x += 1 while x < 5; x", 5},
+		{"x = 5; begin\n x += 1\n end while x < 5; x", 6},
+		{"x = 0; while true; x += 1; break if x == 4; end; x", 4},
+		{"x = 0; while true; x += 1; break x * 2 if x == 4; end", 8},
+		{"x = 0; y = 0; while x < 5; x += 1; next if x == 2; y += x; end; y", 13},
+		{"x = 0; y = 0; while x < 2; y += 1; redo if y == 1; x += 1; end; y", 3},
+		{"def foo; while true; return 7; end; 3; end; foo", 7},
+		{"def foo; yield 1; yield 2; 3; end; foo { |x| break x * 10 if x == 2 }", 20},
+		{"def foo; yield 1; yield 2; 3; end; foo { |x| break x * 10 if x == 5 }", 3},
+		{"def foo; a = yield 1; b = yield 2; a + b; end; foo { |x| next x * 10; 1 }", 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			if tt.expected == nil {
+				testNilObject(t, evaluated)
+				return
+			}
+			testObject(t, evaluated, tt.expected)
+		})
+	}
+
+	t.Run("break outside of a loop", func(t *testing.T) {
+		_, err := testEval("break")
+
+		if err == nil {
+			t.Fatalf("Expected error, got nil")
+		}
+		_, ok := errors.Cause(err).(*object.LocalJumpError)
+		if !ok {
+			t.Logf("Expected LocalJumpError, got %T\n", errors.Cause(err))
+			t.Fail()
+		}
+	})
+}
+
+func TestCaseExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"case 2\nwhen 1 then :one\nwhen 2 then :two\nend", ":two"},
+		{"case 3\nwhen 1, 3 then :odd\nelse :even\nend", ":odd"},
+		{"case 4\nwhen 1, 3 then :odd\nelse :even\nend", ":even"},
+		{"case 4\nwhen 1, 3 then :odd\nend", nil},
+		{"case 'foo'\nwhen Integer then 1\nwhen String then 2\nend", 2},
+		{"case 'foo'\nwhen 'bar'; 1\nwhen 'foo'; 2\nend", 2},
+		{"case :foo\nwhen :foo\n  x = 3\n  x * 2\nend", 6},
+		{"x = 5; case\nwhen x < 3 then :small\nwhen x < 10 then :medium\nend", ":medium"},
+		{"class Foo; end; class Bar < Foo; end; case Bar.new\nwhen Foo then 1\nelse 2\nend", 1},
+		{"case 1 when Integer then 7 end", 7},
+		{"Integer === 5", true},
+		{"String === 5", false},
+		{"5 === 5", true},
+		{"'a' === 'b'", false},
+		{"case 7\nwhen 1..5 then :low\nwhen 6.. then :high\nend", ":high"},
+		{"case 'c'\nwhen 'a'..'z' then :letter\nend", ":letter"},
+		{"(1...5) === 5", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			if tt.expected == nil {
+				testNilObject(t, evaluated)
+				return
+			}
+			testObject(t, evaluated, tt.expected)
+		})
+	}
+}
+
+func TestCaseMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"case 5\nin Integer then :int\nend", ":int"},
+		{"case 5\nin String | Integer => x then x\nend", 5},
+		{"case 5\nin x if x > 10 then :big\nin x unless x > 10 then :small\nend", ":small"},
+		{"y = 3; case 3\nin ^y then :pinned\nend", ":pinned"},
+		{"y = 2; case 4\nin ^(y * 2) then :pinned\nend", ":pinned"},
+		{"case [1, 2]\nin [] then 0\nin [a] then a\nin [a, b] then a + b\nend", 3},
+		{"case [1, 2, 3, 4]\nin [1, *rest, 4] then rest\nend", []string{"2", "3"}},
+		{"case [1, 2, 3]\nin Integer, *tail then tail\nend", []string{"2", "3"}},
+		{"case [1, [2, 3]]\nin [_, [x, *]] then x\nend", 2},
+		{"case [1, 42, 'x', :y]\nin [*, String => s, *post] then post\nend", []string{":y"}},
+		{"case [1, 2]\nin Array[a, b] then b\nend", 2},
+		{"case {:name => 'Bob', :age => 20}\nin {name: String => name, age:} if age > 17 then name\nend", "Bob"},
+		{"case {:name => 'Bob'}\nin {age:} then age\nin {name:} then name\nend", "Bob"},
+		{"case {:a => 1, :b => 2}\nin a: 1, **rest then rest\nend", map[string]string{":b": "2"}},
+		{"case {:a => 1, :b => 2}\nin {a: 1, **nil} then 1\nelse 2\nend", 2},
+		{"case {:a => 1}\nin {} then 1\nelse 2\nend", 2},
+		{"case {:a => 1}\nin Hash(a: x) then x\nend", 1},
+		{"case 5\nin String then 1\nelse 2\nend", 2},
+		{"case 3\nin ..2 then :small\nin 3..4 then :medium\nend", ":medium"},
+		{"case 9\nin 1..2 | 8... then :outer\nend", ":outer"},
+		{"case [1, 7]\nin [Integer, 5..10 => x] then x\nend", 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			testObject(t, evaluated, tt.expected)
+		})
+	}
+
+	t.Run("no matching pattern", func(t *testing.T) {
+		_, err := testEval("case 5\nin String then 1\nend", object.NewMainEnvironment())
+
+		if err == nil {
+			t.Fatalf("Expected error, got nil")
+		}
+		noMatch, ok := errors.Cause(err).(*object.NoMatchingPatternError)
+		if !ok {
+			t.Fatalf("Expected NoMatchingPatternError, got %T\n", errors.Cause(err))
+		}
+		if noMatch.Error() != "5" {
+			t.Logf("Expected error message to equal %q, got %q\n", "5", noMatch.Error())
+			t.Fail()
+		}
+	})
+}
+
 func TestReturnStatements(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -196,7 +406,7 @@ func TestErrorHandling(t *testing.T) {
 		},
 		{
 			`"Hello" - "World"`,
-			"NoMethodError: undefined method `-' for Hello:String",
+			"NoMethodError: undefined method `-' for \"Hello\":String",
 		},
 		{
 			"if (10 > 1); true + false; end",
@@ -259,6 +469,11 @@ end
 	}
 }
 
+func raised(err error, backtrace ...string) error {
+	object.AddBacktrace(err, backtrace)
+	return err
+}
+
 func mustGet(obj object.RubyObject, ok bool) object.RubyObject {
 	if !ok {
 		panic("object not found")
@@ -292,7 +507,7 @@ end`,
 begin
 	raise Exception
 end`,
-			object.NewException("Exception"),
+			raised(object.NewException("Exception"), ":3:in `<main>'"),
 			nil,
 		},
 		{
@@ -342,7 +557,7 @@ begin
 rescue
 	3
 end`,
-			object.NewException("qux"),
+			raised(object.NewException("qux"), ":3:in `<main>'"),
 			nil,
 		},
 		{
@@ -355,6 +570,124 @@ end`,
 			nil,
 			&object.Integer{Value: 3},
 		},
+		{
+			`
+begin
+	1 / 0
+rescue ArgumentError
+	1
+rescue StandardError
+	2
+end`,
+			nil,
+			&object.Integer{Value: 2},
+		},
+		{
+			`
+begin
+	raise ArgumentError.new "x"
+rescue TypeError, ArgumentError => e
+	e.to_s
+end`,
+			nil,
+			&object.String{Value: "x"},
+		},
+		{
+			`
+begin
+	2
+rescue
+	3
+else
+	4
+end`,
+			nil,
+			&object.Integer{Value: 4},
+		},
+		{
+			`
+x = 0
+begin
+	x = 1
+ensure
+	x = x + 5
+end
+x`,
+			nil,
+			&object.Integer{Value: 6},
+		},
+		{
+			`
+x = 0
+begin
+	1 / 0
+rescue
+	x = x + 1
+ensure
+	x = x + 5
+end
+x`,
+			nil,
+			&object.Integer{Value: 6},
+		},
+		{
+			`
+x = 0
+begin
+	x = x + 1
+	raise "again" if x < 3
+	x
+rescue
+	retry
+end`,
+			nil,
+			&object.Integer{Value: 3},
+		},
+		{
+			`
+def foo(x)
+	10 / x
+rescue ZeroDivisionError
+	-1
+else
+	99
+end
+[foo(0), foo(2)]`,
+			nil,
+			&object.Array{Elements: []object.RubyObject{
+				&object.Integer{Value: -1}, &object.Integer{Value: 99},
+			}},
+		},
+		{
+			`
+def foo
+	return 3
+ensure
+	$x = 7
+end
+a = foo
+b = $x
+[a, b]`,
+			nil,
+			&object.Array{Elements: []object.RubyObject{
+				&object.Integer{Value: 3}, &object.Integer{Value: 7},
+			}},
+		},
+		{
+			`x = (raise "bad") rescue 42`,
+			nil,
+			&object.Integer{Value: 42},
+		},
+		{
+			`
+begin
+	raise Exception.new "qux"
+rescue StandardError
+	3
+end`,
+			raised(object.NewException("qux"), ":3:in `<main>'"),
+			nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -387,6 +720,220 @@ end`,
 	}
 }
 
+func TestExceptionBacktrace(t *testing.T) {
+	input := `
+def inner
+	raise "boom"
+end
+def outer
+	yielder { inner }
+end
+def yielder
+	yield
+end
+begin
+	outer
+rescue => e
+	e.backtrace
+end`
+
+	evaluated, err := testEval(input, object.NewMainEnvironment())
+	checkError(t, err)
+
+	testArrayObject(t, evaluated, []string{
+		"\":3:in `inner'\"",
+		"\":6:in `block in outer'\"",
+		"\":9:in `yielder'\"",
+		"\":6:in `outer'\"",
+		"\":12:in `<main>'\"",
+	})
+}
+
+func TestExceptionBacktraceConcurrentEvaluations(t *testing.T) {
+	input := `
+class Recursion
+	def deep(n)
+		if n == 0
+			raise "boom"
+		end
+		deep(n - 1)
+	end
+end
+begin
+	Recursion.new.deep(%d)
+rescue => e
+	e.backtrace.size
+end`
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(depth int) {
+			defer wg.Done()
+			evaluated, err := testEval(fmt.Sprintf(input, depth), object.NewMainEnvironment())
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+				return
+			}
+			expected := fmt.Sprintf("%d", depth+2)
+			if evaluated.Inspect() != expected {
+				t.Errorf("Expected backtrace of depth %d to have %s frames, got %s", depth, expected, evaluated.Inspect())
+			}
+		}(i * 5)
+	}
+	wg.Wait()
+}
+
+func TestUserDefinedException(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			`
+class MyError < StandardError
+end
+begin
+	raise MyError, "msg"
+rescue StandardError => e
+	name = e.class.to_s
+	message = e.message
+	name + ": " + message
+end`,
+			"MyError: msg",
+		},
+		{
+			`
+class MyError < StandardError
+end
+class OtherError < MyError
+end
+begin
+	raise OtherError.new("other")
+rescue ArgumentError
+	1
+rescue MyError => e
+	e.message
+end`,
+			"other",
+		},
+		{
+			`
+class MyError < StandardError
+	def message
+		"custom"
+	end
+end
+begin
+	raise MyError
+rescue => e
+	e.message
+end`,
+			"custom",
+		},
+		{
+			`
+class MyError < StandardError
+end
+begin
+	begin
+		raise "inner"
+	rescue
+		raise MyError, "outer"
+	end
+rescue => e
+	message = e.message
+	cause = e.cause.message
+	message + " caused by " + cause
+end`,
+			"outer caused by inner",
+		},
+		{
+			`
+class MyError < StandardError
+end
+begin
+	begin
+		raise MyError, "again"
+	rescue
+		raise
+	end
+rescue MyError => e
+	e.message
+end`,
+			"again",
+		},
+		{
+			`
+class MyError < StandardError
+end
+begin
+	raise MyError, "full"
+rescue => e
+	message = e.full_message
+	"message: " + message
+end`,
+			"message: :5:in `<main>': full (MyError)",
+		},
+		{
+			`
+class MyError < StandardError
+	def initialize(x)
+		@x = x
+		super("v")
+	end
+	def x
+		@x
+	end
+end
+e = MyError.new(3)
+message = e.message
+message + e.x.to_s`,
+			"v3",
+		},
+		{
+			`
+class MyError < StandardError
+	def initialize(m = "default")
+		super(m)
+	end
+end
+begin
+	raise MyError
+rescue MyError => e
+	first = e.message
+end
+begin
+	raise MyError, "given"
+rescue MyError => e
+	first + " " + e.message
+end`,
+			"default given",
+		},
+		{
+			`
+class MyError < StandardError
+	def initialize
+		raise ArgumentError, "in initialize"
+	end
+end
+begin
+	raise MyError
+rescue ArgumentError => e
+	e.message
+end`,
+			"in initialize",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
 func TestScopedIdentifierExpression(t *testing.T) {
 	objectClassObject, _ := object.NewMainEnvironment().Get("Object")
 	objectClass := objectClassObject.(object.RubyClassObject)
@@ -522,6 +1069,28 @@ end`,
 			t.Fail()
 		}
 	}
+
+	t.Run("set per object", func(t *testing.T) {
+		input := `
+class X
+	def initialize(x)
+		@x = x
+	end
+	def x
+		@x
+	end
+end
+a = X.new(1)
+b = X.new(2)
+[a.x, b.x, @x]`
+
+		evaluated, err := testEval(input, object.NewMainEnvironment())
+		checkError(t, err)
+
+		if evaluated.Inspect() != "[1, 2, nil]" {
+			t.Errorf("Expected [1, 2, nil], got %s", evaluated.Inspect())
+		}
+	})
 }
 
 func TestAssignment(t *testing.T) {
@@ -650,6 +1219,23 @@ func TestAssignment(t *testing.T) {
 				4,
 				[]object.RubyObject{&object.Integer{Value: 3}, object.NIL, object.NIL, &object.Integer{Value: 5}},
 			},
+			{
+				`x = [3, 4]; x[-1] = 5; x`,
+				2,
+				[]object.RubyObject{&object.Integer{Value: 3}, &object.Integer{Value: 5}},
+			},
+			{
+				`x = [1, 2, 3]; x[0, 2] = [7, 8, 9]; x`,
+				4,
+				[]object.RubyObject{
+					&object.Integer{Value: 7}, &object.Integer{Value: 8}, &object.Integer{Value: 9}, &object.Integer{Value: 3},
+				},
+			},
+			{
+				`x = [1, 2, 3]; x[1..] = 5; x`,
+				2,
+				[]object.RubyObject{&object.Integer{Value: 1}, &object.Integer{Value: 5}},
+			},
 		}
 
 		for _, tt := range tests {
@@ -673,6 +1259,13 @@ func TestAssignment(t *testing.T) {
 			}
 		}
 	})
+	t.Run("assign to array index out of range", func(t *testing.T) {
+		_, err := testEval(`x = [3]; x[-3] = 5`, object.NewMainEnvironment())
+
+		if _, ok := errors.Cause(err).(*object.IndexError); !ok {
+			t.Errorf("Expected IndexError, got %T:%v", errors.Cause(err), err)
+		}
+	})
 	t.Run("assign operator on local variable", func(t *testing.T) {
 		tests := []struct {
 			input    string
@@ -695,6 +1288,27 @@ func TestAssignment(t *testing.T) {
 			testIntegerObject(t, evaluated, tt.expected)
 		}
 	})
+	t.Run("assign to attribute", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected string
+		}{
+			{`h = {}; h.default = 7; h[:foo]`, `7`},
+			{`h = {}; h.default=(7); h.default`, `7`},
+			{`h = {}; h.default = 7`, `7`},
+			{`h = {}; h.default = 1; h.default += 2; h.default`, `3`},
+			{`h = {}; a, h.default = 1, 2; [a, h.default]`, `[1, 2]`},
+		}
+
+		for _, tt := range tests {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("Expected %s for %q, got %s", tt.expected, tt.input, evaluated.Inspect())
+			}
+		}
+	})
 }
 
 func TestMultiAssignment(t *testing.T) {
@@ -747,6 +1361,35 @@ func TestMultiAssignment(t *testing.T) {
 				&object.Integer{Value: 2},
 			}},
 		},
+		{
+			name:  "array value spread over lhs",
+			input: "x, y = [1, 2]; [x, y]",
+			output: &object.Array{Elements: []object.RubyObject{
+				&object.Integer{Value: 1},
+				&object.Integer{Value: 2},
+			}},
+		},
+		{
+			name:  "lhs with splat",
+			input: "x, *y, z = 1, 2, 3, 4; [x, y, z]",
+			output: &object.Array{Elements: []object.RubyObject{
+				&object.Integer{Value: 1},
+				&object.Array{Elements: []object.RubyObject{
+					&object.Integer{Value: 2},
+					&object.Integer{Value: 3},
+				}},
+				&object.Integer{Value: 4},
+			}},
+		},
+		{
+			name:  "nested lhs",
+			input: "(x, y), z = [1, 2], 3; [x, y, z]",
+			output: &object.Array{Elements: []object.RubyObject{
+				&object.Integer{Value: 1},
+				&object.Integer{Value: 2},
+				&object.Integer{Value: 3},
+			}},
+		},
 	}
 
 	for _, tt := range tests {
@@ -762,6 +1405,55 @@ func TestMultiAssignment(t *testing.T) {
 	}
 }
 
+func TestSplat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"def foo(a, b, c); [a, b, c]; end; foo(*[1, 2], 3)", "[1, 2, 3]"},
+		{"def foo(a, b, c); [a, b, c]; end; foo(1, *[2, 3])", "[1, 2, 3]"},
+		{"def foo(*a); a; end; foo(*nil)", "[]"},
+		{"def foo(*a); a; end; foo(*1..3)", "[1, 2, 3]"},
+		{"def foo(a, b:, c: 3); [a, b, c]; end; h = {b: 2}; foo(1, **h)", "[1, 2, 3]"},
+		{"def foo(**o); o; end; h = {b: 2}; foo(a: 1, **h, c: 3)", "{:a => 1, :b => 2, :c => 3}"},
+		{"def foo(*a, **o); [a, o]; end; foo(**{})", "[[], {}]"},
+		{"[0, *[1, 2], *nil, 3]", "[0, 1, 2, 3]"},
+		{"x = *1..3; x", "[1, 2, 3]"},
+		{"x = 1, *[2, 3]; x", "[1, 2, 3]"},
+		{"*x, y = 1, 2, 3; [x, y]", "[[1, 2], 3]"},
+		{"x, * = 1, 2; x", "1"},
+		{"*x = nil; x", "[nil]"},
+		{"x, (y, *z), w = 1, [2, 3, 4], 5; [x, y, z, w]", "[1, 2, [3, 4], 5]"},
+		{"[[[1, 2], 3]].map { |(a, b), c| [a, b, c] }", "[[1, 2, 3]]"},
+		{"[[1, [2, 3]]].map { |a, (b, c)| [a, b, c] }", "[[1, 2, 3]]"},
+		{"[[1, 2]].map { |(a, *b)| [a, b] }", "[[1, [2]]]"},
+		{"x = []; {:a => 1}.each { |(k, v)| x << k << v }; x", "[:a, 1]"},
+		{"def foo(*a); a; end; x = [1, 2]; foo *x", "[1, 2]"},
+		{`"2".to_i * 2`, "4"},
+		{"[1, 2].first * 3", "3"},
+		{"x = [1, 2]; x.size ** 3", "8"},
+		{"h = Hash.new { |h, k| h[k] = k.to_s * 2 }; h[:a]", `"aa"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, evaluated.Inspect())
+			}
+		})
+	}
+
+	t.Run("double splat of non hash", func(t *testing.T) {
+		_, err := testEval("def foo(**o); end; foo(**1)", object.NewMainEnvironment())
+
+		if _, ok := errors.Cause(err).(*object.TypeError); !ok {
+			t.Errorf("Expected TypeError, got %T:%v", errors.Cause(err), err)
+		}
+	})
+}
+
 func TestGlobalAssignmentExpression(t *testing.T) {
 	t.Run("assignments", func(t *testing.T) {
 		tests := []struct {
@@ -1055,7 +1747,7 @@ func TestClassObject(t *testing.T) {
 func TestFunctionObject(t *testing.T) {
 	type funcParam struct {
 		name         string
-		defaultValue object.RubyObject
+		defaultValue string
 	}
 	t.Run("methods without receiver", func(t *testing.T) {
 		tests := []struct {
@@ -1082,7 +1774,7 @@ func TestFunctionObject(t *testing.T) {
 			},
 			{
 				"def foo x = 4; 2; end",
-				[]funcParam{{name: "x", defaultValue: &object.Integer{Value: 4}}},
+				[]funcParam{{name: "x", defaultValue: "4"}},
 				"2",
 			},
 		}
@@ -1123,8 +1815,12 @@ func TestFunctionObject(t *testing.T) {
 					t.Logf("Expected parameter %d to have name %q, got %q\n", i+1, testParam.name, param.Name)
 					t.Fail()
 				}
-				if !reflect.DeepEqual(testParam.defaultValue, param.Default) {
-					t.Logf("Expected parameter %d to have default %v, got %v\n", i+1, testParam.defaultValue, param.Default)
+				var defaultValue string
+				if param.Default != nil {
+					defaultValue = param.Default.String()
+				}
+				if testParam.defaultValue != defaultValue {
+					t.Logf("Expected parameter %d to have default %q, got %q\n", i+1, testParam.defaultValue, defaultValue)
 					t.Fail()
 				}
 			}
@@ -1275,6 +1971,81 @@ func TestFunctionApplication(t *testing.T) {
 	}
 }
 
+func TestFunctionParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"def foo(a, b = 2, c); [a, b, c]; end; foo(1, 3)", "[1, 2, 3]"},
+		{"def foo(a, b = 2, c); [a, b, c]; end; foo(1, 4, 3)", "[1, 4, 3]"},
+		{"def foo(a = []); a << 1; end; foo; foo", "[1]"},
+		{`def foo(a: "s"); a << "x"; end; foo; foo`, `"sx"`},
+		{"def foo(a, b = a * 2); [a, b]; end; foo(3)", "[3, 6]"},
+		{"def foo(a, b: a + 1); [a, b]; end; foo(3)", "[3, 4]"},
+		{"class Foo; def bar(a = baz); a; end; def baz; 42; end; end; Foo.new.bar", "42"},
+		{"def foo(a, *b); [a, b]; end; foo(1, 2, 3)", "[1, [2, 3]]"},
+		{"def foo(*a, b); [a, b]; end; foo(1, 2, 3)", "[[1, 2], 3]"},
+		{"def foo(a, *); a; end; foo(1, 2, 3)", "1"},
+		{"def foo(a, b: 2, c:); [a, b, c]; end; foo(1, c: 3)", "[1, 2, 3]"},
+		{"def foo(a, b: 2, c:); [a, b, c]; end; foo(1, c: 3, b: 4)", "[1, 4, 3]"},
+		{"def foo(a:, **opts); [a, opts]; end; foo(a: 1, b: 2)", "[1, {:b => 2}]"},
+		{"def foo(**opts); opts; end; foo", "{}"},
+		{"def foo(*a, **k); [a, k]; end; foo(1, 2 => 3, a: 4)", "[[1], {2 => 3, :a => 4}]"},
+		{"def foo(*a, b: 1); [a, b]; end; foo(1, 2 => 3)", "[[1, {2 => 3}], 1]"},
+		{"def foo(a, &blk); blk.call(a); end; foo(2) { |x| x * 3 }", "6"},
+		{"def foo(&blk); blk; end; foo", "nil"},
+		{"def foo(a, b = 2, *c, d, e:, f: 6, **g, &h); end; method(:foo).parameters",
+			"[[:req, :a], [:opt, :b], [:rest, :c], [:req, :d], [:keyreq, :e], [:key, :f], [:keyrest, :g], [:block, :h]]"},
+		{"def foo(a, *); end; method(:foo).parameters", "[[:req, :a], [:rest]]"},
+		{"def foo(a, b); end; method(:foo).arity", "2"},
+		{"def foo(a, b = 1); end; method(:foo).arity", "-2"},
+		{"def foo(*a); end; method(:foo).arity", "-1"},
+		{"def foo(a, b:); end; method(:foo).arity", "2"},
+		{"def foo(a, b: 1); end; method(:foo).arity", "-2"},
+		{"def foo(a, b); a - b; end; method(:foo).call(5, 3)", "2"},
+		{"[[1, 2, 3]].map { |a, *b| [a, b] }", "[[1, [2, 3]]]"},
+		{"[[1, 2, 3]].map { |a, *b, c| [a, b, c] }", "[[1, [2], 3]]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, evaluated.Inspect())
+			}
+		})
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"def foo(a, b = 1); end; foo", "wrong number of arguments (given 0, expected 1..2)"},
+		{"def foo(a, b = 1); end; foo(1, 2, 3)", "wrong number of arguments (given 3, expected 1..2)"},
+		{"def foo(a, *b); end; foo", "wrong number of arguments (given 0, expected 1+)"},
+		{"def foo(a, b:); end; foo(b: 1)", "wrong number of arguments (given 0, expected 1; required keyword: b)"},
+		{"def foo(a:); end; foo", "missing keyword: :a"},
+		{"def foo(a:, b:); end; foo", "missing keywords: :a, :b"},
+		{"def foo(a: 1); end; foo(b: 2)", "unknown keyword: :b"},
+		{"def foo(a: 1); end; foo(b: 2, c: 3)", "unknown keywords: :b, :c"},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := testEval(tt.input, object.NewMainEnvironment())
+
+			argumentError, ok := errors.Cause(err).(*object.ArgumentError)
+			if !ok {
+				t.Fatalf("Expected ArgumentError, got %T:%v", errors.Cause(err), err)
+			}
+			if argumentError.Error() != tt.expected {
+				t.Errorf("Expected message %q, got %q", tt.expected, argumentError.Error())
+			}
+		})
+	}
+}
+
 func TestGlobalLiteral(t *testing.T) {
 	input := `$foo = 'bar'; $foo`
 
@@ -1305,6 +2076,131 @@ func TestStringLiteral(t *testing.T) {
 	}
 }
 
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a\"b"`, `a"b`},
+		{`"a\tb\n"`, "a\tb\n"},
+		{`"\e[0m\s"`, "\x1b[0m "},
+		{`"\101\x41\u0041\u{41 42}"`, "AAAAB"},
+		{`"\C-a\ca\M-a\M-\C-a\c?"`, "\x01\x01\xe1\x81\x7f"},
+		{`"\#{x}"`, "#{x}"},
+		{`'a\'b\\c\n'`, `a'b\c\n`},
+		{`?\s`, " "},
+		{`?\n`, "\n"},
+		{`?\C-a`, "\x01"},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input)
+		checkError(t, err)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Fatalf("object is not String. got=%T (%+v)", evaluated, evaluated)
+		}
+
+		if str.Value != tt.expected {
+			t.Errorf("String has wrong value. got=%q, want=%q", str.Value, tt.expected)
+		}
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`x = 42; "x is #{x}!"`, `"x is 42!"`},
+		{`x = 2; "#{x * 2}#{x + 1}"`, `"43"`},
+		{`"#{"nested #{1 + 1}"}"`, `"nested 2"`},
+		{`"#{}|#{nil}|#{true}|#{1.5}|#{:sym}"`, `"||true|1.5|sym"`},
+		{`"#{a = 1; a + 1}"`, `"2"`},
+		{`"#{ [1, 2][1] }"`, `"2"`},
+		{`class Foo; def to_s; "foo"; end; end; "#{Foo.new}"`, `"foo"`},
+		{`x = 3; :"sym#{x}"`, ":sym3"},
+		{`x = 3; "a#{x}" 'b' "#{x}c"`, `"a3b3c"`},
+		{"x = 3; \"a\" \\\n  \"#{x}\"", `"a3"`},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("Expected %s, got %s", tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestHeredocs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x = 2\n<<~EOS\n  x is #{x}\nEOS\n", "x is 2\n"},
+		{"<<A + <<B\nfirst\nA\nsecond\nB\n", "first\nsecond\n"},
+		{"<<-'EOS'\n  #{x}\n  EOS\n", "  #{x}\n"},
+		{"x = <<EOS\nfoo\nEOS\nx + __FILE__", "foo\n"},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Fatalf("object is not String. got=%T (%+v)", evaluated, evaluated)
+		}
+
+		if str.Value != tt.expected {
+			t.Errorf("String has wrong value. got=%q, want=%q", str.Value, tt.expected)
+		}
+	}
+}
+
+func TestPercentLiterals(t *testing.T) {
+	t.Run("strings", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected string
+		}{
+			{`%q(a (b) \) #{x})`, "a (b) ) #{x}"},
+			{`x = 2; %Q{x is #{x}\n}`, "x is 2\n"},
+			{`%<a <b>>`, "a <b>"},
+		}
+
+		for _, tt := range tests {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			testStringObject(t, evaluated, tt.expected)
+		}
+	})
+	t.Run("symbol", func(t *testing.T) {
+		evaluated, err := testEval(`%s(foo bar)`, object.NewMainEnvironment())
+		checkError(t, err)
+		testSymbolObject(t, evaluated, "foo bar")
+	})
+	t.Run("arrays", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected []string
+		}{
+			{`%w(a b\ c #{x})`, []string{`"a"`, `"b c"`, `"\#{x}"`}},
+			{`x = 2; %W(a#{x} b\tc)`, []string{`"a2"`, `"b\tc"`}},
+			{`%i(a b)`, []string{":a", ":b"}},
+			{`x = 2; %I(a#{x})`, []string{":a2"}},
+			{`%w()`, []string{}},
+		}
+
+		for _, tt := range tests {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			testArrayObject(t, evaluated, tt.expected)
+		}
+	})
+}
+
 func TestStringConcatenation(t *testing.T) {
 	input := `"Hello" + " " + "World!"`
 
@@ -1320,6 +2216,39 @@ func TestStringConcatenation(t *testing.T) {
 	}
 }
 
+func TestStringMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"hello"[1]`, "e"},
+		{`"hello"[1, 3]`, "ell"},
+		{`"hello"["ll"]`, "ll"},
+		{`x = "ab"; y = x; x << "c"; y`, "abc"},
+		{`x = "hello"; x.upcase!; x`, "HELLO"},
+		{`"hello".gsub("l") { |c| c.upcase }`, "heLLo"},
+		{`"%s is %d" % ["x", 3]`, "x is 3"},
+		{`x = ""; "abc".each_char { |c| x << c << "," }; x`, "a,b,c,"},
+		{`'a"b'.inspect`, `"a\"b"`},
+		{`:"a b".inspect`, `:"a b"`},
+		{`{"a" => [1, :b, nil]}.to_s`, `{"a" => [1, :b, nil]}`},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testStringObject(t, evaluated, tt.expected)
+	}
+
+	t.Run("frozen string", func(t *testing.T) {
+		_, err := testEval(`x = "ab".freeze; x << "c"`, object.NewMainEnvironment())
+
+		if _, ok := errors.Cause(err).(*object.FrozenError); !ok {
+			t.Errorf("Expected FrozenError, got %T:%v", errors.Cause(err), err)
+		}
+	})
+}
+
 func TestSymbolLiteral(t *testing.T) {
 	input := `:foobar;`
 
@@ -1335,6 +2264,169 @@ func TestSymbolLiteral(t *testing.T) {
 	}
 }
 
+func TestRangeLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1..5", "1..5"},
+		{"x = 2; x...x * 3", "2...6"},
+		{"(1..)", "1.."},
+		{"(..5)", "..5"},
+		{"'a'..'c'", `"a".."c"`},
+		{"(1..5).to_a", "[1, 2, 3, 4, 5]"},
+		{"('a'...'d').to_a", `["a", "b", "c"]`},
+		{"(1..).first(3)", "[1, 2, 3]"},
+		{"(1..10).step(3)", "[1, 4, 7, 10]"},
+		{"x = []; (1..3).each { |i| x.push(i * 2) }; x", "[2, 4, 6]"},
+		{"(1..).each { |i| break i if i * i > 20 }", "5"},
+		{"[1, 2, 3, 4][1..2]", "[2, 3]"},
+		{"[1, 2, 3, 4][-2..]", "[3, 4]"},
+		{`"hello"[1...-1]`, `"ell"`},
+		{"Range.new(1, 3, true)", "1...3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, evaluated.Inspect())
+			}
+		})
+	}
+
+	t.Run("bad value for range", func(t *testing.T) {
+		_, err := testEval("1..'a'", object.NewMainEnvironment())
+
+		if _, ok := errors.Cause(err).(*object.ArgumentError); !ok {
+			t.Errorf("Expected ArgumentError, got %T:%v", errors.Cause(err), err)
+		}
+	})
+}
+
+func TestRegexLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`/ab+c/i`, `/ab+c/i`},
+		{`x = "b"; /a#{x}c/`, `/abc/`},
+		{`%r{a/b}`, `/a\/b/`},
+		{`4 / 2`, `2`},
+		{`x = 4; x / 2`, `2`},
+		{`a = 6; a /2`, `3`},
+		{`"hello" =~ /ll/`, `2`},
+		{`"hello" =~ /x/`, `nil`},
+		{`"hello" !~ /x/`, `true`},
+		{`/(\d+)-(\d+)/ =~ "10-20"; [$~[0], $1, $2, $3]`, `["10-20", "10", "20", nil]`},
+		{`"foo bar".match(/(?<first>\w+)/)[:first]`, `"foo"`},
+		{`"a1b22".scan(/\d+/)`, `["1", "22"]`},
+		{`"a1b2".gsub(/\d/) { |d| "<#{d}>" }`, `"a<1>b<2>"`},
+		{`"a1b2".gsub(/[a-z](\d)/) { $1 }`, `"12"`},
+		{`"a, b,c".split(/,\s*/)`, `["a", "b", "c"]`},
+		{`case "v1.2" when /v(\d)/ then $1 else nil end`, `"1"`},
+		{`Regexp.new("a.c") =~ "xabc"`, `1`},
+		{`s = "ab12"[/\d+/]; [s, $~[0]]`, `["12", "12"]`},
+		{`"ab12"[/([a-z]+)(\d+)/, 1]`, `"ab"`},
+		{"\"xaby\" =~ /ab/; \"#{$`}-#{$'}\"", `"x-y"`},
+		{`def m; "zz" =~ /(z)/; end; "ab" =~ /(a)/; m; $1`, `"a"`},
+		{`"ab" =~ /(a)/; [1].each { "q" =~ /(q)/ }; $1`, `"q"`},
+		{`def m; $~; end; "ab" =~ /(a)/; m`, `nil`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, evaluated.Inspect())
+			}
+		})
+	}
+
+	t.Run("unsupported construct", func(t *testing.T) {
+		_, err := testEval(`/(a)\1/`, object.NewMainEnvironment())
+
+		if _, ok := errors.Cause(err).(*object.RegexpError); !ok {
+			t.Errorf("Expected RegexpError, got %T:%v", errors.Cause(err), err)
+		}
+	})
+}
+
+func TestSymbols(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected object.RubyObject
+	}{
+		{`:foo.equal?(:foo)`, object.TRUE},
+		{`:foo.object_id == :foo.object_id`, object.TRUE},
+		{`x = "b"; :"a#{x}".equal?(:ab)`, object.TRUE},
+		{`:"a#{1 + 1}"`, object.NewSymbol("a2")},
+		{`:az.succ`, object.NewSymbol("ba")},
+		{`:foo.upcase`, object.NewSymbol("FOO")},
+		{`:foo.length`, object.NewInteger(3)},
+		{`:a <=> :b`, object.NewInteger(-1)},
+		{`def foo; yield "ab"; end; foo(&:upcase)`, &object.String{Value: "AB"}},
+		{`def foo; yield "ab"; end; blk = :upcase.to_proc; foo(&blk)`, &object.String{Value: "AB"}},
+		{`def foo; block_given?; end; blk = nil; foo(&blk)`, object.FALSE},
+		{`Symbol.all_symbols.include?(:never_evaluated_before)`, object.TRUE},
+		{`Symbol.all_symbols.include?(:"quoted never evaluated before")`, object.TRUE},
+		{`found = Symbol.all_symbols.map { |sym| sym.to_s }.include?("label_never_evaluated"); if false; {label_never_evaluated: 1}; end; found`, object.TRUE},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		if !reflect.DeepEqual(evaluated, tt.expected) {
+			t.Errorf("Expected %s for %q, got %s", tt.expected.Inspect(), tt.input, evaluated.Inspect())
+		}
+	}
+
+	t.Run("block argument without to_proc", func(t *testing.T) {
+		_, err := testEval(`def foo; end; x = 1; foo(&x)`, object.NewMainEnvironment())
+
+		if _, ok := errors.Cause(err).(*object.TypeError); !ok {
+			t.Errorf("Expected TypeError, got %T:%v", errors.Cause(err), err)
+		}
+	})
+}
+
+func TestSuper(t *testing.T) {
+	input := `
+class Greeter
+	def greet(name)
+		"hi " + name
+	end
+end
+class Shouter < Greeter
+	def greet(name)
+		[name].map { |n| super(n + "!") }.first
+	end
+end
+Shouter.new.greet("bob")
+`
+	evaluated, err := testEval(input, object.NewMainEnvironment())
+	checkError(t, err)
+
+	testObject(t, evaluated, "hi bob!")
+
+	t.Run("without superclass method", func(t *testing.T) {
+		_, err := testEval(`class Foo; def bar; super(1); end; end; Foo.new.bar`, object.NewMainEnvironment())
+
+		if _, ok := errors.Cause(err).(*object.NoMethodError); !ok {
+			t.Errorf("Expected NoMethodError, got %T:%v", errors.Cause(err), err)
+		}
+	})
+	t.Run("outside of method", func(t *testing.T) {
+		_, err := testEval(`super(1)`, object.NewMainEnvironment())
+
+		if _, ok := errors.Cause(err).(*object.RuntimeError); !ok {
+			t.Errorf("Expected RuntimeError, got %T:%v", errors.Cause(err), err)
+		}
+	})
+}
+
 func TestMethodCalls(t *testing.T) {
 	input := "x = 2; x.foo :bar"
 
@@ -1437,6 +2529,48 @@ func TestArrayIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestArrayMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`[1, 2, 3].map { |x| x * 2 }`, "[2, 4, 6]"},
+		{`[1, 2, 3, 4].select { |x| x > 2 }`, "[3, 4]"},
+		{`[1, 2, 3].find { |x| x > 5 }`, "nil"},
+		{`x = []; [:a, :b].each_with_index { |e, i| x << [i, e] }; x`, "[[0, :a], [1, :b]]"},
+		{`[3, 1, 2].sort { |a, b| b <=> a }`, "[3, 2, 1]"},
+		{`["ccc", "a", "bb"].sort_by { |s| s.length }`, `["a", "bb", "ccc"]`},
+		{`[1, 2, 3].sum { |x| x * 10 }`, "60"},
+		{`[0.1, 0.2, 0.3].sum`, "0.6"},
+		{`[1, [2, [3]]].flatten.join(", ")`, `"1, 2, 3"`},
+		{`x = [1, 2]; x << 3; x.pop; x.unshift(0); x`, "[0, 1, 2]"},
+		{`[1, 2].zip([3, 4]).map { |a, b| a + b }`, "[4, 6]"},
+		{`[[1, [2]]].dig(0, 1, 0)`, "2"},
+		{`Array.new(3) { |i| i * i }`, "[0, 1, 4]"},
+		{`[1, 2, 3, 4].shuffle({:random => Random.new(3)}) == [1, 2, 3, 4].shuffle({:random => Random.new(3)})`, "true"},
+		{`[1, 2].fetch(5) { |i| i * 2 }`, "10"},
+		{`[65, 66].pack("C*")`, `"AB"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, evaluated.Inspect())
+			}
+		})
+	}
+
+	t.Run("fetch out of range", func(t *testing.T) {
+		_, err := testEval(`[1, 2].fetch(5)`, object.NewMainEnvironment())
+
+		if _, ok := errors.Cause(err).(*object.IndexError); !ok {
+			t.Errorf("Expected IndexError, got %T:%v", errors.Cause(err), err)
+		}
+	})
+}
+
 func TestNilExpression(t *testing.T) {
 	input := "nil"
 	evaluated, err := testEval(input)
@@ -1475,11 +2609,11 @@ func TestHashLiteral(t *testing.T) {
 	}
 
 	expected := map[string]object.RubyObject{
-		"foo":  &object.Integer{Value: 42},
-		":bar": &object.Integer{Value: 2},
-		"true": object.FALSE,
-		"nil":  object.TRUE,
-		"2":    &object.Integer{Value: 2},
+		`"foo"`: &object.Integer{Value: 42},
+		":bar":  &object.Integer{Value: 2},
+		"true":  object.FALSE,
+		"nil":   object.TRUE,
+		"2":     &object.Integer{Value: 2},
 	}
 
 	actual := make(map[string]object.RubyObject)
@@ -1493,6 +2627,30 @@ func TestHashLiteral(t *testing.T) {
 	}
 }
 
+func TestHashLiteralLabels(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{name: "x", "quoted": 1}`, `{:name => "x", :quoted => 1}`},
+		{`x = 1; {x:, y: 2}`, "{:x => 1, :y => 2}"},
+		{"{\n  a: 1,\n  b: 2,\n}", "{:a => 1, :b => 2}"},
+		{`def f(a, opts); [a, opts]; end; f 1, b: 2, "c" => 3`, `[1, {:b => 2, "c" => 3}]`},
+		{`def f(opts); opts; end; f(b: 2)`, "{:b => 2}"},
+		{`[1, a: 2]`, "[1, {:a => 2}]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, evaluated.Inspect())
+			}
+		})
+	}
+}
+
 func TestHashIndexExpressions(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1564,6 +2722,152 @@ func TestHashIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestHashMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{3 => :a, 1 => :b, 2 => :c}`, "{3 => :a, 1 => :b, 2 => :c}"},
+		{`x = {:b => 1, :a => 2}; x[:c] = 3; x.delete(:b); x[:b] = 4; x.keys`, "[:a, :c, :b]"},
+		{`x = []; {:a => 1, :b => 2}.each { |k, v| x << [v, k] }; x`, "[[1, :a], [2, :b]]"},
+		{`{:a => 1, :b => 2}.map { |k, v| v * 10 }`, "[10, 20]"},
+		{`{:a => 1, :b => 2, :c => 3}.select { |k, v| v > 1 }`, "{:b => 2, :c => 3}"},
+		{`{:a => 1}.fetch(:b, 5)`, "5"},
+		{`{:a => 1}.fetch(:b) { |k| k }`, ":b"},
+		{`{:a => {:b => [4, 5]}}.dig(:a, :b, 1)`, "5"},
+		{`{:a => 1, :b => 2}.merge({:b => 3}) { |k, old, new| old + new }`, "{:a => 1, :b => 5}"},
+		{`{:a => 1}.key?(:a)`, "true"},
+		{`{:a => 1, :b => 2}.to_a`, "[[:a, 1], [:b, 2]]"},
+		{`{:a => 1, :b => 2}.transform_values { |v| v * 2 }`, "{:a => 2, :b => 4}"},
+		{`{:a => 1, :b => 2, :c => 3}.group_by { |k, v| v > 1 }`, "{false => [[:a, 1]], true => [[:b, 2], [:c, 3]]}"},
+		{`{:a => 2, :b => 1}.sort_by { |k, v| v }`, "[[:b, 1], [:a, 2]]"},
+		{`x = Hash.new { |h, k| h[k] = k * 2 }; x[3]; x`, "{3 => 6}"},
+		{`Hash.new(0)[:missing]`, "0"},
+		{
+			`
+class Point
+	def initialize(x)
+		@x = x
+	end
+	def x
+		@x
+	end
+	def hash
+		@x.hash
+	end
+	def eql?(other)
+		@x == other.x
+	end
+end
+points = {Point.new(1) => :one}
+points[Point.new(1)] = :uno
+[points[Point.new(1)], points.size]`,
+			"[:uno, 1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, evaluated.Inspect())
+			}
+		})
+	}
+
+	t.Run("fetch missing key", func(t *testing.T) {
+		_, err := testEval(`{:a => 1}.fetch(:b)`, object.NewMainEnvironment())
+
+		if _, ok := errors.Cause(err).(*object.KeyError); !ok {
+			t.Errorf("Expected KeyError, got %T:%v", errors.Cause(err), err)
+		}
+	})
+}
+
+func TestEnumerable(t *testing.T) {
+	numbers := `
+class Numbers
+	include Enumerable
+	def initialize(*items)
+		@items = items
+	end
+	def each
+		@items.each { |i| yield i }
+		self
+	end
+end
+class Naturals
+	include Enumerable
+	def each
+		i = 0
+		while true
+			i += 1
+			yield i
+		end
+	end
+end
+n = Numbers.new(3, 1, 4, 1, 5)
+`
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`n.map { |x| x * 2 }`, "[6, 2, 8, 2, 10]"},
+		{`n.select { |x| x > 2 }`, "[3, 4, 5]"},
+		{`n.reject { |x| x > 2 }`, "[1, 1]"},
+		{`n.find { |x| x > 3 }`, "4"},
+		{`n.reduce("+")`, "14"},
+		{`n.inject(10) { |sum, x| sum + x }`, "24"},
+		{`[n.all? { |x| x > 0 }, n.any? { |x| x > 4 }, n.none?(Integer)]`, "[true, true, false]"},
+		{`[n.count, n.count(1), n.count { |x| x > 2 }]`, "[5, 2, 3]"},
+		{`n.sort`, "[1, 1, 3, 4, 5]"},
+		{`n.sort_by { |x| -x }`, "[5, 4, 3, 1, 1]"},
+		{`[n.min_by { |x| x * x - 6 * x }, n.max_by { |x| x % 4 }]`, "[3, 3]"},
+		{`x = []; n.each_with_index { |v, i| x << i * v }; x`, "[0, 1, 8, 3, 20]"},
+		{`n.each_with_object([]) { |v, memo| memo << v }`, "[3, 1, 4, 1, 5]"},
+		{`n.group_by { |x| x > 2 }`, "{true => [3, 4, 5], false => [1, 1]}"},
+		{`n.partition { |x| x > 2 }`, "[[3, 4, 5], [1, 1]]"},
+		{`x = []; n.each_slice(2) { |s| x << s }; x`, "[[3, 1], [4, 1], [5]]"},
+		{`x = []; n.each_cons(4) { |s| x << s }; x`, "[[3, 1, 4, 1], [1, 4, 1, 5]]"},
+		{`n.zip(n.map { |x| x * 10 })`, "[[3, 30], [1, 10], [4, 40], [1, 10], [5, 50]]"},
+		{`n.zip(n, Naturals.new)`, "[[3, 3, 1], [1, 1, 2], [4, 4, 3], [1, 1, 4], [5, 5, 5]]"},
+		{`[n.each_slice(2).map { |s| s.first }, n.each_with_index.to_a.last]`, "[[3, 4, 5], [5, 4]]"},
+		{`n.to_a`, "[3, 1, 4, 1, 5]"},
+		{`n.to_h { |x| [x, x * x] }`, "{3 => 9, 1 => 1, 4 => 16, 5 => 25}"},
+		{`n.tally`, "{3 => 1, 1 => 2, 4 => 1, 5 => 1}"},
+		{`[n.sum, n.sum(10)]`, "[14, 24]"},
+		{`n.first(2)`, "[3, 1]"},
+		{`n.map { |x| break x * 100 }`, "300"},
+		{`Naturals.new.first(3)`, "[1, 2, 3]"},
+		{`Naturals.new.find { |x| x * x > 50 }`, "8"},
+		{`Naturals.new.lazy.map { |x| x * 2 }.select { |x| x % 3 == 0 }.first(3)`, "[6, 12, 18]"},
+		{`Naturals.new.lazy.reject { |x| x % 2 == 0 }.take(3).to_a`, "[1, 3, 5]"},
+		{`Numbers.ancestors`, "[Numbers, Enumerable, Object, Kernel, BasicObject]"},
+		{`[Numbers.superclass, n.is_a?(Enumerable)]`, "[Object, true]"},
+		{`{:a => 1, :b => 2}.max_by { |k, v| v }`, "[:b, 2]"},
+		{`(1..).lazy.map { |x| x * 2 }.take(2)`, "#<Enumerator::Lazy: #<Enumerator::Lazy: #<Enumerator::Lazy: 1..>:map>:take(2)>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated, err := testEval(numbers+tt.input, object.NewMainEnvironment())
+			checkError(t, err)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, evaluated.Inspect())
+			}
+		})
+	}
+
+	t.Run("each_slice with invalid size", func(t *testing.T) {
+		_, err := testEval(numbers+`n.each_slice(0) { |s| s }`, object.NewMainEnvironment())
+
+		if _, ok := errors.Cause(err).(*object.ArgumentError); !ok {
+			t.Errorf("Expected ArgumentError, got %T:%v", errors.Cause(err), err)
+		}
+	})
+}
+
 func TestKeyword__File__(t *testing.T) {
 	input := "__FILE__"
 
@@ -0,0 +1,209 @@
+package evaluator
+
+import (
+	"github.com/goruby/goruby/ast"
+	"github.com/goruby/goruby/object"
+	"github.com/goruby/goruby/token"
+	"github.com/pkg/errors"
+)
+
+func (e *evaluator) evalCaseMatchExpression(ce *ast.CaseMatchExpression, env object.Environment) (object.RubyObject, error) {
+	subject, err := e.eval(ce.Subject, env)
+	if err != nil {
+		return nil, errors.WithMessage(err, "eval case subject")
+	}
+	for _, in := range ce.Ins {
+		matched, err := e.matchPattern(in.Pattern, subject, env)
+		if err != nil {
+			return nil, err
+		}
+		if matched && in.Guard != nil {
+			guard, err := e.eval(in.Guard, env)
+			if err != nil {
+				return nil, errors.WithMessage(err, "eval pattern guard")
+			}
+			matched = isTruthy(guard) != in.IsGuardNegated()
+		}
+		if matched {
+			return e.eval(in.Body, env)
+		}
+	}
+	if ce.Else != nil {
+		return e.eval(ce.Else, env)
+	}
+	return nil, errors.WithStack(object.NewNoMatchingPatternError(subject))
+}
+
+// matchPattern reports whether obj matches pattern. Variables within the
+// pattern are bound within env while matching.
+func (e *evaluator) matchPattern(pattern ast.Pattern, obj object.RubyObject, env object.Environment) (bool, error) {
+	switch pattern := pattern.(type) {
+	case *ast.ValuePattern:
+		return e.matchValue(pattern.Value, obj, env)
+	case *ast.PinPattern:
+		return e.matchValue(pattern.Value, obj, env)
+	case *ast.VariablePattern:
+		env.Set(pattern.Name.Value, obj)
+		return true, nil
+	case *ast.CapturePattern:
+		matched, err := e.matchPattern(pattern.Pattern, obj, env)
+		if err != nil || !matched {
+			return false, err
+		}
+		env.Set(pattern.Name.Value, obj)
+		return true, nil
+	case *ast.AlternativePattern:
+		for _, alternative := range pattern.Alternatives {
+			matched, err := e.matchPattern(alternative, obj, env)
+			if err != nil || matched {
+				return matched, err
+			}
+		}
+		return false, nil
+	case *ast.ArrayPattern:
+		return e.matchArrayPattern(pattern, obj, env)
+	case *ast.FindPattern:
+		return e.matchFindPattern(pattern, obj, env)
+	case *ast.HashPattern:
+		return e.matchHashPattern(pattern, obj, env)
+	default:
+		return false, errors.Errorf("Unknown pattern type %T", pattern)
+	}
+}
+
+func (e *evaluator) matchValue(value ast.Expression, obj object.RubyObject, env object.Environment) (bool, error) {
+	if value == nil {
+		return true, nil
+	}
+	pattern, err := e.eval(value, env)
+	if err != nil {
+		return false, errors.WithMessage(err, "eval pattern value")
+	}
+	return e.caseEqual(pattern, obj, env)
+}
+
+func (e *evaluator) matchPatterns(patterns []ast.Pattern, objs []object.RubyObject, env object.Environment) (bool, error) {
+	for i, pattern := range patterns {
+		matched, err := e.matchPattern(pattern, objs[i], env)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func bindSplat(splat *ast.SplatPattern, elements []object.RubyObject, env object.Environment) {
+	if splat.Name != nil {
+		env.Set(splat.Name.Value, object.NewArray(elements...))
+	}
+}
+
+func (e *evaluator) matchArrayPattern(pattern *ast.ArrayPattern, obj object.RubyObject, env object.Environment) (bool, error) {
+	matched, err := e.matchValue(pattern.Constant, obj, env)
+	if err != nil || !matched {
+		return false, err
+	}
+	array, ok := obj.(*object.Array)
+	if !ok {
+		return false, nil
+	}
+	splat := -1
+	for i, element := range pattern.Elements {
+		if _, ok := element.(*ast.SplatPattern); ok {
+			splat = i
+		}
+	}
+	elements := array.Elements
+	if splat < 0 {
+		if len(elements) != len(pattern.Elements) {
+			return false, nil
+		}
+		return e.matchPatterns(pattern.Elements, elements, env)
+	}
+	if len(elements) < len(pattern.Elements)-1 {
+		return false, nil
+	}
+	restEnd := len(elements) - (len(pattern.Elements) - splat - 1)
+	matched, err = e.matchPatterns(pattern.Elements[:splat], elements[:splat], env)
+	if err != nil || !matched {
+		return false, err
+	}
+	matched, err = e.matchPatterns(pattern.Elements[splat+1:], elements[restEnd:], env)
+	if err != nil || !matched {
+		return false, err
+	}
+	bindSplat(pattern.Elements[splat].(*ast.SplatPattern), elements[splat:restEnd], env)
+	return true, nil
+}
+
+func (e *evaluator) matchFindPattern(pattern *ast.FindPattern, obj object.RubyObject, env object.Environment) (bool, error) {
+	matched, err := e.matchValue(pattern.Constant, obj, env)
+	if err != nil || !matched {
+		return false, err
+	}
+	array, ok := obj.(*object.Array)
+	if !ok {
+		return false, nil
+	}
+	elements := array.Elements
+	for start := 0; start+len(pattern.Elements) <= len(elements); start++ {
+		end := start + len(pattern.Elements)
+		matched, err := e.matchPatterns(pattern.Elements, elements[start:end], env)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			bindSplat(pattern.Pre, elements[:start], env)
+			bindSplat(pattern.Post, elements[end:], env)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (e *evaluator) matchHashPattern(pattern *ast.HashPattern, obj object.RubyObject, env object.Environment) (bool, error) {
+	matched, err := e.matchValue(pattern.Constant, obj, env)
+	if err != nil || !matched {
+		return false, err
+	}
+	hash, ok := obj.(*object.Hash)
+	if !ok {
+		return false, nil
+	}
+	pairs := hash.Map()
+	if pattern.Token.Type == token.LBRACE && len(pattern.Keys) == 0 && pattern.Rest == nil {
+		// `{}` only matches empty hashes
+		return len(pairs) == 0, nil
+	}
+	keys := make(map[string]bool)
+	for i, key := range pattern.Keys {
+		value, ok := hash.Get(object.NewSymbol(key.Value))
+		if !ok {
+			return false, nil
+		}
+		keys[key.Value] = true
+		if pattern.Values[i] == nil {
+			env.Set(key.Value, value)
+			continue
+		}
+		matched, err := e.matchPattern(pattern.Values[i], value, env)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	if pattern.Rest == nil && !pattern.NoRest {
+		return true, nil
+	}
+	rest := &object.Hash{}
+	for k, v := range pairs {
+		if sym, ok := k.(*object.Symbol); ok && keys[sym.Value] {
+			continue
+		}
+		rest.Set(k, v)
+	}
+	if pattern.NoRest {
+		return len(rest.Map()) == 0, nil
+	}
+	env.Set(pattern.Rest.Name.Value, rest)
+	return true, nil
+}
@@ -4,12 +4,27 @@ import (
 	"bytes"
 	"fmt"
 	"go/token"
+	"strings"
+
+	"github.com/goruby/goruby/ast"
+	"github.com/goruby/goruby/object"
 )
 
 type runtime interface {
-	addFile(*token.File)
-	pushToStack(name string, position int)
+	// pushToStack adds a new frame named name for code located in file
+	pushToStack(name string, file *token.File)
+	// popFromStack removes the innermost frame
 	popFromStack()
+	// setPosition records the position currently evaluated within the
+	// innermost frame
+	setPosition(position int)
+	// newFrame returns the frame named name for the body of a method or
+	// block defined within the innermost frame
+	newFrame(name string) object.Frame
+	// context returns the name of the innermost frame
+	context() string
+	// backtrace returns the formatted frames, innermost first
+	backtrace() []string
 	String() string
 }
 
@@ -18,66 +33,72 @@ func newRuntime() runtime {
 }
 
 type _runtime struct {
-	currentFile    *token.File
-	currentContext *executionContext
-	stack          []*executionContext
-}
-
-func (r *_runtime) addFile(file *token.File) {
-	r.currentFile = file
-	if len(r.stack) == 0 {
-		ctx := &executionContext{file: file}
-		ctx.addStackFrame("<main>", 1)
-		r.currentContext = ctx
-		r.stack = append(r.stack, ctx)
-	}
+	stack []*frame
 }
 
-func (r *_runtime) pushToStack(name string, position int) {
-	ctx := &executionContext{file: r.currentFile}
-	ctx.addStackFrame(name, position)
-	r.currentContext = ctx
-	r.stack = append(r.stack, ctx)
+func (r *_runtime) pushToStack(name string, file *token.File) {
+	r.stack = append(r.stack, &frame{context: name, file: file})
 }
 
 func (r *_runtime) popFromStack() {
 	r.stack = r.stack[:len(r.stack)-1]
-	r.currentContext = r.stack[len(r.stack)-1]
 }
 
-func (r *_runtime) String() string {
-	var out bytes.Buffer
-	for _, ctx := range r.stack {
-		fmt.Fprintln(&out, ctx.String())
+func (r *_runtime) setPosition(position int) {
+	if len(r.stack) == 0 {
+		return
 	}
-	return out.String()
+	r.stack[len(r.stack)-1].position = position
 }
 
-type executionContext struct {
-	file  *token.File
-	frame frame
+func (r *_runtime) newFrame(name string) object.Frame {
+	var file *token.File
+	if len(r.stack) != 0 {
+		file = r.stack[len(r.stack)-1].file
+	}
+	return object.Frame{Name: name, File: file}
 }
 
-func (ec *executionContext) addStackFrame(name string, position int) error {
-	epos := ec.file.Position(token.Pos(position))
-	if epos.Filename == "" || !epos.IsValid() {
-		return fmt.Errorf("Error finding context within file %q", ec.file.Name())
+func (r *_runtime) context() string {
+	if len(r.stack) == 0 {
+		return ""
 	}
-	ec.frame = frame{context: name, position: epos}
-	return nil
+	return r.stack[len(r.stack)-1].context
 }
 
-func (ec *executionContext) String() string {
-	var out bytes.Buffer
-	fmt.Fprintln(&out, ec.frame.String())
-	return out.String()
+func (r *_runtime) backtrace() []string {
+	backtrace := make([]string, len(r.stack))
+	for i, f := range r.stack {
+		backtrace[len(r.stack)-1-i] = f.String()
+	}
+	return backtrace
+}
+
+func (r *_runtime) String() string {
+	return strings.Join(r.backtrace(), "\n")
+}
+
+// blockContext returns the frame name for a block defined within context
+func blockContext(context string) string {
+	if strings.HasPrefix(context, "block in ") {
+		return context
+	}
+	return "block in " + context
 }
 
 type frame struct {
 	context  string
-	position token.Position
+	file     *token.File
+	position int
 }
 
-func (ef frame) String() string {
-	return fmt.Sprintf("%s:in `%s'", ef.position, ef.context)
+func (f *frame) String() string {
+	var out bytes.Buffer
+	var filename string
+	if f.file != nil {
+		filename = f.file.Name()
+	}
+	line := ast.Position(f.file, f.position).Line
+	fmt.Fprintf(&out, "%s:%d:in `%s'", filename, line, f.context)
+	return out.String()
 }
@@ -54,6 +54,7 @@ func TestLoadPath(t *testing.T) {
 		_, err = i.Interpret("", input)
 
 		expectedError := object.NewNoSuchFileLoadError(tmpBase)
+		object.AddBacktrace(expectedError, []string{":2:in `<main>'"})
 
 		if !reflect.DeepEqual(expectedError, errors.Cause(err)) {
 			t.Logf("Expected err to equal\n%s\n\tgot\n%s\n", expectedError, err)
@@ -97,9 +97,10 @@ func TestMethodBlockLeakage(t *testing.T) {
 		_, err := i.Interpret("", input)
 
 		expected := object.NewNoBlockGivenLocalJumpError()
+		object.AddBacktrace(expected, []string{":7:in `sub'", ":12:in `<main>'"})
 
 		if !reflect.DeepEqual(expected, errors.Cause(err)) {
-			t.Logf("Expected error to equal\n%+#v\n\tgot\n%+#v\n", expected, err)
+			t.Logf("Expected error to equal\n%+#v\n\tgot\n%+#v\n", expected, errors.Cause(err))
 			t.Fail()
 		}
 	})
@@ -126,9 +127,10 @@ func TestMethodBlockLeakage(t *testing.T) {
 		_, err := i.Interpret("", input)
 
 		expected := object.NewNoBlockGivenLocalJumpError()
+		object.AddBacktrace(expected, []string{":8:in `sub'", ":16:in `<main>'"})
 
 		if !reflect.DeepEqual(expected, errors.Cause(err)) {
-			t.Logf("Expected error to equal\n%+#v\n\tgot\n%+#v\n", expected, err)
+			t.Logf("Expected error to equal\n%+#v\n\tgot\n%+#v\n", expected, errors.Cause(err))
 			t.Fail()
 		}
 	})
@@ -3,6 +3,7 @@ package lexer
 import (
 	"bytes"
 	"fmt"
+	gotoken "go/token"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -38,6 +39,28 @@ func New(input string) *Lexer {
 	return l
 }
 
+// NewAt returns a Lexer instance ready to process the given input, starting
+// at offset. It is meant to lex code embedded within a token already lexed,
+// e.g. interpolated code within a string literal. If file is not nil, it
+// must be the file given to the Lexer which lexed the embedding token.
+func NewAt(file *gotoken.File, input string, offset int) *Lexer {
+	l := New(input)
+	l.file = file
+	l.pos = offset
+	l.start = offset
+	return l
+}
+
+// NewWithFile returns a Lexer instance ready to process the given input. The
+// lexer registers the start of every line it reads in file, so that token
+// positions can be converted to line and column information. The size of
+// file must match the length of input.
+func NewWithFile(file *gotoken.File, input string) *Lexer {
+	l := New(input)
+	l.file = file
+	return l
+}
+
 // Lexer is the engine to process input and emit Tokens
 type Lexer struct {
 	input     string           // the string being scanned.
@@ -47,6 +70,14 @@ type Lexer struct {
 	width     int              // width of last rune read from input.
 	tokens    chan token.Token // channel of scanned tokens.
 	lastToken token.Token      // lastToken stores the last token emitted by the lexer
+	file      *gotoken.File    // file to register line starts in, if any
+	// heredocEnd is the position after the body of the last heredoc started
+	// on the current line, 0 if there is none
+	heredocEnd int
+	// locals holds the identifiers assigned to so far. Like in MRI a slash
+	// or percent sign following them is a binary operator, as they are
+	// local variables instead of methods taking a literal argument.
+	locals map[string]bool
 }
 
 // NextToken will return the next token processed from the lexer.
@@ -77,6 +108,12 @@ func (l *Lexer) HasNext() bool {
 
 // emit passes a token back to the client.
 func (l *Lexer) emit(t token.Type) {
+	if isAssignment(t) && l.lastToken.Type == token.IDENT {
+		if l.locals == nil {
+			l.locals = make(map[string]bool)
+		}
+		l.locals[l.lastToken.Literal] = true
+	}
 	token := token.NewToken(t, l.input[l.start:l.pos], l.start)
 	l.lastToken = token
 	l.tokens <- token
@@ -92,6 +129,11 @@ func (l *Lexer) next() rune {
 	var r rune
 	r, l.width = utf8.DecodeRuneInString(l.input[l.pos:])
 	l.pos += l.width
+	if r == '\n' && l.file != nil {
+		// AddLine ignores line offsets already known, so backing up over a
+		// newline and reading it again is safe
+		l.file.AddLine(l.pos)
+	}
 	return r
 }
 
@@ -132,6 +174,10 @@ func startLexer(l *Lexer) StateFn {
 		return lexGlobal
 	case '\n':
 		l.emit(token.NEWLINE)
+		if l.heredocEnd != 0 {
+			// continue after the bodies of the heredocs started on this line
+			l.pos, l.start, l.heredocEnd = l.heredocEnd, l.heredocEnd, 0
+		}
 		return startLexer
 	case '\'':
 		return lexSingleQuoteString
@@ -151,15 +197,33 @@ func startLexer(l *Lexer) StateFn {
 		l.emit(token.SYMBEG)
 		return startLexer
 	case '.':
+		if l.peek() == '.' {
+			l.next()
+			if l.peek() == '.' {
+				l.next()
+				l.emit(token.DOT3)
+				return startLexer
+			}
+			l.emit(token.DOT2)
+			return startLexer
+		}
 		l.emit(token.DOT)
 		return startLexer
 	case '=':
 		if l.peek() == '=' {
 			l.next()
+			if l.peek() == '=' {
+				l.next()
+				l.emit(token.CASEEQ)
+				return startLexer
+			}
 			l.emit(token.EQ)
 		} else if l.peek() == '>' {
 			l.next()
 			l.emit(token.HASHROCKET)
+		} else if l.peek() == '~' {
+			l.next()
+			l.emit(token.MATCH)
 		} else {
 			l.emit(token.ASSIGN)
 		}
@@ -184,6 +248,9 @@ func startLexer(l *Lexer) StateFn {
 		if l.peek() == '=' {
 			l.next()
 			l.emit(token.NOTEQ)
+		} else if l.peek() == '~' {
+			l.next()
+			l.emit(token.NOTMATCH)
 		} else {
 			l.emit(token.BANG)
 		}
@@ -201,6 +268,9 @@ func startLexer(l *Lexer) StateFn {
 		}
 		return lexCharacterLiteral
 	case '/':
+		if l.isRegexStart() {
+			return lexRegex
+		}
 		if l.peek() == '=' {
 			l.next()
 			l.emit(token.DIVASSIGN)
@@ -214,6 +284,11 @@ func startLexer(l *Lexer) StateFn {
 			l.emit(token.MULASSIGN)
 			return startLexer
 		}
+		if l.peek() == '*' {
+			l.next()
+			l.emit(token.POW)
+			return startLexer
+		}
 		l.emit(token.ASTERISK)
 		return startLexer
 	case '%':
@@ -222,6 +297,9 @@ func startLexer(l *Lexer) StateFn {
 			l.emit(token.MODASSIGN)
 			return startLexer
 		}
+		if l.isPercentLiteralStart() {
+			return lexPercentLiteral
+		}
 		l.emit(token.MODULO)
 		return startLexer
 	case '&':
@@ -230,7 +308,7 @@ func startLexer(l *Lexer) StateFn {
 			l.emit(token.LOGICALAND)
 			return startLexer
 		}
-		if p := l.peek(); isLetter(p) {
+		if p := l.peek(); isLetter(p) || p == ':' {
 			l.emit(token.CAPTURE)
 			return startLexer
 		}
@@ -249,6 +327,9 @@ func startLexer(l *Lexer) StateFn {
 		}
 		if l.peek() == '<' {
 			l.next()
+			if l.isHeredocStart() {
+				return lexHeredoc
+			}
 			l.emit(token.LSHIFT)
 			return startLexer
 		}
@@ -303,9 +384,26 @@ func startLexer(l *Lexer) StateFn {
 		}
 		l.emit(token.PIPE)
 		return startLexer
+	case '^':
+		l.emit(token.CARET)
+		return startLexer
 	case '@':
 		l.emit(token.AT)
 		return startLexer
+	case '\\':
+		if l.peek() == '\r' {
+			l.next()
+		}
+		if l.peek() != '\n' {
+			return l.errorf("Illegal character: '%c'", r)
+		}
+		// line continuation
+		l.next()
+		l.ignore()
+		if l.heredocEnd != 0 {
+			l.pos, l.start, l.heredocEnd = l.heredocEnd, l.heredocEnd, 0
+		}
+		return startLexer
 
 	default:
 		if isDigit(r) {
@@ -338,37 +436,221 @@ func lexIdentifier(l *Lexer) StateFn {
 }
 
 func lexDigit(l *Lexer) StateFn {
+	if l.input[l.start] == '0' && l.pos < len(l.input) {
+		if radix, ok := integerRadixes[unicode.ToLower(rune(l.input[l.pos]))]; ok {
+			l.next()
+			if l.peek() == '_' {
+				return l.errorf("numeric literal without digits")
+			}
+			return lexRadixDigits(radix)
+		}
+		if isDigitOrUnderscore(rune(l.input[l.pos])) {
+			return lexRadixDigits(integerRadixes['o'])
+		}
+	}
+	if !l.acceptDigits() {
+		return l.errorf("trailing '_' in number")
+	}
+	typ := token.INT
+	if l.acceptFraction() {
+		typ = token.FLOAT
+		if !l.acceptDigits() {
+			return l.errorf("trailing '_' in number")
+		}
+	}
+	if l.acceptExponent() {
+		typ = token.FLOAT
+		if l.peek() == '_' {
+			return l.errorf("numeric literal without digits")
+		}
+		if !l.acceptDigits() {
+			return l.errorf("trailing '_' in number")
+		}
+	}
+	l.emit(typ)
+	return startLexer
+}
+
+// acceptDigits consumes decimal digits separated by single underscores. It
+// returns false if an underscore follows another one or ends the digits.
+func (l *Lexer) acceptDigits() bool {
+	underscore := false
 	r := l.next()
 	for isDigitOrUnderscore(r) {
+		if r == '_' && underscore {
+			return false
+		}
+		underscore = r == '_'
 		r = l.next()
 	}
 	l.backup()
-	l.emit(token.INT)
-	return startLexer
+	return !underscore
 }
 
-func lexSingleQuoteString(l *Lexer) StateFn {
-	l.ignore()
-	r := l.next()
+type integerRadix struct {
+	name  string
+	digit func(rune) bool
+}
 
-	for r != '\'' {
-		r = l.next()
+var integerRadixes = map[rune]integerRadix{
+	'x': {"hexadecimal", func(r rune) bool { return unicode.Is(unicode.ASCII_Hex_Digit, r) }},
+	'd': {"decimal", isDigit},
+	'o': {"octal", func(r rune) bool { return '0' <= r && r <= '7' }},
+	'b': {"binary", func(r rune) bool { return r == '0' || r == '1' }},
+}
+
+// lexRadixDigits returns a StateFn consuming the digits of an integer
+// literal in the given radix, with the radix prefix already consumed
+func lexRadixDigits(radix integerRadix) StateFn {
+	return func(l *Lexer) StateFn {
+		digits := 0
+		underscore := false
+		r := l.next()
+		for isLetter(r) || isDigitOrUnderscore(r) {
+			if r != '_' && !radix.digit(r) {
+				return l.errorf("Invalid %s digit: '%c'", radix.name, r)
+			}
+			if r == '_' && underscore {
+				return l.errorf("trailing '_' in number")
+			}
+			underscore = r == '_'
+			if r != '_' {
+				digits++
+			}
+			r = l.next()
+		}
+		l.backup()
+		if digits == 0 {
+			return l.errorf("numeric literal without digits")
+		}
+		if underscore {
+			return l.errorf("trailing '_' in number")
+		}
+		l.emit(token.INT)
+		return startLexer
+	}
+}
+
+// acceptFraction consumes the dot starting the fractional part of a float
+// literal. A dot not followed by a digit is a method call on the integer and
+// left untouched.
+func (l *Lexer) acceptFraction() bool {
+	rest := l.input[l.pos:]
+	if len(rest) < 2 || rest[0] != '.' || !isDigit(rune(rest[1])) {
+		return false
 	}
-	l.backup()
-	l.emit(token.STRING)
 	l.next()
-	l.ignore()
+	return true
+}
+
+// acceptExponent consumes the exponent marker and sign of a float literal,
+// e.g. `e-` of `e-2`. It also accepts a marker followed by an underscore,
+// leaving it to the caller to report the missing digits.
+func (l *Lexer) acceptExponent() bool {
+	rest := l.input[l.pos:]
+	if len(rest) < 2 || (rest[0] != 'e' && rest[0] != 'E') {
+		return false
+	}
+	digits := rest[1:]
+	if digits[0] == '+' || digits[0] == '-' {
+		digits = digits[1:]
+	}
+	if len(digits) == 0 || !isDigitOrUnderscore(rune(digits[0])) {
+		return false
+	}
+	l.pos += len(rest) - len(digits)
+	return true
+}
+
+func lexSingleQuoteString(l *Lexer) StateFn {
+	if !l.scanSingleQuoted() {
+		return l.errorf("unterminated string meets end of file")
+	}
+	l.emit(token.STRING)
 	return startLexer
 }
 
+// scanSingleQuoted consumes a single quoted string up to and including the
+// closing quote. It returns false if the input ends before.
+func (l *Lexer) scanSingleQuoted() bool {
+	for {
+		switch l.next() {
+		case eof:
+			return false
+		case '\\':
+			if l.next() == eof {
+				return false
+			}
+		case '\'':
+			return true
+		}
+	}
+}
+
+// scanDoubleQuoted consumes a double quoted string up to and including the
+// closing quote, skipping over escaped characters and interpolated code. It
+// returns false if the input ends before.
+func (l *Lexer) scanDoubleQuoted() bool {
+	for {
+		switch l.next() {
+		case eof:
+			return false
+		case '\\':
+			if l.next() == eof {
+				return false
+			}
+		case '#':
+			if l.peek() == '{' {
+				l.next()
+				if !l.scanInterpolation() {
+					return false
+				}
+			}
+		case '"':
+			return true
+		}
+	}
+}
+
+// scanInterpolation consumes the code of an interpolation `#{}` up to and
+// including the closing brace. It returns false if the input ends before.
+func (l *Lexer) scanInterpolation() bool {
+	depth := 1
+	for {
+		switch l.next() {
+		case eof:
+			return false
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return true
+			}
+		case '"':
+			if !l.scanDoubleQuoted() {
+				return false
+			}
+		case '\'':
+			if !l.scanSingleQuoted() {
+				return false
+			}
+		case '$':
+			// a quote or brace can be part of a global like $' or $"
+			if strings.ContainsRune(specialGlobals, l.peek()) {
+				l.next()
+			}
+		}
+	}
+}
+
 func lexCharacterLiteral(l *Lexer) StateFn {
-	l.ignore()
 	r := l.next()
 	if isWhitespace(r) && r != '\t' && r != '\v' && r != '\f' && r != '\r' {
 		return l.errorf("invalid character syntax; use ?\\s")
 	}
 	if r == '\\' {
-		r = l.next()
+		l.scanCharacterEscape()
 	}
 	if p := l.peek(); !isWhitespace(p) && !isExpressionDelimiter(p) {
 		return l.errorf("unexpected '?'")
@@ -377,36 +659,329 @@ func lexCharacterLiteral(l *Lexer) StateFn {
 	return startLexer
 }
 
+// scanCharacterEscape consumes the escape sequence of a character literal
+// following the backslash, e.g. `n`, `u{1F600}` or `M-\C-x`. Decoding and
+// validating the sequence is left to the parser.
+func (l *Lexer) scanCharacterEscape() {
+	hexDigit := integerRadixes['x'].digit
+	octalDigit := integerRadixes['o'].digit
+	acceptUpTo := func(n int, valid func(rune) bool) {
+		for i := 0; i < n && valid(l.peek()); i++ {
+			l.next()
+		}
+	}
+	switch r := l.next(); {
+	case r == 'u' && l.peek() == '{':
+		for r := l.next(); r != '}' && !isExpressionDelimiter(r); r = l.next() {
+		}
+	case r == 'u':
+		acceptUpTo(4, hexDigit)
+	case r == 'x':
+		acceptUpTo(2, hexDigit)
+	case octalDigit(r):
+		acceptUpTo(2, octalDigit)
+	case r == 'c' || (r == 'C' || r == 'M') && l.peek() == '-':
+		if r != 'c' {
+			l.next()
+		}
+		if l.next() == '\\' {
+			l.scanCharacterEscape()
+		}
+	}
+}
+
 func lexString(l *Lexer) StateFn {
-	l.ignore()
-	r := l.next()
+	if !l.scanDoubleQuoted() {
+		return l.errorf("unterminated string meets end of file")
+	}
+	l.emit(token.STRING)
+	return startLexer
+}
 
-	for r != '"' {
-		r = l.next()
+// isHeredocStart reports whether the `<<` just consumed starts a heredoc,
+// i.e. is immediately followed by an identifier, optionally quoted and
+// prefixed with `-` or `~`
+func (l *Lexer) isHeredocStart() bool {
+	if isValueToken(l.lastToken.Type) {
+		return false
 	}
-	l.backup()
+	if l.lastToken.Type == token.IDENT {
+		// `foo <<EOF` passes a heredoc to foo, `foo<<EOF` is a shift
+		if l.start == 0 || !isWhitespace(rune(l.input[l.start-1])) {
+			return false
+		}
+	}
+	rest := strings.TrimLeft(l.input[l.pos:], "-~")
+	if len(l.input[l.pos:])-len(rest) > 1 || rest == "" {
+		return false
+	}
+	return strings.ContainsRune("'\"`", rune(rest[0])) || isLetter(rune(rest[0]))
+}
+
+// lexHeredoc emits the heredoc identifier as token.HEREDOC followed by the
+// body as token.STRING. The body starts on the line following the
+// identifier, or after the body of the previous heredoc started on the same
+// line. Lexing then continues after the identifier.
+func lexHeredoc(l *Lexer) StateFn {
+	indented := l.peek() == '-' || l.peek() == '~'
+	if indented {
+		l.next()
+	}
+	var id string
+	switch quote := l.peek(); quote {
+	case '\'', '"', '`':
+		l.next()
+		idStart := l.pos
+		end := strings.IndexAny(l.input[idStart:], string(quote)+"\n")
+		if end == -1 || l.input[idStart+end] != byte(quote) {
+			return l.errorf("unterminated here document identifier")
+		}
+		id = l.input[idStart : idStart+end]
+		l.pos = idStart + end + 1
+	default:
+		idStart := l.pos
+		r := l.next()
+		for isLetter(r) || isDigit(r) {
+			r = l.next()
+		}
+		l.backup()
+		id = l.input[idStart:l.pos]
+	}
+
+	bodyStart := l.heredocEnd
+	if bodyStart == 0 {
+		newline := strings.IndexByte(l.input[l.pos:], '\n')
+		if newline == -1 {
+			return l.errorf("can't find string %q anywhere before EOF", id)
+		}
+		bodyStart = l.pos + newline + 1
+	}
+	bodyEnd, next := -1, bodyStart
+	for next < len(l.input) {
+		lineEnd := strings.IndexByte(l.input[next:], '\n')
+		if lineEnd == -1 {
+			lineEnd = len(l.input)
+		} else {
+			lineEnd += next
+		}
+		line := strings.TrimSuffix(l.input[next:lineEnd], "\r")
+		if indented {
+			line = strings.TrimLeft(line, " \t")
+		}
+		lineStart := next
+		next = lineEnd + 1
+		if line == id {
+			bodyEnd = lineStart
+			break
+		}
+	}
+	if bodyEnd == -1 {
+		return l.errorf("can't find string %q anywhere before EOF", id)
+	}
+	if next > len(l.input) {
+		next = len(l.input)
+	}
+	if l.file != nil {
+		// register the line starts up to the end of the body in order, as
+		// the remainder of the current line gets lexed afterwards
+		for i := l.pos; i < next; i++ {
+			if l.input[i] == '\n' {
+				l.file.AddLine(i + 1)
+			}
+		}
+	}
+	l.emit(token.HEREDOC)
+	pos := l.pos
+	l.start, l.pos = bodyStart, bodyEnd
 	l.emit(token.STRING)
-	l.next()
-	l.ignore()
+	l.start, l.pos, l.heredocEnd = pos, pos, next
 	return startLexer
 }
 
-func lexGlobal(l *Lexer) StateFn {
-	r := l.next()
+// isAssignment reports whether t assigns to the expression before it
+func isAssignment(t token.Type) bool {
+	switch t {
+	case token.ASSIGN, token.ADDASSIGN, token.SUBASSIGN, token.MULASSIGN, token.DIVASSIGN, token.MODASSIGN:
+		return true
+	default:
+		return false
+	}
+}
 
-	if r == '.' {
-		return l.errorf("Illegal character: '%c'", r)
+// isValueToken reports whether a token of type t ends an operand, so that a
+// following `<<` or `%` is a binary operator
+func isValueToken(t token.Type) bool {
+	switch t {
+	case token.CONST, token.GLOBAL, token.INT, token.FLOAT, token.STRING,
+		token.SYMBOL, token.WORDS, token.REGEX, token.RPAREN, token.RBRACKET,
+		token.RBRACE, token.SELF, token.NIL, token.TRUE, token.FALSE, token.END:
+		return true
+	default:
+		return false
 	}
+}
 
-	if isExpressionDelimiter(r) {
-		return l.errorf("Illegal character: '%c'", r)
+// isPercentLiteralStart reports whether the `%` just consumed starts a
+// percent literal like `%w(a b)` instead of being the modulo operator
+func (l *Lexer) isPercentLiteralStart() bool {
+	if isValueToken(l.lastToken.Type) {
+		return false
 	}
+	rest := l.input[l.pos:]
+	if l.lastToken.Type == token.IDENT {
+		// `foo %w(a)` passes a literal to foo, `foo % w` and `foo%w` are
+		// modulo operations
+		if l.locals[l.lastToken.Literal] || l.start == 0 || !isWhitespace(rune(l.input[l.start-1])) {
+			return false
+		}
+		if rest == "" || unicode.IsSpace(rune(rest[0])) {
+			return false
+		}
+	}
+	if len(rest) > 1 && strings.ContainsRune(percentLiteralTypes, rune(rest[0])) {
+		return isPercentDelimiter(rest[1])
+	}
+	return rest != "" && isPercentDelimiter(rest[0])
+}
 
-	if isWhitespace(r) {
+const percentLiteralTypes = "qQwWiIsr"
+
+var percentLiteralClosers = map[rune]rune{
+	'(': ')',
+	'[': ']',
+	'{': '}',
+	'<': '>',
+}
+
+func isPercentDelimiter(c byte) bool {
+	return c < utf8.RuneSelf && (unicode.IsPunct(rune(c)) || unicode.IsSymbol(rune(c)))
+}
+
+// lexPercentLiteral lexes percent literals. Paired delimiters nest within
+// the literal, any other delimiter ends it on its next occurrence.
+func lexPercentLiteral(l *Lexer) StateFn {
+	kind := 'Q'
+	if strings.ContainsRune(percentLiteralTypes, l.peek()) {
+		kind = l.next()
+	}
+	open := l.next()
+	closer, paired := percentLiteralClosers[open]
+	if !paired {
+		closer = open
+	}
+	interpolated := strings.ContainsRune("QWIr", kind)
+	depth := 1
+	for depth > 0 {
+		r := l.next()
+		switch {
+		case r == eof:
+			switch kind {
+			case 'w', 'W', 'i', 'I':
+				return l.errorf("unterminated list meets end of file")
+			case 'r':
+				return l.errorf("unterminated regexp meets end of file")
+			default:
+				return l.errorf("unterminated string meets end of file")
+			}
+		case r == '\\':
+			l.next()
+		case r == '#' && interpolated && l.peek() == '{':
+			l.next()
+			if !l.scanInterpolation() {
+				return l.errorf("unterminated string meets end of file")
+			}
+		case r == closer:
+			depth--
+		case r == open && paired:
+			depth++
+		}
+	}
+	switch kind {
+	case 'w', 'W', 'i', 'I':
+		l.emit(token.WORDS)
+	case 's':
+		l.emit(token.SYMBOL)
+	case 'r':
+		l.acceptRegexFlags()
+		l.emit(token.REGEX)
+	default:
+		l.emit(token.STRING)
+	}
+	return startLexer
+}
+
+// isRegexStart reports whether the `/` just consumed starts a regular
+// expression literal like `/foo/` instead of being the division operator
+func (l *Lexer) isRegexStart() bool {
+	switch l.lastToken.Type {
+	case token.DEF, token.DOT:
+		return false
+	case token.IDENT:
+		// `foo /a/` passes a regexp to foo, `foo / a`, `foo/a` and
+		// `foo /= a` are divisions
+		if l.locals[l.lastToken.Literal] || l.start == 0 || !isWhitespace(rune(l.input[l.start-1])) {
+			return false
+		}
+		rest := l.input[l.pos:]
+		return rest != "" && !unicode.IsSpace(rune(rest[0])) && rest[0] != '='
+	default:
+		return !isValueToken(l.lastToken.Type)
+	}
+}
+
+// lexRegex lexes a regular expression literal up to the closing slash and
+// its flags. Escaped characters and interpolated code are skipped over.
+func lexRegex(l *Lexer) StateFn {
+	for {
+		switch l.next() {
+		case eof:
+			return l.errorf("unterminated regexp meets end of file")
+		case '\\':
+			if l.next() == eof {
+				return l.errorf("unterminated regexp meets end of file")
+			}
+		case '#':
+			if l.peek() == '{' {
+				l.next()
+				if !l.scanInterpolation() {
+					return l.errorf("unterminated regexp meets end of file")
+				}
+			}
+		case '/':
+			l.acceptRegexFlags()
+			l.emit(token.REGEX)
+			return startLexer
+		}
+	}
+}
+
+// acceptRegexFlags consumes the flags following a regular expression
+// literal, e.g. the `i` of `/foo/i`
+func (l *Lexer) acceptRegexFlags() {
+	for strings.ContainsRune("imxounse", l.peek()) {
+		l.next()
+	}
+}
+
+// specialGlobals holds the characters which form a global variable on their
+// own when following a `$`, e.g. `$~` or `$!`
+const specialGlobals = "~*$?!@/\\,.=:<>\"&`'+"
+
+// lexGlobal lexes global variables, i.e. a `$` followed by a name, by
+// digits like `$1` or by one of the specialGlobals
+func lexGlobal(l *Lexer) StateFn {
+	r := l.next()
+
+	if strings.ContainsRune(specialGlobals, r) {
+		l.emit(token.GLOBAL)
+		return startLexer
+	}
+
+	if unicode.IsSpace(r) || strings.ContainsRune(operatorCharacters, r) || r == eof {
 		return l.errorf("Illegal character: '%c'", r)
 	}
 
-	for !isWhitespace(r) && !isExpressionDelimiter(r) && r != '.' && r != ',' {
+	for !unicode.IsSpace(r) && !strings.ContainsRune(operatorCharacters, r) && r != eof {
 		r = l.next()
 	}
 	l.backup()
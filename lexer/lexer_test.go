@@ -1,6 +1,7 @@
 package lexer
 
 import (
+	gotoken "go/token"
 	"testing"
 
 	"github.com/goruby/goruby/token"
@@ -15,6 +16,9 @@ seven,
 # just comment
 fifty = 5_0
 ten = 10
+pi = 3.14
+1234e-2 1.234E1 1_0.5
+1.e 2.foo
 ?-
 ?\n
 ? foo : bar
@@ -26,18 +30,21 @@ end
 ||
 
 result = add(five, ten)
-!-/*%5;
-+= -= *= /= %=
+!-1/*%5;
++= -= *= 1 /= %=
 5 < 10 > 5
 return
 if 5 < 10 then
 	true
+elsif 5
 else
 	false
 end
 
 begin
 rescue
+retry
+ensure
 end
 
 10 == 10
@@ -56,6 +63,7 @@ end
 :'sym'
 .
 &foo
+&:foo
 &
 &&
 :dotAfter.
@@ -78,6 +86,16 @@ add do |x|
 end
 yield
 while
+until
+break
+next
+redo
+case x
+when 1 then 2
+in [*, ^y] | {a: **nil}
+end
+10 === 9
+2 ** 3
 A::B
 =>
 __FILE__
@@ -123,9 +141,24 @@ $a`
 		{token.ASSIGN, "="},
 		{token.INT, "10"},
 		{token.NEWLINE, "\n"},
-		{token.STRING, "-"},
+		{token.IDENT, "pi"},
+		{token.ASSIGN, "="},
+		{token.FLOAT, "3.14"},
 		{token.NEWLINE, "\n"},
-		{token.STRING, "\\n"},
+		{token.FLOAT, "1234e-2"},
+		{token.FLOAT, "1.234E1"},
+		{token.FLOAT, "1_0.5"},
+		{token.NEWLINE, "\n"},
+		{token.INT, "1"},
+		{token.DOT, "."},
+		{token.IDENT, "e"},
+		{token.INT, "2"},
+		{token.DOT, "."},
+		{token.IDENT, "foo"},
+		{token.NEWLINE, "\n"},
+		{token.STRING, "?-"},
+		{token.NEWLINE, "\n"},
+		{token.STRING, "?\\n"},
 		{token.NEWLINE, "\n"},
 		{token.QMARK, "?"},
 		{token.IDENT, "foo"},
@@ -163,6 +196,7 @@ $a`
 		{token.NEWLINE, "\n"},
 		{token.BANG, "!"},
 		{token.MINUS, "-"},
+		{token.INT, "1"},
 		{token.SLASH, "/"},
 		{token.ASTERISK, "*"},
 		{token.MODULO, "%"},
@@ -172,6 +206,7 @@ $a`
 		{token.ADDASSIGN, "+="},
 		{token.SUBASSIGN, "-="},
 		{token.MULASSIGN, "*="},
+		{token.INT, "1"},
 		{token.DIVASSIGN, "/="},
 		{token.MODASSIGN, "%="},
 		{token.NEWLINE, "\n"},
@@ -191,6 +226,9 @@ $a`
 		{token.NEWLINE, "\n"},
 		{token.TRUE, "true"},
 		{token.NEWLINE, "\n"},
+		{token.ELSIF, "elsif"},
+		{token.INT, "5"},
+		{token.NEWLINE, "\n"},
 		{token.ELSE, "else"},
 		{token.NEWLINE, "\n"},
 		{token.FALSE, "false"},
@@ -202,6 +240,10 @@ $a`
 		{token.NEWLINE, "\n"},
 		{token.RESCUE, "rescue"},
 		{token.NEWLINE, "\n"},
+		{token.RETRY, "retry"},
+		{token.NEWLINE, "\n"},
+		{token.ENSURE, "ensure"},
+		{token.NEWLINE, "\n"},
 		{token.END, "end"},
 		{token.NEWLINE, "\n"},
 		{token.NEWLINE, "\n"},
@@ -229,30 +271,34 @@ $a`
 		{token.LSHIFT, "<<"},
 		{token.INT, "9"},
 		{token.NEWLINE, "\n"},
-		{token.STRING, ""},
+		{token.STRING, `""`},
 		{token.NEWLINE, "\n"},
-		{token.STRING, "foobar"},
+		{token.STRING, `"foobar"`},
 		{token.NEWLINE, "\n"},
-		{token.STRING, "foobar"},
+		{token.STRING, `'foobar'`},
 		{token.NEWLINE, "\n"},
-		{token.STRING, "foo bar"},
+		{token.STRING, `"foo bar"`},
 		{token.NEWLINE, "\n"},
-		{token.STRING, "foo bar"},
+		{token.STRING, `'foo bar'`},
 		{token.NEWLINE, "\n"},
 		{token.SYMBEG, ":"},
 		{token.IDENT, "sym"},
 		{token.NEWLINE, "\n"},
 		{token.SYMBEG, ":"},
-		{token.STRING, "sym"},
+		{token.STRING, `"sym"`},
 		{token.NEWLINE, "\n"},
 		{token.SYMBEG, ":"},
-		{token.STRING, "sym"},
+		{token.STRING, `'sym'`},
 		{token.NEWLINE, "\n"},
 		{token.DOT, "."},
 		{token.NEWLINE, "\n"},
 		{token.CAPTURE, "&"},
 		{token.IDENT, "foo"},
 		{token.NEWLINE, "\n"},
+		{token.CAPTURE, "&"},
+		{token.SYMBEG, ":"},
+		{token.IDENT, "foo"},
+		{token.NEWLINE, "\n"},
 		{token.AND, "&"},
 		{token.NEWLINE, "\n"},
 		{token.LOGICALAND, "&&"},
@@ -317,6 +363,47 @@ $a`
 		{token.NEWLINE, "\n"},
 		{token.WHILE, "while"},
 		{token.NEWLINE, "\n"},
+		{token.UNTIL, "until"},
+		{token.NEWLINE, "\n"},
+		{token.BREAK, "break"},
+		{token.NEWLINE, "\n"},
+		{token.NEXT, "next"},
+		{token.NEWLINE, "\n"},
+		{token.REDO, "redo"},
+		{token.NEWLINE, "\n"},
+		{token.CASE, "case"},
+		{token.IDENT, "x"},
+		{token.NEWLINE, "\n"},
+		{token.WHEN, "when"},
+		{token.INT, "1"},
+		{token.THEN, "then"},
+		{token.INT, "2"},
+		{token.NEWLINE, "\n"},
+		{token.IN, "in"},
+		{token.LBRACKET, "["},
+		{token.ASTERISK, "*"},
+		{token.COMMA, ","},
+		{token.CARET, "^"},
+		{token.IDENT, "y"},
+		{token.RBRACKET, "]"},
+		{token.PIPE, "|"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "a"},
+		{token.COLON, ":"},
+		{token.POW, "**"},
+		{token.NIL, "nil"},
+		{token.RBRACE, "}"},
+		{token.NEWLINE, "\n"},
+		{token.END, "end"},
+		{token.NEWLINE, "\n"},
+		{token.INT, "10"},
+		{token.CASEEQ, "==="},
+		{token.INT, "9"},
+		{token.NEWLINE, "\n"},
+		{token.INT, "2"},
+		{token.POW, "**"},
+		{token.INT, "3"},
+		{token.NEWLINE, "\n"},
 		{token.CONST, "A"},
 		{token.SCOPE, "::"},
 		{token.CONST, "B"},
@@ -364,3 +451,372 @@ $a`
 		}
 	}
 }
+
+func TestLexerWithFile(t *testing.T) {
+	input := "foo = \"bar\nbaz\"\n\n  qux # comment\n"
+	file := gotoken.NewFileSet().AddFile("test.rb", -1, len(input))
+
+	lexer := NewWithFile(file, input)
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+		expectedPos     string
+	}{
+		{token.IDENT, "foo", "test.rb:1:1"},
+		{token.ASSIGN, "=", "test.rb:1:5"},
+		{token.STRING, "\"bar\nbaz\"", "test.rb:1:7"},
+		{token.NEWLINE, "\n", "test.rb:2:5"},
+		{token.NEWLINE, "\n", "test.rb:3:1"},
+		{token.IDENT, "qux", "test.rb:4:3"},
+		{token.HASH, "#", "test.rb:4:7"},
+		{token.STRING, " comment", "test.rb:4:8"},
+		{token.NEWLINE, "\n", "test.rb:4:16"},
+		{token.EOF, "", "test.rb:4:17"},
+	}
+
+	for _, tt := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Logf("Expected token %s(%q), got %s(%q)\n", tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+			t.Fail()
+		}
+
+		pos := file.Position(file.Pos(tok.Pos)).String()
+		if pos != tt.expectedPos {
+			t.Logf("Expected token %s(%q) at %s, got %s\n", tok.Type, tok.Literal, tt.expectedPos, pos)
+			t.Fail()
+		}
+	}
+}
+
+func TestLexerStrings(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{`"a\"b"`, token.STRING, `"a\"b"`},
+		{`"a\\"`, token.STRING, `"a\\"`},
+		{`"#{x}"`, token.STRING, `"#{x}"`},
+		{`"a #{"b #{c}"} d"`, token.STRING, `"a #{"b #{c}"} d"`},
+		{`"#{ {a: '}'} }"`, token.STRING, `"#{ {a: '}'} }"`},
+		{"\"#{$`}-#{$'}\"", token.STRING, "\"#{$`}-#{$'}\""},
+		{`'a\'b'`, token.STRING, `'a\'b'`},
+		{`'a\\'`, token.STRING, `'a\\'`},
+		{`"a\"`, token.ILLEGAL, "unterminated string meets end of file"},
+		{`"#{x"`, token.ILLEGAL, "unterminated string meets end of file"},
+		{`'a\'`, token.ILLEGAL, "unterminated string meets end of file"},
+	}
+
+	for _, tt := range tests {
+		tok := New(tt.input).NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Logf("Expected token %s(%q) for %q, got %s(%q)\n", tt.expectedType, tt.expectedLiteral, tt.input, tok.Type, tok.Literal)
+			t.Fail()
+		}
+	}
+}
+
+func TestLexerCharacterLiterals(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{`?a`, token.STRING, `?a`},
+		{`?\s`, token.STRING, `?\s`},
+		{`?\\`, token.STRING, `?\\`},
+		{`?\x41 `, token.STRING, `?\x41`},
+		{`?\101`, token.STRING, `?\101`},
+		{`?\u0041`, token.STRING, `?\u0041`},
+		{`?\u{1F600}`, token.STRING, `?\u{1F600}`},
+		{`?\C-a`, token.STRING, `?\C-a`},
+		{`?\M-\C-x`, token.STRING, `?\M-\C-x`},
+		{`?\c?`, token.STRING, `?\c?`},
+		{`?ab`, token.ILLEGAL, "unexpected '?'"},
+	}
+
+	for _, tt := range tests {
+		tok := New(tt.input).NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Logf("Expected token %s(%q) for %q, got %s(%q)\n", tt.expectedType, tt.expectedLiteral, tt.input, tok.Type, tok.Literal)
+			t.Fail()
+		}
+	}
+}
+
+func TestLexerLineContinuation(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedTypes []token.Type
+	}{
+		{"\"a\" \\\n\"b\"", []token.Type{token.STRING, token.STRING, token.EOF}},
+		{"x = 1 \\\r\n+ 2", []token.Type{token.IDENT, token.ASSIGN, token.INT, token.PLUS, token.INT, token.EOF}},
+		{"x \\ y", []token.Type{token.IDENT, token.ILLEGAL}},
+	}
+
+	for _, tt := range tests {
+		lexer := New(tt.input)
+		for _, expectedType := range tt.expectedTypes {
+			tok := lexer.NextToken()
+			if tok.Type != expectedType {
+				t.Logf("Expected token %s for %q, got %s(%q)\n", expectedType, tt.input, tok.Type, tok.Literal)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestLexerRanges(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedTypes []token.Type
+	}{
+		{"1..2", []token.Type{token.INT, token.DOT2, token.INT, token.EOF}},
+		{"1...2", []token.Type{token.INT, token.DOT3, token.INT, token.EOF}},
+		{"1.5..2", []token.Type{token.FLOAT, token.DOT2, token.INT, token.EOF}},
+		{"a..b.c", []token.Type{token.IDENT, token.DOT2, token.IDENT, token.DOT, token.IDENT, token.EOF}},
+		{"(..5)", []token.Type{token.LPAREN, token.DOT2, token.INT, token.RPAREN, token.EOF}},
+	}
+
+	for _, tt := range tests {
+		lexer := New(tt.input)
+		for _, expectedType := range tt.expectedTypes {
+			tok := lexer.NextToken()
+			if tok.Type != expectedType {
+				t.Logf("Expected token %s for %q, got %s(%q)\n", expectedType, tt.input, tok.Type, tok.Literal)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestLexerPercentLiterals(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedTypes   []token.Type
+		expectedLiteral string
+	}{
+		{`%q(a (b) \) c)`, []token.Type{token.STRING}, `%q(a (b) \) c)`},
+		{`%Q{x #{"}"}}`, []token.Type{token.STRING}, `%Q{x #{"}"}}`},
+		{`%|y|`, []token.Type{token.STRING}, `%|y|`},
+		{`%w[a b]`, []token.Type{token.WORDS}, `%w[a b]`},
+		{`%W<a#{1} b>`, []token.Type{token.WORDS}, `%W<a#{1} b>`},
+		{`%i(a b)`, []token.Type{token.WORDS}, `%i(a b)`},
+		{`%I(a b)`, []token.Type{token.WORDS}, `%I(a b)`},
+		{`%s(foo)`, []token.Type{token.SYMBOL}, `%s(foo)`},
+		{`%r{a/b}im`, []token.Type{token.REGEX}, `%r{a/b}im`},
+		{`x % 2`, []token.Type{token.IDENT, token.MODULO}, `%`},
+		{`x %(2)`, []token.Type{token.IDENT, token.STRING}, `%(2)`},
+		{`x = 1; x %(2)`, []token.Type{token.IDENT, token.ASSIGN, token.INT, token.SEMICOLON, token.IDENT, token.MODULO}, `%`},
+		{`x%(2)`, []token.Type{token.IDENT, token.MODULO}, `%`},
+		{`3 %(2)`, []token.Type{token.INT, token.MODULO}, `%`},
+		{`x %= 2`, []token.Type{token.IDENT, token.MODASSIGN}, `%=`},
+		{`%w(a b`, []token.Type{token.ILLEGAL}, "unterminated list meets end of file"},
+		{`%r(a`, []token.Type{token.ILLEGAL}, "unterminated regexp meets end of file"},
+		{`%q(a`, []token.Type{token.ILLEGAL}, "unterminated string meets end of file"},
+	}
+
+	for _, tt := range tests {
+		lexer := New(tt.input)
+		var tok token.Token
+		for _, expectedType := range tt.expectedTypes {
+			tok = lexer.NextToken()
+			if tok.Type != expectedType {
+				t.Logf("Expected token %s for %q, got %s(%q)\n", expectedType, tt.input, tok.Type, tok.Literal)
+				t.Fail()
+			}
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Logf("Expected literal %q for %q, got %q\n", tt.expectedLiteral, tt.input, tok.Literal)
+			t.Fail()
+		}
+	}
+}
+
+func TestLexerRegex(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedTypes   []token.Type
+		expectedLiteral string
+	}{
+		{`/ab+c/`, []token.Type{token.REGEX}, `/ab+c/`},
+		{`/a\/b/ix`, []token.Type{token.REGEX}, `/a\/b/ix`},
+		{`/a#{"/"}b/`, []token.Type{token.REGEX}, `/a#{"/"}b/`},
+		{`x = /a/`, []token.Type{token.IDENT, token.ASSIGN, token.REGEX}, `/a/`},
+		{`foo(/a/)`, []token.Type{token.IDENT, token.LPAREN, token.REGEX}, `/a/`},
+		{`puts /a/`, []token.Type{token.IDENT, token.REGEX}, `/a/`},
+		{`a = 6; x = a /2`, []token.Type{token.IDENT, token.ASSIGN, token.INT, token.SEMICOLON, token.IDENT, token.ASSIGN, token.IDENT, token.SLASH}, `/`},
+		{`a += 6; a /2`, []token.Type{token.IDENT, token.ADDASSIGN, token.INT, token.SEMICOLON, token.IDENT, token.SLASH}, `/`},
+		{`x / 2`, []token.Type{token.IDENT, token.SLASH}, `/`},
+		{`x/2`, []token.Type{token.IDENT, token.SLASH}, `/`},
+		{`x /= 2`, []token.Type{token.IDENT, token.DIVASSIGN}, `/=`},
+		{`4 /2`, []token.Type{token.INT, token.SLASH}, `/`},
+		{`a[0] / 2`, []token.Type{token.IDENT, token.LBRACKET, token.INT, token.RBRACKET, token.SLASH}, `/`},
+		{`x =~ /a/`, []token.Type{token.IDENT, token.MATCH, token.REGEX}, `/a/`},
+		{`x !~ /a/`, []token.Type{token.IDENT, token.NOTMATCH, token.REGEX}, `/a/`},
+		{`/a`, []token.Type{token.ILLEGAL}, "unterminated regexp meets end of file"},
+	}
+
+	for _, tt := range tests {
+		lexer := New(tt.input)
+		var tok token.Token
+		for _, expectedType := range tt.expectedTypes {
+			tok = lexer.NextToken()
+			if tok.Type != expectedType {
+				t.Logf("Expected token %s for %q, got %s(%q)\n", expectedType, tt.input, tok.Type, tok.Literal)
+				t.Fail()
+			}
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Logf("Expected literal %q for %q, got %q\n", tt.expectedLiteral, tt.input, tok.Literal)
+			t.Fail()
+		}
+	}
+}
+
+func TestLexerGlobals(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedLiteral string
+	}{
+		{`$foo`, "$foo"},
+		{`$~`, "$~"},
+		{`$1)`, "$1"},
+		{`$12.to_s`, "$12"},
+		{`$&`, "$&"},
+		{"$`", "$`"},
+		{`$'`, "$'"},
+		{`$!.message`, "$!"},
+	}
+
+	for _, tt := range tests {
+		lexer := New(tt.input)
+
+		tok := lexer.NextToken()
+
+		if tok.Type != token.GLOBAL || tok.Literal != tt.expectedLiteral {
+			t.Logf("Expected GLOBAL %q for %q, got %s(%q)\n", tt.expectedLiteral, tt.input, tok.Type, tok.Literal)
+			t.Fail()
+		}
+	}
+}
+
+func TestLexerHeredocs(t *testing.T) {
+	input := "foo(<<A, <<-'B') # c\nbody a\nA\n  body b\n  B\nx <<~C\n  c\nC\n1 << 2\n"
+	file := gotoken.NewFileSet().AddFile("test.rb", -1, len(input))
+
+	lexer := NewWithFile(file, input)
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+		expectedPos     string
+	}{
+		{token.IDENT, "foo", "test.rb:1:1"},
+		{token.LPAREN, "(", "test.rb:1:4"},
+		{token.HEREDOC, "<<A", "test.rb:1:5"},
+		{token.STRING, "body a\n", "test.rb:2:1"},
+		{token.COMMA, ",", "test.rb:1:8"},
+		{token.HEREDOC, "<<-'B'", "test.rb:1:10"},
+		{token.STRING, "  body b\n", "test.rb:4:1"},
+		{token.RPAREN, ")", "test.rb:1:16"},
+		{token.HASH, "#", "test.rb:1:18"},
+		{token.STRING, " c", "test.rb:1:19"},
+		{token.NEWLINE, "\n", "test.rb:1:21"},
+		{token.IDENT, "x", "test.rb:6:1"},
+		{token.HEREDOC, "<<~C", "test.rb:6:3"},
+		{token.STRING, "  c\n", "test.rb:7:1"},
+		{token.NEWLINE, "\n", "test.rb:6:7"},
+		{token.INT, "1", "test.rb:9:1"},
+		{token.LSHIFT, "<<", "test.rb:9:3"},
+		{token.INT, "2", "test.rb:9:6"},
+		{token.NEWLINE, "\n", "test.rb:9:7"},
+		{token.EOF, "", "test.rb:9:8"},
+	}
+
+	for _, tt := range tests {
+		tok := lexer.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Logf("Expected token %s(%q), got %s(%q)\n", tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+			t.Fail()
+		}
+
+		pos := file.Position(file.Pos(tok.Pos)).String()
+		if pos != tt.expectedPos {
+			t.Logf("Expected token %s(%q) at %s, got %s\n", tok.Type, tok.Literal, tt.expectedPos, pos)
+			t.Fail()
+		}
+	}
+
+	t.Run("missing terminator", func(t *testing.T) {
+		lexer := New("x = <<EOS\nfoo\n EOS\n")
+
+		lexer.NextToken()
+		lexer.NextToken()
+		tok := lexer.NextToken()
+
+		if tok.Type != token.ILLEGAL || tok.Literal != `can't find string "EOS" anywhere before EOF` || tok.Pos != 4 {
+			t.Logf("Expected ILLEGAL token, got %s(%q)\n", tok.Type, tok.Literal)
+			t.Fail()
+		}
+	})
+}
+
+func TestLexerIntegerRadixes(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{"0xaa", token.INT, "0xaa"},
+		{"0XaA", token.INT, "0XaA"},
+		{"0252", token.INT, "0252"},
+		{"0o252", token.INT, "0o252"},
+		{"0O2_52", token.INT, "0O2_52"},
+		{"0b1010_1010", token.INT, "0b1010_1010"},
+		{"0B10101010", token.INT, "0B10101010"},
+		{"0d170", token.INT, "0d170"},
+		{"0D1_70", token.INT, "0D1_70"},
+		{"0", token.INT, "0"},
+		{"0.5", token.FLOAT, "0.5"},
+		{"0b102", token.ILLEGAL, "Invalid binary digit: '2'"},
+		{"0o8", token.ILLEGAL, "Invalid octal digit: '8'"},
+		{"089", token.ILLEGAL, "Invalid octal digit: '8'"},
+		{"0xfg", token.ILLEGAL, "Invalid hexadecimal digit: 'g'"},
+		{"0d1a", token.ILLEGAL, "Invalid decimal digit: 'a'"},
+		{"0x", token.ILLEGAL, "numeric literal without digits"},
+		{"0b_", token.ILLEGAL, "numeric literal without digits"},
+		{"0x_a", token.ILLEGAL, "numeric literal without digits"},
+		{"0b_1", token.ILLEGAL, "numeric literal without digits"},
+		{"0o_7", token.ILLEGAL, "numeric literal without digits"},
+		{"1e_5", token.ILLEGAL, "numeric literal without digits"},
+		{"1.5e-_5", token.ILLEGAL, "numeric literal without digits"},
+		{"0_7", token.INT, "0_7"},
+		{"1_000", token.INT, "1_000"},
+		{"1_0.2_5e1_0", token.FLOAT, "1_0.2_5e1_0"},
+		{"1__2", token.ILLEGAL, "trailing '_' in number"},
+		{"12_", token.ILLEGAL, "trailing '_' in number"},
+		{"1.5_", token.ILLEGAL, "trailing '_' in number"},
+		{"1.5__0", token.ILLEGAL, "trailing '_' in number"},
+		{"2e1_", token.ILLEGAL, "trailing '_' in number"},
+		{"0x1__f", token.ILLEGAL, "trailing '_' in number"},
+		{"0b1_", token.ILLEGAL, "trailing '_' in number"},
+	}
+
+	for _, tt := range tests {
+		tok := New(tt.input).NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Logf("Expected token %s(%q) for %q, got %s(%q)\n", tt.expectedType, tt.expectedLiteral, tt.input, tok.Type, tok.Literal)
+			t.Fail()
+		}
+	}
+}
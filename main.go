@@ -9,7 +9,7 @@ import (
 	"strings"
 
 	"github.com/goruby/goruby/interpreter"
-	"github.com/pkg/errors"
+	"github.com/goruby/goruby/object"
 )
 
 type multiString []string
@@ -34,9 +34,9 @@ func main() {
 	interpreter := interpreter.New()
 	if len(onelineScripts) != 0 {
 		input := strings.Join(onelineScripts, "\n")
-		_, err := interpreter.Interpret("", input)
+		_, err := interpreter.Interpret("-e", input)
 		if err != nil {
-			fmt.Printf("%v\n", errors.Cause(err))
+			fmt.Fprintln(os.Stderr, object.FullMessage(err))
 			os.Exit(1)
 		}
 		return
@@ -53,7 +53,7 @@ func main() {
 	}
 	_, err = interpreter.Interpret(args[0], fileBytes)
 	if err != nil {
-		fmt.Printf("%v\n", errors.Cause(err))
+		fmt.Fprintln(os.Stderr, object.FullMessage(err))
 		os.Exit(1)
 	}
 	return
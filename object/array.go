@@ -1,17 +1,20 @@
 package object
 
 import (
+	"fmt"
 	"hash/fnv"
+	"math"
+	"sort"
 	"strings"
 )
 
-var arrayClass RubyClassObject = newClass(
+var arrayClass RubyClassObject = newMixin(newClass(
 	"Array",
 	objectClass,
 	arrayMethods,
 	arrayClassMethods,
-	func(c RubyClassObject, args ...RubyObject) (RubyObject, error) { return NewArray(args...), nil },
-)
+	func(c RubyClassObject, args ...RubyObject) (RubyObject, error) { return NewArray(), nil },
+), enumerableModule)
 
 func init() {
 	classes.Set("Array", arrayClass)
@@ -54,11 +57,277 @@ func (a *Array) hashKey() hashKey {
 	return hashKey{Type: a.Type(), Value: h.Sum64()}
 }
 
+// uniqueElements returns the elements of a without duplicates, keeping the
+// first occurrence. Elements are identical if their hash keys are.
+func uniqueElements(elements []RubyObject) []RubyObject {
+	seen := make(map[hashKey]bool)
+	unique := []RubyObject{}
+	for _, element := range elements {
+		key := hash(element)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, element)
+	}
+	return unique
+}
+
+// isEqual reports whether a == b when called within context
+func isEqual(context CallContext, a, b RubyObject) (bool, error) {
+	equal, err := Send(&callContext{receiver: a, env: context.Env(), eval: context.Eval}, "==", b)
+	if err != nil {
+		return false, err
+	}
+	return equal != NIL && equal != FALSE, nil
+}
+
+// sortCompare compares a and b with the block if given and with `<=>`
+// otherwise. Like MRI it returns an ArgumentError if a and b are not
+// comparable.
+func sortCompare(context CallContext, block *Proc, a, b RubyObject) (int, error) {
+	if block == nil {
+		cmp, ok, err := compare(context, a, b)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, newComparisonError(a, b)
+		}
+		return cmp, nil
+	}
+	result, err := block.Call(context, a, b)
+	if err != nil {
+		return 0, err
+	}
+	cmp, ok := result.(*Integer)
+	if !ok {
+		return 0, newComparisonError(a, b)
+	}
+	return cmp.BigInt().Sign(), nil
+}
+
+func newComparisonError(a, b RubyObject) *ArgumentError {
+	return NewArgumentError(
+		"comparison of %s with %s failed",
+		a.Class().(RubyObject).Inspect(),
+		b.Class().(RubyObject).Inspect(),
+	)
+}
+
+// sortElements sorts elements in place using sortCompare. As sort.SliceStable
+// asks whether a later element is less than an earlier one, the arguments are
+// swapped to compare elements in their order, which errors report as in MRI.
+func sortElements(context CallContext, block *Proc, elements []RubyObject) error {
+	var err error
+	sort.SliceStable(elements, func(i, j int) bool {
+		if err != nil {
+			return false
+		}
+		var cmp int
+		cmp, err = sortCompare(context, block, elements[j], elements[i])
+		return cmp > 0
+	})
+	return err
+}
+
+// flatten appends the elements of elements to flat, flattening nested
+// arrays up to depth levels. A negative depth flattens all levels. It
+// returns an ArgumentError for arrays containing themselves.
+func flatten(flat []RubyObject, elements []RubyObject, depth int, seen []*Array) ([]RubyObject, error) {
+	for _, element := range elements {
+		nested, ok := element.(*Array)
+		if !ok || depth == 0 {
+			flat = append(flat, element)
+			continue
+		}
+		for _, array := range seen {
+			if array == nested {
+				return nil, NewArgumentError("tried to flatten recursive array")
+			}
+		}
+		var err error
+		flat, err = flatten(flat, nested.Elements, depth-1, append(seen, nested))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return flat, nil
+}
+
+// join converts the elements of array into strings and joins them with sep.
+// Nested arrays are joined recursively.
+func join(context CallContext, array *Array, sep string, seen []*Array) (string, error) {
+	for _, other := range seen {
+		if other == array {
+			return "", NewArgumentError("recursive array join")
+		}
+	}
+	parts := make([]string, len(array.Elements))
+	for i, element := range array.Elements {
+		var err error
+		if nested, ok := element.(*Array); ok {
+			parts[i], err = join(context, nested, sep, append(seen, array))
+		} else {
+			parts[i], err = convertToString(context, element)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(parts, sep), nil
+}
+
+// arrayCount returns the count argument of methods like Array#first and
+// Array#pop
+func arrayCount(arg RubyObject) (int, error) {
+	count, ok := arg.(*Integer)
+	if !ok {
+		return 0, NewImplicitConversionTypeError(count, arg)
+	}
+	if count.Value < 0 {
+		return 0, NewArgumentError("negative array size")
+	}
+	if count.Value > math.MaxInt32 {
+		return math.MaxInt32, nil
+	}
+	return int(count.Value), nil
+}
+
+// randomArgument returns the generator given with the `random:` option of
+// Array#shuffle and Array#sample, or the default generator
+func randomArgument(args []RubyObject) (*Random, []RubyObject, error) {
+	if len(args) == 0 {
+		return defaultRandom, args, nil
+	}
+	options, ok := args[len(args)-1].(*Hash)
+	if !ok {
+		return defaultRandom, args, nil
+	}
+	args = args[:len(args)-1]
+	random, ok := options.Get(NewSymbol("random"))
+	if !ok {
+		return defaultRandom, args, nil
+	}
+	generator, ok := random.(*Random)
+	if !ok {
+		return nil, nil, NewWrongArgumentTypeError(&Random{}, random)
+	}
+	return generator, args, nil
+}
+
 var arrayClassMethods = map[string]RubyMethod{}
 
 var arrayMethods = map[string]RubyMethod{
-	"push":    publicMethod(arrayPush),
-	"unshift": publicMethod(arrayUnshift),
+	"initialize":      privateMethod(arrayInitialize),
+	"push":            publicMethod(arrayPush),
+	"append":          publicMethod(arrayPush),
+	"<<":              withArity(1, publicMethod(arrayPush)),
+	"unshift":         publicMethod(arrayUnshift),
+	"prepend":         publicMethod(arrayUnshift),
+	"pop":             publicMethod(arrayPop),
+	"shift":           publicMethod(arrayShift),
+	"concat":          publicMethod(arrayConcat),
+	"insert":          publicMethod(arrayInsert),
+	"delete":          publicMethod(arrayDelete),
+	"delete_at":       withArity(1, publicMethod(arrayDeleteAt)),
+	"clear":           withArity(0, publicMethod(arrayClear)),
+	"[]":              publicMethod(arrayIndex),
+	"slice":           publicMethod(arrayIndex),
+	"[]=":             publicMethod(arraySetIndex),
+	"at":              withArity(1, publicMethod(arrayAt)),
+	"fetch":           publicMethod(arrayFetch),
+	"dig":             publicMethod(arrayDig),
+	"first":           publicMethod(arrayFirst),
+	"last":            publicMethod(arrayLast),
+	"length":          withArity(0, publicMethod(arrayLength)),
+	"size":            withArity(0, publicMethod(arrayLength)),
+	"empty?":          withArity(0, publicMethod(arrayIsEmpty)),
+	"each":            publicMethod(arrayEach),
+	"each_with_index": publicMethod(arrayEachWithIndex),
+	"map":             publicMethod(arrayMap),
+	"collect":         publicMethod(arrayMap),
+	"select":          publicMethod(arraySelect),
+	"filter":          publicMethod(arraySelect),
+	"reject":          publicMethod(arrayReject),
+	"find":            publicMethod(arrayFind),
+	"detect":          publicMethod(arrayFind),
+	"include?":        withArity(1, publicMethod(arrayInclude)),
+	"index":           publicMethod(arrayIndexOf),
+	"find_index":      publicMethod(arrayIndexOf),
+	"sort":            publicMethod(arraySort),
+	"sort_by":         publicMethod(arraySortBy),
+	"uniq":            publicMethod(arrayUniq),
+	"flatten":         publicMethod(arrayFlatten),
+	"compact":         withArity(0, publicMethod(arrayCompact)),
+	"zip":             publicMethod(arrayZip),
+	"join":            publicMethod(arrayJoin),
+	"reverse":         withArity(0, publicMethod(arrayReverse)),
+	"rotate":          publicMethod(arrayRotate),
+	"shuffle":         publicMethod(arrayShuffle),
+	"sample":          publicMethod(arraySample),
+	"+":               withArity(1, publicMethod(arrayAdd)),
+	"-":               withArity(1, publicMethod(arraySub)),
+	"&":               withArity(1, publicMethod(arrayIntersection)),
+	"|":               withArity(1, publicMethod(arrayUnion)),
+	"*":               withArity(1, publicMethod(arrayMul)),
+	"==":              withArity(1, publicMethod(arrayEqual)),
+	"<=>":             withArity(1, publicMethod(arraySpaceship)),
+	"pack":            withArity(1, publicMethod(arrayPack)),
+	"sum":             publicMethod(arraySum),
+	"min":             publicMethod(arrayMin),
+	"max":             publicMethod(arrayMax),
+	"to_a":            withArity(0, publicMethod(arrayToA)),
+	"entries":         withArity(0, publicMethod(arrayToA)),
+	"to_s":            withArity(0, publicMethod(arrayInspect)),
+	"inspect":         withArity(0, publicMethod(arrayInspect)),
+}
+
+// arrayInitialize populates a new array with size copies of a default
+// object, with the results of the block called with every index, or with the
+// elements of another array
+func arrayInitialize(context CallContext, args ...RubyObject) (RubyObject, error) {
+	self, _ := context.Receiver().(*Self)
+	block, args, hasBlock := extractBlockFromArgs(args)
+	if len(args) > 2 {
+		return nil, NewWrongNumberOfArgumentsError(2, len(args))
+	}
+	array := NewArray()
+	self.RubyObject = array
+	if len(args) == 0 {
+		return self, nil
+	}
+	if other, ok := args[0].(*Array); ok && len(args) == 1 {
+		array.Elements = append(array.Elements, other.Elements...)
+		return self, nil
+	}
+	size, ok := args[0].(*Integer)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(size, args[0])
+	}
+	if size.Value < 0 {
+		return nil, NewArgumentError("negative array size")
+	}
+	var value RubyObject = NIL
+	if len(args) == 2 {
+		value = args[1]
+	}
+	for i := int64(0); i < size.Value; i++ {
+		if hasBlock {
+			var err error
+			value, err = block.Call(context, NewInteger(i))
+			if err != nil {
+				return nil, err
+			}
+		}
+		array.Elements = append(array.Elements, value)
+	}
+	return self, nil
+}
+
+func arrayInspect(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	return &String{Value: array.Inspect()}, nil
 }
 
 func arrayPush(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -72,3 +341,1162 @@ func arrayUnshift(context CallContext, args ...RubyObject) (RubyObject, error) {
 	array.Elements = append(args, array.Elements...)
 	return array, nil
 }
+
+// arrayPop removes the last element, or the last n elements as Array
+func arrayPop(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	switch len(args) {
+	case 0:
+		if len(array.Elements) == 0 {
+			return NIL, nil
+		}
+		last := array.Elements[len(array.Elements)-1]
+		array.Elements = array.Elements[:len(array.Elements)-1]
+		return last, nil
+	case 1:
+		count, err := arrayCount(args[0])
+		if err != nil {
+			return nil, err
+		}
+		start := len(array.Elements) - count
+		if start < 0 {
+			start = 0
+		}
+		popped := NewArray(array.Elements[start:]...)
+		array.Elements = array.Elements[:start]
+		return popped, nil
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+}
+
+// arrayShift removes the first element, or the first n elements as Array
+func arrayShift(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	switch len(args) {
+	case 0:
+		if len(array.Elements) == 0 {
+			return NIL, nil
+		}
+		first := array.Elements[0]
+		array.Elements = array.Elements[1:]
+		return first, nil
+	case 1:
+		count, err := arrayCount(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if count > len(array.Elements) {
+			count = len(array.Elements)
+		}
+		shifted := NewArray(array.Elements[:count]...)
+		array.Elements = array.Elements[count:]
+		return shifted, nil
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+}
+
+func arrayConcat(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	var elements []RubyObject
+	for _, arg := range args {
+		other, ok := arg.(*Array)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(other, arg)
+		}
+		elements = append(elements, other.Elements...)
+	}
+	array.Elements = append(array.Elements, elements...)
+	return array, nil
+}
+
+// arrayInsert inserts the objects before the element at the given index.
+// Negative indices count from the end and insert after the element.
+func arrayInsert(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	if len(args) < 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	index, ok := args[0].(*Integer)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(index, args[0])
+	}
+	if len(args) == 1 {
+		return array, nil
+	}
+	position := index.Value
+	if position < 0 {
+		position += int64(len(array.Elements)) + 1
+		if position < 0 {
+			return nil, NewIndexError(
+				"index %d too small for array; minimum: -%d", index.Value, len(array.Elements)+1,
+			)
+		}
+	}
+	for int64(len(array.Elements)) < position {
+		array.Elements = append(array.Elements, NIL)
+	}
+	elements := append([]RubyObject{}, array.Elements[:position]...)
+	elements = append(elements, args[1:]...)
+	array.Elements = append(elements, array.Elements[position:]...)
+	return array, nil
+}
+
+// arrayDelete removes all elements equal to the argument and returns the
+// last one removed. If none is found it returns nil or the result of the
+// block.
+func arrayDelete(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	block, args, hasBlock := extractBlockFromArgs(args)
+	if len(args) != 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	var deleted RubyObject
+	kept := []RubyObject{}
+	for _, element := range array.Elements {
+		equal, err := isEqual(context, element, args[0])
+		if err != nil {
+			return nil, err
+		}
+		if equal {
+			deleted = element
+			continue
+		}
+		kept = append(kept, element)
+	}
+	array.Elements = kept
+	if deleted != nil {
+		return deleted, nil
+	}
+	if hasBlock {
+		return block.Call(context, args[0])
+	}
+	return NIL, nil
+}
+
+func arrayDeleteAt(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	index, ok := args[0].(*Integer)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(index, args[0])
+	}
+	i, ok := normalizeIndex(index.Value, len(array.Elements))
+	if !ok || i == len(array.Elements) {
+		return NIL, nil
+	}
+	deleted := array.Elements[i]
+	array.Elements = append(array.Elements[:i:i], array.Elements[i+1:]...)
+	return deleted, nil
+}
+
+func arrayClear(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	array.Elements = []RubyObject{}
+	return array, nil
+}
+
+// arrayIndex returns the element at the given index, or the slice given by
+// a start index and a length or by a range
+func arrayIndex(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	var start, length int
+	switch len(args) {
+	case 1:
+		switch arg := args[0].(type) {
+		case *Integer:
+			index, ok := normalizeIndex(arg.Value, len(array.Elements))
+			if !ok || index == len(array.Elements) {
+				return NIL, nil
+			}
+			return array.Elements[index], nil
+		case *Range:
+			var ok bool
+			var err error
+			start, length, ok, err = arg.indices(len(array.Elements))
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return NIL, nil
+			}
+		default:
+			return nil, NewImplicitConversionTypeError(&Integer{}, arg)
+		}
+	case 2:
+		index, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(index, args[0])
+		}
+		count, ok := args[1].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(count, args[1])
+		}
+		start, ok = normalizeIndex(index.Value, len(array.Elements))
+		if !ok || count.Value < 0 {
+			return NIL, nil
+		}
+		length = len(array.Elements) - start
+		if count.Value < int64(length) {
+			length = int(count.Value)
+		}
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	return NewArray(array.Elements[start : start+length]...), nil
+}
+
+// arraySetIndex sets the element at the given index, or replaces the slice
+// given by a start index and a length or by a range with the value. Arrays
+// grow as needed and are padded with nil.
+func arraySetIndex(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	size := int64(len(array.Elements))
+	var start, length int64
+	switch len(args) {
+	case 2:
+		switch arg := args[0].(type) {
+		case *Integer:
+			index := arg.Value
+			if index < 0 {
+				index += size
+				if index < 0 {
+					return nil, NewIndexError("index %d too small for array; minimum: -%d", arg.Value, size)
+				}
+			}
+			for int64(len(array.Elements)) <= index {
+				array.Elements = append(array.Elements, NIL)
+			}
+			array.Elements[index] = args[1]
+			return args[1], nil
+		case *Range:
+			begin, end := int64(0), int64(-1)
+			if arg.Begin != NIL {
+				first, ok := arg.Begin.(*Integer)
+				if !ok {
+					return nil, NewImplicitConversionTypeError(first, arg.Begin)
+				}
+				begin = first.Value
+			}
+			exclusive := arg.Exclusive
+			if arg.End != NIL {
+				last, ok := arg.End.(*Integer)
+				if !ok {
+					return nil, NewImplicitConversionTypeError(last, arg.End)
+				}
+				end = last.Value
+			} else {
+				exclusive = false
+			}
+			start = begin
+			if start < 0 {
+				start += size
+				if start < 0 {
+					return nil, NewRangeError("%s out of range", arg.Inspect())
+				}
+			}
+			if end < 0 {
+				end += size
+			}
+			if !exclusive {
+				end++
+			}
+			length = end - start
+			if length < 0 {
+				length = 0
+			}
+		default:
+			return nil, NewImplicitConversionTypeError(&Integer{}, arg)
+		}
+	case 3:
+		index, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(index, args[0])
+		}
+		count, ok := args[1].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(count, args[1])
+		}
+		if count.Value < 0 {
+			return nil, NewIndexError("negative length (%d)", count.Value)
+		}
+		start = index.Value
+		if start < 0 {
+			start += size
+			if start < 0 {
+				return nil, NewIndexError("index %d too small for array; minimum: -%d", index.Value, size)
+			}
+		}
+		length = count.Value
+	default:
+		return nil, NewWrongNumberOfArgumentsError(2, len(args))
+	}
+	value := args[len(args)-1]
+	replacement := []RubyObject{value}
+	if other, ok := value.(*Array); ok {
+		replacement = other.Elements
+	}
+	for int64(len(array.Elements)) < start {
+		array.Elements = append(array.Elements, NIL)
+	}
+	if start+length > int64(len(array.Elements)) {
+		length = int64(len(array.Elements)) - start
+	}
+	elements := append([]RubyObject{}, array.Elements[:start]...)
+	elements = append(elements, replacement...)
+	array.Elements = append(elements, array.Elements[start+length:]...)
+	return value, nil
+}
+
+func arrayAt(context CallContext, args ...RubyObject) (RubyObject, error) {
+	index, ok := args[0].(*Integer)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(index, args[0])
+	}
+	return arrayIndex(context, index)
+}
+
+// arrayFetch returns the element at the given index. For indices outside of
+// the array it returns the default value or the result of the block, or
+// an IndexError if neither is given.
+func arrayFetch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	block, args, hasBlock := extractBlockFromArgs(args)
+	if len(args) < 1 || len(args) > 2 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	index, ok := args[0].(*Integer)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(index, args[0])
+	}
+	i, ok := normalizeIndex(index.Value, len(array.Elements))
+	if ok && i < len(array.Elements) {
+		return array.Elements[i], nil
+	}
+	if hasBlock {
+		return block.Call(context, index)
+	}
+	if len(args) == 2 {
+		return args[1], nil
+	}
+	return nil, NewIndexError(
+		"index %d outside of array bounds: %d...%d", index.Value, -len(array.Elements), len(array.Elements),
+	)
+}
+
+// arrayDig extracts the nested value given by the sequence of indices by
+// calling dig on every intermediate value
+func arrayDig(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if len(args) < 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	value, err := arrayIndex(context, args[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 1 || value == NIL {
+		return value, nil
+	}
+	if !respondTo(value, "dig") {
+		return nil, NewTypeError(fmt.Sprintf("%s does not have #dig method", value.Class().Name()))
+	}
+	return Send(&callContext{receiver: value, env: context.Env(), eval: context.Eval}, "dig", args[1:]...)
+}
+
+func arrayFirst(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	switch len(args) {
+	case 0:
+		if len(array.Elements) == 0 {
+			return NIL, nil
+		}
+		return array.Elements[0], nil
+	case 1:
+		count, err := arrayCount(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if count > len(array.Elements) {
+			count = len(array.Elements)
+		}
+		return NewArray(array.Elements[:count]...), nil
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+}
+
+func arrayLast(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	switch len(args) {
+	case 0:
+		if len(array.Elements) == 0 {
+			return NIL, nil
+		}
+		return array.Elements[len(array.Elements)-1], nil
+	case 1:
+		count, err := arrayCount(args[0])
+		if err != nil {
+			return nil, err
+		}
+		start := len(array.Elements) - count
+		if start < 0 {
+			start = 0
+		}
+		return NewArray(array.Elements[start:]...), nil
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+}
+
+func arrayLength(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return NewInteger(int64(len(context.Receiver().(*Array).Elements))), nil
+}
+
+func arrayIsEmpty(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return nativeBoolToBooleanObject(len(context.Receiver().(*Array).Elements) == 0), nil
+}
+
+// arrayIteration returns the block given to an iterating method without
+// further arguments
+func arrayIteration(args []RubyObject) (*Proc, error) {
+	block, args, ok := extractBlockFromArgs(args)
+	if !ok {
+		return nil, NewNoBlockGivenLocalJumpError()
+	}
+	if len(args) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(args))
+	}
+	return block, nil
+}
+
+func arrayEach(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	block, err := arrayIteration(args)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(array.Elements); i++ {
+		if _, err := block.Call(context, array.Elements[i]); err != nil {
+			return nil, err
+		}
+	}
+	return array, nil
+}
+
+func arrayEachWithIndex(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	if len(args) == 0 {
+		return newEnumerator(context, "each_with_index"), nil
+	}
+	block, err := arrayIteration(args)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(array.Elements); i++ {
+		if _, err := block.Call(context, array.Elements[i], NewInteger(int64(i))); err != nil {
+			return nil, err
+		}
+	}
+	return array, nil
+}
+
+func arrayMap(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	block, err := arrayIteration(args)
+	if err != nil {
+		return nil, err
+	}
+	mapped := NewArray()
+	for i := 0; i < len(array.Elements); i++ {
+		result, err := block.Call(context, array.Elements[i])
+		if err != nil {
+			return nil, err
+		}
+		mapped.Elements = append(mapped.Elements, result)
+	}
+	return mapped, nil
+}
+
+// arrayFilter returns the elements for which the block returns a truthy
+// value, or a falsey value if reject is true
+func arrayFilter(context CallContext, args []RubyObject, reject bool) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	block, err := arrayIteration(args)
+	if err != nil {
+		return nil, err
+	}
+	filtered := NewArray()
+	for i := 0; i < len(array.Elements); i++ {
+		result, err := block.Call(context, array.Elements[i])
+		if err != nil {
+			return nil, err
+		}
+		if (result != NIL && result != FALSE) != reject {
+			filtered.Elements = append(filtered.Elements, array.Elements[i])
+		}
+	}
+	return filtered, nil
+}
+
+func arraySelect(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return arrayFilter(context, args, false)
+}
+
+func arrayReject(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return arrayFilter(context, args, true)
+}
+
+func arrayFind(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	block, err := arrayIteration(args)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(array.Elements); i++ {
+		result, err := block.Call(context, array.Elements[i])
+		if err != nil {
+			return nil, err
+		}
+		if result != NIL && result != FALSE {
+			return array.Elements[i], nil
+		}
+	}
+	return NIL, nil
+}
+
+func arrayInclude(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	for _, element := range array.Elements {
+		equal, err := isEqual(context, element, args[0])
+		if err != nil {
+			return nil, err
+		}
+		if equal {
+			return TRUE, nil
+		}
+	}
+	return FALSE, nil
+}
+
+// arrayIndexOf returns the index of the first element equal to the argument
+// or, if a block is given, of the first element the block returns a truthy
+// value for
+func arrayIndexOf(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	block, args, hasBlock := extractBlockFromArgs(args)
+	if len(args) > 1 || len(args) == 0 && !hasBlock {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	for i := 0; i < len(array.Elements); i++ {
+		var found bool
+		if len(args) == 1 {
+			equal, err := isEqual(context, array.Elements[i], args[0])
+			if err != nil {
+				return nil, err
+			}
+			found = equal
+		} else {
+			result, err := block.Call(context, array.Elements[i])
+			if err != nil {
+				return nil, err
+			}
+			found = result != NIL && result != FALSE
+		}
+		if found {
+			return NewInteger(int64(i)), nil
+		}
+	}
+	return NIL, nil
+}
+
+func arraySort(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	block, args, _ := extractBlockFromArgs(args)
+	if len(args) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(args))
+	}
+	sorted := NewArray(array.Elements...)
+	if err := sortElements(context, block, sorted.Elements); err != nil {
+		return nil, err
+	}
+	return sorted, nil
+}
+
+// arraySortBy sorts the elements by the keys the block returns for them
+func arraySortBy(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	block, err := arrayIteration(args)
+	if err != nil {
+		return nil, err
+	}
+	type keyedElement struct {
+		key     RubyObject
+		element RubyObject
+	}
+	keyed := make([]keyedElement, len(array.Elements))
+	for i, element := range array.Elements {
+		key, err := block.Call(context, element)
+		if err != nil {
+			return nil, err
+		}
+		keyed[i] = keyedElement{key: key, element: element}
+	}
+	sort.SliceStable(keyed, func(i, j int) bool {
+		if err != nil {
+			return false
+		}
+		var cmp int
+		cmp, err = sortCompare(context, nil, keyed[i].key, keyed[j].key)
+		return cmp < 0
+	})
+	if err != nil {
+		return nil, err
+	}
+	sorted := NewArray()
+	for _, k := range keyed {
+		sorted.Elements = append(sorted.Elements, k.element)
+	}
+	return sorted, nil
+}
+
+// arrayUniq removes duplicate elements, or elements for which the block
+// returns duplicate values
+func arrayUniq(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	block, args, hasBlock := extractBlockFromArgs(args)
+	if len(args) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(args))
+	}
+	if !hasBlock {
+		return NewArray(uniqueElements(array.Elements)...), nil
+	}
+	seen := make(map[hashKey]bool)
+	unique := NewArray()
+	for _, element := range array.Elements {
+		value, err := block.Call(context, element)
+		if err != nil {
+			return nil, err
+		}
+		key := hash(value)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique.Elements = append(unique.Elements, element)
+	}
+	return unique, nil
+}
+
+func arrayFlatten(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	depth := -1
+	switch len(args) {
+	case 0:
+	case 1:
+		level, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(level, args[0])
+		}
+		depth = int(level.Value)
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	flat, err := flatten([]RubyObject{}, array.Elements, depth, []*Array{array})
+	if err != nil {
+		return nil, err
+	}
+	return NewArray(flat...), nil
+}
+
+func arrayCompact(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	compacted := NewArray()
+	for _, element := range array.Elements {
+		if element != NIL {
+			compacted.Elements = append(compacted.Elements, element)
+		}
+	}
+	return compacted, nil
+}
+
+// arrayZip merges the elements of the receiver with the elements at the
+// same index of every argument. With a block every tuple is yielded and nil
+// is returned.
+// zipValues returns the values of arg to zip with n elements. Arrays are
+// used as they are, any other object is iterated with each until n values
+// are taken, so that endless enumerables can be zipped as well.
+func zipValues(context CallContext, arg RubyObject, n int) ([]RubyObject, error) {
+	if other, ok := arg.(*Array); ok {
+		return other.Elements, nil
+	}
+	if !respondTo(arg, "each") {
+		return nil, NewTypeError(
+			fmt.Sprintf("wrong argument type %s (must respond to :each)", arg.Class().Name()),
+		)
+	}
+	var values []RubyObject
+	if n == 0 {
+		return values, nil
+	}
+	err := enumerate(
+		&callContext{receiver: arg, env: context.Env(), eval: context.Eval},
+		func(value RubyObject) (bool, error) {
+			values = append(values, value)
+			return len(values) < n, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func arrayZip(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	block, args, hasBlock := extractBlockFromArgs(args)
+	others := make([][]RubyObject, len(args))
+	for i, arg := range args {
+		other, err := zipValues(context, arg, len(array.Elements))
+		if err != nil {
+			return nil, err
+		}
+		others[i] = other
+	}
+	zipped := NewArray()
+	for i, element := range array.Elements {
+		tuple := NewArray(element)
+		for _, other := range others {
+			if i < len(other) {
+				tuple.Elements = append(tuple.Elements, other[i])
+			} else {
+				tuple.Elements = append(tuple.Elements, NIL)
+			}
+		}
+		if hasBlock {
+			if _, err := block.Call(context, tuple); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		zipped.Elements = append(zipped.Elements, tuple)
+	}
+	if hasBlock {
+		return NIL, nil
+	}
+	return zipped, nil
+}
+
+func arrayJoin(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	var sep string
+	switch len(args) {
+	case 0:
+	case 1:
+		switch arg := args[0].(type) {
+		case *String:
+			sep = arg.Value
+		default:
+			if arg != NIL {
+				return nil, NewImplicitConversionTypeError(&String{}, arg)
+			}
+		}
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	joined, err := join(context, array, sep, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &String{Value: joined}, nil
+}
+
+func arrayReverse(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	reversed := NewArray()
+	for i := len(array.Elements) - 1; i >= 0; i-- {
+		reversed.Elements = append(reversed.Elements, array.Elements[i])
+	}
+	return reversed, nil
+}
+
+// arrayRotate returns a new array with the element at the given index, 1 by
+// default, as its first element
+func arrayRotate(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	count := int64(1)
+	switch len(args) {
+	case 0:
+	case 1:
+		n, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(n, args[0])
+		}
+		count = n.Value
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	if len(array.Elements) == 0 {
+		return NewArray(), nil
+	}
+	start := int(count % int64(len(array.Elements)))
+	if start < 0 {
+		start += len(array.Elements)
+	}
+	rotated := NewArray(array.Elements[start:]...)
+	rotated.Elements = append(rotated.Elements, array.Elements[:start]...)
+	return rotated, nil
+}
+
+// arrayShuffle returns the elements in random order. A generator can be
+// given with the `random:` option to get a reproducible order.
+func arrayShuffle(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	random, args, err := randomArgument(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(args))
+	}
+	shuffled := NewArray(array.Elements...)
+	for i := len(shuffled.Elements); i > 1; i-- {
+		j := random.intn(i)
+		shuffled.Elements[i-1], shuffled.Elements[j] = shuffled.Elements[j], shuffled.Elements[i-1]
+	}
+	return shuffled, nil
+}
+
+// arraySample returns a random element, or n distinct random elements as
+// Array. A generator can be given with the `random:` option.
+func arraySample(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	random, args, err := randomArgument(args)
+	if err != nil {
+		return nil, err
+	}
+	switch len(args) {
+	case 0:
+		if len(array.Elements) == 0 {
+			return NIL, nil
+		}
+		return array.Elements[random.intn(len(array.Elements))], nil
+	case 1:
+		count, err := arrayCount(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if count > len(array.Elements) {
+			count = len(array.Elements)
+		}
+		elements := append([]RubyObject{}, array.Elements...)
+		for i := 0; i < count; i++ {
+			j := i + random.intn(len(elements)-i)
+			elements[i], elements[j] = elements[j], elements[i]
+		}
+		return NewArray(elements[:count]...), nil
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+}
+
+func arrayAdd(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	other, ok := args[0].(*Array)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(other, args[0])
+	}
+	sum := NewArray(array.Elements...)
+	sum.Elements = append(sum.Elements, other.Elements...)
+	return sum, nil
+}
+
+// arraySub returns the elements not contained in the argument
+func arraySub(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	other, ok := args[0].(*Array)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(other, args[0])
+	}
+	exclude := make(map[hashKey]bool)
+	for _, element := range other.Elements {
+		exclude[hash(element)] = true
+	}
+	difference := NewArray()
+	for _, element := range array.Elements {
+		if !exclude[hash(element)] {
+			difference.Elements = append(difference.Elements, element)
+		}
+	}
+	return difference, nil
+}
+
+// arrayIntersection returns the unique elements contained in both arrays
+func arrayIntersection(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	other, ok := args[0].(*Array)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(other, args[0])
+	}
+	include := make(map[hashKey]bool)
+	for _, element := range other.Elements {
+		include[hash(element)] = true
+	}
+	intersection := NewArray()
+	for _, element := range uniqueElements(array.Elements) {
+		if include[hash(element)] {
+			intersection.Elements = append(intersection.Elements, element)
+		}
+	}
+	return intersection, nil
+}
+
+// arrayUnion returns the unique elements contained in either array
+func arrayUnion(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	other, ok := args[0].(*Array)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(other, args[0])
+	}
+	elements := append(append([]RubyObject{}, array.Elements...), other.Elements...)
+	return NewArray(uniqueElements(elements)...), nil
+}
+
+// arrayMul repeats the elements the given number of times, or joins them
+// if given a String
+func arrayMul(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	switch arg := args[0].(type) {
+	case *String:
+		joined, err := join(context, array, arg.Value, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &String{Value: joined}, nil
+	case *Integer:
+		if arg.Value < 0 {
+			return nil, NewArgumentError("negative argument")
+		}
+		repeated := NewArray()
+		for i := int64(0); i < arg.Value; i++ {
+			repeated.Elements = append(repeated.Elements, array.Elements...)
+		}
+		return repeated, nil
+	default:
+		return nil, NewImplicitConversionTypeError(&Integer{}, arg)
+	}
+}
+
+func arrayEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	other, ok := args[0].(*Array)
+	if !ok {
+		return FALSE, nil
+	}
+	equal, err := arraysEqual(context, array, other, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !equal {
+		return FALSE, nil
+	}
+	return TRUE, nil
+}
+
+// arraysEqual compares a and b element by element, comparing nested arrays
+// recursively. Like in MRI the comparison of a pair of arrays already
+// compared further up in seen is considered equal, so arrays containing
+// themselves do not recurse endlessly.
+func arraysEqual(context CallContext, a, b *Array, seen [][2]*Array) (bool, error) {
+	if a == b {
+		return true, nil
+	}
+	if len(a.Elements) != len(b.Elements) {
+		return false, nil
+	}
+	for _, pair := range seen {
+		if pair[0] == a && pair[1] == b {
+			return true, nil
+		}
+	}
+	seen = append(seen, [2]*Array{a, b})
+	for i, element := range a.Elements {
+		var equal bool
+		var err error
+		nestedA, okA := element.(*Array)
+		nestedB, okB := b.Elements[i].(*Array)
+		if okA && okB {
+			equal, err = arraysEqual(context, nestedA, nestedB, seen)
+		} else {
+			equal, err = isEqual(context, element, b.Elements[i])
+		}
+		if err != nil {
+			return false, err
+		}
+		if !equal {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// arraySpaceship compares the arrays element by element and then by their
+// length. It returns nil if the argument is no array or an element pair is
+// not comparable.
+func arraySpaceship(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	other, ok := args[0].(*Array)
+	if !ok {
+		return NIL, nil
+	}
+	for i := 0; i < len(array.Elements) && i < len(other.Elements); i++ {
+		cmp, ok, err := compare(context, array.Elements[i], other.Elements[i])
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return NIL, nil
+		}
+		if cmp != 0 {
+			return NewInteger(int64(cmp)), nil
+		}
+	}
+	switch {
+	case len(array.Elements) < len(other.Elements):
+		return NewInteger(-1), nil
+	case len(array.Elements) > len(other.Elements):
+		return NewInteger(1), nil
+	default:
+		return NewInteger(0), nil
+	}
+}
+
+func arrayPack(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	template, ok := args[0].(*String)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(template, args[0])
+	}
+	packed, err := pack(template.Value, array.Elements)
+	if err != nil {
+		return nil, err
+	}
+	return &String{Value: packed}, nil
+}
+
+// arraySum adds up the elements, or the values the block returns for them,
+// starting with 0 or the given initial value. Like MRI floating point
+// numbers are summed up using the Kahan-Babuska algorithm.
+func arraySum(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	block, args, hasBlock := extractBlockFromArgs(args)
+	if len(args) > 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	var sum RubyObject = NewInteger(0)
+	if len(args) == 1 {
+		sum = args[0]
+	}
+	var f, c float64
+	inFloat := false
+	for _, element := range array.Elements {
+		value := element
+		if hasBlock {
+			var err error
+			value, err = block.Call(context, element)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !inFloat && isNumeric(sum) && isNumeric(value) {
+			_, sumIsFloat := sum.(*Float)
+			_, valueIsFloat := value.(*Float)
+			if sumIsFloat || valueIsFloat {
+				inFloat = true
+				f, _ = formatFloatValue(sum)
+				c = 0
+			}
+		}
+		if inFloat && isNumeric(value) {
+			x, _ := formatFloatValue(value)
+			t := f + x
+			if math.Abs(f) >= math.Abs(x) {
+				c += (f - t) + x
+			} else {
+				c += (x - t) + f
+			}
+			f = t
+			continue
+		}
+		if inFloat {
+			sum = NewFloat(f + c)
+			inFloat = false
+		}
+		var err error
+		sum, err = Send(&callContext{receiver: sum, env: context.Env(), eval: context.Eval}, "+", value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if inFloat {
+		return NewFloat(f + c), nil
+	}
+	return sum, nil
+}
+
+// arrayExtremum returns the smallest element if sign is -1 and the largest
+// if sign is 1. Given a count it returns that many elements, ordered from
+// the extremum on.
+func arrayExtremum(context CallContext, args []RubyObject, sign int) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	block, args, _ := extractBlockFromArgs(args)
+	switch len(args) {
+	case 0:
+		var extremum RubyObject = NIL
+		for i, element := range array.Elements {
+			if i == 0 {
+				extremum = element
+				continue
+			}
+			cmp, err := sortCompare(context, block, element, extremum)
+			if err != nil {
+				return nil, err
+			}
+			if cmp == sign {
+				extremum = element
+			}
+		}
+		return extremum, nil
+	case 1:
+		count, err := arrayCount(args[0])
+		if err != nil {
+			return nil, err
+		}
+		sorted := append([]RubyObject{}, array.Elements...)
+		if err := sortElements(context, block, sorted); err != nil {
+			return nil, err
+		}
+		if sign > 0 {
+			for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+				sorted[i], sorted[j] = sorted[j], sorted[i]
+			}
+		}
+		if count > len(sorted) {
+			count = len(sorted)
+		}
+		return NewArray(sorted[:count]...), nil
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+}
+
+func arrayMin(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return arrayExtremum(context, args, -1)
+}
+
+func arrayMax(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return arrayExtremum(context, args, 1)
+}
+
+func arrayToA(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver(), nil
+}
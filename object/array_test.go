@@ -3,6 +3,8 @@ package object
 import (
 	"reflect"
 	"testing"
+
+	"github.com/goruby/goruby/ast"
 )
 
 func TestArrayPush(t *testing.T) {
@@ -94,3 +96,388 @@ func TestArrayUnshift(t *testing.T) {
 		}
 	})
 }
+
+func TestArrayIndex(t *testing.T) {
+	array := NewArray(NewInteger(1), NewInteger(2), NewInteger(3), NewInteger(4))
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{[]RubyObject{NewInteger(1)}, NewInteger(2), nil},
+		{[]RubyObject{NewInteger(-1)}, NewInteger(4), nil},
+		{[]RubyObject{NewInteger(4)}, NIL, nil},
+		{[]RubyObject{NewInteger(1), NewInteger(2)}, NewArray(NewInteger(2), NewInteger(3)), nil},
+		{[]RubyObject{NewInteger(4), NewInteger(2)}, NewArray(), nil},
+		{[]RubyObject{NewInteger(5), NewInteger(2)}, NIL, nil},
+		{[]RubyObject{&Range{Begin: NewInteger(1), End: NewInteger(2)}}, NewArray(NewInteger(2), NewInteger(3)), nil},
+		{[]RubyObject{&Range{Begin: NewInteger(2), End: NIL}}, NewArray(NewInteger(3), NewInteger(4)), nil},
+		{[]RubyObject{&Range{Begin: NIL, End: NewInteger(-3), Exclusive: true}}, NewArray(NewInteger(1)), nil},
+		{[]RubyObject{&Range{Begin: NewInteger(5), End: NIL}}, NIL, nil},
+		{[]RubyObject{&String{Value: "a"}}, nil, NewImplicitConversionTypeError(&Integer{}, &String{})},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: array}
+
+		result, err := arrayIndex(context, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestArraySetIndex(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+		array     *Array
+		err       error
+	}{
+		{[]RubyObject{NewInteger(1), TRUE}, TRUE, NewArray(NewInteger(1), TRUE, NewInteger(3)), nil},
+		{[]RubyObject{NewInteger(-1), TRUE}, TRUE, NewArray(NewInteger(1), NewInteger(2), TRUE), nil},
+		{[]RubyObject{NewInteger(4), TRUE}, TRUE, NewArray(NewInteger(1), NewInteger(2), NewInteger(3), NIL, TRUE), nil},
+		{[]RubyObject{NewInteger(-4), TRUE}, nil, nil, NewIndexError("index -4 too small for array; minimum: -3")},
+		{
+			[]RubyObject{NewInteger(0), NewInteger(2), NewArray(TRUE, FALSE, NIL)},
+			NewArray(TRUE, FALSE, NIL),
+			NewArray(TRUE, FALSE, NIL, NewInteger(3)),
+			nil,
+		},
+		{[]RubyObject{NewInteger(1), NewInteger(0), TRUE}, TRUE, NewArray(NewInteger(1), TRUE, NewInteger(2), NewInteger(3)), nil},
+		{[]RubyObject{NewInteger(5), NewInteger(1), TRUE}, TRUE, NewArray(NewInteger(1), NewInteger(2), NewInteger(3), NIL, NIL, TRUE), nil},
+		{[]RubyObject{NewInteger(0), NewInteger(-1), TRUE}, nil, nil, NewIndexError("negative length (-1)")},
+		{[]RubyObject{&Range{Begin: NewInteger(1), End: NIL}, TRUE}, TRUE, NewArray(NewInteger(1), TRUE), nil},
+		{[]RubyObject{&Range{Begin: NewInteger(-1), End: NewInteger(0)}, TRUE}, TRUE, NewArray(NewInteger(1), NewInteger(2), TRUE, NewInteger(3)), nil},
+		{[]RubyObject{&Range{Begin: NewInteger(-5), End: NewInteger(1)}, TRUE}, nil, nil, NewRangeError("-5..1 out of range")},
+		{[]RubyObject{&String{Value: "a"}, TRUE}, nil, nil, NewImplicitConversionTypeError(&Integer{}, &String{})},
+	}
+
+	for _, testCase := range tests {
+		array := NewArray(NewInteger(1), NewInteger(2), NewInteger(3))
+		context := &callContext{receiver: array}
+
+		result, err := arraySetIndex(context, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		if testCase.err == nil {
+			checkResult(t, result, testCase.result)
+			checkResult(t, array, testCase.array)
+		}
+	}
+}
+
+func TestArrayMethods(t *testing.T) {
+	integers := func(values ...int64) *Array {
+		array := NewArray()
+		for _, v := range values {
+			array.Elements = append(array.Elements, NewInteger(v))
+		}
+		return array
+	}
+
+	tests := []struct {
+		receiver  *Array
+		method    string
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{integers(1, 2, 3), "length", nil, NewInteger(3), nil},
+		{integers(), "size", nil, NewInteger(0), nil},
+		{integers(), "empty?", nil, TRUE, nil},
+		{integers(1), "empty?", nil, FALSE, nil},
+		{integers(1, 2, 3), "first", nil, NewInteger(1), nil},
+		{integers(), "first", nil, NIL, nil},
+		{integers(1, 2, 3), "first", []RubyObject{NewInteger(2)}, integers(1, 2), nil},
+		{integers(1, 2, 3), "first", []RubyObject{NewInteger(5)}, integers(1, 2, 3), nil},
+		{integers(1, 2, 3), "first", []RubyObject{NewInteger(-1)}, nil, NewArgumentError("negative array size")},
+		{integers(1, 2, 3), "last", nil, NewInteger(3), nil},
+		{integers(1, 2, 3), "last", []RubyObject{NewInteger(2)}, integers(2, 3), nil},
+		{integers(1, 2, 3), "pop", nil, NewInteger(3), nil},
+		{integers(), "pop", nil, NIL, nil},
+		{integers(1, 2, 3), "pop", []RubyObject{NewInteger(2)}, integers(2, 3), nil},
+		{integers(1, 2, 3), "shift", nil, NewInteger(1), nil},
+		{integers(1, 2, 3), "shift", []RubyObject{NewInteger(5)}, integers(1, 2, 3), nil},
+		{integers(1), "<<", []RubyObject{NewInteger(2)}, integers(1, 2), nil},
+		{integers(1), "concat", []RubyObject{integers(2), integers(3, 4)}, integers(1, 2, 3, 4), nil},
+		{integers(1), "concat", []RubyObject{NewInteger(2)}, nil, NewImplicitConversionTypeError(&Array{}, &Integer{})},
+		{integers(1, 2), "insert", []RubyObject{NewInteger(1), NewInteger(5), NewInteger(6)}, integers(1, 5, 6, 2), nil},
+		{integers(1, 2), "insert", []RubyObject{NewInteger(-2), NewInteger(5)}, integers(1, 5, 2), nil},
+		{integers(1, 2), "insert", []RubyObject{NewInteger(4), NewInteger(5)}, NewArray(NewInteger(1), NewInteger(2), NIL, NIL, NewInteger(5)), nil},
+		{
+			integers(1, 2), "insert", []RubyObject{NewInteger(-4), NewInteger(5)},
+			nil, NewIndexError("index -4 too small for array; minimum: -3"),
+		},
+		{integers(1, 2, 1), "delete", []RubyObject{NewInteger(1)}, NewInteger(1), nil},
+		{integers(1, 2, 1), "delete", []RubyObject{NewInteger(3)}, NIL, nil},
+		{integers(1, 2, 3), "delete_at", []RubyObject{NewInteger(-1)}, NewInteger(3), nil},
+		{integers(1, 2, 3), "delete_at", []RubyObject{NewInteger(3)}, NIL, nil},
+		{integers(1, 2, 3), "clear", nil, integers(), nil},
+		{integers(1, 2, 3), "at", []RubyObject{NewInteger(-1)}, NewInteger(3), nil},
+		{integers(1, 2, 3), "fetch", []RubyObject{NewInteger(1)}, NewInteger(2), nil},
+		{integers(1, 2, 3), "fetch", []RubyObject{NewInteger(5), TRUE}, TRUE, nil},
+		{
+			integers(1, 2, 3), "fetch", []RubyObject{NewInteger(-4)},
+			nil, NewIndexError("index -4 outside of array bounds: -3...3"),
+		},
+		{NewArray(integers(1, 2), NIL), "dig", []RubyObject{NewInteger(0), NewInteger(1)}, NewInteger(2), nil},
+		{NewArray(integers(1, 2), NIL), "dig", []RubyObject{NewInteger(1), NewInteger(1)}, NIL, nil},
+		{
+			integers(1, 2), "dig", []RubyObject{NewInteger(0), NewInteger(1)},
+			nil, NewTypeError("Integer does not have #dig method"),
+		},
+		{integers(1, 2, 3), "include?", []RubyObject{NewInteger(2)}, TRUE, nil},
+		{integers(1, 2, 3), "include?", []RubyObject{&String{Value: "2"}}, FALSE, nil},
+		{integers(1, 2, 3), "index", []RubyObject{NewInteger(3)}, NewInteger(2), nil},
+		{integers(1, 2, 3), "index", []RubyObject{NewInteger(4)}, NIL, nil},
+		{integers(3, 1, 2), "sort", nil, integers(1, 2, 3), nil},
+		{
+			NewArray(&String{Value: "b"}, &String{Value: "a"}), "sort", nil,
+			NewArray(&String{Value: "a"}, &String{Value: "b"}), nil,
+		},
+		{
+			NewArray(NewInteger(1), &String{Value: "a"}), "sort", nil,
+			nil, NewArgumentError("comparison of Integer with String failed"),
+		},
+		{integers(1, 2, 1, 3, 2), "uniq", nil, integers(1, 2, 3), nil},
+		{NewArray(integers(1), integers(1), NewFloat(1)), "uniq", nil, NewArray(integers(1), NewFloat(1)), nil},
+		{NewArray(NewInteger(1), NewArray(NewInteger(2), integers(3))), "flatten", nil, integers(1, 2, 3), nil},
+		{
+			NewArray(NewInteger(1), NewArray(NewInteger(2), integers(3))), "flatten", []RubyObject{NewInteger(1)},
+			NewArray(NewInteger(1), NewInteger(2), integers(3)), nil,
+		},
+		{NewArray(NewInteger(1), NIL, NewInteger(2), NIL), "compact", nil, integers(1, 2), nil},
+		{
+			integers(1, 2), "zip", []RubyObject{integers(3), integers(5, 6)},
+			NewArray(integers(1, 3, 5), NewArray(NewInteger(2), NIL, NewInteger(6))), nil,
+		},
+		{integers(1, 2), "zip", []RubyObject{NewInteger(1)}, nil, NewTypeError("wrong argument type Integer (must respond to :each)")},
+		{
+			integers(1, 2), "zip", []RubyObject{&Range{Begin: NewInteger(3), End: NIL}},
+			NewArray(integers(1, 3), integers(2, 4)), nil,
+		},
+		{NewArray(NewInteger(1), NewArray(&String{Value: "a"}, NIL)), "join", []RubyObject{&String{Value: "-"}}, &String{Value: "1-a-"}, nil},
+		{integers(1, 2), "join", nil, &String{Value: "12"}, nil},
+		{integers(1, 2), "join", []RubyObject{NewInteger(1)}, nil, NewImplicitConversionTypeError(&String{}, &Integer{})},
+		{integers(1, 2, 3), "reverse", nil, integers(3, 2, 1), nil},
+		{integers(1, 2, 3), "rotate", nil, integers(2, 3, 1), nil},
+		{integers(1, 2, 3), "rotate", []RubyObject{NewInteger(-1)}, integers(3, 1, 2), nil},
+		{integers(1, 2, 3), "rotate", []RubyObject{NewInteger(7)}, integers(2, 3, 1), nil},
+		{integers(), "rotate", nil, integers(), nil},
+		{integers(1, 2), "+", []RubyObject{integers(2)}, integers(1, 2, 2), nil},
+		{integers(1, 2), "+", []RubyObject{NewInteger(2)}, nil, NewImplicitConversionTypeError(&Array{}, &Integer{})},
+		{integers(1, 2, 3, 2), "-", []RubyObject{integers(2)}, integers(1, 3), nil},
+		{integers(1, 1, 2, 3), "&", []RubyObject{integers(3, 1)}, integers(1, 3), nil},
+		{integers(1, 1, 2), "|", []RubyObject{integers(3, 1)}, integers(1, 2, 3), nil},
+		{integers(1, 2), "*", []RubyObject{NewInteger(2)}, integers(1, 2, 1, 2), nil},
+		{integers(1, 2), "*", []RubyObject{&String{Value: ","}}, &String{Value: "1,2"}, nil},
+		{integers(1, 2), "*", []RubyObject{NewInteger(-1)}, nil, NewArgumentError("negative argument")},
+		{integers(1, 2), "==", []RubyObject{NewArray(NewFloat(1), NewInteger(2))}, TRUE, nil},
+		{integers(1, 2), "==", []RubyObject{integers(1)}, FALSE, nil},
+		{integers(1, 2), "==", []RubyObject{NewInteger(1)}, FALSE, nil},
+		{integers(1, 2), "<=>", []RubyObject{integers(1, 3)}, NewInteger(-1), nil},
+		{integers(1, 2), "<=>", []RubyObject{integers(1)}, NewInteger(1), nil},
+		{integers(1, 2), "<=>", []RubyObject{integers(1, 2)}, NewInteger(0), nil},
+		{integers(1, 2), "<=>", []RubyObject{NewArray(NewInteger(1), &String{Value: "a"})}, NIL, nil},
+		{integers(1, 2), "<=>", []RubyObject{NewInteger(1)}, NIL, nil},
+		{integers(1, 2, 3), "sum", nil, NewInteger(6), nil},
+		{integers(1, 2, 3), "sum", []RubyObject{NewFloat(0.5)}, NewFloat(6.5), nil},
+		{NewArray(NewFloat(0.1), NewFloat(0.2), NewFloat(0.3)), "sum", nil, NewFloat(0.6), nil},
+		{NewArray(&String{Value: "a"}, &String{Value: "b"}), "sum", []RubyObject{&String{Value: ""}}, &String{Value: "ab"}, nil},
+		{integers(3, 1, 2), "min", nil, NewInteger(1), nil},
+		{integers(), "min", nil, NIL, nil},
+		{integers(3, 1, 2), "max", nil, NewInteger(3), nil},
+		{integers(3, 1, 2), "min", []RubyObject{NewInteger(2)}, integers(1, 2), nil},
+		{integers(3, 1, 2), "max", []RubyObject{NewInteger(2)}, integers(3, 2), nil},
+		{integers(65, 66), "pack", []RubyObject{&String{Value: "C*"}}, &String{Value: "AB"}, nil},
+		{integers(1, 2), "to_a", nil, integers(1, 2), nil},
+		{integers(1, 2), "inspect", nil, &String{Value: "[1, 2]"}, nil},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.receiver, env: NewMainEnvironment()}
+
+		result, err := Send(context, testCase.method, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		if testCase.err == nil {
+			checkResult(t, result, testCase.result)
+		}
+	}
+}
+
+func TestArrayMutation(t *testing.T) {
+	array := NewArray(NewInteger(1), NewInteger(2), NewInteger(3))
+	context := &callContext{receiver: array, env: NewMainEnvironment()}
+
+	_, err := arrayPop(context)
+	checkError(t, err, nil)
+	_, err = arrayShift(context)
+	checkError(t, err, nil)
+	_, err = arrayInsert(context, NewInteger(0), NIL)
+	checkError(t, err, nil)
+	_, err = arrayDeleteAt(context, NewInteger(0))
+	checkError(t, err, nil)
+	_, err = arrayConcat(context, NewArray(NewInteger(4)))
+	checkError(t, err, nil)
+
+	checkResult(t, array, NewArray(NewInteger(2), NewInteger(4)))
+}
+
+func TestArrayRecursiveEqual(t *testing.T) {
+	recursive := func(element RubyObject) *Array {
+		array := NewArray(element)
+		array.Elements = append(array.Elements, array)
+		return array
+	}
+	a, b, c := recursive(NewInteger(1)), recursive(NewInteger(1)), recursive(NewInteger(2))
+
+	tests := []struct {
+		receiver *Array
+		argument *Array
+		result   RubyObject
+	}{
+		{a, a, TRUE},
+		{a, b, TRUE},
+		{a, c, FALSE},
+		{NewArray(a), NewArray(b), TRUE},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.receiver, env: NewMainEnvironment()}
+
+		result, err := Send(context, "==", testCase.argument)
+
+		checkError(t, err, nil)
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestArrayBlockMethods(t *testing.T) {
+	double := func(node ast.Node, env Environment) (RubyObject, error) {
+		x, _ := env.Get("x")
+		return NewInteger(x.(*Integer).Value * 2), nil
+	}
+	isOdd := func(node ast.Node, env Environment) (RubyObject, error) {
+		x, _ := env.Get("x")
+		return nativeBoolToBooleanObject(x.(*Integer).Value%2 == 1), nil
+	}
+	descending := func(node ast.Node, env Environment) (RubyObject, error) {
+		x, _ := env.Get("x")
+		y, _ := env.Get("y")
+		return NewInteger(int64(y.(*Integer).cmp(x.(*Integer)))), nil
+	}
+	block := func(names ...string) *Proc {
+		var params []*ast.FunctionParameter
+		for _, name := range names {
+			params = append(params, &ast.FunctionParameter{Name: &ast.Identifier{Value: name}})
+		}
+		return &Proc{Parameters: params, Body: &ast.BlockStatement{}, Env: NewEnvironment()}
+	}
+	array := NewArray(NewInteger(1), NewInteger(2), NewInteger(3))
+
+	tests := []struct {
+		method    RubyMethod
+		eval      func(node ast.Node, env Environment) (RubyObject, error)
+		arguments []RubyObject
+		result    RubyObject
+	}{
+		{publicMethod(arrayMap), double, []RubyObject{block("x")}, NewArray(NewInteger(2), NewInteger(4), NewInteger(6))},
+		{publicMethod(arraySelect), isOdd, []RubyObject{block("x")}, NewArray(NewInteger(1), NewInteger(3))},
+		{publicMethod(arrayReject), isOdd, []RubyObject{block("x")}, NewArray(NewInteger(2))},
+		{publicMethod(arrayFind), isOdd, []RubyObject{block("x")}, NewInteger(1)},
+		{publicMethod(arrayIndexOf), isOdd, []RubyObject{block("x")}, NewInteger(0)},
+		{publicMethod(arraySum), double, []RubyObject{block("x")}, NewInteger(12)},
+		{publicMethod(arraySortBy), double, []RubyObject{block("x")}, array},
+		{publicMethod(arrayUniq), isOdd, []RubyObject{block("x")}, NewArray(NewInteger(1), NewInteger(2))},
+		{publicMethod(arraySort), descending, []RubyObject{block("x", "y")}, NewArray(NewInteger(3), NewInteger(2), NewInteger(1))},
+		{publicMethod(arrayMax), descending, []RubyObject{block("x", "y")}, NewInteger(1)},
+		{publicMethod(arrayDelete), double, []RubyObject{NewInteger(5), block("x")}, NewInteger(10)},
+		{publicMethod(arrayFetch), double, []RubyObject{NewInteger(5), block("x")}, NewInteger(10)},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: array, env: NewEnvironment(), eval: testCase.eval}
+
+		result, err := testCase.method.Call(context, testCase.arguments...)
+
+		checkError(t, err, nil)
+		checkResult(t, result, testCase.result)
+	}
+
+	t.Run("each_with_index", func(t *testing.T) {
+		var pairs []RubyObject
+		eval := func(node ast.Node, env Environment) (RubyObject, error) {
+			x, _ := env.Get("x")
+			i, _ := env.Get("i")
+			pairs = append(pairs, NewArray(x, i))
+			return NIL, nil
+		}
+		context := &callContext{receiver: array, env: NewEnvironment(), eval: eval}
+
+		result, err := arrayEachWithIndex(context, block("x", "i"))
+
+		checkError(t, err, nil)
+		checkResult(t, result, array)
+		checkResult(t, NewArray(pairs...), NewArray(
+			NewArray(NewInteger(1), NewInteger(0)),
+			NewArray(NewInteger(2), NewInteger(1)),
+			NewArray(NewInteger(3), NewInteger(2)),
+		))
+	})
+	t.Run("map without block", func(t *testing.T) {
+		context := &callContext{receiver: array}
+
+		_, err := arrayMap(context)
+
+		checkError(t, err, NewNoBlockGivenLocalJumpError())
+	})
+}
+
+func TestArrayRandomness(t *testing.T) {
+	array := NewArray(NewInteger(1), NewInteger(2), NewInteger(3), NewInteger(4), NewInteger(5))
+	options := func(seed int64) *Hash {
+		hash := &Hash{}
+		hash.Set(NewSymbol("random"), NewRandom(seed))
+		return hash
+	}
+	context := &callContext{receiver: array}
+
+	t.Run("shuffle", func(t *testing.T) {
+		first, err := arrayShuffle(context, options(42))
+		checkError(t, err, nil)
+		second, err := arrayShuffle(context, options(42))
+		checkError(t, err, nil)
+
+		checkResult(t, first, second)
+		if len(first.(*Array).Elements) != len(array.Elements) {
+			t.Logf("Expected shuffled array to keep all elements, got %s\n", first.Inspect())
+			t.Fail()
+		}
+	})
+	t.Run("sample", func(t *testing.T) {
+		first, err := arraySample(context, NewInteger(3), options(7))
+		checkError(t, err, nil)
+		second, err := arraySample(context, NewInteger(3), options(7))
+		checkError(t, err, nil)
+
+		checkResult(t, first, second)
+		unique := uniqueElements(first.(*Array).Elements)
+		if len(unique) != 3 {
+			t.Logf("Expected 3 distinct elements, got %s\n", first.Inspect())
+			t.Fail()
+		}
+	})
+	t.Run("invalid generator", func(t *testing.T) {
+		hash := &Hash{}
+		hash.Set(NewSymbol("random"), NewInteger(1))
+
+		_, err := arrayShuffle(context, hash)
+
+		checkError(t, err, NewWrongArgumentTypeError(&Random{}, NewInteger(1)))
+	})
+}
@@ -38,6 +38,7 @@ var basicObjectClassMethods = map[string]RubyMethod{}
 var basicObjectMethods = map[string]RubyMethod{
 	"initialize":     privateMethod(basicObjectInitialize),
 	"method_missing": privateMethod(basicObjectMethodMissing),
+	"==":             withArity(1, publicMethod(basicObjectEqual)),
 }
 
 func basicObjectMethodMissing(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -54,3 +55,18 @@ func basicObjectMethodMissing(context CallContext, args ...RubyObject) (RubyObje
 func basicObjectInitialize(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return context.Receiver(), nil
 }
+
+func basicObjectEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	receiver := context.Receiver()
+	if self, ok := receiver.(*Self); ok {
+		receiver = self.RubyObject
+	}
+	other := args[0]
+	if self, ok := other.(*Self); ok {
+		other = self.RubyObject
+	}
+	if receiver == other {
+		return TRUE, nil
+	}
+	return FALSE, nil
+}
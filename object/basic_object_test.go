@@ -29,3 +29,27 @@ func TestBasicObjectInitialize(t *testing.T) {
 
 	checkResult(t, result, context.Receiver())
 }
+
+func TestBasicObjectEqual(t *testing.T) {
+	object := &Object{}
+	tests := []struct {
+		receiver RubyObject
+		argument RubyObject
+		result   RubyObject
+	}{
+		{object, object, TRUE},
+		{object, &Object{}, FALSE},
+		{&Self{RubyObject: object, Name: "main"}, object, TRUE},
+		{NIL, NIL, TRUE},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.receiver}
+
+		result, err := basicObjectEqual(context, testCase.argument)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
@@ -16,6 +16,14 @@ func init() {
 	classes.Set("FalseClass", falseClass)
 }
 
+// nativeBoolToBooleanObject returns TRUE for true and FALSE for false
+func nativeBoolToBooleanObject(b bool) RubyObject {
+	if b {
+		return TRUE
+	}
+	return FALSE
+}
+
 // Boolean represents a Boolean object in Ruby
 type Boolean struct {
 	Value bool
@@ -46,13 +54,20 @@ func (b *Boolean) hashKey() hashKey {
 }
 
 var booleanTrueMethods = map[string]RubyMethod{
-	"==": withArity(1, publicMethod(booleanEq)),
-	"!=": withArity(1, publicMethod(booleanNeq)),
+	"==":   withArity(1, publicMethod(booleanEq)),
+	"!=":   withArity(1, publicMethod(booleanNeq)),
+	"to_s": withArity(0, publicMethod(booleanToS)),
 }
 
 var booleanFalseMethods = map[string]RubyMethod{
-	"==": withArity(1, publicMethod(booleanEq)),
-	"!=": withArity(1, publicMethod(booleanNeq)),
+	"==":   withArity(1, publicMethod(booleanEq)),
+	"!=":   withArity(1, publicMethod(booleanNeq)),
+	"to_s": withArity(0, publicMethod(booleanToS)),
+}
+
+func booleanToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	b := context.Receiver().(*Boolean)
+	return &String{Value: b.Inspect()}, nil
 }
 
 func booleanEq(context CallContext, args ...RubyObject) (RubyObject, error) {
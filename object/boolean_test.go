@@ -38,7 +38,7 @@ func TestBooleanEq(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{&String{Value: ""}},
 			FALSE,
 			nil,
 		},
@@ -77,7 +77,7 @@ func TestBooleanNeq(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{&String{Value: ""}},
 			TRUE,
 			nil,
 		},
@@ -98,3 +98,23 @@ func TestBooleanNeq(t *testing.T) {
 		checkResult(t, result, testCase.result)
 	}
 }
+
+func TestBooleanToS(t *testing.T) {
+	tests := []struct {
+		receiver RubyObject
+		result   RubyObject
+	}{
+		{TRUE, &String{Value: "true"}},
+		{FALSE, &String{Value: "false"}},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.receiver}
+
+		result, err := booleanToS(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
@@ -16,7 +16,7 @@ var notInstantiatable = func(c RubyClassObject, args ...RubyObject) (RubyObject,
 	return nil, NewNoMethodError(c, "new")
 }
 var defaultBuilder = func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
-	return &classInstance{class: c}, nil
+	return &classInstance{class: c, Environment: NewEnvironment()}, nil
 }
 
 func init() {
@@ -28,7 +28,11 @@ func init() {
 func NewClass(name string, superClass RubyClass, env Environment) RubyClassObject {
 	instanceMethods := map[string]RubyMethod{}
 	classMethods := map[string]RubyMethod{}
-	return newClassWithEnv(name, superClass, instanceMethods, classMethods, defaultBuilder, env)
+	builder := defaultBuilder
+	if isExceptionClass(superClass) {
+		builder = exceptionInstanceBuilder
+	}
+	return newClassWithEnv(name, superClass, instanceMethods, classMethods, builder, env)
 }
 
 // newClass returns a new Ruby Class
@@ -121,6 +125,8 @@ var classInstanceMethods = map[string]RubyMethod{
 
 type classInstance struct {
 	class RubyClassObject
+	// Environment holds the instance variables
+	Environment
 }
 
 func (o *classInstance) Inspect() string  { return fmt.Sprintf("#<%s:%p>", o.class.Inspect(), o) }
@@ -130,6 +136,13 @@ func (o *classInstance) Type() Type       { return CLASS_INSTANCE_OBJ }
 func classSuperclass(context CallContext, args ...RubyObject) (RubyObject, error) {
 	class := context.Receiver().(RubyClass)
 	superclass := class.SuperClass()
+	for {
+		included, ok := superclass.(*includedModule)
+		if !ok {
+			break
+		}
+		superclass = included.superClass
+	}
 	if superclass == nil {
 		return NIL, nil
 	}
@@ -72,6 +72,15 @@ func TestNewClass(t *testing.T) {
 			t.Fail()
 		}
 	})
+	t.Run("exception subclass", func(t *testing.T) {
+		classObject := NewClass("MyError", standardErrorClass, nil)
+
+		instance, err := classObject.New()
+
+		checkError(t, err, nil)
+
+		checkResult(t, instance, &exceptionInstance{class: classObject, message: "MyError", Environment: NewEnvironment()})
+	})
 }
 
 func TestClassInspect(t *testing.T) {
@@ -251,7 +260,7 @@ func TestClassNew(t *testing.T) {
 		eval:     func(ast.Node, Environment) (RubyObject, error) { return nil, nil },
 	}
 
-	args := []RubyObject{&String{"foo"}, &Symbol{"bar"}, &Integer{7}}
+	args := []RubyObject{&String{Value: "foo"}, &Symbol{"bar"}, NewInteger(7)}
 
 	result, err := classNew(context, args...)
 	if err != nil {
@@ -314,7 +323,7 @@ func TestClassInitialize(t *testing.T) {
 		env:      env,
 	}
 
-	result, err := classInitialize(context, &String{"foo"}, &Symbol{"bar"}, &Integer{7})
+	result, err := classInitialize(context, &String{Value: "foo"}, &Symbol{"bar"}, NewInteger(7))
 	if err != nil {
 		t.Logf("Expected no error, got %T:%v\n", err, err)
 		t.Fail()
@@ -0,0 +1,652 @@
+package object
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+var enumerableModule = newModule("Enumerable", enumerableMethodSet, nil)
+
+func init() {
+	classes.Set("Enumerable", enumerableModule)
+}
+
+var enumerableMethodSet = map[string]RubyMethod{
+	"to_a":             withArity(0, publicMethod(enumerableToA)),
+	"entries":          withArity(0, publicMethod(enumerableToA)),
+	"map":              publicMethod(enumerableMap),
+	"collect":          publicMethod(enumerableMap),
+	"select":           publicMethod(enumerableSelect),
+	"filter":           publicMethod(enumerableSelect),
+	"reject":           publicMethod(enumerableReject),
+	"find":             publicMethod(enumerableFind),
+	"detect":           publicMethod(enumerableFind),
+	"reduce":           publicMethod(enumerableReduce),
+	"inject":           publicMethod(enumerableReduce),
+	"all?":             publicMethod(enumerableAll),
+	"any?":             publicMethod(enumerableAny),
+	"none?":            publicMethod(enumerableNone),
+	"count":            publicMethod(enumerableCount),
+	"first":            publicMethod(enumerableFirst),
+	"sort":             publicMethod(enumerableSort),
+	"sort_by":          publicMethod(enumerableSortBy),
+	"min_by":           publicMethod(enumerableMinBy),
+	"max_by":           publicMethod(enumerableMaxBy),
+	"each_with_index":  publicMethod(enumerableEachWithIndex),
+	"each_with_object": withArity(2, publicMethod(enumerableEachWithObject)),
+	"group_by":         publicMethod(enumerableGroupBy),
+	"partition":        publicMethod(enumerablePartition),
+	"each_slice":       publicMethod(enumerableEachSlice),
+	"each_cons":        publicMethod(enumerableEachCons),
+	"zip":              publicMethod(enumerableZip),
+	"to_h":             publicMethod(enumerableToH),
+	"tally":            withArity(0, publicMethod(enumerableTally)),
+	"sum":              publicMethod(enumerableSum),
+	"lazy":             withArity(0, publicMethod(enumerableLazy)),
+}
+
+// enumerate calls each on the receiver of context and hands every value it
+// yields to fn. Multiple values yielded at once are handed over as Array.
+// The iteration stops early if fn returns false.
+func enumerate(context CallContext, fn func(value RubyObject) (bool, error)) error {
+	var block *Proc
+	block = newNativeProc(func(args ...RubyObject) (RubyObject, error) {
+		var value RubyObject = NIL
+		switch len(args) {
+		case 0:
+		case 1:
+			value = args[0]
+		default:
+			value = NewArray(args...)
+		}
+		more, err := fn(value)
+		if err != nil {
+			return nil, err
+		}
+		if !more {
+			return nil, &Jump{JumpType: BreakJump, Value: NIL, Block: block}
+		}
+		return NIL, nil
+	})
+	_, err := Send(
+		&callContext{receiver: context.Receiver(), env: context.Env(), eval: context.Eval},
+		"each",
+		block,
+	)
+	if jump, ok := errors.Cause(err).(*Jump); ok && jump.Block == block {
+		return nil
+	}
+	return err
+}
+
+// enumerateWithBlock calls the block within args for every value of the
+// receiver of context and hands the value and the result of the block to fn
+func enumerateWithBlock(
+	context CallContext,
+	args []RubyObject,
+	fn func(value, result RubyObject) (bool, error),
+) error {
+	block, err := arrayIteration(args)
+	if err != nil {
+		return err
+	}
+	return enumerate(context, func(value RubyObject) (bool, error) {
+		result, err := block.Call(context, value)
+		if err != nil {
+			return false, err
+		}
+		return fn(value, result)
+	})
+}
+
+// enumerableEntries returns all values of the receiver of context
+func enumerableEntries(context CallContext) (*Array, error) {
+	entries := NewArray()
+	err := enumerate(context, func(value RubyObject) (bool, error) {
+		entries.Elements = append(entries.Elements, value)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// sendToEntries calls method on an Array of all values of the receiver of
+// context
+func sendToEntries(context CallContext, method string, args ...RubyObject) (RubyObject, error) {
+	entries, err := enumerableEntries(context)
+	if err != nil {
+		return nil, err
+	}
+	return Send(&callContext{receiver: entries, env: context.Env(), eval: context.Eval}, method, args...)
+}
+
+func enumerableToA(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return enumerableEntries(context)
+}
+
+func enumerableMap(context CallContext, args ...RubyObject) (RubyObject, error) {
+	mapped := NewArray()
+	err := enumerateWithBlock(context, args, func(value, result RubyObject) (bool, error) {
+		mapped.Elements = append(mapped.Elements, result)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapped, nil
+}
+
+func enumerableSelect(context CallContext, args ...RubyObject) (RubyObject, error) {
+	selected := NewArray()
+	err := enumerateWithBlock(context, args, func(value, result RubyObject) (bool, error) {
+		if result != NIL && result != FALSE {
+			selected.Elements = append(selected.Elements, value)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+func enumerableReject(context CallContext, args ...RubyObject) (RubyObject, error) {
+	kept := NewArray()
+	err := enumerateWithBlock(context, args, func(value, result RubyObject) (bool, error) {
+		if result == NIL || result == FALSE {
+			kept.Elements = append(kept.Elements, value)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return kept, nil
+}
+
+func enumerableFind(context CallContext, args ...RubyObject) (RubyObject, error) {
+	var found RubyObject = NIL
+	err := enumerateWithBlock(context, args, func(value, result RubyObject) (bool, error) {
+		if result != NIL && result != FALSE {
+			found = value
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// enumerableReduce combines all values with the block, or with the method
+// named by a Symbol or String. An initial value may be given as first
+// argument.
+func enumerableReduce(context CallContext, args ...RubyObject) (RubyObject, error) {
+	block, args, hasBlock := extractBlockFromArgs(args)
+	var memo, operator RubyObject
+	switch {
+	case hasBlock && len(args) == 0:
+	case hasBlock && len(args) == 1:
+		memo = args[0]
+	case !hasBlock && len(args) == 1:
+		operator = args[0]
+	case !hasBlock && len(args) == 2:
+		memo, operator = args[0], args[1]
+	case hasBlock:
+		return nil, NewWrongNumberOfArgumentsRangeError(0, 1, len(args))
+	default:
+		return nil, NewWrongNumberOfArgumentsRangeError(1, 2, len(args))
+	}
+	var method string
+	switch operator := operator.(type) {
+	case nil:
+	case *Symbol:
+		method = operator.Value
+	case *String:
+		method = operator.Value
+	default:
+		return nil, NewTypeError(fmt.Sprintf("%s is not a symbol nor a string", operator.Inspect()))
+	}
+	err := enumerate(context, func(value RubyObject) (bool, error) {
+		if memo == nil {
+			memo = value
+			return true, nil
+		}
+		var err error
+		if hasBlock {
+			memo, err = block.Call(context, memo, value)
+		} else {
+			memo, err = Send(&callContext{receiver: memo, env: context.Env(), eval: context.Eval}, method, value)
+		}
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if memo == nil {
+		return NIL, nil
+	}
+	return memo, nil
+}
+
+// enumerableMatcher returns a function which tests values against the
+// pattern within args using `===`, with the block within args or for being
+// truthy, in that order
+func enumerableMatcher(context CallContext, args []RubyObject) (func(RubyObject) (bool, error), error) {
+	block, args, hasBlock := extractBlockFromArgs(args)
+	if len(args) > 1 {
+		return nil, NewWrongNumberOfArgumentsRangeError(0, 1, len(args))
+	}
+	switch {
+	case len(args) == 1:
+		pattern := args[0]
+		return func(value RubyObject) (bool, error) {
+			result, err := Send(&callContext{receiver: pattern, env: context.Env(), eval: context.Eval}, "===", value)
+			if err != nil {
+				return false, err
+			}
+			return result != NIL && result != FALSE, nil
+		}, nil
+	case hasBlock:
+		return func(value RubyObject) (bool, error) {
+			result, err := block.Call(context, value)
+			if err != nil {
+				return false, err
+			}
+			return result != NIL && result != FALSE, nil
+		}, nil
+	default:
+		return func(value RubyObject) (bool, error) {
+			return value != NIL && value != FALSE, nil
+		}, nil
+	}
+}
+
+// enumerableFindMatch reports whether any value of the receiver of context
+// matches or, if negate is true, does not match the pattern or block within
+// args
+func enumerableFindMatch(context CallContext, args []RubyObject, negate bool) (bool, error) {
+	matches, err := enumerableMatcher(context, args)
+	if err != nil {
+		return false, err
+	}
+	found := false
+	err = enumerate(context, func(value RubyObject) (bool, error) {
+		match, err := matches(value)
+		if err != nil {
+			return false, err
+		}
+		found = match != negate
+		return !found, nil
+	})
+	return found, err
+}
+
+func enumerableAll(context CallContext, args ...RubyObject) (RubyObject, error) {
+	found, err := enumerableFindMatch(context, args, true)
+	if err != nil {
+		return nil, err
+	}
+	return nativeBoolToBooleanObject(!found), nil
+}
+
+func enumerableAny(context CallContext, args ...RubyObject) (RubyObject, error) {
+	found, err := enumerableFindMatch(context, args, false)
+	if err != nil {
+		return nil, err
+	}
+	return nativeBoolToBooleanObject(found), nil
+}
+
+func enumerableNone(context CallContext, args ...RubyObject) (RubyObject, error) {
+	found, err := enumerableFindMatch(context, args, false)
+	if err != nil {
+		return nil, err
+	}
+	return nativeBoolToBooleanObject(!found), nil
+}
+
+// enumerableCount counts all values, the values equal to the argument or
+// the values the block returns a truthy value for
+func enumerableCount(context CallContext, args ...RubyObject) (RubyObject, error) {
+	block, args, hasBlock := extractBlockFromArgs(args)
+	if len(args) > 1 {
+		return nil, NewWrongNumberOfArgumentsRangeError(0, 1, len(args))
+	}
+	count := 0
+	err := enumerate(context, func(value RubyObject) (bool, error) {
+		switch {
+		case len(args) == 1:
+			equal, err := isEqual(context, value, args[0])
+			if err != nil {
+				return false, err
+			}
+			if equal {
+				count++
+			}
+		case hasBlock:
+			result, err := block.Call(context, value)
+			if err != nil {
+				return false, err
+			}
+			if result != NIL && result != FALSE {
+				count++
+			}
+		default:
+			count++
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewInteger(int64(count)), nil
+}
+
+// enumerableFirst returns the first value, or an Array of the first n values
+// if n is given. It stops the iteration as soon as it has enough values.
+func enumerableFirst(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if len(args) > 1 {
+		return nil, NewWrongNumberOfArgumentsRangeError(0, 1, len(args))
+	}
+	if len(args) == 0 {
+		var first RubyObject = NIL
+		err := enumerate(context, func(value RubyObject) (bool, error) {
+			first = value
+			return false, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return first, nil
+	}
+	n, err := enumerableSize(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, NewArgumentError("attempt to take negative size")
+	}
+	first := NewArray()
+	if n == 0 {
+		return first, nil
+	}
+	err = enumerate(context, func(value RubyObject) (bool, error) {
+		first.Elements = append(first.Elements, value)
+		return len(first.Elements) < n, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return first, nil
+}
+
+// enumerableSize returns the Integer value of arg
+func enumerableSize(arg RubyObject) (int, error) {
+	size, ok := arg.(*Integer)
+	if !ok {
+		return 0, NewImplicitConversionTypeError(size, arg)
+	}
+	return int(size.Value), nil
+}
+
+func enumerableSort(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return sendToEntries(context, "sort", args...)
+}
+
+func enumerableSortBy(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return sendToEntries(context, "sort_by", args...)
+}
+
+// enumerableExtremeBy returns the value with the smallest key returned by
+// the block if sign is -1 and the one with the biggest key if sign is 1
+func enumerableExtremeBy(context CallContext, args []RubyObject, sign int) (RubyObject, error) {
+	var extreme, extremeKey RubyObject
+	err := enumerateWithBlock(context, args, func(value, key RubyObject) (bool, error) {
+		if extreme == nil {
+			extreme, extremeKey = value, key
+			return true, nil
+		}
+		cmp, err := sortCompare(context, nil, key, extremeKey)
+		if err != nil {
+			return false, err
+		}
+		if cmp == sign {
+			extreme, extremeKey = value, key
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if extreme == nil {
+		return NIL, nil
+	}
+	return extreme, nil
+}
+
+func enumerableMinBy(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return enumerableExtremeBy(context, args, -1)
+}
+
+func enumerableMaxBy(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return enumerableExtremeBy(context, args, 1)
+}
+
+func enumerableEachWithIndex(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if len(args) == 0 {
+		return newEnumerator(context, "each_with_index"), nil
+	}
+	block, err := arrayIteration(args)
+	if err != nil {
+		return nil, err
+	}
+	index := 0
+	err = enumerate(context, func(value RubyObject) (bool, error) {
+		_, err := block.Call(context, value, NewInteger(int64(index)))
+		index++
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return context.Receiver(), nil
+}
+
+func enumerableEachWithObject(context CallContext, args ...RubyObject) (RubyObject, error) {
+	block, args, ok := extractBlockFromArgs(args)
+	if !ok {
+		return nil, NewNoBlockGivenLocalJumpError()
+	}
+	memo := args[0]
+	err := enumerate(context, func(value RubyObject) (bool, error) {
+		_, err := block.Call(context, value, memo)
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return memo, nil
+}
+
+func enumerableGroupBy(context CallContext, args ...RubyObject) (RubyObject, error) {
+	groups := NewHash()
+	err := enumerateWithBlock(context, args, func(value, key RubyObject) (bool, error) {
+		_, group, err := groups.find(context, key)
+		if err != nil {
+			return false, err
+		}
+		if group != nil {
+			members := group.Value.(*Array)
+			members.Elements = append(members.Elements, value)
+			return true, nil
+		}
+		return true, groups.store(context, key, NewArray(value))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func enumerablePartition(context CallContext, args ...RubyObject) (RubyObject, error) {
+	selected, rejected := NewArray(), NewArray()
+	err := enumerateWithBlock(context, args, func(value, result RubyObject) (bool, error) {
+		if result != NIL && result != FALSE {
+			selected.Elements = append(selected.Elements, value)
+		} else {
+			rejected.Elements = append(rejected.Elements, value)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewArray(selected, rejected), nil
+}
+
+// enumerableWindowArgs returns the block and the positive window size given
+// to each_slice and each_cons. The block is nil if none was given.
+func enumerableWindowArgs(args []RubyObject, message string) (*Proc, int, error) {
+	block, args, ok := extractBlockFromArgs(args)
+	if len(args) != 1 {
+		return nil, 0, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	size, err := enumerableSize(args[0])
+	if err != nil {
+		return nil, 0, err
+	}
+	if size <= 0 {
+		return nil, 0, NewArgumentError("%s", message)
+	}
+	if !ok {
+		return nil, size, nil
+	}
+	return block, size, nil
+}
+
+func enumerableEachSlice(context CallContext, args ...RubyObject) (RubyObject, error) {
+	block, size, err := enumerableWindowArgs(args, "invalid slice size")
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return newEnumerator(context, "each_slice", args...), nil
+	}
+	slice := NewArray()
+	err = enumerate(context, func(value RubyObject) (bool, error) {
+		slice.Elements = append(slice.Elements, value)
+		if len(slice.Elements) < size {
+			return true, nil
+		}
+		_, err := block.Call(context, slice)
+		slice = NewArray()
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(slice.Elements) > 0 {
+		if _, err := block.Call(context, slice); err != nil {
+			return nil, err
+		}
+	}
+	return context.Receiver(), nil
+}
+
+func enumerableEachCons(context CallContext, args ...RubyObject) (RubyObject, error) {
+	block, size, err := enumerableWindowArgs(args, "invalid size")
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return newEnumerator(context, "each_cons", args...), nil
+	}
+	var window []RubyObject
+	err = enumerate(context, func(value RubyObject) (bool, error) {
+		window = append(window, value)
+		if len(window) < size {
+			return true, nil
+		}
+		window = window[len(window)-size:]
+		_, err := block.Call(context, NewArray(window...))
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return context.Receiver(), nil
+}
+
+func enumerableZip(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return sendToEntries(context, "zip", args...)
+}
+
+// enumerableToH builds a Hash from the values, or the results of the block,
+// which have to be Arrays of a key and a value
+func enumerableToH(context CallContext, args ...RubyObject) (RubyObject, error) {
+	block, args, hasBlock := extractBlockFromArgs(args)
+	if len(args) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(args))
+	}
+	hash := NewHash()
+	index := 0
+	err := enumerate(context, func(value RubyObject) (bool, error) {
+		pair := value
+		if hasBlock {
+			var err error
+			pair, err = block.Call(context, value)
+			if err != nil {
+				return false, err
+			}
+		}
+		array, ok := pair.(*Array)
+		if !ok {
+			return false, NewTypeError(fmt.Sprintf(
+				"wrong element type %s at %d (expected array)", pair.Class().Name(), index,
+			))
+		}
+		if len(array.Elements) != 2 {
+			return false, NewArgumentError(
+				"wrong array length at %d (expected 2, was %d)", index, len(array.Elements),
+			)
+		}
+		index++
+		return true, hash.store(context, array.Elements[0], array.Elements[1])
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hash, nil
+}
+
+func enumerableTally(context CallContext, args ...RubyObject) (RubyObject, error) {
+	tally := NewHash()
+	err := enumerate(context, func(value RubyObject) (bool, error) {
+		_, pair, err := tally.find(context, value)
+		if err != nil {
+			return false, err
+		}
+		if pair != nil {
+			pair.Value = NewInteger(pair.Value.(*Integer).Value + 1)
+			return true, nil
+		}
+		return true, tally.store(context, value, NewInteger(1))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tally, nil
+}
+
+func enumerableSum(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return sendToEntries(context, "sum", args...)
+}
+
+func enumerableLazy(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return &Lazy{Source: context.Receiver()}, nil
+}
@@ -0,0 +1,162 @@
+package object
+
+import (
+	"testing"
+)
+
+func TestEnumerableMethods(t *testing.T) {
+	block := func(fn func(args ...RubyObject) RubyObject) *Proc {
+		return newNativeProc(func(args ...RubyObject) (RubyObject, error) {
+			return fn(args...), nil
+		})
+	}
+	double := block(func(args ...RubyObject) RubyObject {
+		return NewInteger(args[0].(*Integer).Value * 2)
+	})
+	isOdd := block(func(args ...RubyObject) RubyObject {
+		return nativeBoolToBooleanObject(args[0].(*Integer).Value%2 == 1)
+	})
+	add := block(func(args ...RubyObject) RubyObject {
+		return NewInteger(args[0].(*Integer).Value + args[1].(*Integer).Value)
+	})
+	negate := block(func(args ...RubyObject) RubyObject {
+		return NewInteger(-args[0].(*Integer).Value)
+	})
+	pair := block(func(args ...RubyObject) RubyObject {
+		return NewArray(args[0], NewInteger(args[0].(*Integer).Value*10))
+	})
+	integers := func(values ...int64) *Array {
+		array := NewArray()
+		for _, v := range values {
+			array.Elements = append(array.Elements, NewInteger(v))
+		}
+		return array
+	}
+	oneToFive := &Range{Begin: NewInteger(1), End: NewInteger(5)}
+
+	tests := []struct {
+		name      string
+		method    RubyMethod
+		arguments []RubyObject
+		result    string
+	}{
+		{"to_a", enumerableMethodSet["to_a"], nil, "[1, 2, 3, 4, 5]"},
+		{"map", enumerableMethodSet["map"], []RubyObject{double}, "[2, 4, 6, 8, 10]"},
+		{"select", enumerableMethodSet["select"], []RubyObject{isOdd}, "[1, 3, 5]"},
+		{"reject", enumerableMethodSet["reject"], []RubyObject{isOdd}, "[2, 4]"},
+		{"find", enumerableMethodSet["find"], []RubyObject{block(func(args ...RubyObject) RubyObject {
+			return nativeBoolToBooleanObject(args[0].(*Integer).Value > 3)
+		})}, "4"},
+		{"reduce with block", enumerableMethodSet["reduce"], []RubyObject{add}, "15"},
+		{"reduce with initial value", enumerableMethodSet["reduce"], []RubyObject{NewInteger(10), add}, "25"},
+		{"reduce with symbol", enumerableMethodSet["inject"], []RubyObject{NewSymbol("*")}, "120"},
+		{"all?", enumerableMethodSet["all?"], []RubyObject{isOdd}, "false"},
+		{"all? with pattern", enumerableMethodSet["all?"], []RubyObject{integerClass}, "true"},
+		{"any?", enumerableMethodSet["any?"], []RubyObject{isOdd}, "true"},
+		{"none?", enumerableMethodSet["none?"], nil, "false"},
+		{"count", enumerableMethodSet["count"], nil, "5"},
+		{"count with argument", enumerableMethodSet["count"], []RubyObject{NewInteger(2)}, "1"},
+		{"count with block", enumerableMethodSet["count"], []RubyObject{isOdd}, "3"},
+		{"first", enumerableMethodSet["first"], nil, "1"},
+		{"first n", enumerableMethodSet["first"], []RubyObject{NewInteger(2)}, "[1, 2]"},
+		{"min_by", enumerableMethodSet["min_by"], []RubyObject{negate}, "5"},
+		{"max_by", enumerableMethodSet["max_by"], []RubyObject{negate}, "1"},
+		{"group_by", enumerableMethodSet["group_by"], []RubyObject{isOdd}, "{true => [1, 3, 5], false => [2, 4]}"},
+		{"partition", enumerableMethodSet["partition"], []RubyObject{isOdd}, "[[1, 3, 5], [2, 4]]"},
+		{"to_h", enumerableMethodSet["to_h"], []RubyObject{pair}, "{1 => 10, 2 => 20, 3 => 30, 4 => 40, 5 => 50}"},
+		{"tally", enumerableMethodSet["tally"], nil, "{1 => 1, 2 => 1, 3 => 1, 4 => 1, 5 => 1}"},
+		{"zip", enumerableMethodSet["zip"], []RubyObject{integers(5, 4)}, "[[1, 5], [2, 4], [3, nil], [4, nil], [5, nil]]"},
+		{"zip with enumerable", enumerableMethodSet["zip"], []RubyObject{oneToFive}, "[[1, 1], [2, 2], [3, 3], [4, 4], [5, 5]]"},
+		{"each_with_object", enumerableMethodSet["each_with_object"], []RubyObject{NewArray(), block(func(args ...RubyObject) RubyObject {
+			memo := args[1].(*Array)
+			memo.Elements = append(memo.Elements, args[0])
+			return memo
+		})}, "[1, 2, 3, 4, 5]"},
+		{"lazy", enumerableMethodSet["lazy"], nil, "#<Enumerator::Lazy: 1..5>"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			context := &callContext{receiver: oneToFive, env: NewEnvironment()}
+
+			result, err := testCase.method.Call(context, testCase.arguments...)
+
+			checkError(t, err, nil)
+			if result.Inspect() != testCase.result {
+				t.Logf("Expected result to equal %s, got %s\n", testCase.result, result.Inspect())
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("windows", func(t *testing.T) {
+		var windows []RubyObject
+		collect := block(func(args ...RubyObject) RubyObject {
+			windows = append(windows, args[0])
+			return NIL
+		})
+		context := &callContext{receiver: oneToFive, env: NewEnvironment()}
+
+		result, err := enumerableEachSlice(context, NewInteger(2), collect)
+
+		checkError(t, err, nil)
+		checkResult(t, result, oneToFive)
+		checkResult(t, NewArray(windows...), NewArray(integers(1, 2), integers(3, 4), integers(5)))
+
+		windows = nil
+		result, err = enumerableEachCons(context, NewInteger(4), collect)
+
+		checkError(t, err, nil)
+		checkResult(t, result, oneToFive)
+		checkResult(t, NewArray(windows...), NewArray(integers(1, 2, 3, 4), integers(2, 3, 4, 5)))
+
+		_, err = enumerableEachSlice(context, NewInteger(0), collect)
+
+		checkError(t, err, NewArgumentError("invalid slice size"))
+	})
+	t.Run("stops early", func(t *testing.T) {
+		endless := &Range{Begin: NewInteger(1), End: NIL}
+		context := &callContext{receiver: endless, env: NewEnvironment()}
+
+		result, err := enumerableFirst(context, NewInteger(3))
+
+		checkError(t, err, nil)
+		checkResult(t, result, integers(1, 2, 3))
+
+		result, err = enumerableAny(context, isOdd)
+
+		checkError(t, err, nil)
+		checkResult(t, result, TRUE)
+	})
+	t.Run("enumerators without block", func(t *testing.T) {
+		context := &callContext{receiver: oneToFive, env: NewEnvironment()}
+
+		result, err := enumerableEachSlice(context, NewInteger(2))
+
+		checkError(t, err, nil)
+		checkResult(t, result, &Enumerator{Receiver: oneToFive, Method: "each_slice", Args: []RubyObject{NewInteger(2)}})
+
+		result, err = enumerableEachWithIndex(context)
+
+		checkError(t, err, nil)
+		checkResult(t, result, &Enumerator{Receiver: oneToFive, Method: "each_with_index"})
+
+		_, err = enumerableEachCons(context, NewInteger(0))
+
+		checkError(t, err, NewArgumentError("invalid size"))
+	})
+	t.Run("without block", func(t *testing.T) {
+		context := &callContext{receiver: oneToFive, env: NewEnvironment()}
+
+		_, err := enumerableMap(context)
+
+		checkError(t, err, NewNoBlockGivenLocalJumpError())
+	})
+	t.Run("to_h with invalid elements", func(t *testing.T) {
+		context := &callContext{receiver: oneToFive, env: NewEnvironment()}
+
+		_, err := enumerableToH(context)
+
+		checkError(t, err, NewTypeError("wrong element type Integer at 0 (expected array)"))
+	})
+}
@@ -0,0 +1,87 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+)
+
+var enumeratorClass RubyClassObject = newMixin(newClass(
+	"Enumerator",
+	objectClass,
+	enumeratorMethods,
+	enumeratorClassMethods,
+	notInstantiatable,
+), enumerableModule)
+
+func init() {
+	classes.Set("Enumerator", enumeratorClass)
+}
+
+// An Enumerator represents the iteration of an iterating method called
+// without a block, e.g. `"abc".each_char`. Its each calls Method with Args
+// and the block on Receiver, which makes all Enumerable methods available
+// for it. External iteration with next is not supported.
+type Enumerator struct {
+	Receiver RubyObject
+	Method   string
+	Args     []RubyObject
+}
+
+// newEnumerator returns an Enumerator for the method called on the
+// receiver of context with args
+func newEnumerator(context CallContext, method string, args ...RubyObject) *Enumerator {
+	receiver := context.Receiver()
+	if self, ok := receiver.(*Self); ok {
+		receiver = self.RubyObject
+	}
+	return &Enumerator{Receiver: receiver, Method: method, Args: args}
+}
+
+// Inspect returns the receiver, method and arguments in the format of MRI,
+// e.g. `#<Enumerator: [1, 2, 3]:each_slice(2)>`
+func (e *Enumerator) Inspect() string {
+	var args string
+	if len(e.Args) != 0 {
+		inspected := make([]string, len(e.Args))
+		for i, arg := range e.Args {
+			inspected[i] = arg.Inspect()
+		}
+		args = "(" + strings.Join(inspected, ", ") + ")"
+	}
+	return fmt.Sprintf("#<Enumerator: %s:%s%s>", e.Receiver.Inspect(), e.Method, args)
+}
+
+// Type returns ENUMERATOR_OBJ
+func (e *Enumerator) Type() Type { return ENUMERATOR_OBJ }
+
+// Class returns enumeratorClass
+func (e *Enumerator) Class() RubyClass { return enumeratorClass }
+
+var enumeratorClassMethods = map[string]RubyMethod{}
+
+var enumeratorMethods = map[string]RubyMethod{
+	"each":    publicMethod(enumeratorEach),
+	"inspect": withArity(0, publicMethod(enumeratorInspect)),
+	"to_s":    withArity(0, publicMethod(enumeratorInspect)),
+}
+
+func enumeratorEach(context CallContext, args ...RubyObject) (RubyObject, error) {
+	enumerator := context.Receiver().(*Enumerator)
+	block, args, ok := extractBlockFromArgs(args)
+	if len(args) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(args))
+	}
+	if !ok {
+		return enumerator, nil
+	}
+	arguments := append(append([]RubyObject{}, enumerator.Args...), block)
+	return Send(
+		&callContext{receiver: enumerator.Receiver, env: context.Env(), eval: context.Eval},
+		enumerator.Method,
+		arguments...,
+	)
+}
+
+func enumeratorInspect(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return &String{Value: context.Receiver().Inspect()}, nil
+}
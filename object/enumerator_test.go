@@ -0,0 +1,58 @@
+package object
+
+import (
+	"testing"
+)
+
+func TestEnumerator(t *testing.T) {
+	var values []RubyObject
+	collect := newNativeProc(func(args ...RubyObject) (RubyObject, error) {
+		values = append(values, NewArray(args...))
+		return NIL, nil
+	})
+	array := NewArray(NewInteger(4), NewInteger(5), NewInteger(6))
+
+	t.Run("each", func(t *testing.T) {
+		values = nil
+		enumerator := &Enumerator{Receiver: array, Method: "each_with_index"}
+		context := &callContext{receiver: enumerator, env: NewEnvironment()}
+
+		result, err := enumeratorEach(context, collect)
+
+		checkError(t, err, nil)
+		checkResult(t, result, array)
+		expected := NewArray(
+			NewArray(NewInteger(4), NewInteger(0)),
+			NewArray(NewInteger(5), NewInteger(1)),
+			NewArray(NewInteger(6), NewInteger(2)),
+		)
+		checkResult(t, NewArray(values...), expected)
+	})
+	t.Run("each without block", func(t *testing.T) {
+		enumerator := &Enumerator{Receiver: array, Method: "each_with_index"}
+		context := &callContext{receiver: enumerator, env: NewEnvironment()}
+
+		result, err := enumeratorEach(context)
+
+		checkError(t, err, nil)
+		checkResult(t, result, enumerator)
+	})
+	t.Run("Enumerable methods", func(t *testing.T) {
+		enumerator := &Enumerator{Receiver: array, Method: "each_slice", Args: []RubyObject{NewInteger(2)}}
+		context := &callContext{receiver: enumerator, env: NewEnvironment()}
+
+		result, err := enumerableToA(context)
+
+		checkError(t, err, nil)
+		checkResult(t, result, NewArray(NewArray(NewInteger(4), NewInteger(5)), NewArray(NewInteger(6))))
+	})
+	t.Run("Inspect", func(t *testing.T) {
+		enumerator := &Enumerator{Receiver: array, Method: "each_slice", Args: []RubyObject{NewInteger(2)}}
+
+		expected := "#<Enumerator: [4, 5, 6]:each_slice(2)>"
+		if enumerator.Inspect() != expected {
+			t.Logf("Expected Inspect to return %s, got %s\n", expected, enumerator.Inspect())
+			t.Fail()
+		}
+	})
+}
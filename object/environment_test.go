@@ -7,7 +7,7 @@ import (
 
 func TestEnvStat(t *testing.T) {
 	t.Run("top level env", func(t *testing.T) {
-		obj := &Integer{42}
+		obj := NewInteger(42)
 
 		env := &environment{store: map[string]RubyObject{"foo": obj}}
 
@@ -32,7 +32,7 @@ func TestEnvStat(t *testing.T) {
 		}
 	})
 	t.Run("two level nested", func(t *testing.T) {
-		obj := &Integer{42}
+		obj := NewInteger(42)
 
 		root := &environment{store: map[string]RubyObject{"foo": obj}}
 		outer := &environment{store: make(map[string]RubyObject), outer: root}
@@ -59,7 +59,7 @@ func TestEnvStat(t *testing.T) {
 		}
 	})
 	t.Run("two level nested same value with different keys", func(t *testing.T) {
-		obj := &Integer{42}
+		obj := NewInteger(42)
 
 		root := &environment{store: map[string]RubyObject{"foo": obj}}
 		outer := &environment{store: map[string]RubyObject{"bar": obj}, outer: root}
@@ -86,7 +86,7 @@ func TestEnvStat(t *testing.T) {
 		}
 	})
 	t.Run("two level nested overshadowed key", func(t *testing.T) {
-		obj := &Integer{42}
+		obj := NewInteger(42)
 
 		root := &environment{store: map[string]RubyObject{"foo": obj}}
 		outer := &environment{store: map[string]RubyObject{"foo": TRUE}, outer: root}
@@ -113,7 +113,7 @@ func TestEnvStat(t *testing.T) {
 		}
 	})
 	t.Run("two level nested not found", func(t *testing.T) {
-		obj := &Integer{42}
+		obj := NewInteger(42)
 
 		root := &environment{store: map[string]RubyObject{"foo": FALSE}}
 		outer := &environment{store: map[string]RubyObject{"bar": TRUE}, outer: root}
@@ -1,8 +1,11 @@
 package object
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
+
+	"github.com/pkg/errors"
 )
 
 var (
@@ -33,6 +36,15 @@ var (
 			return &RuntimeError{message: c.Name()}, nil
 		},
 	)
+	frozenErrorClass RubyClassObject = newClass(
+		"FrozenError",
+		runtimeErrorClass,
+		nil,
+		nil,
+		func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
+			return &FrozenError{message: c.Name()}, nil
+		},
+	)
 	zeroDivisionErrorClass RubyClassObject = newClass(
 		"ZeroDivisionError",
 		standardErrorClass,
@@ -114,6 +126,24 @@ var (
 			return &NotImplementedError{message: c.Name()}, nil
 		},
 	)
+	rangeErrorClass RubyClassObject = newClass(
+		"RangeError",
+		standardErrorClass,
+		nil,
+		nil,
+		func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
+			return &RangeError{message: c.Name()}, nil
+		},
+	)
+	floatDomainErrorClass RubyClassObject = newClass(
+		"FloatDomainError",
+		rangeErrorClass,
+		nil,
+		nil,
+		func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
+			return &FloatDomainError{message: c.Name()}, nil
+		},
+	)
 	localJumpErrorClass RubyClassObject = newClass(
 		"LocalJumpError",
 		standardErrorClass,
@@ -123,11 +153,49 @@ var (
 			return &LocalJumpError{message: c.Name()}, nil
 		},
 	)
+	noMatchingPatternErrorClass RubyClassObject = newClass(
+		"NoMatchingPatternError",
+		standardErrorClass,
+		nil,
+		nil,
+		func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
+			return &NoMatchingPatternError{message: c.Name()}, nil
+		},
+	)
+	indexErrorClass RubyClassObject = newClass(
+		"IndexError",
+		standardErrorClass,
+		nil,
+		nil,
+		func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
+			return &IndexError{message: c.Name()}, nil
+		},
+	)
+	keyErrorClass RubyClassObject = newClass(
+		"KeyError",
+		indexErrorClass,
+		nil,
+		nil,
+		func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
+			return &KeyError{message: c.Name()}, nil
+		},
+	)
+	regexpErrorClass RubyClassObject = newClass(
+		"RegexpError",
+		standardErrorClass,
+		nil,
+		nil,
+		func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
+			return &RegexpError{message: c.Name()}, nil
+		},
+	)
 )
 
 func init() {
 	classes.Set("Exception", exceptionClass)
 	classes.Set("StandardError", standardErrorClass)
+	classes.Set("RuntimeError", runtimeErrorClass)
+	classes.Set("FrozenError", frozenErrorClass)
 	classes.Set("ZeroDivisionError", zeroDivisionErrorClass)
 	classes.Set("ArgumentError", argumentErrorClass)
 	classes.Set("NameError", nameErrorClass)
@@ -137,19 +205,88 @@ func init() {
 	classes.Set("LoadError", loadErrorClass)
 	classes.Set("SyntaxError", syntaxErrorClass)
 	classes.Set("NotImplementedError", notImplementedErrorClass)
+	classes.Set("RangeError", rangeErrorClass)
+	classes.Set("FloatDomainError", floatDomainErrorClass)
+	classes.Set("LocalJumpError", localJumpErrorClass)
+	classes.Set("NoMatchingPatternError", noMatchingPatternErrorClass)
+	classes.Set("IndexError", indexErrorClass)
+	classes.Set("KeyError", keyErrorClass)
+	classes.Set("RegexpError", regexpErrorClass)
 }
 
 func formatException(exception RubyObject, message string) string {
 	return fmt.Sprintf("%s: %s", reflect.TypeOf(exception).Elem().Name(), message)
 }
 
+// AddBacktrace attaches backtrace to the Ruby exception err is caused by,
+// unless it already carries a backtrace from being raised before.
+func AddBacktrace(err error, backtrace []string) {
+	exc, ok := errors.Cause(err).(exception)
+	if !ok || exc.Backtrace() != nil {
+		return
+	}
+	exc.setBacktrace(backtrace)
+}
+
+// IsStandardError reports whether obj is an instance of StandardError or one
+// of its subclasses, i.e. whether it is rescued by a bare rescue clause
+func IsStandardError(obj RubyObject) bool {
+	return isA(obj, standardErrorClass)
+}
+
+// FullMessage formats the exception err is caused by the way MRI prints
+// uncaught exceptions, i.e. the place it was raised at, the message and the
+// class name, followed by the remaining backtrace lines.
+func FullMessage(err error) string {
+	err = errors.Cause(err)
+	exc, ok := err.(exception)
+	if !ok {
+		return err.Error()
+	}
+	var out bytes.Buffer
+	backtrace := exc.Backtrace()
+	if len(backtrace) > 0 {
+		fmt.Fprintf(&out, "%s: ", backtrace[0])
+	}
+	fmt.Fprintf(&out, "%s (%s)", exc.Error(), exc.(RubyObject).Class().Name())
+	for i := 1; i < len(backtrace); i++ {
+		fmt.Fprintf(&out, "\n\tfrom %s", backtrace[i])
+	}
+	return out.String()
+}
+
 type exception interface {
 	setErrorMessage(string)
+	setBacktrace([]string)
+	Backtrace() []string
+	setCause(RubyObject)
+	Cause() RubyObject
 	error
 }
 
+// exceptionState holds the state all exceptions share once raised, i.e.
+// their backtrace and cause
+type exceptionState struct {
+	backtrace []string
+	cause     RubyObject
+}
+
+func (e *exceptionState) setBacktrace(backtrace []string) {
+	e.backtrace = backtrace
+}
+
+// Backtrace returns the backtrace of the place the exception was raised at
+func (e *exceptionState) Backtrace() []string { return e.backtrace }
+
+func (e *exceptionState) setCause(cause RubyObject) {
+	e.cause = cause
+}
+
+// Cause returns the exception which was being handled when e was raised
+func (e *exceptionState) Cause() RubyObject { return e.cause }
+
 // NewException creates a new exception with the given message template and
-//uses fmt.Sprintf to interpolate the args into messageinto message.
+// uses fmt.Sprintf to interpolate the args into messageinto message.
 func NewException(message string, args ...interface{}) *Exception {
 	return &Exception{message: fmt.Sprintf(message, args...)}
 }
@@ -157,6 +294,7 @@ func NewException(message string, args ...interface{}) *Exception {
 // Exception represents a basic exception
 type Exception struct {
 	message string
+	exceptionState
 }
 
 // Type returns the type of the RubyObject
@@ -178,9 +316,13 @@ var exceptionClassMethods = map[string]RubyMethod{
 }
 
 var exceptionMethods = map[string]RubyMethod{
-	"initialize": privateMethod(exceptionInitialize),
-	"exception":  publicMethod(exceptionException),
-	"to_s":       withArity(0, publicMethod(exceptionToS)),
+	"initialize":   privateMethod(exceptionInitialize),
+	"exception":    publicMethod(exceptionException),
+	"to_s":         withArity(0, publicMethod(exceptionToS)),
+	"message":      withArity(0, publicMethod(exceptionMessage)),
+	"full_message": publicMethod(exceptionFullMessage),
+	"backtrace":    withArity(0, publicMethod(exceptionBacktrace)),
+	"cause":        withArity(0, publicMethod(exceptionCause)),
 }
 
 func exceptionInitialize(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -205,29 +347,11 @@ func exceptionInitialize(context CallContext, args ...RubyObject) (RubyObject, e
 
 func exceptionClassException(context CallContext, args ...RubyObject) (RubyObject, error) {
 	receiver := context.Receiver()
-	var message string
-	class, ok := receiver.(RubyClass)
-	if ok {
-		receiver, _ = class.New()
-	}
-	if class == nil {
-		class = receiver.Class()
-	}
-	message = class.Name()
-	if len(args) == 1 {
-		msg, err := stringify(args[0])
-		if err != nil {
-			return nil, err
-		}
-		message = msg
-	}
-	if exception, ok := receiver.(exception); ok {
-		msg := exception.Error()
-		if msg != message {
-			exception.setErrorMessage(message)
-		}
+	if self, ok := receiver.(*Self); ok {
+		receiver = self.RubyObject
 	}
-	return receiver, nil
+	newContext := &callContext{receiver: receiver, env: context.Env(), eval: context.Eval}
+	return Send(newContext, "new", args...)
 }
 
 func exceptionException(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -266,6 +390,47 @@ func exceptionToS(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return nil, nil
 }
 
+func exceptionMessage(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return Send(context, "to_s")
+}
+
+func exceptionFullMessage(context CallContext, args ...RubyObject) (RubyObject, error) {
+	receiver := context.Receiver()
+	if self, ok := receiver.(*Self); ok {
+		receiver = self.RubyObject
+	}
+	exc, ok := receiver.(exception)
+	if !ok {
+		return nil, NewTypeError("exception object expected")
+	}
+	return &String{Value: FullMessage(exc)}, nil
+}
+
+func exceptionCause(context CallContext, args ...RubyObject) (RubyObject, error) {
+	receiver := context.Receiver()
+	if self, ok := receiver.(*Self); ok {
+		receiver = self.RubyObject
+	}
+	exc, ok := receiver.(exception)
+	if !ok || exc.Cause() == nil {
+		return NIL, nil
+	}
+	return exc.Cause(), nil
+}
+
+func exceptionBacktrace(context CallContext, args ...RubyObject) (RubyObject, error) {
+	receiver := context.Receiver()
+	exc, ok := receiver.(exception)
+	if !ok || exc.Backtrace() == nil {
+		return NIL, nil
+	}
+	backtrace := NewArray()
+	for _, line := range exc.Backtrace() {
+		backtrace.Elements = append(backtrace.Elements, &String{Value: line})
+	}
+	return backtrace, nil
+}
+
 // NewStandardError returns a StandardError with the given message
 func NewStandardError(message string) *StandardError {
 	return &StandardError{message: message}
@@ -274,6 +439,7 @@ func NewStandardError(message string) *StandardError {
 // StandardError is the default class for rescue blocks
 type StandardError struct {
 	message string
+	exceptionState
 }
 
 // Type returns EXCEPTION_OBJ
@@ -300,6 +466,7 @@ func NewRuntimeError(format string, args ...interface{}) *RuntimeError {
 // RuntimeError is a generic error class raised when an invalid operation is attempted
 type RuntimeError struct {
 	message string
+	exceptionState
 }
 
 // Type returns EXCEPTION_OBJ
@@ -316,6 +483,34 @@ func (e *RuntimeError) setErrorMessage(msg string) {
 // Class returns runtimeErrorClass
 func (e *RuntimeError) Class() RubyClass { return runtimeErrorClass }
 
+// NewFrozenError returns a FrozenError for an attempt to modify the frozen
+// object obj
+func NewFrozenError(obj RubyObject) *FrozenError {
+	return &FrozenError{
+		message: fmt.Sprintf("can't modify frozen %s: %s", obj.Class().Name(), obj.Inspect()),
+	}
+}
+
+// FrozenError represents an error for an attempt to modify a frozen object
+type FrozenError struct {
+	message string
+	exceptionState
+}
+
+// Type returns EXCEPTION_OBJ
+func (e *FrozenError) Type() Type { return EXCEPTION_OBJ }
+
+// Inspect returns a string starting with the exception class name, followed by the message
+func (e *FrozenError) Inspect() string { return formatException(e, e.message) }
+func (e *FrozenError) Error() string   { return e.message }
+
+func (e *FrozenError) setErrorMessage(msg string) {
+	e.message = msg
+}
+
+// Class returns frozenErrorClass
+func (e *FrozenError) Class() RubyClass { return frozenErrorClass }
+
 // NewZeroDivisionError returns a new ZeroDivisionError with the default message
 func NewZeroDivisionError() *ZeroDivisionError {
 	return &ZeroDivisionError{
@@ -326,6 +521,7 @@ func NewZeroDivisionError() *ZeroDivisionError {
 // ZeroDivisionError represents an arithmethic error when dividing through 0
 type ZeroDivisionError struct {
 	message string
+	exceptionState
 }
 
 // Type returns EXCEPTION_OBJ
@@ -353,6 +549,26 @@ func NewWrongNumberOfArgumentsError(expected, actual int) *ArgumentError {
 	}
 }
 
+// NewWrongNumberOfArgumentsRangeError returns an ArgumentError for a method
+// accepting between min and max arguments. A max of -1 means there is no
+// upper limit.
+func NewWrongNumberOfArgumentsRangeError(min, max, actual int) *ArgumentError {
+	expected := fmt.Sprintf("%d..%d", min, max)
+	switch {
+	case max < 0:
+		expected = fmt.Sprintf("%d+", min)
+	case min == max:
+		expected = fmt.Sprintf("%d", min)
+	}
+	return &ArgumentError{
+		message: fmt.Sprintf(
+			"wrong number of arguments (given %d, expected %s)",
+			actual,
+			expected,
+		),
+	}
+}
+
 // NewArgumentError creates an ArgumentError. It has the same API as fmt.Errorf
 func NewArgumentError(format string, args ...interface{}) *ArgumentError {
 	return &ArgumentError{
@@ -363,6 +579,7 @@ func NewArgumentError(format string, args ...interface{}) *ArgumentError {
 // ArgumentError represents an error in method call arguments
 type ArgumentError struct {
 	message string
+	exceptionState
 }
 
 // Type returns EXCEPTION_OBJ
@@ -401,9 +618,22 @@ func NewUndefinedLocalVariableOrMethodNameError(context RubyObject, name string)
 	}
 }
 
+// NewUndefinedMethodNameError returns a NameError with the default message
+// for looking up methods class does not have, as done by Kernel#method
+func NewUndefinedMethodNameError(class RubyClass, name string) *NameError {
+	return &NameError{
+		message: fmt.Sprintf(
+			"undefined method `%s' for class `%s'",
+			name,
+			class.(RubyObject).Inspect(),
+		),
+	}
+}
+
 // A NameError represents an error accessing an identifier unknown to the environment
 type NameError struct {
 	message string
+	exceptionState
 }
 
 // Type returns EXCEPTION_OBJ
@@ -444,9 +674,23 @@ func NewPrivateNoMethodError(context RubyObject, method string) *NoMethodError {
 	}
 }
 
+// NewNoSuperMethodError returns a NoMethodError with the default message for
+// super calls without an implementation in a superclass
+func NewNoSuperMethodError(context RubyObject, method string) *NoMethodError {
+	return &NoMethodError{
+		message: fmt.Sprintf(
+			"super: no superclass method `%s' for %s:%s",
+			method,
+			context.Inspect(),
+			context.Class().(RubyObject).Inspect(),
+		),
+	}
+}
+
 // NoMethodError represents an error finding a fitting method on an object
 type NoMethodError struct {
 	message string
+	exceptionState
 }
 
 // Type returns EXCEPTION_OBJ
@@ -504,6 +748,7 @@ func NewTypeError(message string) *TypeError {
 // TypeError represents an error when the given type does not fit in the given context
 type TypeError struct {
 	message string
+	exceptionState
 }
 
 // Type returns EXCEPTION_OBJ
@@ -528,6 +773,7 @@ func NewScriptError(format string, args ...interface{}) *ScriptError {
 // ScriptError represetns an error in the loaded script
 type ScriptError struct {
 	message string
+	exceptionState
 }
 
 // Type returns EXCEPTION_OBJ
@@ -557,6 +803,7 @@ func NewNoSuchFileLoadError(filepath string) *LoadError {
 // LoadError represents an error while loading another file
 type LoadError struct {
 	message string
+	exceptionState
 }
 
 // Type returns EXCEPTION_OBJ
@@ -588,6 +835,7 @@ func NewSyntaxError(syntaxError error) *SyntaxError {
 type SyntaxError struct {
 	err     error
 	message string
+	exceptionState
 }
 
 // Type returns EXCEPTION_OBJ
@@ -615,6 +863,7 @@ func NewNotImplementedError(format string, args ...interface{}) *NotImplementedE
 // NotImplementedError represents an error for a not implemented feature on a given platform
 type NotImplementedError struct {
 	message string
+	exceptionState
 }
 
 // Type returns EXCEPTION_OBJ
@@ -631,6 +880,56 @@ func (e *NotImplementedError) setErrorMessage(msg string) {
 // Class returns notImplementedErrorClass
 func (e *NotImplementedError) Class() RubyClass { return notImplementedErrorClass }
 
+// NewRangeError returns a RangeError with the formatted message
+func NewRangeError(format string, args ...interface{}) *RangeError {
+	return &RangeError{message: fmt.Sprintf(format, args...)}
+}
+
+// RangeError represents an error for a value out of its valid range
+type RangeError struct {
+	message string
+	exceptionState
+}
+
+// Type returns EXCEPTION_OBJ
+func (e *RangeError) Type() Type { return EXCEPTION_OBJ }
+
+// Inspect returns a string starting with the exception class name, followed by the message
+func (e *RangeError) Inspect() string { return formatException(e, e.message) }
+func (e *RangeError) Error() string   { return e.message }
+
+func (e *RangeError) setErrorMessage(msg string) {
+	e.message = msg
+}
+
+// Class returns rangeErrorClass
+func (e *RangeError) Class() RubyClass { return rangeErrorClass }
+
+// NewFloatDomainError returns a FloatDomainError for the formatted float value
+func NewFloatDomainError(value string) *FloatDomainError {
+	return &FloatDomainError{message: value}
+}
+
+// FloatDomainError represents an error for a Float which cannot be converted, e.g. Infinity or NaN
+type FloatDomainError struct {
+	message string
+	exceptionState
+}
+
+// Type returns EXCEPTION_OBJ
+func (e *FloatDomainError) Type() Type { return EXCEPTION_OBJ }
+
+// Inspect returns a string starting with the exception class name, followed by the message
+func (e *FloatDomainError) Inspect() string { return formatException(e, e.message) }
+func (e *FloatDomainError) Error() string   { return e.message }
+
+func (e *FloatDomainError) setErrorMessage(msg string) {
+	e.message = msg
+}
+
+// Class returns floatDomainErrorClass
+func (e *FloatDomainError) Class() RubyClass { return floatDomainErrorClass }
+
 // NewNoBlockGivenLocalJumpError returns a LocalJumpError with the default message for missing blocks
 func NewNoBlockGivenLocalJumpError() *LocalJumpError {
 	return &LocalJumpError{message: "no block given (yield)"}
@@ -639,6 +938,7 @@ func NewNoBlockGivenLocalJumpError() *LocalJumpError {
 // LocalJumpError represents an error for a not supported jump
 type LocalJumpError struct {
 	message string
+	exceptionState
 }
 
 // Type returns EXCEPTION_OBJ
@@ -652,5 +952,148 @@ func (e *LocalJumpError) setErrorMessage(msg string) {
 	e.message = msg
 }
 
-// Class returns notImplementedErrorClass
-func (e *LocalJumpError) Class() RubyClass { return notImplementedErrorClass }
+// Class returns localJumpErrorClass
+func (e *LocalJumpError) Class() RubyClass { return localJumpErrorClass }
+
+// NewNoMatchingPatternError returns a NoMatchingPatternError for a value
+// no in clause of a case expression matched
+func NewNoMatchingPatternError(value RubyObject) *NoMatchingPatternError {
+	return &NoMatchingPatternError{message: value.Inspect()}
+}
+
+// NoMatchingPatternError represents an error for a value not matching any
+// pattern of a case expression
+type NoMatchingPatternError struct {
+	message string
+	exceptionState
+}
+
+// Type returns EXCEPTION_OBJ
+func (e *NoMatchingPatternError) Type() Type { return EXCEPTION_OBJ }
+
+// Inspect returns a string starting with the exception class name, followed by the message
+func (e *NoMatchingPatternError) Inspect() string { return formatException(e, e.message) }
+func (e *NoMatchingPatternError) Error() string   { return e.message }
+
+func (e *NoMatchingPatternError) setErrorMessage(msg string) {
+	e.message = msg
+}
+
+// Class returns noMatchingPatternErrorClass
+func (e *NoMatchingPatternError) Class() RubyClass { return noMatchingPatternErrorClass }
+
+// NewIndexError returns an IndexError with the formatted message
+func NewIndexError(format string, args ...interface{}) *IndexError {
+	return &IndexError{message: fmt.Sprintf(format, args...)}
+}
+
+// IndexError represents an error for an index or key which is out of range
+// or does not exist
+type IndexError struct {
+	message string
+	exceptionState
+}
+
+// Type returns EXCEPTION_OBJ
+func (e *IndexError) Type() Type { return EXCEPTION_OBJ }
+
+// Inspect returns a string starting with the exception class name, followed by the message
+func (e *IndexError) Inspect() string { return formatException(e, e.message) }
+func (e *IndexError) Error() string   { return e.message }
+
+func (e *IndexError) setErrorMessage(msg string) {
+	e.message = msg
+}
+
+// Class returns indexErrorClass
+func (e *IndexError) Class() RubyClass { return indexErrorClass }
+
+// NewKeyError returns a KeyError for a key which was not found
+func NewKeyError(key RubyObject) *KeyError {
+	return &KeyError{message: fmt.Sprintf("key not found: %s", key.Inspect())}
+}
+
+// KeyError represents an error for a key which does not exist
+type KeyError struct {
+	message string
+	exceptionState
+}
+
+// Type returns EXCEPTION_OBJ
+func (e *KeyError) Type() Type { return EXCEPTION_OBJ }
+
+// Inspect returns a string starting with the exception class name, followed by the message
+func (e *KeyError) Inspect() string { return formatException(e, e.message) }
+func (e *KeyError) Error() string   { return e.message }
+
+func (e *KeyError) setErrorMessage(msg string) {
+	e.message = msg
+}
+
+// Class returns keyErrorClass
+func (e *KeyError) Class() RubyClass { return keyErrorClass }
+
+// NewRegexpError returns a RegexpError with the formatted message
+func NewRegexpError(format string, args ...interface{}) *RegexpError {
+	return &RegexpError{message: fmt.Sprintf(format, args...)}
+}
+
+// RegexpError represents an error for an invalid or unsupported regular
+// expression
+type RegexpError struct {
+	message string
+	exceptionState
+}
+
+// Type returns EXCEPTION_OBJ
+func (e *RegexpError) Type() Type { return EXCEPTION_OBJ }
+
+// Inspect returns a string starting with the exception class name, followed by the message
+func (e *RegexpError) Inspect() string { return formatException(e, e.message) }
+func (e *RegexpError) Error() string   { return e.message }
+
+func (e *RegexpError) setErrorMessage(msg string) {
+	e.message = msg
+}
+
+// Class returns regexpErrorClass
+func (e *RegexpError) Class() RubyClass { return regexpErrorClass }
+
+// isExceptionClass reports whether class is Exception or one of its subclasses
+func isExceptionClass(class RubyClass) bool {
+	for ; class != nil; class = class.SuperClass() {
+		if class == exceptionClass {
+			return true
+		}
+	}
+	return false
+}
+
+// exceptionInstanceBuilder builds the instances of user defined exception classes
+var exceptionInstanceBuilder = func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
+	return &exceptionInstance{class: c, message: c.Name(), Environment: NewEnvironment()}, nil
+}
+
+// exceptionInstance represents an instance of a user defined subclass of
+// Exception
+type exceptionInstance struct {
+	class   RubyClassObject
+	message string
+	exceptionState
+	// Environment holds the instance variables
+	Environment
+}
+
+// Type returns EXCEPTION_OBJ
+func (e *exceptionInstance) Type() Type { return EXCEPTION_OBJ }
+
+// Inspect returns a string starting with the exception class name, followed by the message
+func (e *exceptionInstance) Inspect() string { return fmt.Sprintf("%s: %s", e.class.Name(), e.message) }
+func (e *exceptionInstance) Error() string   { return e.message }
+
+func (e *exceptionInstance) setErrorMessage(msg string) {
+	e.message = msg
+}
+
+// Class returns the user defined exception class
+func (e *exceptionInstance) Class() RubyClass { return e.class }
@@ -1,6 +1,11 @@
 package object
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+)
 
 func TestExceptionInitialize(t *testing.T) {
 	context := &callContext{
@@ -104,3 +109,134 @@ func TestExceptionToS(t *testing.T) {
 
 	checkResult(t, result, &String{Value: "x"})
 }
+
+func TestExceptionBacktrace(t *testing.T) {
+	t.Run("not raised", func(t *testing.T) {
+		context := &callContext{
+			receiver: &Exception{message: "x"},
+			env:      NewMainEnvironment(),
+		}
+
+		result, err := exceptionBacktrace(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, NIL)
+	})
+	t.Run("raised", func(t *testing.T) {
+		context := &callContext{
+			receiver: &Exception{message: "x", exceptionState: exceptionState{backtrace: []string{"foo.rb:2:in `bar'", "foo.rb:5:in `<main>'"}}},
+			env:      NewMainEnvironment(),
+		}
+
+		result, err := exceptionBacktrace(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, NewArray(&String{Value: "foo.rb:2:in `bar'"}, &String{Value: "foo.rb:5:in `<main>'"}))
+	})
+}
+
+func TestExceptionMessage(t *testing.T) {
+	context := &callContext{
+		receiver: &Exception{message: "x"},
+		env:      NewMainEnvironment(),
+	}
+
+	result, err := exceptionMessage(context)
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, &String{Value: "x"})
+}
+
+func TestExceptionFullMessage(t *testing.T) {
+	context := &callContext{
+		receiver: &Exception{message: "x", exceptionState: exceptionState{backtrace: []string{"foo.rb:2:in `bar'"}}},
+		env:      NewMainEnvironment(),
+	}
+
+	result, err := exceptionFullMessage(context)
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, &String{Value: "foo.rb:2:in `bar': x (Exception)"})
+}
+
+func TestExceptionCause(t *testing.T) {
+	t.Run("without cause", func(t *testing.T) {
+		context := &callContext{
+			receiver: &Exception{message: "x"},
+			env:      NewMainEnvironment(),
+		}
+
+		result, err := exceptionCause(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, NIL)
+	})
+	t.Run("with cause", func(t *testing.T) {
+		cause := &RuntimeError{message: "y"}
+		context := &callContext{
+			receiver: &Exception{message: "x", exceptionState: exceptionState{cause: cause}},
+			env:      NewMainEnvironment(),
+		}
+
+		result, err := exceptionCause(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, cause)
+	})
+}
+
+func TestAddBacktrace(t *testing.T) {
+	t.Run("new exception", func(t *testing.T) {
+		exc := &RuntimeError{message: "x"}
+
+		AddBacktrace(errors.WithStack(exc), []string{"foo.rb:1:in `<main>'"})
+
+		checkResult(t, exc, &RuntimeError{message: "x", exceptionState: exceptionState{backtrace: []string{"foo.rb:1:in `<main>'"}}})
+	})
+	t.Run("raised before", func(t *testing.T) {
+		exc := &RuntimeError{message: "x", exceptionState: exceptionState{backtrace: []string{"foo.rb:3:in `bar'"}}}
+
+		AddBacktrace(exc, []string{"foo.rb:1:in `<main>'"})
+
+		checkResult(t, exc, &RuntimeError{message: "x", exceptionState: exceptionState{backtrace: []string{"foo.rb:3:in `bar'"}}})
+	})
+}
+
+func TestFullMessage(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{
+			&RuntimeError{message: "boom"},
+			"boom (RuntimeError)",
+		},
+		{
+			errors.WithMessage(&RuntimeError{message: "boom", exceptionState: exceptionState{backtrace: []string{"foo.rb:2:in `bar'"}}}, "eval"),
+			"foo.rb:2:in `bar': boom (RuntimeError)",
+		},
+		{
+			&ArgumentError{message: "bad", exceptionState: exceptionState{backtrace: []string{"foo.rb:2:in `bar'", "foo.rb:4:in `block in qux'", "foo.rb:5:in `<main>'"}}},
+			"foo.rb:2:in `bar': bad (ArgumentError)\n\tfrom foo.rb:4:in `block in qux'\n\tfrom foo.rb:5:in `<main>'",
+		},
+		{
+			fmt.Errorf("no ruby error"),
+			"no ruby error",
+		},
+	}
+
+	for _, tt := range tests {
+		actual := FullMessage(tt.err)
+
+		if actual != tt.expected {
+			t.Logf("Expected full message to equal\n%q\n\tgot\n%q\n", tt.expected, actual)
+			t.Fail()
+		}
+	}
+}
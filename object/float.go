@@ -0,0 +1,408 @@
+package object
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var floatClass RubyClassObject = newClass(
+	"Float", objectClass, floatMethods, floatClassMethods, notInstantiatable,
+)
+
+func init() {
+	classes.Set("Float", floatClass)
+	floatEnv := floatClass.(Environment)
+	floatEnv.Set("INFINITY", NewFloat(math.Inf(1)))
+	floatEnv.Set("NAN", NewFloat(math.NaN()))
+	floatEnv.Set("EPSILON", NewFloat(math.Nextafter(1, 2)-1))
+	floatEnv.Set("MAX", NewFloat(math.MaxFloat64))
+	floatEnv.Set("MIN", NewFloat(2.2250738585072014e-308))
+}
+
+// NewFloat returns a new Float with the given value
+func NewFloat(value float64) *Float {
+	return &Float{Value: value}
+}
+
+// Float represents a double precision floating point number in Ruby
+type Float struct {
+	Value float64
+}
+
+// Inspect returns the value formatted the way MRI does
+func (f *Float) Inspect() string { return formatFloat(f.Value) }
+
+// Type returns FLOAT_OBJ
+func (f *Float) Type() Type { return FLOAT_OBJ }
+
+// Class returns floatClass
+func (f *Float) Class() RubyClass { return floatClass }
+
+func (f *Float) hashKey() hashKey {
+	value := f.Value
+	// -0.0 is eql to 0.0 and needs the same key
+	if value == 0 {
+		value = 0
+	}
+	return hashKey{Type: f.Type(), Value: math.Float64bits(value)}
+}
+
+// formatFloat formats value as MRI does, i.e. always with a fractional part
+// and in scientific notation for very large and very small values
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "Infinity"
+	case math.IsInf(value, -1):
+		return "-Infinity"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	abs := math.Abs(value)
+	if abs != 0 && (abs >= 1e16 || abs < 1e-4) {
+		formatted := strconv.FormatFloat(value, 'e', -1, 64)
+		parts := strings.SplitN(formatted, "e", 2)
+		if !strings.Contains(parts[0], ".") {
+			parts[0] += ".0"
+		}
+		return parts[0] + "e" + parts[1]
+	}
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+	if !strings.Contains(formatted, ".") {
+		formatted += ".0"
+	}
+	return formatted
+}
+
+var floatClassMethods = map[string]RubyMethod{}
+
+var floatMethods = map[string]RubyMethod{
+	"+":         withArity(1, publicMethod(floatAdd)),
+	"-":         withArity(1, publicMethod(floatSub)),
+	"*":         withArity(1, publicMethod(floatMul)),
+	"/":         withArity(1, publicMethod(floatDiv)),
+	"%":         withArity(1, publicMethod(floatModulo)),
+	"**":        withArity(1, publicMethod(floatPow)),
+	"<":         withArity(1, publicMethod(floatLt)),
+	">":         withArity(1, publicMethod(floatGt)),
+	"<=":        withArity(1, publicMethod(floatLte)),
+	">=":        withArity(1, publicMethod(floatGte)),
+	"==":        withArity(1, publicMethod(floatEq)),
+	"!=":        withArity(1, publicMethod(floatNeq)),
+	"<=>":       withArity(1, publicMethod(floatSpaceship)),
+	"coerce":    withArity(1, publicMethod(floatCoerce)),
+	"round":     publicMethod(floatRound),
+	"floor":     publicMethod(floatFloor),
+	"ceil":      publicMethod(floatCeil),
+	"to_i":      withArity(0, publicMethod(floatToI)),
+	"truncate":  withArity(0, publicMethod(floatToI)),
+	"to_f":      withArity(0, publicMethod(floatToF)),
+	"to_s":      withArity(0, publicMethod(floatToS)),
+	"inspect":   withArity(0, publicMethod(floatToS)),
+	"abs":       withArity(0, publicMethod(floatAbs)),
+	"nan?":      withArity(0, publicMethod(floatIsNan)),
+	"infinite?": withArity(0, publicMethod(floatIsInfinite)),
+	"finite?":   withArity(0, publicMethod(floatIsFinite)),
+}
+
+// floatValue returns the value of obj as float64 if obj is a Float or an
+// Integer
+func floatValue(obj RubyObject) (float64, bool) {
+	switch obj := obj.(type) {
+	case *Float:
+		return obj.Value, true
+	case *Integer:
+		return obj.float(), true
+	default:
+		return 0, false
+	}
+}
+
+// numericCmp compares the Floats or Integers left and right and returns -1,
+// 0 or +1. Integers are compared exactly instead of being converted to
+// float64, which would round large values or overflow to Infinity. The
+// boolean is false if either value is NaN.
+func numericCmp(left, right RubyObject) (int, bool) {
+	if l, ok := left.(*Float); ok {
+		if r, ok := right.(*Float); ok {
+			switch {
+			case l.Value < r.Value:
+				return -1, true
+			case l.Value > r.Value:
+				return 1, true
+			case l.Value == r.Value:
+				return 0, true
+			default:
+				return 0, false
+			}
+		}
+	}
+	l, ok := exactFloat(left)
+	if !ok {
+		return 0, false
+	}
+	r, ok := exactFloat(right)
+	if !ok {
+		return 0, false
+	}
+	return l.Cmp(r), true
+}
+
+// exactFloat returns the Float or Integer obj as big.Float without rounding.
+// It returns false for NaN and any other object.
+func exactFloat(obj RubyObject) (*big.Float, bool) {
+	switch obj := obj.(type) {
+	case *Float:
+		if math.IsNaN(obj.Value) {
+			return nil, false
+		}
+		return big.NewFloat(obj.Value), true
+	case *Integer:
+		return new(big.Float).SetInt(obj.BigInt()), true
+	default:
+		return nil, false
+	}
+}
+
+func floatAdd(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	add, ok := floatValue(args[0])
+	if !ok {
+		return coerceBinop(context, "+", args[0])
+	}
+	return NewFloat(f.Value + add), nil
+}
+
+func floatSub(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	sub, ok := floatValue(args[0])
+	if !ok {
+		return coerceBinop(context, "-", args[0])
+	}
+	return NewFloat(f.Value - sub), nil
+}
+
+func floatMul(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	factor, ok := floatValue(args[0])
+	if !ok {
+		return coerceBinop(context, "*", args[0])
+	}
+	return NewFloat(f.Value * factor), nil
+}
+
+func floatDiv(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	divisor, ok := floatValue(args[0])
+	if !ok {
+		return coerceBinop(context, "/", args[0])
+	}
+	return NewFloat(f.Value / divisor), nil
+}
+
+func floatModulo(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	mod, ok := floatValue(args[0])
+	if !ok {
+		return coerceBinop(context, "%", args[0])
+	}
+	result := math.Mod(f.Value, mod)
+	if result != 0 && (result < 0) != (mod < 0) {
+		result += mod
+	}
+	return NewFloat(result), nil
+}
+
+func floatPow(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	exp, ok := floatValue(args[0])
+	if !ok {
+		return coerceBinop(context, "**", args[0])
+	}
+	return NewFloat(math.Pow(f.Value, exp)), nil
+}
+
+func floatLt(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if _, ok := floatValue(args[0]); !ok {
+		return coerceComparison(context, "<", args[0])
+	}
+	cmp, ok := numericCmp(context.Receiver(), args[0])
+	return nativeBoolToBooleanObject(ok && cmp < 0), nil
+}
+
+func floatGt(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if _, ok := floatValue(args[0]); !ok {
+		return coerceComparison(context, ">", args[0])
+	}
+	cmp, ok := numericCmp(context.Receiver(), args[0])
+	return nativeBoolToBooleanObject(ok && cmp > 0), nil
+}
+
+func floatLte(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if _, ok := floatValue(args[0]); !ok {
+		return coerceComparison(context, "<=", args[0])
+	}
+	cmp, ok := numericCmp(context.Receiver(), args[0])
+	return nativeBoolToBooleanObject(ok && cmp <= 0), nil
+}
+
+func floatGte(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if _, ok := floatValue(args[0]); !ok {
+		return coerceComparison(context, ">=", args[0])
+	}
+	cmp, ok := numericCmp(context.Receiver(), args[0])
+	return nativeBoolToBooleanObject(ok && cmp >= 0), nil
+}
+
+func floatEq(context CallContext, args ...RubyObject) (RubyObject, error) {
+	cmp, ok := numericCmp(context.Receiver(), args[0])
+	return nativeBoolToBooleanObject(ok && cmp == 0), nil
+}
+
+func floatNeq(context CallContext, args ...RubyObject) (RubyObject, error) {
+	cmp, ok := numericCmp(context.Receiver(), args[0])
+	return nativeBoolToBooleanObject(!ok || cmp != 0), nil
+}
+
+func floatSpaceship(context CallContext, args ...RubyObject) (RubyObject, error) {
+	cmp, ok := numericCmp(context.Receiver(), args[0])
+	if !ok {
+		return NIL, nil
+	}
+	return NewInteger(int64(cmp)), nil
+}
+
+func floatCoerce(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	other, ok := floatValue(args[0])
+	if !ok {
+		return nil, NewCoercionTypeError(f, args[0])
+	}
+	return NewArray(NewFloat(other), f), nil
+}
+
+// roundFloat rounds value to digits decimal digits using round. It returns an
+// Integer for digits <= 0 and a Float otherwise, as Float#round, Float#floor
+// and Float#ceil do.
+func roundFloat(value float64, round func(float64) float64, args []RubyObject) (RubyObject, error) {
+	var digits int64
+	if len(args) > 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	if len(args) == 1 {
+		ndigits, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(&Integer{}, args[0])
+		}
+		digits = ndigits.Value
+		if ndigits.IsBig() {
+			digits = int64(ndigits.big.Sign()) * math.MaxInt32
+		}
+	}
+	if digits > 0 {
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return NewFloat(value), nil
+		}
+		// like MRI, the value is returned unchanged if it has no more
+		// significant digits than requested
+		_, binexp := math.Frexp(value)
+		significant := int64(binexp/3 - 1)
+		if binexp > 0 {
+			significant = int64(binexp / 4)
+		}
+		if digits >= 17-significant {
+			return NewFloat(value), nil
+		}
+		return NewFloat(scalePow10(round(scalePow10(value, int(digits))), -int(digits))), nil
+	}
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return nil, NewFloatDomainError(formatFloat(value))
+	}
+	// like MRI, the result is 0 once -digits exceeds the decimal digits of
+	// the integer value, which always spans at least an int64
+	magnitude := int64(19)
+	if abs := math.Abs(value); abs >= 1e19 {
+		magnitude = int64(math.Log10(abs)) + 1
+	}
+	if -digits > magnitude {
+		return NewInteger(0), nil
+	}
+	exp := new(big.Int).Exp(big.NewInt(10), big.NewInt(-digits), nil)
+	rounded, _ := big.NewFloat(round(scalePow10(value, int(digits)))).Int(nil)
+	return NewBigInteger(rounded.Mul(rounded, exp)), nil
+}
+
+// scalePow10 returns value * 10**exp. It scales in steps so that the power
+// of ten does not overflow on its own.
+func scalePow10(value float64, exp int) float64 {
+	for exp > 300 {
+		value *= 1e300
+		exp -= 300
+	}
+	for exp < -300 {
+		value /= 1e300
+		exp += 300
+	}
+	if exp < 0 {
+		return value / math.Pow10(-exp)
+	}
+	return value * math.Pow10(exp)
+}
+
+func floatRound(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	return roundFloat(f.Value, math.Round, args)
+}
+
+func floatFloor(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	return roundFloat(f.Value, math.Floor, args)
+}
+
+func floatCeil(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	return roundFloat(f.Value, math.Ceil, args)
+}
+
+func floatToI(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	return roundFloat(f.Value, math.Trunc, nil)
+}
+
+func floatToF(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver(), nil
+}
+
+func floatToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	return &String{Value: f.Inspect()}, nil
+}
+
+func floatAbs(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	return NewFloat(math.Abs(f.Value)), nil
+}
+
+func floatIsNan(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	return nativeBoolToBooleanObject(math.IsNaN(f.Value)), nil
+}
+
+func floatIsInfinite(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	switch {
+	case math.IsInf(f.Value, 1):
+		return NewInteger(1), nil
+	case math.IsInf(f.Value, -1):
+		return NewInteger(-1), nil
+	default:
+		return NIL, nil
+	}
+}
+
+func floatIsFinite(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	return nativeBoolToBooleanObject(!math.IsInf(f.Value, 0) && !math.IsNaN(f.Value)), nil
+}
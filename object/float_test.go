@@ -0,0 +1,312 @@
+package object
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestFloat_hashKey(t *testing.T) {
+	float1 := NewFloat(1.5)
+	float2 := NewFloat(1.5)
+	diff := NewFloat(3.5)
+
+	if float1.hashKey() != float2.hashKey() {
+		t.Errorf("floats with same value have different hash keys")
+	}
+
+	if float1.hashKey() == diff.hashKey() {
+		t.Errorf("floats with different value have same hash keys")
+	}
+}
+
+func TestFloatInspect(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{1, "1.0"},
+		{-2.5, "-2.5"},
+		{12.34, "12.34"},
+		{1e20, "1.0e+20"},
+		{1.5e-5, "1.5e-05"},
+		{0, "0.0"},
+		{math.Inf(1), "Infinity"},
+		{math.Inf(-1), "-Infinity"},
+		{math.NaN(), "NaN"},
+	}
+
+	for _, tt := range tests {
+		actual := NewFloat(tt.value).Inspect()
+		if actual != tt.expected {
+			t.Errorf("Expected %f to inspect as %q, got %q", tt.value, tt.expected, actual)
+		}
+	}
+}
+
+func TestFloatAdd(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{
+			[]RubyObject{NewFloat(1.5)},
+			NewFloat(4),
+			nil,
+		},
+		{
+			[]RubyObject{NewInteger(2)},
+			NewFloat(4.5),
+			nil,
+		},
+		{
+			[]RubyObject{&String{Value: ""}},
+			nil,
+			NewCoercionTypeError(&String{}, &Float{}),
+		},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(2.5)}
+
+		result, err := floatAdd(context, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatDiv(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{
+			[]RubyObject{NewInteger(2)},
+			NewFloat(2.5),
+			nil,
+		},
+		{
+			[]RubyObject{NewFloat(0)},
+			NewFloat(math.Inf(1)),
+			nil,
+		},
+		{
+			[]RubyObject{&String{Value: ""}},
+			nil,
+			NewCoercionTypeError(&String{}, &Float{}),
+		},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(5)}
+
+		result, err := floatDiv(context, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatModulo(t *testing.T) {
+	tests := []struct {
+		receiver float64
+		argument RubyObject
+		result   RubyObject
+	}{
+		{7.5, NewInteger(2), NewFloat(1.5)},
+		{-7, NewInteger(3), NewFloat(2)},
+		{7, NewFloat(-3), NewFloat(-2)},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(testCase.receiver)}
+
+		result, err := floatModulo(context, testCase.argument)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatPow(t *testing.T) {
+	tests := []struct {
+		receiver float64
+		argument RubyObject
+		result   RubyObject
+	}{
+		{2, NewInteger(3), NewFloat(8)},
+		{4, NewFloat(0.5), NewFloat(2)},
+		{2, NewInteger(-1), NewFloat(0.5)},
+		{10, NewInteger(400), NewFloat(math.Inf(1))},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(testCase.receiver)}
+
+		result, err := floatPow(context, testCase.argument)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatLt(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{
+			[]RubyObject{NewInteger(3)},
+			TRUE,
+			nil,
+		},
+		{
+			[]RubyObject{NewFloat(2.5)},
+			FALSE,
+			nil,
+		},
+		{
+			[]RubyObject{&String{Value: ""}},
+			nil,
+			NewArgumentError("comparison of Float with String failed"),
+		},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(2.5)}
+
+		result, err := floatLt(context, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatEq(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+	}{
+		{[]RubyObject{NewFloat(2)}, TRUE},
+		{[]RubyObject{NewInteger(2)}, TRUE},
+		{[]RubyObject{NewFloat(2.5)}, FALSE},
+		{[]RubyObject{&String{Value: ""}}, FALSE},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(2)}
+
+		result, err := floatEq(context, testCase.arguments...)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatSpaceship(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+	}{
+		{[]RubyObject{NewFloat(1)}, NewInteger(1)},
+		{[]RubyObject{NewInteger(2)}, NewInteger(0)},
+		{[]RubyObject{NewFloat(3)}, NewInteger(-1)},
+		{[]RubyObject{NewFloat(math.NaN())}, NIL},
+		{[]RubyObject{&String{Value: ""}}, NIL},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(2)}
+
+		result, err := floatSpaceship(context, testCase.arguments...)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatCoerce(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{
+			[]RubyObject{NewInteger(1)},
+			NewArray(NewFloat(1), NewFloat(2)),
+			nil,
+		},
+		{
+			[]RubyObject{NewFloat(1.5)},
+			NewArray(NewFloat(1.5), NewFloat(2)),
+			nil,
+		},
+		{
+			[]RubyObject{&String{Value: ""}},
+			nil,
+			NewCoercionTypeError(&Float{}, &String{}),
+		},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(2)}
+
+		result, err := floatCoerce(context, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatRound(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    RubyMethod
+		receiver  float64
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{"round", floatMethods["round"], 2.5, nil, NewInteger(3), nil},
+		{"round", floatMethods["round"], 3.14159, []RubyObject{NewInteger(2)}, NewFloat(3.14), nil},
+		{"round", floatMethods["round"], 1234.5, []RubyObject{NewInteger(-2)}, NewInteger(1200), nil},
+		{"floor", floatMethods["floor"], -2.5, nil, NewInteger(-3), nil},
+		{"floor", floatMethods["floor"], 3.789, []RubyObject{NewInteger(1)}, NewFloat(3.7), nil},
+		{"ceil", floatMethods["ceil"], 2.1, nil, NewInteger(3), nil},
+		{"to_i", floatMethods["to_i"], -2.9, nil, NewInteger(-2), nil},
+		{"round", floatMethods["round"], 0.0, []RubyObject{NewInteger(-400)}, NewInteger(0), nil},
+		{"round", floatMethods["round"], 1.5, []RubyObject{NewInteger(400)}, NewFloat(1.5), nil},
+		{"round", floatMethods["round"], 1.5, []RubyObject{NewBigInteger(new(big.Int).Lsh(big.NewInt(1), 70))}, NewFloat(1.5), nil},
+		{"round", floatMethods["round"], 1.25e-300, []RubyObject{NewInteger(301)}, NewFloat(1.3e-300), nil},
+		{"round", floatMethods["round"], 1.7e308, []RubyObject{NewInteger(-308)}, NewBigInteger(new(big.Int).Mul(big.NewInt(2), new(big.Int).Exp(big.NewInt(10), big.NewInt(308), nil))), nil},
+		{"floor", floatMethods["floor"], -0.5, []RubyObject{NewInteger(-400)}, NewInteger(0), nil},
+		{"floor", floatMethods["floor"], -0.5, []RubyObject{NewInteger(-5)}, NewInteger(-100000), nil},
+		{"round", floatMethods["round"], 2.5, []RubyObject{&String{Value: ""}}, nil, NewImplicitConversionTypeError(&Integer{}, &String{})},
+		{"round", floatMethods["round"], math.Inf(1), nil, nil, NewFloatDomainError("Infinity")},
+		{"to_i", floatMethods["to_i"], math.NaN(), nil, nil, NewFloatDomainError("NaN")},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			context := &callContext{receiver: NewFloat(testCase.receiver)}
+
+			result, err := testCase.method.Call(context, testCase.arguments...)
+
+			checkError(t, err, testCase.err)
+
+			checkResult(t, result, testCase.result)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package object
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// formatDirectives maps the conversion characters of Ruby format strings to
+// the kind of argument they format
+var formatDirectives = map[byte]string{
+	'd': "integer",
+	'i': "integer",
+	'u': "integer",
+	'x': "integer",
+	'X': "integer",
+	'o': "integer",
+	'b': "integer",
+	'f': "float",
+	'e': "float",
+	'E': "float",
+	'g': "float",
+	'G': "float",
+	's': "string",
+	'p': "string",
+	'c': "character",
+}
+
+// format formats args according to the format string template, as done by
+// Kernel#format and String#%. Arguments are consumed in order, unless named
+// references like `%<name>s` or `%{name}` look them up within named.
+func format(template string, args []RubyObject, named *Hash) (string, error) {
+	var out strings.Builder
+	next := 0
+	nextArg := func() (RubyObject, error) {
+		if next >= len(args) {
+			return nil, NewArgumentError("too few arguments")
+		}
+		next++
+		return args[next-1], nil
+	}
+	namedArg := func(name string) (RubyObject, error) {
+		if named == nil {
+			return nil, NewArgumentError("one hash required")
+		}
+		arg, ok := named.Get(NewSymbol(name))
+		if !ok {
+			return nil, NewArgumentError("key<%s> not found", name)
+		}
+		return arg, nil
+	}
+	for i := 0; i < len(template); i++ {
+		if template[i] != '%' {
+			out.WriteByte(template[i])
+			continue
+		}
+		start := i
+		i++
+		if i >= len(template) {
+			return "", NewArgumentError("incomplete format specifier; use %%%% (double %%) instead")
+		}
+		if template[i] == '%' {
+			out.WriteByte('%')
+			continue
+		}
+		var arg RubyObject
+		if template[i] == '{' || template[i] == '<' {
+			closer := map[byte]byte{'{': '}', '<': '>'}[template[i]]
+			end := strings.IndexByte(template[i:], closer)
+			if end == -1 {
+				return "", NewArgumentError("malformed name - unmatched parenthesis")
+			}
+			var err error
+			arg, err = namedArg(template[i+1 : i+end])
+			if err != nil {
+				return "", err
+			}
+			if template[i] == '{' {
+				str, err := stringify(arg)
+				if err != nil {
+					return "", err
+				}
+				out.WriteString(str)
+				i += end
+				continue
+			}
+			i += end + 1
+		}
+		spec := "%"
+		for ; i < len(template) && strings.IndexByte("-+ 0#", template[i]) != -1; i++ {
+			spec += template[i : i+1]
+		}
+		width, precision := "", ""
+		for ; i < len(template) && (isDigitByte(template[i]) || template[i] == '*'); i++ {
+			if template[i] != '*' {
+				width += template[i : i+1]
+				continue
+			}
+			widthArg, err := nextArg()
+			if err != nil {
+				return "", err
+			}
+			w, ok := widthArg.(*Integer)
+			if !ok {
+				return "", NewImplicitConversionTypeError(&Integer{}, widthArg)
+			}
+			if w.Value < 0 {
+				spec += "-"
+			}
+			width = strconv.FormatInt(abs(w.Value), 10)
+		}
+		if i < len(template) && template[i] == '.' {
+			precision = "."
+			for i++; i < len(template) && isDigitByte(template[i]); i++ {
+				precision += template[i : i+1]
+			}
+		}
+		if i >= len(template) {
+			return "", NewArgumentError("malformed format string - %s", template[start:])
+		}
+		verb := template[i]
+		kind, ok := formatDirectives[verb]
+		if !ok {
+			return "", NewArgumentError("malformed format string - %%%c", verb)
+		}
+		if arg == nil {
+			var err error
+			arg, err = nextArg()
+			if err != nil {
+				return "", err
+			}
+		}
+		spec += width + precision
+		formatted, err := formatArgument(spec, verb, kind, arg)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(formatted)
+	}
+	return out.String(), nil
+}
+
+// formatArgument formats arg with the Go format specification spec
+// according to the Ruby conversion character verb
+func formatArgument(spec string, verb byte, kind string, arg RubyObject) (string, error) {
+	switch kind {
+	case "integer":
+		value, err := formatIntegerValue(arg)
+		if err != nil {
+			return "", err
+		}
+		switch verb {
+		case 'i', 'u':
+			verb = 'd'
+		}
+		return fmt.Sprintf(spec+string(verb), value), nil
+	case "float":
+		value, err := formatFloatValue(arg)
+		if err != nil {
+			return "", err
+		}
+		if (verb == 'g' || verb == 'G') && !strings.Contains(spec, ".") {
+			// Ruby follows C and defaults to a precision of 6
+			spec += ".6"
+		}
+		return fmt.Sprintf(spec+string(verb), value), nil
+	case "character":
+		switch arg := arg.(type) {
+		case *Integer:
+			return fmt.Sprintf(spec+"c", rune(arg.Value)), nil
+		case *String:
+			runes := []rune(arg.Value)
+			if len(runes) == 0 {
+				return "", NewArgumentError("%%c requires a character")
+			}
+			return fmt.Sprintf(spec+"c", runes[0]), nil
+		default:
+			return "", NewImplicitConversionTypeError(&Integer{}, arg)
+		}
+	default:
+		if verb == 'p' {
+			return fmt.Sprintf(spec+"s", arg.Inspect()), nil
+		}
+		str, err := stringify(arg)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(spec+"s", str), nil
+	}
+}
+
+func formatIntegerValue(arg RubyObject) (*big.Int, error) {
+	switch arg := arg.(type) {
+	case *Integer:
+		return arg.BigInt(), nil
+	case *Float:
+		value, _ := big.NewFloat(arg.Value).Int(nil)
+		return value, nil
+	case *String:
+		value, ok := new(big.Int).SetString(strings.Replace(arg.Value, "_", "", -1), 0)
+		if !ok {
+			return nil, NewArgumentError("invalid value for Integer(): %q", arg.Value)
+		}
+		return value, nil
+	default:
+		return nil, NewTypeError(fmt.Sprintf("can't convert %s into Integer", arg.Class().Name()))
+	}
+}
+
+func formatFloatValue(arg RubyObject) (float64, error) {
+	switch arg := arg.(type) {
+	case *Integer:
+		return arg.float(), nil
+	case *Float:
+		return arg.Value, nil
+	case *String:
+		value, err := strconv.ParseFloat(strings.Replace(arg.Value, "_", "", -1), 64)
+		if err != nil {
+			return 0, NewArgumentError("invalid value for Float(): %q", arg.Value)
+		}
+		return value, nil
+	default:
+		return 0, NewTypeError(fmt.Sprintf("can't convert %s into Float", arg.Class().Name()))
+	}
+}
+
+func isDigitByte(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func abs(i int64) int64 {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,62 @@
+package object
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	named := &Hash{}
+	named.Set(&Symbol{Value: "a"}, NewInteger(42))
+	named.Set(&Symbol{Value: "b"}, &String{Value: "foo"})
+
+	tests := []struct {
+		template string
+		args     []RubyObject
+		result   string
+		err      error
+	}{
+		{"plain", nil, "plain", nil},
+		{"%d%%", []RubyObject{NewInteger(50)}, "50%", nil},
+		{"%+05d|%-4d|% d", []RubyObject{NewInteger(7), NewInteger(8), NewInteger(9)}, "+0007|8   | 9", nil},
+		{"%x %X %o %b %#x", []RubyObject{NewInteger(255), NewInteger(255), NewInteger(8), NewInteger(5), NewInteger(255)}, "ff FF 10 101 0xff", nil},
+		{"%d", []RubyObject{NewFloat(3.99)}, "3", nil},
+		{"%d", []RubyObject{&String{Value: "0x1f"}}, "31", nil},
+		{"%.2f %e %g", []RubyObject{NewFloat(3.14159), NewFloat(1234.5), NewFloat(1234567.0)}, "3.14 1.234500e+03 1.23457e+06", nil},
+		{"%f", []RubyObject{NewInteger(2)}, "2.000000", nil},
+		{"%5s|%-5s|%.2s", []RubyObject{&String{Value: "ab"}, &Symbol{Value: "cd"}, &String{Value: "xyz"}}, "   ab|cd   |xy", nil},
+		{"%p", []RubyObject{&Symbol{Value: "sym"}}, ":sym", nil},
+		{"%c%c", []RubyObject{NewInteger(65), &String{Value: "bc"}}, "Ab", nil},
+		{"%*d|%-*d", []RubyObject{NewInteger(3), NewInteger(1), NewInteger(3), NewInteger(2)}, "  1|2  ", nil},
+		{"%d %d", []RubyObject{NewInteger(1)}, "", NewArgumentError("too few arguments")},
+		{"%z", []RubyObject{NewInteger(1)}, "", NewArgumentError("malformed format string - %%z")},
+		{"%", nil, "", NewArgumentError("incomplete format specifier; use %%%% (double %%) instead")},
+		{"%d", []RubyObject{NIL}, "", NewTypeError("can't convert NilClass into Integer")},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.template, func(t *testing.T) {
+			result, err := format(testCase.template, testCase.args, nil)
+
+			checkError(t, err, testCase.err)
+
+			if result != testCase.result {
+				t.Logf("Expected %q, got %q\n", testCase.result, result)
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("named references", func(t *testing.T) {
+		result, err := format("%<a>05d %{b}", nil, named)
+
+		checkError(t, err, nil)
+
+		if result != "00042 foo" {
+			t.Logf("Expected %q, got %q\n", "00042 foo", result)
+			t.Fail()
+		}
+	})
+	t.Run("missing key", func(t *testing.T) {
+		_, err := format("%{c}", nil, named)
+
+		checkError(t, err, NewArgumentError("key<c> not found"))
+	})
+}
@@ -0,0 +1,31 @@
+package object
+
+import (
+	"go/token"
+
+	"github.com/goruby/goruby/ast"
+)
+
+// Frame describes the frame the body of a method or block is evaluated in
+type Frame struct {
+	// Name names the frame within backtraces, e.g. `foo` or `block in foo`
+	Name string
+	// File is the file the method or block is defined in
+	File *token.File
+}
+
+// A FrameBody is the body of a method or block to be evaluated within its
+// own Frame
+type FrameBody struct {
+	*ast.BlockStatement
+	Frame
+}
+
+// frameBody returns body to be evaluated within frame, or body itself if the
+// frame is unnamed
+func frameBody(body *ast.BlockStatement, frame Frame) ast.Node {
+	if frame.Name == "" {
+		return body
+	}
+	return &FrameBody{BlockStatement: body, Frame: frame}
+}
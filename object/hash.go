@@ -1,32 +1,40 @@
 package object
 
 import (
+	"encoding/binary"
 	"fmt"
 	"hash/fnv"
 	"strings"
 )
 
-var hashClass RubyClassObject = newClass(
+var hashClass RubyClassObject = newMixin(newClass(
 	"Hash",
 	objectClass,
 	hashMethods,
 	hashClassMethods,
 	func(RubyClassObject, ...RubyObject) (RubyObject, error) {
-		return &Hash{hashMap: make(map[hashKey]hashPair)}, nil
+		return NewHash(), nil
 	},
-)
+), enumerableModule)
 
 func init() {
 	classes.Set("Hash", hashClass)
 }
 
+// NewHash returns a new empty Hash
+func NewHash() *Hash {
+	return &Hash{hashMap: make(map[hashKey][]*hashPair)}
+}
+
 type hashKey struct {
 	Type  Type
 	Value uint64
 }
 
 func (h hashKey) bytes() []byte {
-	return append([]byte(h.Type), byte(h.Value))
+	value := make([]byte, 8)
+	binary.LittleEndian.PutUint64(value, h.Value)
+	return append([]byte(h.Type), value...)
 }
 
 func hash(obj RubyObject) hashKey {
@@ -39,47 +47,298 @@ func hash(obj RubyObject) hashKey {
 	return hashKey{Type: obj.Type(), Value: h.Sum64()}
 }
 
+// userDefined reports whether the method obj responds to is defined in Ruby
+// code rather than built in
+func userDefined(obj RubyObject, method string) bool {
+	for class := obj.Class(); class != nil; class = class.SuperClass() {
+		if fn, ok := class.Methods().Get(method); ok {
+			_, ok := fn.(*Function)
+			return ok
+		}
+	}
+	return false
+}
+
+// hashKeyOf returns the hash key of obj within context. Objects defining
+// their own `hash` method are hashed by calling it. Without a context the
+// builtin hash key is used.
+func hashKeyOf(context CallContext, obj RubyObject) (hashKey, error) {
+	if context == nil || !userDefined(obj, "hash") {
+		return hash(obj), nil
+	}
+	value, err := Send(&callContext{receiver: obj, env: context.Env(), eval: context.Eval}, "hash")
+	if err != nil {
+		return hashKey{}, err
+	}
+	integer, ok := value.(*Integer)
+	if !ok {
+		return hashKey{}, NewImplicitConversionTypeError(integer, value)
+	}
+	return hashKey{Type: obj.Type(), Value: integer.hashKey().Value}, nil
+}
+
+// isEql reports whether a.eql?(b) within context. Objects without their own
+// `eql?` method are compared by builtinEql.
+func isEql(context CallContext, a, b RubyObject) (bool, error) {
+	if context == nil || !userDefined(a, "eql?") {
+		return builtinEql(context, a, b)
+	}
+	eql, err := Send(&callContext{receiver: a, env: context.Env(), eval: context.Eval}, "eql?", b)
+	if err != nil {
+		return false, err
+	}
+	return eql != NIL && eql != FALSE, nil
+}
+
+// builtinEql implements eql? for builtin objects. Numbers, strings, symbols
+// and regexps are eql if they are of the same kind and have the same value,
+// arrays, hashes and ranges if their contents are eql. Any other objects are
+// eql only if they are identical.
+func builtinEql(context CallContext, a, b RubyObject) (bool, error) {
+	if a == b {
+		return true, nil
+	}
+	switch a := a.(type) {
+	case *Integer:
+		b, ok := b.(*Integer)
+		return ok && a.cmp(b) == 0, nil
+	case *Float:
+		b, ok := b.(*Float)
+		return ok && a.Value == b.Value, nil
+	case *String:
+		b, ok := b.(*String)
+		return ok && a.Value == b.Value, nil
+	case *Symbol:
+		b, ok := b.(*Symbol)
+		return ok && a.Value == b.Value, nil
+	case *Boolean:
+		b, ok := b.(*Boolean)
+		return ok && a.Value == b.Value, nil
+	case *Regexp:
+		b, ok := b.(*Regexp)
+		return ok && a.Source == b.Source && a.Options == b.Options, nil
+	case *Range:
+		b, ok := b.(*Range)
+		if !ok || a.Exclusive != b.Exclusive {
+			return false, nil
+		}
+		return allEql(context, []RubyObject{a.Begin, a.End}, []RubyObject{b.Begin, b.End})
+	case *Array:
+		b, ok := b.(*Array)
+		if !ok || len(a.Elements) != len(b.Elements) {
+			return false, nil
+		}
+		return allEql(context, a.Elements, b.Elements)
+	case *Hash:
+		b, ok := b.(*Hash)
+		if !ok || len(a.pairs) != len(b.pairs) {
+			return false, nil
+		}
+		for _, pair := range a.pairs {
+			_, other, err := b.find(context, pair.Key)
+			if err != nil || other == nil {
+				return false, err
+			}
+			eql, err := isEql(context, pair.Value, other.Value)
+			if err != nil || !eql {
+				return false, err
+			}
+		}
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// allEql reports whether the objects of a and b are pairwise eql
+func allEql(context CallContext, a, b []RubyObject) (bool, error) {
+	for i := range a {
+		eql, err := isEql(context, a[i], b[i])
+		if err != nil || !eql {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
 type hashPair struct {
 	Key   RubyObject
 	Value RubyObject
 }
 
-// A Hash represents a Ruby Hash
+// A Hash represents a Ruby Hash. It keeps its pairs in insertion order.
 type Hash struct {
-	hashMap map[hashKey]hashPair
+	hashMap map[hashKey][]*hashPair // the pairs, bucketed by the hash key of their Key
+	pairs   []*hashPair
+	// Default is returned for missing keys if there is no DefaultProc
+	Default RubyObject
+	// DefaultProc is called with the hash and the key for missing keys
+	DefaultProc *Proc
+	// iterating counts the iterations in progress, which forbid adding keys
+	iterating int
 }
 
 func (h *Hash) init() {
 	if h.hashMap == nil {
-		h.hashMap = make(map[hashKey]hashPair)
+		h.hashMap = make(map[hashKey][]*hashPair)
+	}
+}
+
+// find returns the hash key of key and the pair stored for it, if any
+func (h *Hash) find(context CallContext, key RubyObject) (hashKey, *hashPair, error) {
+	if self, ok := key.(*Self); ok {
+		key = self.RubyObject
+	}
+	k, err := hashKeyOf(context, key)
+	if err != nil {
+		return k, nil, err
+	}
+	for _, pair := range h.hashMap[k] {
+		eql, err := isEql(context, key, pair.Key)
+		if err != nil {
+			return k, nil, err
+		}
+		if eql {
+			return k, pair, nil
+		}
+	}
+	return k, nil, nil
+}
+
+// store puts value for key into the Hash. Like in MRI String keys are
+// copied, so that changing the original does not affect the Hash.
+func (h *Hash) store(context CallContext, key, value RubyObject) error {
+	h.init()
+	k, pair, err := h.find(context, key)
+	if err != nil {
+		return err
+	}
+	if pair != nil {
+		pair.Value = value
+		return nil
+	}
+	if h.iterating > 0 {
+		return NewRuntimeError("can't add a new key into hash during iteration")
+	}
+	switch obj := key.(type) {
+	case *Self:
+		key = obj.RubyObject
+	case *String:
+		key = &String{Value: obj.Value}
+	}
+	pair = &hashPair{Key: key, Value: value}
+	h.hashMap[k] = append(h.hashMap[k], pair)
+	h.pairs = append(h.pairs, pair)
+	return nil
+}
+
+// remove deletes the pair for key and returns it, or nil if there is none
+func (h *Hash) remove(context CallContext, key RubyObject) (*hashPair, error) {
+	k, pair, err := h.find(context, key)
+	if err != nil || pair == nil {
+		return nil, err
+	}
+	h.hashMap[k] = removePair(h.hashMap[k], pair)
+	if len(h.hashMap[k]) == 0 {
+		delete(h.hashMap, k)
+	}
+	h.pairs = removePair(h.pairs, pair)
+	return pair, nil
+}
+
+func removePair(pairs []*hashPair, pair *hashPair) []*hashPair {
+	for i, p := range pairs {
+		if p == pair {
+			return append(pairs[:i:i], pairs[i+1:]...)
+		}
 	}
+	return pairs
+}
+
+// value returns the value for key, or the default of the Hash if the key
+// is missing
+func (h *Hash) value(context CallContext, key RubyObject) (RubyObject, error) {
+	_, pair, err := h.find(context, key)
+	if err != nil {
+		return nil, err
+	}
+	if pair != nil {
+		return pair.Value, nil
+	}
+	if h.DefaultProc != nil {
+		return h.DefaultProc.Call(context, h, key)
+	}
+	if h.Default != nil {
+		return h.Default, nil
+	}
+	return NIL, nil
+}
+
+// copy returns a shallow copy of the Hash, including its defaults
+func (h *Hash) copy() *Hash {
+	copied := &Hash{
+		hashMap:     make(map[hashKey][]*hashPair),
+		Default:     h.Default,
+		DefaultProc: h.DefaultProc,
+	}
+	copies := make(map[*hashPair]*hashPair, len(h.pairs))
+	for _, pair := range h.pairs {
+		copies[pair] = &hashPair{Key: pair.Key, Value: pair.Value}
+		copied.pairs = append(copied.pairs, copies[pair])
+	}
+	for k, bucket := range h.hashMap {
+		for _, pair := range bucket {
+			copied.hashMap[k] = append(copied.hashMap[k], copies[pair])
+		}
+	}
+	return copied
 }
 
 // Set puts the object obj into the Hash
 func (h *Hash) Set(key, value RubyObject) RubyObject {
-	h.init()
-	h.hashMap[hash(key)] = hashPair{Key: key, Value: value}
+	h.store(nil, key, value)
 	return value
 }
 
 // Get retrieves the object for key within the hash. If not found, the boolean will be false
 func (h *Hash) Get(key RubyObject) (RubyObject, bool) {
-	v, ok := h.hashMap[hash(key)]
-	if !ok {
+	_, pair, _ := h.find(nil, key)
+	if pair == nil {
 		return nil, false
 	}
-	return v.Value, true
+	return pair.Value, true
 }
 
 // Map returns a map of RubyObject to RubyObject
 func (h *Hash) Map() map[RubyObject]RubyObject {
 	hashmap := make(map[RubyObject]RubyObject)
-	for _, v := range h.hashMap {
+	for _, v := range h.pairs {
 		hashmap[v.Key] = v.Value
 	}
 	return hashmap
 }
 
+// Keys returns the keys of the Hash in insertion order
+func (h *Hash) Keys() []RubyObject {
+	keys := make([]RubyObject, len(h.pairs))
+	for i, pair := range h.pairs {
+		keys[i] = pair.Key
+	}
+	return keys
+}
+
+// Values returns the values of the Hash in insertion order
+func (h *Hash) Values() []RubyObject {
+	values := make([]RubyObject, len(h.pairs))
+	for i, pair := range h.pairs {
+		values[i] = pair.Value
+	}
+	return values
+}
+
+// Len returns the number of pairs within the Hash
+func (h *Hash) Len() int { return len(h.pairs) }
+
 // Type returns the ObjectType of the array
 func (h *Hash) Type() Type { return HASH_OBJ }
 
@@ -87,8 +346,8 @@ func (h *Hash) Type() Type { return HASH_OBJ }
 // surrounded by brackets
 func (h *Hash) Inspect() string {
 	elems := []string{}
-	for _, v := range h.hashMap {
-		elems = append(elems, fmt.Sprintf("%q => %q", v.Key.Inspect(), v.Value.Inspect()))
+	for _, v := range h.pairs {
+		elems = append(elems, fmt.Sprintf("%s => %s", v.Key.Inspect(), v.Value.Inspect()))
 	}
 	return "{" + strings.Join(elems, ", ") + "}"
 }
@@ -96,14 +355,404 @@ func (h *Hash) Inspect() string {
 // Class returns the class of the Array
 func (h *Hash) Class() RubyClass { return hashClass }
 
+// hashKey returns the same key for hashes with the same pairs, regardless of
+// their order
 func (h *Hash) hashKey() hashKey {
-	hash := fnv.New64a()
-	for k := range h.hashMap {
-		hash.Write(k.bytes())
+	var sum uint64
+	for _, pair := range h.pairs {
+		pairHash := fnv.New64a()
+		pairHash.Write(hash(pair.Key).bytes())
+		pairHash.Write(hash(pair.Value).bytes())
+		sum += pairHash.Sum64()
 	}
-	return hashKey{Type: h.Type(), Value: hash.Sum64()}
+	return hashKey{Type: h.Type(), Value: sum}
 }
 
 var hashClassMethods = map[string]RubyMethod{}
 
-var hashMethods = map[string]RubyMethod{}
+var hashMethods = map[string]RubyMethod{
+	"initialize":       privateMethod(hashInitialize),
+	"to_s":             withArity(0, publicMethod(hashInspect)),
+	"inspect":          withArity(0, publicMethod(hashInspect)),
+	"[]":               withArity(1, publicMethod(hashIndex)),
+	"[]=":              withArity(2, publicMethod(hashSetIndex)),
+	"store":            withArity(2, publicMethod(hashSetIndex)),
+	"fetch":            publicMethod(hashFetch),
+	"dig":              publicMethod(hashDig),
+	"delete":           publicMethod(hashDelete),
+	"key?":             withArity(1, publicMethod(hashHasKey)),
+	"has_key?":         withArity(1, publicMethod(hashHasKey)),
+	"include?":         withArity(1, publicMethod(hashHasKey)),
+	"member?":          withArity(1, publicMethod(hashHasKey)),
+	"default":          withArity(0, publicMethod(hashDefault)),
+	"default=":         withArity(1, publicMethod(hashSetDefault)),
+	"keys":             withArity(0, publicMethod(hashKeys)),
+	"values":           withArity(0, publicMethod(hashValues)),
+	"length":           withArity(0, publicMethod(hashSize)),
+	"size":             withArity(0, publicMethod(hashSize)),
+	"empty?":           withArity(0, publicMethod(hashIsEmpty)),
+	"each":             publicMethod(hashEach),
+	"each_pair":        publicMethod(hashEach),
+	"map":              publicMethod(hashMap),
+	"collect":          publicMethod(hashMap),
+	"select":           publicMethod(hashSelect),
+	"filter":           publicMethod(hashSelect),
+	"reject":           publicMethod(hashReject),
+	"merge":            publicMethod(hashMerge),
+	"transform_values": publicMethod(hashTransformValues),
+	"group_by":         publicMethod(hashGroupBy),
+	"sort_by":          publicMethod(hashSortBy),
+	"to_a":             withArity(0, publicMethod(hashToA)),
+	"to_h":             withArity(0, publicMethod(hashToH)),
+	"==":               withArity(1, publicMethod(hashEqual)),
+}
+
+// hashInitialize sets the default value or, if a block is given, the default
+// proc of a new Hash
+func hashInitialize(context CallContext, args ...RubyObject) (RubyObject, error) {
+	self, _ := context.Receiver().(*Self)
+	block, args, hasBlock := extractBlockFromArgs(args)
+	if hasBlock && len(args) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(args))
+	}
+	if len(args) > 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	hash := NewHash()
+	if len(args) == 1 {
+		hash.Default = args[0]
+	}
+	if hasBlock {
+		hash.DefaultProc = block
+	}
+	self.RubyObject = hash
+	return self, nil
+}
+
+func hashInspect(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	return &String{Value: hash.Inspect()}, nil
+}
+
+func hashIndex(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	return hash.value(context, args[0])
+}
+
+func hashSetIndex(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	if err := hash.store(context, args[0], args[1]); err != nil {
+		return nil, err
+	}
+	return args[1], nil
+}
+
+// hashFetch returns the value for the key. For missing keys it returns the
+// result of the block or the given default, and raises a KeyError otherwise.
+func hashFetch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	block, args, hasBlock := extractBlockFromArgs(args)
+	if len(args) < 1 || len(args) > 2 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	_, pair, err := hash.find(context, args[0])
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case pair != nil:
+		return pair.Value, nil
+	case hasBlock:
+		return block.Call(context, args[0])
+	case len(args) == 2:
+		return args[1], nil
+	default:
+		return nil, NewKeyError(args[0])
+	}
+}
+
+func hashDig(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if len(args) < 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	hash := context.Receiver().(*Hash)
+	value, err := hash.value(context, args[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 1 || value == NIL {
+		return value, nil
+	}
+	if !respondTo(value, "dig") {
+		return nil, NewTypeError(fmt.Sprintf("%s does not have #dig method", value.Class().Name()))
+	}
+	return Send(&callContext{receiver: value, env: context.Env(), eval: context.Eval}, "dig", args[1:]...)
+}
+
+// hashDelete removes the pair for the key and returns its value. For missing
+// keys it returns the result of the block, or nil.
+func hashDelete(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	block, args, hasBlock := extractBlockFromArgs(args)
+	if len(args) != 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	pair, err := hash.remove(context, args[0])
+	if err != nil {
+		return nil, err
+	}
+	if pair != nil {
+		return pair.Value, nil
+	}
+	if hasBlock {
+		return block.Call(context, args[0])
+	}
+	return NIL, nil
+}
+
+func hashHasKey(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	_, pair, err := hash.find(context, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return nativeBoolToBooleanObject(pair != nil), nil
+}
+
+func hashDefault(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	if hash.Default == nil {
+		return NIL, nil
+	}
+	return hash.Default, nil
+}
+
+// hashSetDefault sets the default value, which replaces the default proc
+func hashSetDefault(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	hash.Default = args[0]
+	hash.DefaultProc = nil
+	return args[0], nil
+}
+
+func hashKeys(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	return NewArray(hash.Keys()...), nil
+}
+
+func hashValues(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	values := NewArray()
+	for _, pair := range hash.pairs {
+		values.Elements = append(values.Elements, pair.Value)
+	}
+	return values, nil
+}
+
+func hashSize(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	return NewInteger(int64(hash.Len())), nil
+}
+
+func hashIsEmpty(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	return nativeBoolToBooleanObject(hash.Len() == 0), nil
+}
+
+// hashIterate calls fn with each pair of the Hash and the result of the
+// block for it. The block gets the pair as Array, which it spreads over its
+// parameters. Like in MRI no keys can be added to the Hash meanwhile.
+func hashIterate(context CallContext, args []RubyObject, fn func(pair *hashPair, result RubyObject) error) error {
+	hash := context.Receiver().(*Hash)
+	block, err := arrayIteration(args)
+	if err != nil {
+		return err
+	}
+	hash.iterating++
+	defer func() { hash.iterating-- }()
+	for i := 0; i < len(hash.pairs); i++ {
+		pair := hash.pairs[i]
+		result, err := block.Call(context, NewArray(pair.Key, pair.Value))
+		if err != nil {
+			return err
+		}
+		if err := fn(pair, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hashEach(context CallContext, args ...RubyObject) (RubyObject, error) {
+	err := hashIterate(context, args, func(*hashPair, RubyObject) error { return nil })
+	if err != nil {
+		return nil, err
+	}
+	return context.Receiver(), nil
+}
+
+func hashMap(context CallContext, args ...RubyObject) (RubyObject, error) {
+	mapped := NewArray()
+	err := hashIterate(context, args, func(pair *hashPair, result RubyObject) error {
+		mapped.Elements = append(mapped.Elements, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapped, nil
+}
+
+func hashSelect(context CallContext, args ...RubyObject) (RubyObject, error) {
+	selected := NewHash()
+	err := hashIterate(context, args, func(pair *hashPair, result RubyObject) error {
+		if result == NIL || result == FALSE {
+			return nil
+		}
+		return selected.store(context, pair.Key, pair.Value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+func hashReject(context CallContext, args ...RubyObject) (RubyObject, error) {
+	kept := NewHash()
+	err := hashIterate(context, args, func(pair *hashPair, result RubyObject) error {
+		if result != NIL && result != FALSE {
+			return nil
+		}
+		return kept.store(context, pair.Key, pair.Value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return kept, nil
+}
+
+// hashMerge returns a new Hash with the pairs of all given hashes. The
+// block, if given, resolves duplicate keys from the key, the old and the new
+// value.
+func hashMerge(context CallContext, args ...RubyObject) (RubyObject, error) {
+	block, args, hasBlock := extractBlockFromArgs(args)
+	merged := context.Receiver().(*Hash).copy()
+	for _, arg := range args {
+		other, ok := arg.(*Hash)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(other, arg)
+		}
+		for _, pair := range other.pairs {
+			value := pair.Value
+			if hasBlock {
+				_, existing, err := merged.find(context, pair.Key)
+				if err != nil {
+					return nil, err
+				}
+				if existing != nil {
+					value, err = block.Call(context, pair.Key, existing.Value, pair.Value)
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+			if err := merged.store(context, pair.Key, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return merged, nil
+}
+
+func hashTransformValues(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	block, err := arrayIteration(args)
+	if err != nil {
+		return nil, err
+	}
+	transformed := NewHash()
+	for i := 0; i < len(hash.pairs); i++ {
+		pair := hash.pairs[i]
+		value, err := block.Call(context, pair.Value)
+		if err != nil {
+			return nil, err
+		}
+		if err := transformed.store(context, pair.Key, value); err != nil {
+			return nil, err
+		}
+	}
+	return transformed, nil
+}
+
+// hashGroupBy groups the pairs, as two element arrays, by the result of the
+// block
+func hashGroupBy(context CallContext, args ...RubyObject) (RubyObject, error) {
+	groups := NewHash()
+	err := hashIterate(context, args, func(pair *hashPair, result RubyObject) error {
+		_, group, err := groups.find(context, result)
+		if err != nil {
+			return err
+		}
+		element := NewArray(pair.Key, pair.Value)
+		if group != nil {
+			members := group.Value.(*Array)
+			members.Elements = append(members.Elements, element)
+			return nil
+		}
+		return groups.store(context, result, NewArray(element))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// hashSortBy returns the pairs, as two element arrays, sorted by the result
+// of the block
+func hashSortBy(context CallContext, args ...RubyObject) (RubyObject, error) {
+	pairs, err := hashToA(context)
+	if err != nil {
+		return nil, err
+	}
+	return Send(&callContext{receiver: pairs, env: context.Env(), eval: context.Eval}, "sort_by", args...)
+}
+
+func hashToA(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	pairs := NewArray()
+	for _, pair := range hash.pairs {
+		pairs.Elements = append(pairs.Elements, NewArray(pair.Key, pair.Value))
+	}
+	return pairs, nil
+}
+
+func hashToH(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver(), nil
+}
+
+// hashEqual reports whether both hashes have the same keys with equal values,
+// regardless of their order
+func hashEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	hash := context.Receiver().(*Hash)
+	other, ok := args[0].(*Hash)
+	if !ok || hash.Len() != other.Len() {
+		return FALSE, nil
+	}
+	for _, pair := range hash.pairs {
+		_, otherPair, err := other.find(context, pair.Key)
+		if err != nil {
+			return nil, err
+		}
+		if otherPair == nil {
+			return FALSE, nil
+		}
+		equal, err := isEqual(context, pair.Value, otherPair.Value)
+		if err != nil {
+			return nil, err
+		}
+		if !equal {
+			return FALSE, nil
+		}
+	}
+	return TRUE, nil
+}
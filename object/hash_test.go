@@ -1,28 +1,28 @@
 package object
 
 import (
+	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/goruby/goruby/ast"
 )
 
 func TestHashSet(t *testing.T) {
 	t.Run("Set on initialized hash", func(t *testing.T) {
-		hash := &Hash{hashMap: make(map[hashKey]hashPair)}
+		hash := NewHash()
 
 		key := &String{Value: "foo"}
 		value := &Integer{Value: 42}
 
 		result := hash.Set(key, value)
 
-		if len(hash.hashMap) != 1 {
-			t.Logf("Expected hashMap to contain 1 item, got %d\n", len(hash.hashMap))
-			t.Fail()
+		if len(hash.pairs) != 1 {
+			t.Logf("Expected hash to contain 1 item, got %d\n", len(hash.pairs))
+			t.FailNow()
 		}
 
-		var values []hashPair
-		for _, v := range hash.hashMap {
-			values = append(values, v)
-		}
+		values := hash.pairs
 
 		if !reflect.DeepEqual(values[0].Key, key) {
 			t.Logf("Expect hashPair Key to equal\n%v\n\tgot\n%v\n", key, values[0].Key)
@@ -46,15 +46,12 @@ func TestHashSet(t *testing.T) {
 
 		result := hash.Set(key, value)
 
-		if len(hash.hashMap) != 1 {
-			t.Logf("Expected hashMap to contain 1 item, got %d\n", len(hash.hashMap))
-			t.Fail()
+		if len(hash.pairs) != 1 {
+			t.Logf("Expected hash to contain 1 item, got %d\n", len(hash.pairs))
+			t.FailNow()
 		}
 
-		var values []hashPair
-		for _, v := range hash.hashMap {
-			values = append(values, v)
-		}
+		values := hash.pairs
 
 		if !reflect.DeepEqual(values[0].Key, key) {
 			t.Logf("Expect hashPair Key to equal\n%v\n\tgot\n%v\n", key, values[0].Key)
@@ -77,9 +74,8 @@ func TestHashGet(t *testing.T) {
 		key := &String{Value: "foo"}
 		value := &Integer{Value: 42}
 
-		hash := &Hash{hashMap: map[hashKey]hashPair{
-			key.hashKey(): hashPair{Key: key, Value: value},
-		}}
+		hash := NewHash()
+		hash.Set(key, value)
 
 		result, ok := hash.Get(key)
 
@@ -96,7 +92,7 @@ func TestHashGet(t *testing.T) {
 	t.Run("value not found", func(t *testing.T) {
 		key := &String{Value: "foo"}
 
-		hash := &Hash{hashMap: map[hashKey]hashPair{}}
+		hash := NewHash()
 
 		result, ok := hash.Get(key)
 
@@ -110,6 +106,26 @@ func TestHashGet(t *testing.T) {
 			t.Fail()
 		}
 	})
+	t.Run("negative big integer key", func(t *testing.T) {
+		positive := NewBigInteger(new(big.Int).Lsh(big.NewInt(1), 64))
+		negative := NewBigInteger(new(big.Int).Neg(positive.BigInt()))
+
+		hash := NewHash()
+		hash.Set(positive, NewSymbol("a"))
+
+		_, ok := hash.Get(negative)
+
+		if ok {
+			t.Logf("Expected -(2**64) not to be found for key 2**64")
+			t.Fail()
+		}
+
+		hash.Set(negative, NewSymbol("b"))
+
+		result, _ := hash.Get(negative)
+
+		checkResult(t, result, NewSymbol("b"))
+	})
 	t.Run("on uninitalized hash", func(t *testing.T) {
 		key := &String{Value: "foo"}
 
@@ -134,14 +150,13 @@ func TestHashMap(t *testing.T) {
 		key := &String{Value: "foo"}
 		value := &Integer{Value: 42}
 
-		hash := &Hash{hashMap: map[hashKey]hashPair{
-			key.hashKey(): hashPair{Key: key, Value: value},
-		}}
+		hash := NewHash()
+		hash.Set(key, value)
 
 		var result map[RubyObject]RubyObject = hash.Map()
 
 		expected := map[string]RubyObject{
-			"foo": value,
+			`"foo"`: value,
 		}
 		actual := make(map[string]RubyObject)
 		for k, v := range result {
@@ -206,3 +221,324 @@ func Test_hash(t *testing.T) {
 		}
 	})
 }
+
+func TestIsEql(t *testing.T) {
+	large := NewBigInteger(new(big.Int).Lsh(big.NewInt(1), 64))
+	negative := NewBigInteger(new(big.Int).Neg(large.BigInt()))
+	object := &basicObject{}
+	tests := []struct {
+		a, b     RubyObject
+		expected bool
+	}{
+		{large, NewBigInteger(large.BigInt()), true},
+		{large, negative, false},
+		{NewInteger(1), NewFloat(1), false},
+		{NewFloat(0), NewFloat(-0.0), true},
+		{&String{Value: "a"}, &String{Value: "a"}, true},
+		{&String{Value: "a"}, NewSymbol("a"), false},
+		{NewArray(NewInteger(1), &String{Value: "a"}), NewArray(NewInteger(1), &String{Value: "a"}), true},
+		{NewArray(NewInteger(1)), NewArray(NewFloat(1)), false},
+		{&Range{Begin: NewInteger(1), End: NewInteger(2)}, &Range{Begin: NewInteger(1), End: NewInteger(2)}, true},
+		{&Range{Begin: NewInteger(1), End: NewInteger(2)}, &Range{Begin: NewInteger(1), End: NewInteger(2), Exclusive: true}, false},
+		{object, object, true},
+		{object, &basicObject{}, false},
+	}
+
+	for _, testCase := range tests {
+		eql, err := isEql(nil, testCase.a, testCase.b)
+
+		checkError(t, err, nil)
+		if eql != testCase.expected {
+			t.Logf("Expected %s.eql?(%s) to be %t\n", testCase.a.Inspect(), testCase.b.Inspect(), testCase.expected)
+			t.Fail()
+		}
+	}
+
+	t.Run("hashes", func(t *testing.T) {
+		a := NewHash()
+		a.Set(NewSymbol("a"), NewInteger(1))
+		b := NewHash()
+		b.Set(NewSymbol("a"), NewInteger(1))
+
+		eql, _ := isEql(nil, a, b)
+
+		if !eql {
+			t.Logf("Expected hashes with the same pairs to be eql")
+			t.Fail()
+		}
+
+		b.Set(NewSymbol("a"), NewFloat(1))
+
+		eql, _ = isEql(nil, a, b)
+
+		if eql {
+			t.Logf("Expected hashes with different values not to be eql")
+			t.Fail()
+		}
+	})
+	t.Run("keys with the same hash key", func(t *testing.T) {
+		// small integers are hashed by value, so this collides with large
+		colliding := NewInteger(int64(large.hashKey().Value))
+		h := NewHash()
+		h.Set(large, NewSymbol("a"))
+
+		_, ok := h.Get(colliding)
+
+		if ok {
+			t.Logf("Expected colliding keys not to be merged")
+			t.Fail()
+		}
+	})
+}
+
+func TestHashKeyBytes(t *testing.T) {
+	low := hashKey{Type: INTEGER_OBJ, Value: 1}
+	high := hashKey{Type: INTEGER_OBJ, Value: 1 << 40}
+
+	if reflect.DeepEqual(low.bytes(), high.bytes()) {
+		t.Logf("Expected bytes of %v and %v to differ", low, high)
+		t.Fail()
+	}
+}
+
+func TestHashOrder(t *testing.T) {
+	hash := NewHash()
+	hash.Set(NewInteger(3), NewInteger(1))
+	hash.Set(NewInteger(1), NewInteger(2))
+	hash.Set(NewInteger(2), NewInteger(3))
+	hash.Set(NewInteger(3), NewInteger(4))
+
+	context := &callContext{receiver: hash, env: NewMainEnvironment()}
+	_, err := hashDelete(context, NewInteger(1))
+	checkError(t, err, nil)
+	hash.Set(NewInteger(1), NewInteger(5))
+
+	checkResult(t, NewArray(hash.Keys()...), NewArray(NewInteger(3), NewInteger(2), NewInteger(1)))
+	checkResult(t, NewArray(hash.Values()...), NewArray(NewInteger(4), NewInteger(3), NewInteger(5)))
+
+	expected := "{3 => 4, 2 => 3, 1 => 5}"
+	if hash.Inspect() != expected {
+		t.Logf("Expected hash to inspect as %s, got %s\n", expected, hash.Inspect())
+		t.Fail()
+	}
+}
+
+func hashOf(pairs ...RubyObject) *Hash {
+	hash := NewHash()
+	for i := 0; i < len(pairs); i += 2 {
+		hash.Set(pairs[i], pairs[i+1])
+	}
+	return hash
+}
+
+func TestHashMethods(t *testing.T) {
+	foo, bar := NewSymbol("foo"), NewSymbol("bar")
+
+	tests := []struct {
+		receiver  *Hash
+		method    string
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{hashOf(foo, NewInteger(1)), "[]", []RubyObject{foo}, NewInteger(1), nil},
+		{hashOf(foo, NewInteger(1)), "[]", []RubyObject{bar}, NIL, nil},
+		{hashOf(foo, NewInteger(1)), "[]=", []RubyObject{bar, NewInteger(2)}, NewInteger(2), nil},
+		{hashOf(foo, NewInteger(1)), "fetch", []RubyObject{foo}, NewInteger(1), nil},
+		{hashOf(foo, NewInteger(1)), "fetch", []RubyObject{bar, TRUE}, TRUE, nil},
+		{hashOf(foo, NewInteger(1)), "fetch", []RubyObject{bar}, nil, NewKeyError(bar)},
+		{hashOf(foo, hashOf(bar, NewArray(NewInteger(2)))), "dig", []RubyObject{foo, bar, NewInteger(0)}, NewInteger(2), nil},
+		{hashOf(foo, NewInteger(1)), "dig", []RubyObject{bar, foo}, NIL, nil},
+		{
+			hashOf(foo, NewInteger(1)), "dig", []RubyObject{foo, bar},
+			nil, NewTypeError("Integer does not have #dig method"),
+		},
+		{hashOf(foo, NewInteger(1), bar, NewInteger(2)), "delete", []RubyObject{foo}, NewInteger(1), nil},
+		{hashOf(foo, NewInteger(1)), "delete", []RubyObject{bar}, NIL, nil},
+		{hashOf(foo, NewInteger(1)), "key?", []RubyObject{foo}, TRUE, nil},
+		{hashOf(foo, NewInteger(1)), "include?", []RubyObject{bar}, FALSE, nil},
+		{hashOf(foo, NewInteger(1), bar, NewInteger(2)), "keys", nil, NewArray(foo, bar), nil},
+		{hashOf(foo, NewInteger(1), bar, NewInteger(2)), "values", nil, NewArray(NewInteger(1), NewInteger(2)), nil},
+		{hashOf(foo, NewInteger(1)), "size", nil, NewInteger(1), nil},
+		{hashOf(), "empty?", nil, TRUE, nil},
+		{
+			hashOf(foo, NewInteger(1), bar, NewInteger(2)), "to_a", nil,
+			NewArray(NewArray(foo, NewInteger(1)), NewArray(bar, NewInteger(2))), nil,
+		},
+		{
+			hashOf(foo, NewInteger(1)), "merge", []RubyObject{hashOf(bar, NewInteger(2), foo, NewInteger(3))},
+			hashOf(foo, NewInteger(3), bar, NewInteger(2)), nil,
+		},
+		{hashOf(foo, NewInteger(1)), "merge", []RubyObject{NewInteger(2)}, nil, NewImplicitConversionTypeError(&Hash{}, &Integer{})},
+		{hashOf(foo, NewInteger(1), bar, NewInteger(2)), "==", []RubyObject{hashOf(bar, NewFloat(2), foo, NewInteger(1))}, TRUE, nil},
+		{hashOf(foo, NewInteger(1)), "==", []RubyObject{hashOf(foo, NewInteger(2))}, FALSE, nil},
+		{hashOf(foo, NewInteger(1)), "==", []RubyObject{NewArray()}, FALSE, nil},
+		{hashOf(&String{Value: "a"}, NewInteger(1)), "inspect", nil, &String{Value: `{"a" => 1}`}, nil},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.receiver, env: NewMainEnvironment()}
+
+		result, err := Send(context, testCase.method, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		if testCase.err == nil {
+			checkResult(t, result, testCase.result)
+		}
+	}
+}
+
+func TestHashBlockMethods(t *testing.T) {
+	double := func(node ast.Node, env Environment) (RubyObject, error) {
+		v, _ := env.Get("v")
+		return NewInteger(v.(*Integer).Value * 2), nil
+	}
+	isOdd := func(node ast.Node, env Environment) (RubyObject, error) {
+		v, _ := env.Get("v")
+		return nativeBoolToBooleanObject(v.(*Integer).Value%2 == 1), nil
+	}
+	sum := func(node ast.Node, env Environment) (RubyObject, error) {
+		a, _ := env.Get("a")
+		b, _ := env.Get("b")
+		return NewInteger(a.(*Integer).Value + b.(*Integer).Value), nil
+	}
+	block := func(names ...string) *Proc {
+		var params []*ast.FunctionParameter
+		for _, name := range names {
+			params = append(params, &ast.FunctionParameter{Name: &ast.Identifier{Value: name}})
+		}
+		return &Proc{Parameters: params, Body: &ast.BlockStatement{}, Env: NewEnvironment()}
+	}
+	a, b, c := NewSymbol("a"), NewSymbol("b"), NewSymbol("c")
+	hash := hashOf(a, NewInteger(3), b, NewInteger(2), c, NewInteger(1))
+
+	tests := []struct {
+		method    RubyMethod
+		eval      func(node ast.Node, env Environment) (RubyObject, error)
+		arguments []RubyObject
+		result    RubyObject
+	}{
+		{publicMethod(hashMap), double, []RubyObject{block("k", "v")}, NewArray(NewInteger(6), NewInteger(4), NewInteger(2))},
+		{publicMethod(hashSelect), isOdd, []RubyObject{block("k", "v")}, hashOf(a, NewInteger(3), c, NewInteger(1))},
+		{publicMethod(hashReject), isOdd, []RubyObject{block("k", "v")}, hashOf(b, NewInteger(2))},
+		{publicMethod(hashTransformValues), double, []RubyObject{block("v")}, hashOf(a, NewInteger(6), b, NewInteger(4), c, NewInteger(2))},
+		{
+			publicMethod(hashGroupBy), isOdd, []RubyObject{block("k", "v")},
+			hashOf(
+				TRUE, NewArray(NewArray(a, NewInteger(3)), NewArray(c, NewInteger(1))),
+				FALSE, NewArray(NewArray(b, NewInteger(2))),
+			),
+		},
+		{
+			publicMethod(hashSortBy), double, []RubyObject{block("k", "v")},
+			NewArray(NewArray(c, NewInteger(1)), NewArray(b, NewInteger(2)), NewArray(a, NewInteger(3))),
+		},
+		{publicMethod(hashFetch), double, []RubyObject{NewInteger(5), block("v")}, NewInteger(10)},
+		{
+			publicMethod(hashMerge), sum, []RubyObject{hashOf(b, NewInteger(5)), block("k", "a", "b")},
+			hashOf(a, NewInteger(3), b, NewInteger(7), c, NewInteger(1)),
+		},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: hash, env: NewMainEnvironment(), eval: testCase.eval}
+
+		result, err := testCase.method.Call(context, testCase.arguments...)
+
+		checkError(t, err, nil)
+		checkResult(t, result, testCase.result)
+	}
+
+	t.Run("each", func(t *testing.T) {
+		var pairs []RubyObject
+		eval := func(node ast.Node, env Environment) (RubyObject, error) {
+			k, _ := env.Get("k")
+			v, _ := env.Get("v")
+			pairs = append(pairs, NewArray(k, v))
+			return NIL, nil
+		}
+		context := &callContext{receiver: hash, env: NewMainEnvironment(), eval: eval}
+
+		result, err := hashEach(context, block("k", "v"))
+
+		checkError(t, err, nil)
+		checkResult(t, result, hash)
+		checkResult(t, NewArray(pairs...), NewArray(
+			NewArray(a, NewInteger(3)), NewArray(b, NewInteger(2)), NewArray(c, NewInteger(1)),
+		))
+	})
+	t.Run("adding keys while iterating", func(t *testing.T) {
+		hash := hashOf(a, NewInteger(1))
+		eval := func(node ast.Node, env Environment) (RubyObject, error) {
+			k, _ := env.Get("k")
+			return NIL, hash.store(nil, k, NewInteger(2))
+		}
+		context := &callContext{receiver: hash, env: NewMainEnvironment(), eval: eval}
+
+		_, err := hashEach(context, block("k", "v"))
+		checkError(t, err, nil)
+		checkResult(t, hash, hashOf(a, NewInteger(2)))
+
+		eval = func(node ast.Node, env Environment) (RubyObject, error) {
+			return NIL, hash.store(nil, b, NewInteger(2))
+		}
+		context = &callContext{receiver: hash, env: NewMainEnvironment(), eval: eval}
+
+		_, err = hashEach(context, block("k", "v"))
+		checkError(t, err, NewRuntimeError("can't add a new key into hash during iteration"))
+
+		err = hash.store(nil, b, NewInteger(2))
+		checkError(t, err, nil)
+	})
+}
+
+func TestHashDefault(t *testing.T) {
+	t.Run("default value", func(t *testing.T) {
+		context := &callContext{receiver: hashClass, env: NewMainEnvironment()}
+
+		hash, err := Send(context, "new", NewInteger(0))
+		checkError(t, err, nil)
+
+		context = &callContext{receiver: hash, env: NewMainEnvironment()}
+		result, err := Send(context, "[]", NewSymbol("foo"))
+		checkError(t, err, nil)
+		checkResult(t, result, NewInteger(0))
+
+		_, err = Send(context, "default=", NewInteger(5))
+		checkError(t, err, nil)
+		result, err = Send(context, "[]", NewSymbol("foo"))
+		checkError(t, err, nil)
+		checkResult(t, result, NewInteger(5))
+
+		result, err = Send(context, "fetch", NewSymbol("foo"))
+		checkError(t, err, NewKeyError(NewSymbol("foo")))
+	})
+	t.Run("default proc", func(t *testing.T) {
+		eval := func(node ast.Node, env Environment) (RubyObject, error) {
+			h, _ := env.Get("h")
+			k, _ := env.Get("k")
+			return h.(*Hash).Set(k, NewInteger(k.(*Integer).Value*3)), nil
+		}
+		block := &Proc{
+			Parameters: []*ast.FunctionParameter{
+				{Name: &ast.Identifier{Value: "h"}},
+				{Name: &ast.Identifier{Value: "k"}},
+			},
+			Body: &ast.BlockStatement{},
+			Env:  NewEnvironment(),
+		}
+		context := &callContext{receiver: hashClass, env: NewMainEnvironment(), eval: eval}
+
+		hash, err := Send(context, "new", block)
+		checkError(t, err, nil)
+
+		context = &callContext{receiver: hash, env: NewMainEnvironment(), eval: eval}
+		result, err := Send(context, "[]", NewInteger(2))
+		checkError(t, err, nil)
+		checkResult(t, result, NewInteger(6))
+		expected := hashOf(NewInteger(2), NewInteger(6))
+		expected.DefaultProc = block
+		checkResult(t, hash, expected)
+	})
+}
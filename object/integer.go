@@ -1,6 +1,11 @@
 package object
 
-import "fmt"
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/big"
+)
 
 var integerClass RubyClassObject = newClass(
 	"Integer", objectClass, integerMethods, integerClassMethods, notInstantiatable,
@@ -15,13 +20,40 @@ func NewInteger(value int64) *Integer {
 	return &Integer{Value: value}
 }
 
-// Integer represents an integer in Ruby
+// NewBigInteger returns a new Integer with the given value. The Integer
+// holds a plain int64 if value fits into it.
+func NewBigInteger(value *big.Int) *Integer {
+	if value.IsInt64() {
+		return &Integer{Value: value.Int64()}
+	}
+	return &Integer{big: value}
+}
+
+// Integer represents an integer in Ruby. Values exceeding int64 are kept
+// within a big.Int, in which case Value is not set.
 type Integer struct {
 	Value int64
+	big   *big.Int
+}
+
+// IsBig reports whether the value of i does not fit into an int64
+func (i *Integer) IsBig() bool { return i.big != nil }
+
+// BigInt returns the value of i as a new big.Int
+func (i *Integer) BigInt() *big.Int {
+	if i.big != nil {
+		return new(big.Int).Set(i.big)
+	}
+	return big.NewInt(i.Value)
 }
 
 // Inspect returns the value as string
-func (i *Integer) Inspect() string { return fmt.Sprintf("%d", i.Value) }
+func (i *Integer) Inspect() string {
+	if i.big != nil {
+		return i.big.String()
+	}
+	return fmt.Sprintf("%d", i.Value)
+}
 
 // Type returns INTEGER_OBJ
 func (i *Integer) Type() Type { return INTEGER_OBJ }
@@ -30,179 +62,335 @@ func (i *Integer) Type() Type { return INTEGER_OBJ }
 func (i *Integer) Class() RubyClass { return integerClass }
 
 func (i *Integer) hashKey() hashKey {
+	if i.big != nil {
+		h := fnv.New64a()
+		h.Write([]byte{byte(i.big.Sign() + 1)})
+		h.Write(i.big.Bytes())
+		return hashKey{Type: i.Type(), Value: h.Sum64()}
+	}
 	return hashKey{Type: i.Type(), Value: uint64(i.Value)}
 }
 
+// float returns the value of i as float64
+func (i *Integer) float() float64 {
+	if i.big != nil {
+		f, _ := new(big.Float).SetInt(i.big).Float64()
+		return f
+	}
+	return float64(i.Value)
+}
+
+// cmp compares i and other and returns -1, 0 or +1
+func (i *Integer) cmp(other *Integer) int {
+	if i.big == nil && other.big == nil {
+		switch {
+		case i.Value < other.Value:
+			return -1
+		case i.Value > other.Value:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return i.BigInt().Cmp(other.BigInt())
+}
+
+// maxPowBits is the maximum size in bits of the results of Integer#**, which
+// is the limit MRI applies as well
+const maxPowBits = 32 * 1024 * 1024
+
 var integerClassMethods = map[string]RubyMethod{}
 
 var integerMethods = map[string]RubyMethod{
-	"div": withArity(1, publicMethod(integerDiv)),
-	"/":   withArity(1, publicMethod(integerDiv)),
-	"*":   withArity(1, publicMethod(integerMul)),
-	"+":   withArity(1, publicMethod(integerAdd)),
-	"-":   withArity(1, publicMethod(integerSub)),
-	"%":   withArity(1, publicMethod(integerModulo)),
-	"<":   withArity(1, publicMethod(integerLt)),
-	">":   withArity(1, publicMethod(integerGt)),
-	"==":  withArity(1, publicMethod(integerEq)),
-	"!=":  withArity(1, publicMethod(integerNeq)),
-	">=":  withArity(1, publicMethod(integerGte)),
-	"<=":  withArity(1, publicMethod(integerLte)),
-	"<=>": withArity(1, publicMethod(integerSpaceship)),
+	"div":    withArity(1, publicMethod(integerDiv)),
+	"/":      withArity(1, publicMethod(integerDiv)),
+	"*":      withArity(1, publicMethod(integerMul)),
+	"+":      withArity(1, publicMethod(integerAdd)),
+	"-":      withArity(1, publicMethod(integerSub)),
+	"%":      withArity(1, publicMethod(integerModulo)),
+	"**":     withArity(1, publicMethod(integerPow)),
+	"<":      withArity(1, publicMethod(integerLt)),
+	">":      withArity(1, publicMethod(integerGt)),
+	"==":     withArity(1, publicMethod(integerEq)),
+	"!=":     withArity(1, publicMethod(integerNeq)),
+	">=":     withArity(1, publicMethod(integerGte)),
+	"<=":     withArity(1, publicMethod(integerLte)),
+	"<=>":    withArity(1, publicMethod(integerSpaceship)),
+	"coerce": withArity(1, publicMethod(integerCoerce)),
+	"to_f":   withArity(0, publicMethod(integerToF)),
+	"to_s":   withArity(0, publicMethod(integerToS)),
+	"succ":   withArity(0, publicMethod(integerSucc)),
+	"next":   withArity(0, publicMethod(integerSucc)),
+}
+
+func integerSucc(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return integerAdd(context, NewInteger(1))
 }
 
 func integerDiv(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	divisor, ok := args[0].(*Integer)
 	if !ok {
-		return nil, NewCoercionTypeError(args[0], i)
+		return coerceBinop(context, "/", args[0])
 	}
-	if divisor.Value == 0 {
+	if divisor.big == nil && divisor.Value == 0 {
 		return nil, NewZeroDivisionError()
 	}
-	return NewInteger(i.Value / divisor.Value), nil
+	if i.big == nil && divisor.big == nil && !(i.Value == math.MinInt64 && divisor.Value == -1) {
+		quotient := i.Value / divisor.Value
+		// round towards negative infinity as MRI does
+		if i.Value%divisor.Value != 0 && (i.Value < 0) != (divisor.Value < 0) {
+			quotient--
+		}
+		return NewInteger(quotient), nil
+	}
+	quotient, remainder := new(big.Int).QuoRem(i.BigInt(), divisor.BigInt(), new(big.Int))
+	if remainder.Sign() != 0 && remainder.Sign() != divisor.BigInt().Sign() {
+		quotient.Sub(quotient, big.NewInt(1))
+	}
+	return NewBigInteger(quotient), nil
 }
 
 func integerMul(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	factor, ok := args[0].(*Integer)
 	if !ok {
-		return nil, NewCoercionTypeError(args[0], i)
+		return coerceBinop(context, "*", args[0])
+	}
+	if i.big == nil && factor.big == nil {
+		product := i.Value * factor.Value
+		if i.Value == 0 || (product/i.Value == factor.Value && !(i.Value == -1 && factor.Value == math.MinInt64)) {
+			return NewInteger(product), nil
+		}
 	}
-	return NewInteger(i.Value * factor.Value), nil
+	return NewBigInteger(new(big.Int).Mul(i.BigInt(), factor.BigInt())), nil
 }
 
 func integerAdd(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	add, ok := args[0].(*Integer)
 	if !ok {
-		return nil, NewCoercionTypeError(args[0], i)
+		return coerceBinop(context, "+", args[0])
 	}
-	return NewInteger(i.Value + add.Value), nil
+	if i.big == nil && add.big == nil {
+		sum := i.Value + add.Value
+		if (sum > i.Value) == (add.Value > 0) {
+			return NewInteger(sum), nil
+		}
+	}
+	return NewBigInteger(new(big.Int).Add(i.BigInt(), add.BigInt())), nil
 }
 
 func integerSub(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	sub, ok := args[0].(*Integer)
 	if !ok {
-		return nil, NewCoercionTypeError(args[0], i)
+		return coerceBinop(context, "-", args[0])
+	}
+	if i.big == nil && sub.big == nil {
+		diff := i.Value - sub.Value
+		if (diff < i.Value) == (sub.Value > 0) {
+			return NewInteger(diff), nil
+		}
 	}
-	return NewInteger(i.Value - sub.Value), nil
+	return NewBigInteger(new(big.Int).Sub(i.BigInt(), sub.BigInt())), nil
 }
 
 func integerModulo(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	mod, ok := args[0].(*Integer)
 	if !ok {
-		return nil, NewCoercionTypeError(args[0], i)
+		return coerceBinop(context, "%", args[0])
+	}
+	if mod.big == nil && mod.Value == 0 {
+		return nil, NewZeroDivisionError()
+	}
+	// the result has the sign of mod, as in MRI
+	if i.big == nil && mod.big == nil {
+		remainder := i.Value % mod.Value
+		if remainder != 0 && (remainder < 0) != (mod.Value < 0) {
+			remainder += mod.Value
+		}
+		return NewInteger(remainder), nil
+	}
+	remainder := new(big.Int).Rem(i.BigInt(), mod.BigInt())
+	if remainder.Sign() != 0 && remainder.Sign() != mod.BigInt().Sign() {
+		remainder.Add(remainder, mod.BigInt())
+	}
+	return NewBigInteger(remainder), nil
+}
+
+func integerPow(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	exp, ok := args[0].(*Integer)
+	if !ok {
+		return coerceBinop(context, "**", args[0])
 	}
-	return NewInteger(i.Value % mod.Value), nil
+	if exp.cmp(NewInteger(0)) < 0 {
+		return NewFloat(math.Pow(i.float(), exp.float())), nil
+	}
+	base := i.BigInt()
+	// estimate the size of the result, which stays constant for 0, 1 and -1
+	if base.CmpAbs(big.NewInt(1)) > 0 {
+		bits := new(big.Int).Mul(big.NewInt(int64(base.BitLen())), exp.BigInt())
+		if bits.Cmp(big.NewInt(maxPowBits)) > 0 {
+			return nil, NewArgumentError("exponent is too large")
+		}
+	}
+	return NewBigInteger(base.Exp(base, exp.BigInt(), nil)), nil
 }
 
 func integerLt(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
+	if _, ok := args[0].(*Float); ok {
+		cmp, ok := numericCmp(i, args[0])
+		return nativeBoolToBooleanObject(ok && cmp < 0), nil
+	}
 	right, ok := args[0].(*Integer)
 	if !ok {
-		return nil, NewArgumentError(
-			"comparison of Integer with %s failed",
-			args[0].Class().(RubyObject).Inspect(),
-		)
+		return coerceComparison(context, "<", args[0])
 	}
-	if i.Value < right.Value {
-		return TRUE, nil
-	}
-	return FALSE, nil
+	return nativeBoolToBooleanObject(i.cmp(right) < 0), nil
 }
 
 func integerGt(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
+	if _, ok := args[0].(*Float); ok {
+		cmp, ok := numericCmp(i, args[0])
+		return nativeBoolToBooleanObject(ok && cmp > 0), nil
+	}
 	right, ok := args[0].(*Integer)
 	if !ok {
-		return nil, NewArgumentError(
-			"comparison of Integer with %s failed",
-			args[0].Class().(RubyObject).Inspect(),
-		)
+		return coerceComparison(context, ">", args[0])
 	}
-	if i.Value > right.Value {
-		return TRUE, nil
-	}
-	return FALSE, nil
+	return nativeBoolToBooleanObject(i.cmp(right) > 0), nil
 }
 
 func integerEq(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
+	if _, ok := args[0].(*Float); ok {
+		cmp, ok := numericCmp(i, args[0])
+		return nativeBoolToBooleanObject(ok && cmp == 0), nil
+	}
 	right, ok := args[0].(*Integer)
 	if !ok {
-		return nil, NewArgumentError(
-			"comparison of Integer with %s failed",
-			args[0].Class().(RubyObject).Inspect(),
-		)
-	}
-	if i.Value == right.Value {
-		return TRUE, nil
+		return FALSE, nil
 	}
-	return FALSE, nil
+	return nativeBoolToBooleanObject(i.cmp(right) == 0), nil
 }
 
 func integerNeq(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
+	if _, ok := args[0].(*Float); ok {
+		cmp, ok := numericCmp(i, args[0])
+		return nativeBoolToBooleanObject(!ok || cmp != 0), nil
+	}
 	right, ok := args[0].(*Integer)
 	if !ok {
-		return nil, NewArgumentError(
-			"comparison of Integer with %s failed",
-			args[0].Class().(RubyObject).Inspect(),
-		)
-	}
-	if i.Value != right.Value {
 		return TRUE, nil
 	}
-	return FALSE, nil
+	return nativeBoolToBooleanObject(i.cmp(right) != 0), nil
 }
 
 func integerSpaceship(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
+	if _, ok := args[0].(*Float); ok {
+		cmp, ok := numericCmp(i, args[0])
+		if !ok {
+			return NIL, nil
+		}
+		return NewInteger(int64(cmp)), nil
+	}
 	right, ok := args[0].(*Integer)
 	if !ok {
 		return NIL, nil
 	}
-	switch {
-	case i.Value > right.Value:
-		return &Integer{Value: 1}, nil
-	case i.Value < right.Value:
-		return &Integer{Value: -1}, nil
-	case i.Value == right.Value:
-		return &Integer{Value: 0}, nil
-	default:
-		panic("not reachable")
-	}
+	return &Integer{Value: int64(i.cmp(right))}, nil
 }
 
 func integerGte(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
+	if _, ok := args[0].(*Float); ok {
+		cmp, ok := numericCmp(i, args[0])
+		return nativeBoolToBooleanObject(ok && cmp >= 0), nil
+	}
 	right, ok := args[0].(*Integer)
 	if !ok {
-		return nil, NewArgumentError(
-			"comparison of Integer with %s failed",
-			args[0].Class().(RubyObject).Inspect(),
-		)
+		return coerceComparison(context, ">=", args[0])
 	}
-	if i.Value >= right.Value {
-		return TRUE, nil
-	}
-	return FALSE, nil
+	return nativeBoolToBooleanObject(i.cmp(right) >= 0), nil
 }
 
 func integerLte(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
+	if _, ok := args[0].(*Float); ok {
+		cmp, ok := numericCmp(i, args[0])
+		return nativeBoolToBooleanObject(ok && cmp <= 0), nil
+	}
 	right, ok := args[0].(*Integer)
+	if !ok {
+		return coerceComparison(context, "<=", args[0])
+	}
+	return nativeBoolToBooleanObject(i.cmp(right) <= 0), nil
+}
+
+func integerCoerce(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	switch other := args[0].(type) {
+	case *Integer:
+		return NewArray(other, i), nil
+	case *Float:
+		return NewArray(other, NewFloat(i.float())), nil
+	default:
+		return nil, NewCoercionTypeError(i, args[0])
+	}
+}
+
+func integerToF(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	return NewFloat(i.float()), nil
+}
+
+func integerToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	return &String{Value: i.Inspect()}, nil
+}
+
+// coerce converts the receiver of context and arg into a pair of objects of
+// the same kind by calling arg.coerce(receiver)
+func coerce(context CallContext, arg RubyObject) (RubyObject, RubyObject, bool) {
+	coerceContext := &callContext{receiver: arg, env: context.Env(), eval: context.Eval}
+	coerced, err := Send(coerceContext, "coerce", context.Receiver())
+	if err != nil {
+		return nil, nil, false
+	}
+	pair, ok := coerced.(*Array)
+	if !ok || len(pair.Elements) != 2 {
+		return nil, nil, false
+	}
+	return pair.Elements[0], pair.Elements[1], true
+}
+
+// coerceBinop applies the binary operator op to the receiver of context and
+// arg after coercing both to the same kind
+func coerceBinop(context CallContext, op string, arg RubyObject) (RubyObject, error) {
+	left, right, ok := coerce(context, arg)
+	if !ok {
+		return nil, NewCoercionTypeError(arg, context.Receiver())
+	}
+	return Send(&callContext{receiver: left, env: context.Env(), eval: context.Eval}, op, right)
+}
+
+// coerceComparison is like coerceBinop for comparison operators, which raise
+// an ArgumentError if arg cannot be coerced
+func coerceComparison(context CallContext, op string, arg RubyObject) (RubyObject, error) {
+	left, right, ok := coerce(context, arg)
 	if !ok {
 		return nil, NewArgumentError(
-			"comparison of Integer with %s failed",
-			args[0].Class().(RubyObject).Inspect(),
+			"comparison of %s with %s failed",
+			context.Receiver().Class().(RubyObject).Inspect(),
+			arg.Class().(RubyObject).Inspect(),
 		)
 	}
-	if i.Value <= right.Value {
-		return TRUE, nil
-	}
-	return FALSE, nil
+	return Send(&callContext{receiver: left, env: context.Env(), eval: context.Eval}, op, right)
 }
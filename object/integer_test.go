@@ -1,6 +1,8 @@
 package object
 
 import (
+	"math"
+	"math/big"
 	"reflect"
 	"testing"
 )
@@ -22,6 +24,43 @@ func TestInteger_hashKey(t *testing.T) {
 	if hello1.hashKey() == diff1.hashKey() {
 		t.Errorf("strings with different content have same hash keys")
 	}
+
+	big1, _ := new(big.Int).SetString("18446744073709551616", 10)
+	big2, _ := new(big.Int).SetString("18446744073709551616", 10)
+
+	if NewBigInteger(big1).hashKey() != NewBigInteger(big2).hashKey() {
+		t.Errorf("big integers with same value have different hash keys")
+	}
+
+	if NewBigInteger(big.NewInt(3)).hashKey() != diff1.hashKey() {
+		t.Errorf("integers with same value have different hash keys")
+	}
+
+	negative := new(big.Int).Neg(big1)
+
+	if NewBigInteger(negative).hashKey() == NewBigInteger(big1).hashKey() {
+		t.Errorf("big integers with different sign have same hash keys")
+	}
+}
+
+func TestNewBigInteger(t *testing.T) {
+	t.Run("fits into int64", func(t *testing.T) {
+		integer := NewBigInteger(big.NewInt(42))
+
+		checkResult(t, integer, NewInteger(42))
+	})
+	t.Run("exceeds int64", func(t *testing.T) {
+		value, _ := new(big.Int).SetString("-18446744073709551616", 10)
+
+		integer := NewBigInteger(value)
+
+		if !integer.IsBig() {
+			t.Errorf("Expected integer to be big")
+		}
+		if integer.Inspect() != "-18446744073709551616" {
+			t.Errorf("Expected Inspect to return %s, got %s", "-18446744073709551616", integer.Inspect())
+		}
+	})
 }
 
 func TestIntegerDiv(t *testing.T) {
@@ -36,7 +75,7 @@ func TestIntegerDiv(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{&String{Value: ""}},
 			nil,
 			NewCoercionTypeError(&String{}, &Integer{}),
 		},
@@ -58,6 +97,39 @@ func TestIntegerDiv(t *testing.T) {
 	}
 }
 
+func TestIntegerFlooredDivision(t *testing.T) {
+	bigValue := new(big.Int).Lsh(big.NewInt(1), 64)
+	negativeBig := new(big.Int).Neg(bigValue)
+	tests := []struct {
+		receiver *Integer
+		argument *Integer
+		quotient RubyObject
+		modulo   RubyObject
+	}{
+		{NewInteger(7), NewInteger(2), NewInteger(3), NewInteger(1)},
+		{NewInteger(-7), NewInteger(2), NewInteger(-4), NewInteger(1)},
+		{NewInteger(7), NewInteger(-2), NewInteger(-4), NewInteger(-1)},
+		{NewInteger(-7), NewInteger(-2), NewInteger(3), NewInteger(-1)},
+		{NewInteger(-6), NewInteger(2), NewInteger(-3), NewInteger(0)},
+		{NewBigInteger(negativeBig), NewInteger(3), NewBigInteger(new(big.Int).Div(negativeBig, big.NewInt(3))), NewInteger(2)},
+		{NewInteger(5), NewBigInteger(negativeBig), NewInteger(-1), NewBigInteger(new(big.Int).Add(negativeBig, big.NewInt(5)))},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.receiver}
+
+		quotient, err := integerDiv(context, testCase.argument)
+
+		checkError(t, err, nil)
+		checkResult(t, quotient, testCase.quotient)
+
+		modulo, err := integerModulo(context, testCase.argument)
+
+		checkError(t, err, nil)
+		checkResult(t, modulo, testCase.modulo)
+	}
+}
+
 func TestIntegerMul(t *testing.T) {
 	tests := []struct {
 		arguments []RubyObject
@@ -70,7 +142,7 @@ func TestIntegerMul(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{&String{Value: ""}},
 			nil,
 			NewCoercionTypeError(&String{}, &Integer{}),
 		},
@@ -99,7 +171,17 @@ func TestIntegerAdd(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{NewFloat(2.5)},
+			NewFloat(4.5),
+			nil,
+		},
+		{
+			[]RubyObject{NewInteger(math.MaxInt64)},
+			NewBigInteger(new(big.Int).Add(big.NewInt(math.MaxInt64), big.NewInt(2))),
+			nil,
+		},
+		{
+			[]RubyObject{&String{Value: ""}},
 			nil,
 			NewCoercionTypeError(&String{}, &Integer{}),
 		},
@@ -128,7 +210,7 @@ func TestIntegerSub(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{&String{Value: ""}},
 			nil,
 			NewCoercionTypeError(&String{}, &Integer{}),
 		},
@@ -157,7 +239,7 @@ func TestIntegerModulo(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{&String{Value: ""}},
 			nil,
 			NewCoercionTypeError(&String{}, &Integer{}),
 		},
@@ -191,7 +273,7 @@ func TestIntegerLt(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{&String{Value: ""}},
 			nil,
 			NewArgumentError("comparison of Integer with String failed"),
 		},
@@ -225,7 +307,7 @@ func TestIntegerGt(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{&String{Value: ""}},
 			nil,
 			NewArgumentError("comparison of Integer with String failed"),
 		},
@@ -259,9 +341,9 @@ func TestIntegerEq(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{&String{Value: ""}},
+			FALSE,
 			nil,
-			NewArgumentError("comparison of Integer with String failed"),
 		},
 	}
 
@@ -293,9 +375,9 @@ func TestIntegerNeq(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{&String{Value: ""}},
+			TRUE,
 			nil,
-			NewArgumentError("comparison of Integer with String failed"),
 		},
 	}
 
@@ -332,7 +414,7 @@ func TestIntegerGte(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{&String{Value: ""}},
 			nil,
 			NewArgumentError("comparison of Integer with String failed"),
 		},
@@ -371,7 +453,7 @@ func TestIntegerLte(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{&String{Value: ""}},
 			nil,
 			NewArgumentError("comparison of Integer with String failed"),
 		},
@@ -410,7 +492,7 @@ func TestIntegerSpaceship(t *testing.T) {
 			nil,
 		},
 		{
-			[]RubyObject{&String{""}},
+			[]RubyObject{&String{Value: ""}},
 			NIL,
 			nil,
 		},
@@ -427,6 +509,176 @@ func TestIntegerSpaceship(t *testing.T) {
 	}
 }
 
+func TestIntegerPow(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{
+			[]RubyObject{NewInteger(3)},
+			NewInteger(8),
+			nil,
+		},
+		{
+			[]RubyObject{NewInteger(64)},
+			NewBigInteger(new(big.Int).Lsh(big.NewInt(1), 64)),
+			nil,
+		},
+		{
+			[]RubyObject{NewInteger(-1)},
+			NewFloat(0.5),
+			nil,
+		},
+		{
+			[]RubyObject{NewInteger(100000000000)},
+			nil,
+			NewArgumentError("exponent is too large"),
+		},
+		{
+			[]RubyObject{&String{Value: ""}},
+			nil,
+			NewCoercionTypeError(&String{}, &Integer{}),
+		},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewInteger(2)}
+
+		result, err := integerPow(context, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestIntegerPowWithoutGrowth(t *testing.T) {
+	for _, base := range []int64{0, 1, -1} {
+		context := &callContext{receiver: NewInteger(base)}
+
+		result, err := integerPow(context, NewInteger(100000000001))
+
+		checkError(t, err, nil)
+		checkResult(t, result, NewInteger(base))
+	}
+}
+
+func TestIntegerFloatComparison(t *testing.T) {
+	huge := NewBigInteger(new(big.Int).Exp(big.NewInt(10), big.NewInt(400), nil))
+	// 2**53 + 1 is the smallest integer which is not exactly a float64
+	inexact := NewInteger(1<<53 + 1)
+	infinity := NewFloat(math.Inf(1))
+	tests := []struct {
+		name     string
+		method   RubyMethod
+		receiver RubyObject
+		argument RubyObject
+		result   RubyObject
+	}{
+		{"==", integerMethods["=="], huge, infinity, FALSE},
+		{"!=", integerMethods["!="], huge, infinity, TRUE},
+		{"<", integerMethods["<"], huge, infinity, TRUE},
+		{">", integerMethods[">"], huge, NewFloat(math.Inf(-1)), TRUE},
+		{"<=", integerMethods["<="], huge, NewFloat(math.MaxFloat64), FALSE},
+		{">=", integerMethods[">="], huge, NewFloat(math.NaN()), FALSE},
+		{"<=>", integerMethods["<=>"], huge, infinity, NewInteger(-1)},
+		{"<=> NaN", integerMethods["<=>"], huge, NewFloat(math.NaN()), NIL},
+		{"== inexact", integerMethods["=="], inexact, NewFloat(1 << 53), FALSE},
+		{"> inexact", integerMethods[">"], inexact, NewFloat(1 << 53), TRUE},
+		{"== exact", integerMethods["=="], NewInteger(3), NewFloat(3), TRUE},
+		{"Float#==", floatMethods["=="], infinity, huge, FALSE},
+		{"Float#<", floatMethods["<"], infinity, huge, FALSE},
+		{"Float#>", floatMethods[">"], infinity, huge, TRUE},
+		{"Float#<=>", floatMethods["<=>"], infinity, huge, NewInteger(1)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			context := &callContext{receiver: testCase.receiver}
+
+			result, err := testCase.method.Call(context, testCase.argument)
+
+			checkError(t, err, nil)
+			checkResult(t, result, testCase.result)
+		})
+	}
+}
+
+func TestIntegerCoerce(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{
+			[]RubyObject{NewInteger(1)},
+			NewArray(NewInteger(1), NewInteger(2)),
+			nil,
+		},
+		{
+			[]RubyObject{NewFloat(1.5)},
+			NewArray(NewFloat(1.5), NewFloat(2)),
+			nil,
+		},
+		{
+			[]RubyObject{&String{Value: ""}},
+			nil,
+			NewCoercionTypeError(&Integer{}, &String{}),
+		},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewInteger(2)}
+
+		result, err := integerCoerce(context, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestIntegerToF(t *testing.T) {
+	context := &callContext{receiver: NewInteger(2)}
+
+	result, err := integerToF(context)
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, NewFloat(2))
+}
+
+func TestIntegerSucc(t *testing.T) {
+	context := &callContext{receiver: NewInteger(2)}
+
+	result, err := integerSucc(context)
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, NewInteger(3))
+}
+
+func TestIntegerToS(t *testing.T) {
+	tests := []struct {
+		receiver *Integer
+		result   RubyObject
+	}{
+		{NewInteger(-42), &String{Value: "-42"}},
+		{NewBigInteger(new(big.Int).Lsh(big.NewInt(1), 64)), &String{Value: "18446744073709551616"}},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.receiver}
+
+		result, err := integerToS(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
 func checkError(t *testing.T, actual, expected error) {
 	t.Helper()
 	if !reflect.DeepEqual(expected, actual) {
@@ -6,7 +6,7 @@ func getMethods(class RubyClass, visibility MethodVisibility, addSuperMethods bo
 		methods := class.Methods().GetAll()
 		for meth, fn := range methods {
 			if fn.Visibility() == visibility {
-				methodSymbols = append(methodSymbols, &Symbol{meth})
+				methodSymbols = append(methodSymbols, NewSymbol(meth))
 			}
 		}
 		if !addSuperMethods {
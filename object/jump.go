@@ -0,0 +1,46 @@
+package object
+
+import "fmt"
+
+// JumpType describes the kind of a loop control jump
+type JumpType int
+
+const (
+	// BreakJump leaves the enclosing loop or block call
+	BreakJump JumpType = iota
+	// NextJump skips to the next iteration of the enclosing loop or block
+	NextJump
+	// RedoJump restarts the current iteration without checking the condition
+	RedoJump
+	// RetryJump restarts the begin block whose rescue clause it was raised in
+	RetryJump
+)
+
+var jumpTypeNames = map[JumpType]string{
+	BreakJump: "break",
+	NextJump:  "next",
+	RedoJump:  "redo",
+	RetryJump: "retry",
+}
+
+func (j JumpType) String() string { return jumpTypeNames[j] }
+
+// Jump represents a break, next, redo or retry travelling up the evaluation stack.
+// It is no real Ruby object and only used within the interpreter evaluation
+type Jump struct {
+	JumpType
+	// Value is the value given to break or next, NIL otherwise
+	Value RubyObject
+	// Block is the Proc a break escaped from, if any
+	Block *Proc
+}
+
+func (j *Jump) Error() string {
+	return fmt.Sprintf("%s from proc-closure", j.JumpType)
+}
+
+// NewJumpLocalJumpError returns a LocalJumpError for a jump which escaped
+// every enclosing loop and block
+func NewJumpLocalJumpError(jump *Jump) *LocalJumpError {
+	return &LocalJumpError{message: jump.Error()}
+}
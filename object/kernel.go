@@ -3,10 +3,12 @@ package object
 import (
 	"fmt"
 	"go/token"
+	"io"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/goruby/goruby/parser"
@@ -15,6 +17,9 @@ import (
 
 var kernelModule = newModule("Kernel", kernelMethodSet, nil)
 
+// stdout is where puts, print and p write to
+var stdout io.Writer = os.Stdout
+
 func init() {
 	classes.Set("Kernel", kernelModule)
 }
@@ -22,17 +27,31 @@ func init() {
 var kernelMethodSet = map[string]RubyMethod{
 	"to_s":              withArity(0, publicMethod(kernelToS)),
 	"nil?":              withArity(0, publicMethod(kernelIsNil)),
+	"equal?":            withArity(1, publicMethod(kernelIsEqual)),
+	"eql?":              withArity(1, publicMethod(kernelIsEql)),
+	"hash":              withArity(0, publicMethod(kernelHash)),
+	"object_id":         withArity(0, publicMethod(kernelObjectID)),
 	"methods":           publicMethod(kernelMethods),
+	"method":            withArity(1, publicMethod(kernelMethod)),
 	"public_methods":    publicMethod(kernelPublicMethods),
 	"protected_methods": publicMethod(kernelProtectedMethods),
 	"private_methods":   publicMethod(kernelPrivateMethods),
 	"class":             withArity(0, publicMethod(kernelClass)),
+	"is_a?":             withArity(1, publicMethod(kernelIsA)),
+	"kind_of?":          withArity(1, publicMethod(kernelIsA)),
+	"instance_of?":      withArity(1, publicMethod(kernelInstanceOf)),
+	"inspect":           withArity(0, publicMethod(kernelInspect)),
 	"puts":              privateMethod(kernelPuts),
+	"print":             privateMethod(kernelPrint),
+	"p":                 privateMethod(kernelP),
 	"require":           withArity(1, privateMethod(kernelRequire)),
 	"extend":            publicMethod(kernelExtend),
 	"block_given?":      withArity(0, privateMethod(kernelBlockGiven)),
 	"tap":               publicMethod(kernelTap),
+	"!~":                withArity(1, publicMethod(kernelNotMatch)),
 	"raise":             privateMethod(kernelRaise),
+	"rand":              privateMethod(kernelRand),
+	"srand":             privateMethod(kernelSrand),
 }
 
 func kernelToS(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -44,15 +63,97 @@ func kernelToS(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return &String{Value: val}, nil
 }
 
+func kernelInspect(context CallContext, args ...RubyObject) (RubyObject, error) {
+	receiver := context.Receiver()
+	if self, ok := receiver.(*Self); ok {
+		receiver = self.RubyObject
+	}
+	return &String{Value: receiver.Inspect()}, nil
+}
+
+// inspect returns the value of the String obj.inspect returns when called
+// within context
+func inspect(context CallContext, obj RubyObject) (string, error) {
+	inspected, err := Send(&callContext{receiver: obj, env: context.Env(), eval: context.Eval}, "inspect")
+	if err != nil {
+		return "", err
+	}
+	if str, ok := inspected.(*String); ok {
+		return str.Value, nil
+	}
+	return obj.Inspect(), nil
+}
+
 func kernelPuts(context CallContext, args ...RubyObject) (RubyObject, error) {
-	out := ""
+	var out strings.Builder
+	if len(args) == 0 {
+		out.WriteString("\n")
+	}
+	if err := writeLines(context, &out, args); err != nil {
+		return nil, err
+	}
+	fmt.Fprint(stdout, out.String())
+	return NIL, nil
+}
+
+// writeLines writes the string representation of every arg to out, each
+// terminated by a newline. The elements of arrays are written one by one.
+func writeLines(context CallContext, out *strings.Builder, args []RubyObject) error {
 	for _, arg := range args {
-		out += arg.Inspect()
+		if array, ok := arg.(*Array); ok {
+			if len(array.Elements) == 0 {
+				out.WriteString("\n")
+			}
+			if err := writeLines(context, out, array.Elements); err != nil {
+				return err
+			}
+			continue
+		}
+		str, err := convertToString(context, arg)
+		if err != nil {
+			return err
+		}
+		out.WriteString(str)
+		if !strings.HasSuffix(str, "\n") {
+			out.WriteString("\n")
+		}
 	}
-	fmt.Println(out)
+	return nil
+}
+
+func kernelPrint(context CallContext, args ...RubyObject) (RubyObject, error) {
+	var out strings.Builder
+	for _, arg := range args {
+		str, err := convertToString(context, arg)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString(str)
+	}
+	fmt.Fprint(stdout, out.String())
 	return NIL, nil
 }
 
+func kernelP(context CallContext, args ...RubyObject) (RubyObject, error) {
+	var out strings.Builder
+	for _, arg := range args {
+		str, err := inspect(context, arg)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString(str + "\n")
+	}
+	fmt.Fprint(stdout, out.String())
+	switch len(args) {
+	case 0:
+		return NIL, nil
+	case 1:
+		return args[0], nil
+	default:
+		return NewArray(args...), nil
+	}
+}
+
 func kernelMethods(context CallContext, args ...RubyObject) (RubyObject, error) {
 	showInstanceMethods := true
 	if len(args) == 1 {
@@ -81,6 +182,27 @@ func kernelMethods(context CallContext, args ...RubyObject) (RubyObject, error)
 	return &Array{Elements: append(publicMethods.Elements, protectedMethods.Elements...)}, nil
 }
 
+// kernelMethod returns the method called like the given Symbol or String
+// bound to the receiver, regardless of its visibility
+func kernelMethod(context CallContext, args ...RubyObject) (RubyObject, error) {
+	var name string
+	switch arg := args[0].(type) {
+	case *Symbol:
+		name = arg.Value
+	case *String:
+		name = arg.Value
+	default:
+		return nil, NewTypeError(fmt.Sprintf("%s is not a symbol nor a string", arg.Inspect()))
+	}
+	receiver := context.Receiver()
+	for class := receiver.Class(); class != nil; class = class.SuperClass() {
+		if fn, ok := class.Methods().Get(name); ok {
+			return &Method{Receiver: receiver, Name: name, Fn: fn}, nil
+		}
+	}
+	return nil, NewUndefinedMethodNameError(receiver.Class(), name)
+}
+
 func kernelPublicMethods(context CallContext, args ...RubyObject) (RubyObject, error) {
 	showSuperClassMethods := true
 	if len(args) == 1 {
@@ -124,6 +246,56 @@ func kernelIsNil(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return FALSE, nil
 }
 
+// kernelIsEqual reports whether the receiver and the argument are the very
+// same object. Like in MRI integers are treated as immediate values.
+func kernelIsEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return nativeBoolToBooleanObject(objectID(context.Receiver()) == objectID(args[0])), nil
+}
+
+func kernelIsEql(context CallContext, args ...RubyObject) (RubyObject, error) {
+	receiver := context.Receiver()
+	if self, ok := receiver.(*Self); ok {
+		receiver = self.RubyObject
+	}
+	eql, err := isEql(nil, receiver, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return nativeBoolToBooleanObject(eql), nil
+}
+
+func kernelHash(context CallContext, args ...RubyObject) (RubyObject, error) {
+	receiver := context.Receiver()
+	if self, ok := receiver.(*Self); ok {
+		receiver = self.RubyObject
+	}
+	return NewInteger(int64(hash(receiver).Value)), nil
+}
+
+func kernelNotMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	match, err := Send(&callContext{receiver: context.Receiver(), env: context.Env(), eval: context.Eval}, "=~", args[0])
+	if err != nil {
+		return nil, err
+	}
+	return nativeBoolToBooleanObject(match == NIL || match == FALSE), nil
+}
+
+func kernelObjectID(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return NewInteger(objectID(context.Receiver())), nil
+}
+
+// objectID returns the identity of obj. Fixnums are mapped to 2n+1 as done
+// by MRI, any other object is identified by its address, which is even.
+func objectID(obj RubyObject) int64 {
+	if self, ok := obj.(*Self); ok {
+		obj = self.RubyObject
+	}
+	if i, ok := obj.(*Integer); ok && i.big == nil {
+		return 2*i.Value + 1
+	}
+	return int64(reflect.ValueOf(obj).Pointer())
+}
+
 func kernelClass(context CallContext, args ...RubyObject) (RubyObject, error) {
 	receiver := context.Receiver()
 	if _, ok := receiver.(RubyClassObject); ok {
@@ -132,6 +304,39 @@ func kernelClass(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return receiver.Class().(RubyClassObject), nil
 }
 
+func kernelIsA(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if !isClassOrModule(args[0]) {
+		return nil, NewTypeError("class or module required")
+	}
+	if isA(context.Receiver(), args[0]) {
+		return TRUE, nil
+	}
+	return FALSE, nil
+}
+
+func kernelInstanceOf(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if !isClassOrModule(args[0]) {
+		return nil, NewTypeError("class or module required")
+	}
+	class, err := kernelClass(context)
+	if err != nil {
+		return nil, err
+	}
+	if class == args[0] {
+		return TRUE, nil
+	}
+	return FALSE, nil
+}
+
+func isClassOrModule(obj RubyObject) bool {
+	switch obj.(type) {
+	case RubyClass, *Module:
+		return true
+	default:
+		return false
+	}
+}
+
 func kernelRequire(context CallContext, args ...RubyObject) (RubyObject, error) {
 	if len(args) != 1 {
 		return nil, NewWrongNumberOfArgumentsError(1, len(args))
@@ -156,7 +361,7 @@ func kernelRequire(context CallContext, args ...RubyObject) (RubyObject, error)
 	}
 	loaded := false
 	for _, feat := range arr.Elements {
-		if feat.Inspect() == absolutePath {
+		if str, ok := feat.(*String); ok && str.Value == absolutePath {
 			loaded = true
 			break
 		}
@@ -243,22 +448,38 @@ func kernelTap(context CallContext, args ...RubyObject) (RubyObject, error) {
 }
 
 func kernelRaise(context CallContext, args ...RubyObject) (RubyObject, error) {
+	current, _ := context.Env().Get("$!")
+	var exc RubyObject
 	switch len(args) {
+	case 0:
+		if err, ok := current.(error); ok && current != NIL {
+			return nil, err
+		}
+		return nil, NewRuntimeError("")
 	case 1:
-		switch arg := args[0].(type) {
-		case *String:
-			return nil, NewRuntimeError(arg.Value)
-		default:
-			exc, err := Send(NewCallContext(context.Env(), arg), "exception")
-			if err != nil {
-				return nil, NewTypeError("exception class/object expected")
-			}
-			if excAsErr, ok := exc.(error); ok {
-				return nil, excAsErr
-			}
-			return nil, nil
+		if str, ok := args[0].(*String); ok {
+			exc = NewRuntimeError("%s", str.Value)
+			break
+		}
+		fallthrough
+	case 2:
+		if !respondTo(args[0], "exception") {
+			return nil, NewTypeError("exception class/object expected")
+		}
+		var err error
+		exc, err = Send(&callContext{receiver: args[0], env: context.Env(), eval: context.Eval}, "exception", args[1:]...)
+		if err != nil {
+			return nil, err
 		}
 	default:
-		return nil, NewRuntimeError("")
+		return nil, NewWrongNumberOfArgumentsError(2, len(args))
+	}
+	raised, ok := exc.(exception)
+	if !ok {
+		return nil, NewTypeError("exception object expected")
+	}
+	if current != nil && current != NIL && current != exc && raised.Cause() == nil {
+		raised.setCause(current)
 	}
+	return nil, raised
 }
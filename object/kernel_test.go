@@ -1,7 +1,9 @@
 package object
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
 	"reflect"
 	"sort"
@@ -264,7 +266,7 @@ func TestKernelIsNil(t *testing.T) {
 
 func TestKernelClass(t *testing.T) {
 	t.Run("regular object", func(t *testing.T) {
-		context := &callContext{receiver: &Integer{1}}
+		context := &callContext{receiver: NewInteger(1)}
 
 		result, err := kernelClass(context)
 
@@ -313,6 +315,62 @@ func TestKernelClass(t *testing.T) {
 	})
 }
 
+func TestKernelIsA(t *testing.T) {
+	tests := []struct {
+		receiver RubyObject
+		argument RubyObject
+		result   RubyObject
+		err      error
+	}{
+		{NewInteger(3), integerClass, TRUE, nil},
+		{NewInteger(3), objectClass, TRUE, nil},
+		{NewInteger(3), kernelModule, TRUE, nil},
+		{NewInteger(3), stringClass, FALSE, nil},
+		{NewZeroDivisionError(), standardErrorClass, TRUE, nil},
+		{&StandardError{}, zeroDivisionErrorClass, FALSE, nil},
+		{NewInteger(3), NewInteger(3), nil, NewTypeError("class or module required")},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.receiver}
+
+		result, err := kernelIsA(context, testCase.argument)
+
+		checkError(t, err, testCase.err)
+
+		if result != testCase.result {
+			t.Logf("Expected %s.is_a?(%s) to be %v, got %v\n", testCase.receiver.Inspect(), testCase.argument.Inspect(), testCase.result, result)
+			t.Fail()
+		}
+	}
+}
+
+func TestKernelInstanceOf(t *testing.T) {
+	tests := []struct {
+		receiver RubyObject
+		argument RubyObject
+		result   RubyObject
+	}{
+		{NewInteger(3), integerClass, TRUE},
+		{NewInteger(3), objectClass, FALSE},
+		{NewZeroDivisionError(), zeroDivisionErrorClass, TRUE},
+		{NewZeroDivisionError(), standardErrorClass, FALSE},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.receiver}
+
+		result, err := kernelInstanceOf(context, testCase.argument)
+
+		checkError(t, err, nil)
+
+		if result != testCase.result {
+			t.Logf("Expected %s.instance_of?(%s) to be %s, got %s\n", testCase.receiver.Inspect(), testCase.argument.Inspect(), testCase.result.Inspect(), result.Inspect())
+			t.Fail()
+		}
+	}
+}
+
 func TestKernelRequire(t *testing.T) {
 	t.Run("wiring together", func(t *testing.T) {
 		evalCallCount := 0
@@ -328,7 +386,7 @@ func TestKernelRequire(t *testing.T) {
 			eval:     eval,
 			receiver: &Object{},
 		}
-		name := &String{"./fixtures/testfile.rb"}
+		name := &String{Value: "./fixtures/testfile.rb"}
 
 		result, err := kernelRequire(context, name)
 
@@ -371,7 +429,7 @@ func TestKernelRequire(t *testing.T) {
 			eval:     eval,
 			receiver: &Object{},
 		}
-		name := &String{"./fixtures/testfile.rb"}
+		name := &String{Value: "./fixtures/testfile.rb"}
 
 		_, err := kernelRequire(context, name)
 		if err != nil {
@@ -393,7 +451,7 @@ func TestKernelRequire(t *testing.T) {
 		}
 
 		abs, _ := filepath.Abs("./fixtures/testfile.rb")
-		expected := NewArray(&String{abs})
+		expected := NewArray(&String{Value: abs})
 
 		if !reflect.DeepEqual(expected, arr) {
 			t.Logf("Expected $LOADED_FEATURES to equal\n%#v\n\tgot\n%#v\n", expected.Inspect(), arr.Inspect())
@@ -411,7 +469,7 @@ func TestKernelRequire(t *testing.T) {
 			eval:     eval,
 			receiver: &Object{},
 		}
-		name := &String{"./fixtures/testfile"}
+		name := &String{Value: "./fixtures/testfile"}
 
 		_, err := kernelRequire(context, name)
 		if err != nil {
@@ -433,7 +491,7 @@ func TestKernelRequire(t *testing.T) {
 		}
 
 		abs, _ := filepath.Abs("./fixtures/testfile.rb")
-		expected := NewArray(&String{abs})
+		expected := NewArray(&String{Value: abs})
 
 		if !reflect.DeepEqual(expected, arr) {
 			t.Logf("Expected $LOADED_FEATURES to equal\n%#v\n\tgot\n%#v\n", expected.Inspect(), arr.Inspect())
@@ -442,7 +500,7 @@ func TestKernelRequire(t *testing.T) {
 	})
 	t.Run("env side effects $LOADED_FEATURES exist", func(t *testing.T) {
 		env := NewEnvironment()
-		env.SetGlobal("$LOADED_FEATURES", NewArray(&String{"foo"}))
+		env.SetGlobal("$LOADED_FEATURES", NewArray(&String{Value: "foo"}))
 		eval := func(node ast.Node, env Environment) (RubyObject, error) {
 			return TRUE, nil
 		}
@@ -452,7 +510,7 @@ func TestKernelRequire(t *testing.T) {
 			eval:     eval,
 			receiver: &Object{},
 		}
-		name := &String{"./fixtures/testfile"}
+		name := &String{Value: "./fixtures/testfile"}
 
 		_, err := kernelRequire(context, name)
 		if err != nil {
@@ -474,7 +532,7 @@ func TestKernelRequire(t *testing.T) {
 		}
 
 		abs, _ := filepath.Abs("./fixtures/testfile.rb")
-		expected := NewArray(&String{"foo"}, &String{abs})
+		expected := NewArray(&String{Value: "foo"}, &String{Value: abs})
 
 		if !reflect.DeepEqual(expected, arr) {
 			t.Logf("Expected $LOADED_FEATURES to equal\n%#v\n\tgot\n%#v\n", expected.Inspect(), arr.Inspect())
@@ -550,7 +608,7 @@ func TestKernelRequire(t *testing.T) {
 			eval:     eval,
 			receiver: &Object{},
 		}
-		name := &String{"./fixtures/testfile_constants.rb"}
+		name := &String{Value: "./fixtures/testfile_constants.rb"}
 
 		_, err := kernelRequire(context, name)
 		if err != nil {
@@ -615,7 +673,7 @@ func TestKernelRequire(t *testing.T) {
 			eval:     eval,
 			receiver: &Object{},
 		}
-		name := &String{"./fixtures/testfile"}
+		name := &String{Value: "./fixtures/testfile"}
 
 		_, err := kernelRequire(context, name)
 		if err != nil {
@@ -641,7 +699,7 @@ func TestKernelRequire(t *testing.T) {
 			eval:     eval,
 			receiver: &Object{},
 		}
-		name := &String{"file/not/exist"}
+		name := &String{Value: "file/not/exist"}
 
 		_, err := kernelRequire(context, name)
 		if err == nil {
@@ -687,7 +745,7 @@ func TestKernelRequire(t *testing.T) {
 			eval:     eval,
 			receiver: &Object{},
 		}
-		name := &String{"./fixtures/testfile_syntax_error.rb"}
+		name := &String{Value: "./fixtures/testfile_syntax_error.rb"}
 
 		_, err := kernelRequire(context, name)
 		if err == nil {
@@ -738,7 +796,7 @@ func TestKernelRequire(t *testing.T) {
 			eval:     eval,
 			receiver: &Object{},
 		}
-		name := &String{"./fixtures/testfile_name_error.rb"}
+		name := &String{Value: "./fixtures/testfile_name_error.rb"}
 
 		_, err := kernelRequire(context, name)
 		if err == nil {
@@ -776,7 +834,7 @@ func TestKernelRequire(t *testing.T) {
 	t.Run("already loaded", func(t *testing.T) {
 		abs, _ := filepath.Abs("./fixtures/testfile.rb")
 		env := NewEnvironment()
-		env.SetGlobal("$LOADED_FEATURES", NewArray(&String{abs}))
+		env.SetGlobal("$LOADED_FEATURES", NewArray(&String{Value: abs}))
 		eval := func(node ast.Node, env Environment) (RubyObject, error) {
 			return TRUE, nil
 		}
@@ -786,7 +844,7 @@ func TestKernelRequire(t *testing.T) {
 			eval:     eval,
 			receiver: &Object{},
 		}
-		name := &String{"./fixtures/testfile.rb"}
+		name := &String{Value: "./fixtures/testfile.rb"}
 
 		result, err := kernelRequire(context, name)
 		if err != nil {
@@ -819,7 +877,7 @@ func TestKernelRequire(t *testing.T) {
 			t.FailNow()
 		}
 
-		expected := NewArray(&String{abs})
+		expected := NewArray(&String{Value: abs})
 
 		if !reflect.DeepEqual(expected, arr) {
 			t.Logf("Expected $LOADED_FEATURES to equal\n%#v\n\tgot\n%#v\n", expected.Inspect(), arr.Inspect())
@@ -1036,6 +1094,149 @@ func TestKernelToS(t *testing.T) {
 	})
 }
 
+func TestKernelIsEqual(t *testing.T) {
+	str := &String{Value: "foo"}
+	tests := []struct {
+		receiver RubyObject
+		argument RubyObject
+		result   RubyObject
+	}{
+		{str, str, TRUE},
+		{str, &String{Value: "foo"}, FALSE},
+		{&Self{RubyObject: str, Name: "foo"}, str, TRUE},
+		{NewSymbol("foo"), NewSymbol("foo"), TRUE},
+		{NewInteger(2), NewInteger(2), TRUE},
+		{NewInteger(2), NewInteger(3), FALSE},
+		{NIL, NIL, TRUE},
+		{NIL, FALSE, FALSE},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.receiver}
+
+		result, err := kernelIsEqual(context, testCase.argument)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestKernelObjectID(t *testing.T) {
+	t.Run("integer", func(t *testing.T) {
+		context := &callContext{receiver: NewInteger(3)}
+
+		result, err := kernelObjectID(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, NewInteger(7))
+	})
+	t.Run("symbol", func(t *testing.T) {
+		first, err := kernelObjectID(&callContext{receiver: NewSymbol("foo")})
+		checkError(t, err, nil)
+		second, err := kernelObjectID(&callContext{receiver: NewSymbol("foo")})
+		checkError(t, err, nil)
+
+		checkResult(t, first, second)
+	})
+}
+
+func TestKernelInspect(t *testing.T) {
+	self := &Self{RubyObject: &String{Value: "foo"}, Name: "main"}
+	context := &callContext{receiver: self}
+
+	result, err := kernelInspect(context)
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, &String{Value: `"foo"`})
+}
+
+func TestKernelPuts(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		output    string
+	}{
+		{nil, "\n"},
+		{[]RubyObject{&String{Value: "foo"}}, "foo\n"},
+		{[]RubyObject{&String{Value: "foo\n"}}, "foo\n"},
+		{[]RubyObject{&String{Value: "foo"}, NewInteger(3)}, "foo\n3\n"},
+		{[]RubyObject{NIL}, "\n"},
+		{[]RubyObject{NewArray(&String{Value: "a"}, NewArray(NewInteger(1)))}, "a\n1\n"},
+		{[]RubyObject{NewArray()}, "\n"},
+	}
+
+	for _, testCase := range tests {
+		var out bytes.Buffer
+		stdout = &out
+		context := &callContext{receiver: &Object{}, env: NewMainEnvironment()}
+
+		result, err := kernelPuts(context, testCase.arguments...)
+
+		stdout = os.Stdout
+		checkError(t, err, nil)
+		checkResult(t, result, NIL)
+		if out.String() != testCase.output {
+			t.Logf("Expected output to equal %q, got %q\n", testCase.output, out.String())
+			t.Fail()
+		}
+	}
+}
+
+func TestKernelPrint(t *testing.T) {
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	context := &callContext{receiver: &Object{}, env: NewMainEnvironment()}
+
+	result, err := kernelPrint(context, &String{Value: "foo"}, NewInteger(3), NIL, &Symbol{Value: "bar"})
+
+	checkError(t, err, nil)
+	checkResult(t, result, NIL)
+	if out.String() != "foo3bar" {
+		t.Logf("Expected output to equal %q, got %q\n", "foo3bar", out.String())
+		t.Fail()
+	}
+}
+
+func TestKernelP(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+		output    string
+	}{
+		{nil, NIL, ""},
+		{[]RubyObject{&String{Value: "foo"}}, &String{Value: "foo"}, "\"foo\"\n"},
+		{
+			[]RubyObject{NIL, &Symbol{Value: "bar"}},
+			NewArray(NIL, &Symbol{Value: "bar"}),
+			"nil\n:bar\n",
+		},
+		{
+			[]RubyObject{NewArray(&String{Value: "a"}, NewInteger(1))},
+			NewArray(&String{Value: "a"}, NewInteger(1)),
+			"[\"a\", 1]\n",
+		},
+	}
+
+	for _, testCase := range tests {
+		var out bytes.Buffer
+		stdout = &out
+		context := &callContext{receiver: &Object{}, env: NewMainEnvironment()}
+
+		result, err := kernelP(context, testCase.arguments...)
+
+		stdout = os.Stdout
+		checkError(t, err, nil)
+		checkResult(t, result, testCase.result)
+		if out.String() != testCase.output {
+			t.Logf("Expected output to equal %q, got %q\n", testCase.output, out.String())
+			t.Fail()
+		}
+	}
+}
+
 func TestKernelRaise(t *testing.T) {
 	object := &Self{RubyObject: &Object{}, Name: "x"}
 	env := NewMainEnvironment()
@@ -1095,4 +1296,40 @@ func TestKernelRaise(t *testing.T) {
 			})
 		})
 	})
+
+	t.Run("with 2 args", func(t *testing.T) {
+		result, err := kernelRaise(context, argumentErrorClass, &String{Value: "bad"})
+
+		checkResult(t, result, nil)
+
+		checkError(t, err, &ArgumentError{message: "bad"})
+	})
+
+	t.Run("within rescue", func(t *testing.T) {
+		current := &StandardError{message: "current"}
+		env := NewEnclosedEnvironment(NewMainEnvironment())
+		env.SetGlobal("$!", current)
+		context := &callContext{
+			receiver: object,
+			env:      env,
+		}
+
+		t.Run("without args", func(t *testing.T) {
+			result, err := kernelRaise(context)
+
+			checkResult(t, result, nil)
+
+			if err != current {
+				t.Logf("Expected current exception to be reraised, got %v\n", err)
+				t.Fail()
+			}
+		})
+		t.Run("with args", func(t *testing.T) {
+			result, err := kernelRaise(context, &String{Value: "ouch"})
+
+			checkResult(t, result, nil)
+
+			checkError(t, err, &RuntimeError{message: "ouch", exceptionState: exceptionState{cause: current}})
+		})
+	})
 }
@@ -0,0 +1,163 @@
+package object
+
+import (
+	"fmt"
+)
+
+var lazyClass RubyClassObject = newMixin(newClass(
+	"Enumerator::Lazy",
+	objectClass,
+	lazyMethods,
+	lazyClassMethods,
+	notInstantiatable,
+), enumerableModule)
+
+// Lazy represents an Enumerator::Lazy. It applies its operations to the
+// values of Source only when they are requested by one of the Enumerable
+// methods, so that it can work on sources which never stop yielding, like
+// endless ranges.
+type Lazy struct {
+	Source     RubyObject
+	operations []lazyOperation
+}
+
+// lazyOperation is a step of the pipeline of a Lazy. Its block is called
+// for every value passing the step, except for take which passes count
+// values.
+type lazyOperation struct {
+	name  string
+	block *Proc
+	count int
+}
+
+// Inspect returns the source and the operations in the format of MRI,
+// e.g. `#<Enumerator::Lazy: #<Enumerator::Lazy: 1..>:map>`
+func (l *Lazy) Inspect() string {
+	out := fmt.Sprintf("#<Enumerator::Lazy: %s>", l.Source.Inspect())
+	for _, op := range l.operations {
+		if op.name == "take" {
+			out = fmt.Sprintf("#<Enumerator::Lazy: %s:take(%d)>", out, op.count)
+			continue
+		}
+		out = fmt.Sprintf("#<Enumerator::Lazy: %s:%s>", out, op.name)
+	}
+	return out
+}
+
+// Type returns LAZY_OBJ
+func (l *Lazy) Type() Type { return LAZY_OBJ }
+
+// Class returns lazyClass
+func (l *Lazy) Class() RubyClass { return lazyClass }
+
+// with returns a copy of l with op appended to its operations
+func (l *Lazy) with(op lazyOperation) *Lazy {
+	operations := append(append([]lazyOperation{}, l.operations...), op)
+	return &Lazy{Source: l.Source, operations: operations}
+}
+
+var lazyClassMethods = map[string]RubyMethod{}
+
+var lazyMethods = map[string]RubyMethod{
+	"each":       publicMethod(lazyEach),
+	"map":        publicMethod(lazyBlockOperation("map")),
+	"collect":    publicMethod(lazyBlockOperation("map")),
+	"select":     publicMethod(lazyBlockOperation("select")),
+	"filter":     publicMethod(lazyBlockOperation("select")),
+	"reject":     publicMethod(lazyBlockOperation("reject")),
+	"take_while": publicMethod(lazyBlockOperation("take_while")),
+	"drop_while": publicMethod(lazyBlockOperation("drop_while")),
+	"take":       withArity(1, publicMethod(lazyTake)),
+	"force":      withArity(0, publicMethod(enumerableToA)),
+	"lazy":       withArity(0, publicMethod(lazyLazy)),
+}
+
+// lazyEach hands the values of the source which pass all operations to the
+// block. It stops iterating the source once a take or take_while is done.
+func lazyEach(context CallContext, args ...RubyObject) (RubyObject, error) {
+	lazy := context.Receiver().(*Lazy)
+	block, err := arrayIteration(args)
+	if err != nil {
+		return nil, err
+	}
+	taken := make([]int, len(lazy.operations))
+	dropped := make([]bool, len(lazy.operations))
+	source := &callContext{receiver: lazy.Source, env: context.Env(), eval: context.Eval}
+	err = enumerate(source, func(value RubyObject) (bool, error) {
+		more := true
+		for i, op := range lazy.operations {
+			if op.name == "take" {
+				if taken[i] >= op.count {
+					return false, nil
+				}
+				taken[i]++
+				more = more && taken[i] < op.count
+				continue
+			}
+			if op.name == "drop_while" && dropped[i] {
+				continue
+			}
+			result, err := op.block.Call(context, value)
+			if err != nil {
+				return false, err
+			}
+			truthy := result != NIL && result != FALSE
+			switch op.name {
+			case "map":
+				value = result
+			case "select":
+				if !truthy {
+					return more, nil
+				}
+			case "reject":
+				if truthy {
+					return more, nil
+				}
+			case "take_while":
+				if !truthy {
+					return false, nil
+				}
+			case "drop_while":
+				if truthy {
+					return more, nil
+				}
+				dropped[i] = true
+			}
+		}
+		_, err := block.Call(context, value)
+		return more && err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lazy, nil
+}
+
+// lazyBlockOperation returns a method which adds the operation name with
+// the given block to the receiver
+func lazyBlockOperation(name string) func(CallContext, ...RubyObject) (RubyObject, error) {
+	return func(context CallContext, args ...RubyObject) (RubyObject, error) {
+		block, err := arrayIteration(args)
+		if err != nil {
+			return nil, err
+		}
+		lazy := context.Receiver().(*Lazy)
+		return lazy.with(lazyOperation{name: name, block: block}), nil
+	}
+}
+
+func lazyTake(context CallContext, args ...RubyObject) (RubyObject, error) {
+	count, err := enumerableSize(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if count < 0 {
+		return nil, NewArgumentError("attempt to take negative size")
+	}
+	lazy := context.Receiver().(*Lazy)
+	return lazy.with(lazyOperation{name: "take", count: count}), nil
+}
+
+func lazyLazy(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver(), nil
+}
@@ -0,0 +1,106 @@
+package object
+
+import (
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	var evaluated []RubyObject
+	double := newNativeProc(func(args ...RubyObject) (RubyObject, error) {
+		evaluated = append(evaluated, args[0])
+		return NewInteger(args[0].(*Integer).Value * 2), nil
+	})
+	multipleOfThree := newNativeProc(func(args ...RubyObject) (RubyObject, error) {
+		return nativeBoolToBooleanObject(args[0].(*Integer).Value%3 == 0), nil
+	})
+	lessThanFive := newNativeProc(func(args ...RubyObject) (RubyObject, error) {
+		return nativeBoolToBooleanObject(args[0].(*Integer).Value < 5), nil
+	})
+	endless := &Lazy{Source: &Range{Begin: NewInteger(1), End: NIL}}
+
+	tests := []struct {
+		name      string
+		lazy      *Lazy
+		evaluated int
+		result    RubyObject
+	}{
+		{
+			"map and select",
+			endless.with(lazyOperation{name: "map", block: double}).
+				with(lazyOperation{name: "select", block: multipleOfThree}).
+				with(lazyOperation{name: "take", count: 2}),
+			6,
+			NewArray(NewInteger(6), NewInteger(12)),
+		},
+		{
+			"take",
+			endless.with(lazyOperation{name: "map", block: double}).
+				with(lazyOperation{name: "take", count: 3}),
+			3,
+			NewArray(NewInteger(2), NewInteger(4), NewInteger(6)),
+		},
+		{
+			"reject and take_while",
+			endless.with(lazyOperation{name: "reject", block: multipleOfThree}).
+				with(lazyOperation{name: "take_while", block: lessThanFive}),
+			0,
+			NewArray(NewInteger(1), NewInteger(2), NewInteger(4)),
+		},
+		{
+			"drop_while",
+			endless.with(lazyOperation{name: "drop_while", block: lessThanFive}).
+				with(lazyOperation{name: "take", count: 2}),
+			0,
+			NewArray(NewInteger(5), NewInteger(6)),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			evaluated = nil
+			context := &callContext{receiver: testCase.lazy, env: NewEnvironment()}
+
+			result, err := enumerableToA(context)
+
+			checkError(t, err, nil)
+			checkResult(t, result, testCase.result)
+			if len(evaluated) != testCase.evaluated {
+				t.Logf("Expected %d values to be evaluated, got %d\n", testCase.evaluated, len(evaluated))
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("operations do not change the receiver", func(t *testing.T) {
+		context := &callContext{receiver: endless, env: NewEnvironment()}
+
+		mapped, err := lazyBlockOperation("map")(context, double)
+
+		checkError(t, err, nil)
+		if len(endless.operations) != 0 {
+			t.Logf("Expected receiver to have no operations, got %d\n", len(endless.operations))
+			t.Fail()
+		}
+		if len(mapped.(*Lazy).operations) != 1 {
+			t.Logf("Expected result to have 1 operation, got %d\n", len(mapped.(*Lazy).operations))
+			t.Fail()
+		}
+	})
+	t.Run("take with negative size", func(t *testing.T) {
+		context := &callContext{receiver: endless, env: NewEnvironment()}
+
+		_, err := lazyTake(context, NewInteger(-1))
+
+		checkError(t, err, NewArgumentError("attempt to take negative size"))
+	})
+	t.Run("Inspect", func(t *testing.T) {
+		lazy := endless.with(lazyOperation{name: "map", block: double}).
+			with(lazyOperation{name: "take", count: 3})
+
+		expected := "#<Enumerator::Lazy: #<Enumerator::Lazy: #<Enumerator::Lazy: 1..>:map>:take(3)>"
+		if lazy.Inspect() != expected {
+			t.Logf("Expected Inspect to return %s, got %s\n", expected, lazy.Inspect())
+			t.Fail()
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package object
+
+import (
+	"fmt"
+
+	"github.com/goruby/goruby/ast"
+)
+
+var methodClass RubyClassObject = newClass(
+	"Method",
+	objectClass,
+	methodMethods,
+	methodClassMethods,
+	notInstantiatable,
+)
+
+func init() {
+	classes.Set("Method", methodClass)
+}
+
+// A Method represents a method bound to its receiver, as returned by
+// Kernel#method
+type Method struct {
+	Receiver RubyObject
+	Name     string
+	Fn       RubyMethod
+}
+
+// Inspect returns the class and the name of the method
+func (m *Method) Inspect() string {
+	return fmt.Sprintf("#<Method: %s#%s>", m.Receiver.Class().(RubyObject).Inspect(), m.Name)
+}
+
+// Type returns METHOD_OBJ
+func (m *Method) Type() Type { return METHOD_OBJ }
+
+// Class returns methodClass
+func (m *Method) Class() RubyClass { return methodClass }
+
+// signature returns the signature of user defined methods. The boolean is
+// false for builtin methods, which accept any arguments.
+func (m *Method) signature() (signature, bool) {
+	fn, ok := m.Fn.(*Function)
+	if !ok {
+		return signature{}, false
+	}
+	return functionParameters(fn.Parameters).signature(), true
+}
+
+var methodClassMethods = map[string]RubyMethod{}
+
+var methodMethods = map[string]RubyMethod{
+	"call":       publicMethod(methodCall),
+	"arity":      withArity(0, publicMethod(methodArity)),
+	"parameters": withArity(0, publicMethod(methodParameters)),
+	"name":       withArity(0, publicMethod(methodName)),
+	"receiver":   withArity(0, publicMethod(methodReceiver)),
+	"inspect":    withArity(0, publicMethod(methodInspect)),
+	"to_s":       withArity(0, publicMethod(methodInspect)),
+}
+
+func methodCall(context CallContext, args ...RubyObject) (RubyObject, error) {
+	method := context.Receiver().(*Method)
+	return method.Fn.Call(&callContext{receiver: method.Receiver, env: context.Env(), eval: context.Eval}, args...)
+}
+
+func methodArity(context CallContext, args ...RubyObject) (RubyObject, error) {
+	sig, ok := context.Receiver().(*Method).signature()
+	if !ok {
+		return NewInteger(-1), nil
+	}
+	return NewInteger(int64(sig.arity())), nil
+}
+
+// methodParameters returns the parameters as pairs of their kind and name,
+// like [[:req, :a], [:opt, :b], [:rest, :c], [:keyreq, :d], [:key, :e],
+// [:keyrest, :f], [:block, :g]]
+func methodParameters(context CallContext, args ...RubyObject) (RubyObject, error) {
+	method := context.Receiver().(*Method)
+	fn, ok := method.Fn.(*Function)
+	if !ok {
+		return NewArray(NewArray(NewSymbol("rest"))), nil
+	}
+	parameters := NewArray()
+	for _, param := range fn.Parameters {
+		var kind string
+		switch param.Kind {
+		case ast.RestParameter:
+			kind = "rest"
+		case ast.KeywordParameter:
+			kind = "key"
+			if param.Default == nil {
+				kind = "keyreq"
+			}
+		case ast.KeywordRestParameter:
+			kind = "keyrest"
+		case ast.BlockParameter:
+			kind = "block"
+		default:
+			kind = "req"
+			if param.Default != nil {
+				kind = "opt"
+			}
+		}
+		pair := NewArray(NewSymbol(kind))
+		if param.Name != "" {
+			pair.Elements = append(pair.Elements, NewSymbol(param.Name))
+		}
+		parameters.Elements = append(parameters.Elements, pair)
+	}
+	return parameters, nil
+}
+
+func methodName(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return NewSymbol(context.Receiver().(*Method).Name), nil
+}
+
+func methodReceiver(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver().(*Method).Receiver, nil
+}
+
+func methodInspect(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return &String{Value: context.Receiver().Inspect()}, nil
+}
@@ -0,0 +1,105 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/goruby/goruby/ast"
+)
+
+func TestMethodParameters(t *testing.T) {
+	tests := []struct {
+		fn       RubyMethod
+		expected RubyObject
+	}{
+		{
+			&Function{Parameters: []*FunctionParameter{
+				{Name: "a"},
+				{Name: "b", Default: &ast.Nil{}},
+				{Name: "c", Kind: ast.RestParameter},
+				{Name: "d", Kind: ast.KeywordParameter},
+				{Name: "e", Default: &ast.Nil{}, Kind: ast.KeywordParameter},
+				{Name: "f", Kind: ast.KeywordRestParameter},
+				{Name: "g", Kind: ast.BlockParameter},
+			}},
+			NewArray(
+				NewArray(NewSymbol("req"), NewSymbol("a")),
+				NewArray(NewSymbol("opt"), NewSymbol("b")),
+				NewArray(NewSymbol("rest"), NewSymbol("c")),
+				NewArray(NewSymbol("keyreq"), NewSymbol("d")),
+				NewArray(NewSymbol("key"), NewSymbol("e")),
+				NewArray(NewSymbol("keyrest"), NewSymbol("f")),
+				NewArray(NewSymbol("block"), NewSymbol("g")),
+			),
+		},
+		{
+			&Function{Parameters: []*FunctionParameter{{Kind: ast.RestParameter}}},
+			NewArray(NewArray(NewSymbol("rest"))),
+		},
+		{
+			publicMethod(nil),
+			NewArray(NewArray(NewSymbol("rest"))),
+		},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: &Method{Receiver: NIL, Name: "foo", Fn: testCase.fn}}
+
+		result, err := methodParameters(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.expected)
+	}
+}
+
+func TestMethodArity(t *testing.T) {
+	tests := []struct {
+		parameters []*FunctionParameter
+		expected   RubyObject
+	}{
+		{[]*FunctionParameter{}, NewInteger(0)},
+		{[]*FunctionParameter{{Name: "a"}, {Name: "b"}}, NewInteger(2)},
+		{[]*FunctionParameter{{Name: "a"}, {Name: "b", Default: &ast.Nil{}}}, NewInteger(-2)},
+		{[]*FunctionParameter{{Name: "a", Kind: ast.RestParameter}}, NewInteger(-1)},
+		{[]*FunctionParameter{{Name: "a"}, {Name: "b", Kind: ast.KeywordParameter}}, NewInteger(2)},
+		{[]*FunctionParameter{{Name: "a"}, {Name: "b", Default: &ast.Nil{}, Kind: ast.KeywordParameter}}, NewInteger(-2)},
+		{[]*FunctionParameter{{Name: "a", Kind: ast.KeywordRestParameter}}, NewInteger(-1)},
+		{[]*FunctionParameter{{Name: "a", Kind: ast.BlockParameter}}, NewInteger(0)},
+	}
+
+	for _, testCase := range tests {
+		fn := &Function{Parameters: testCase.parameters}
+		context := &callContext{receiver: &Method{Receiver: NIL, Name: "foo", Fn: fn}}
+
+		result, err := methodArity(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.expected)
+	}
+}
+
+func TestKernelMethod(t *testing.T) {
+	t.Run("existing method", func(t *testing.T) {
+		context := &callContext{receiver: NewInteger(2)}
+
+		result, err := kernelMethod(context, NewSymbol("+"))
+
+		checkError(t, err, nil)
+
+		method, ok := result.(*Method)
+		if !ok {
+			t.Fatalf("Expected Method, got %T", result)
+		}
+		if method.Name != "+" || method.Receiver != context.receiver {
+			t.Errorf("Expected method + bound to 2, got %s bound to %s", method.Name, method.Receiver.Inspect())
+		}
+	})
+	t.Run("missing method", func(t *testing.T) {
+		context := &callContext{receiver: NewInteger(2)}
+
+		_, err := kernelMethod(context, NewSymbol("foo"))
+
+		checkError(t, err, NewUndefinedMethodNameError(integerClass, "foo"))
+	})
+}
@@ -22,3 +22,32 @@ func (m *mixin) Methods() MethodSet {
 	}
 	return NewMethodSet(methods)
 }
+
+// newIncludedModule returns a class which places the methods of module
+// between a class and its superClass, as done by including the module into
+// the class.
+func newIncludedModule(module *Module, superClass RubyClass) *includedModule {
+	return &includedModule{module, superClass}
+}
+
+type includedModule struct {
+	*Module
+	superClass RubyClass
+}
+
+func (i *includedModule) Methods() MethodSet    { return i.Module.Class().Methods() }
+func (i *includedModule) SuperClass() RubyClass { return i.superClass }
+func (i *includedModule) Name() string          { return i.Module.name }
+func (i *includedModule) New(args ...RubyObject) (RubyObject, error) {
+	return nil, NewNoMethodError(i.Module, "new")
+}
+
+// includes reports whether module is already included into class
+func includes(class RubyClass, module *Module) bool {
+	for ; class != nil; class = class.SuperClass() {
+		if included, ok := class.(*includedModule); ok && included.Module == module {
+			return true
+		}
+	}
+	return false
+}
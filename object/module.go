@@ -79,6 +79,7 @@ var moduleMethods = map[string]RubyMethod{
 	"append_features":            withArity(1, privateMethod(moduleAppendFeatures)),
 	"to_s":                       withArity(0, publicMethod(moduleToS)),
 	"inspect":                    withArity(0, publicMethod(moduleToS)),
+	"===":                        withArity(1, publicMethod(moduleCaseEqual)),
 }
 
 func moduleToS(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -93,15 +94,53 @@ func moduleToS(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return &String{Value: val}, nil
 }
 
+func moduleCaseEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	receiver := context.Receiver()
+	if self, ok := receiver.(*Self); ok {
+		receiver = self.RubyObject
+	}
+	if isA(args[0], receiver) {
+		return TRUE, nil
+	}
+	return FALSE, nil
+}
+
+// isA reports whether obj is an instance of module, i.e. module is the class
+// of obj, one of its superclasses or one of the modules they include
+func isA(obj RubyObject, module RubyObject) bool {
+	if self, ok := obj.(*Self); ok {
+		obj = self.RubyObject
+	}
+	for class := obj.Class(); class != nil; class = class.SuperClass() {
+		if mixin, ok := class.(*mixin); ok {
+			for _, m := range mixin.modules {
+				if RubyObject(m) == module {
+					return true
+				}
+			}
+		}
+		if included, ok := class.(*includedModule); ok && RubyObject(included.Module) == module {
+			return true
+		}
+		if c, ok := class.(RubyObject); ok && c == module {
+			return true
+		}
+	}
+	return false
+}
+
 func moduleAncestors(context CallContext, args ...RubyObject) (RubyObject, error) {
 	class := context.Receiver().(RubyClassObject)
 	var ancestors []RubyObject
-	ancestors = append(ancestors, &String{class.Inspect()})
-
 	if mixin, ok := class.(*mixin); ok {
+		ancestors = append(ancestors, mixin.RubyClassObject)
 		for _, m := range mixin.modules {
-			ancestors = append(ancestors, &String{m.name})
+			ancestors = append(ancestors, m)
 		}
+	} else if included, ok := class.(*includedModule); ok {
+		ancestors = append(ancestors, included.Module)
+	} else {
+		ancestors = append(ancestors, class)
 	}
 	superClass := class.SuperClass()
 	if superClass != nil {
@@ -121,9 +160,12 @@ func moduleIncludedModules(context CallContext, args ...RubyObject) (RubyObject,
 
 	if mixin, ok := class.(*mixin); ok {
 		for _, m := range mixin.modules {
-			includedModules = append(includedModules, &String{m.name})
+			includedModules = append(includedModules, m)
 		}
 	}
+	if included, ok := class.(*includedModule); ok {
+		includedModules = append(includedModules, included.Module)
+	}
 
 	superClass := class.SuperClass()
 	if superClass != nil {
@@ -231,5 +273,8 @@ func moduleAppendFeatures(context CallContext, args ...RubyObject) (RubyObject,
 	for k, v := range self.RubyObject.Class().Methods().GetAll() {
 		module.addMethod(k, v)
 	}
+	if class, ok := self.RubyObject.(*class); ok && !includes(class, module) {
+		class.superClass = newIncludedModule(module, class.superClass)
+	}
 	return module, nil
 }
@@ -629,6 +629,36 @@ func TestModuleAppendFeatures(t *testing.T) {
 			t.Fail()
 		}
 	})
+	t.Run("includes module into class", func(t *testing.T) {
+		receiver := NewClass("X", objectClass, nil)
+		context := &callContext{
+			receiver: &Self{RubyObject: receiver, Name: "X"},
+		}
+		module := newModule("foo", map[string]RubyMethod{"bar": nil}, nil)
+
+		moduleAppendFeatures(context, module)
+		moduleAppendFeatures(context, module)
+
+		included, ok := receiver.SuperClass().(*includedModule)
+		if !ok {
+			t.Fatalf("Expected superclass to be the included module, got %T", receiver.SuperClass())
+		}
+		if included.Module != module || included.SuperClass() != objectClass {
+			t.Logf("Expected module to be included once between class and superclass")
+			t.Fail()
+		}
+		if _, ok := included.Methods().Get("bar"); !ok {
+			t.Logf("Expected method bar to be within included module")
+			t.Fail()
+		}
+		instance, _ := receiver.New()
+		if !isA(instance, module) {
+			t.Logf("Expected instance to be a foo")
+			t.Fail()
+		}
+		superclass, _ := classSuperclass(&callContext{receiver: receiver})
+		checkResult(t, superclass, objectClass.RubyClassObject)
+	})
 }
 
 func TestModuleInclude(t *testing.T) {
@@ -703,3 +733,34 @@ func TestModuleInclude(t *testing.T) {
 		}
 	})
 }
+
+func TestModuleCaseEqual(t *testing.T) {
+	tests := []struct {
+		receiver RubyObject
+		argument RubyObject
+		result   RubyObject
+	}{
+		{integerClass, NewInteger(3), TRUE},
+		{objectClass, NewInteger(3), TRUE},
+		{basicObjectClass, NIL, TRUE},
+		{kernelModule, &String{Value: "foo"}, TRUE},
+		{stringClass, NewInteger(3), FALSE},
+		{integerClass, integerClass, FALSE},
+		{classClass, integerClass, TRUE},
+		{standardErrorClass, NewZeroDivisionError(), TRUE},
+		{zeroDivisionErrorClass, &StandardError{}, FALSE},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.receiver}
+
+		result, err := moduleCaseEqual(context, testCase.argument)
+
+		checkError(t, err, nil)
+
+		if result != testCase.result {
+			t.Logf("Expected %s === %s to be %s, got %s\n", testCase.receiver.Inspect(), testCase.argument.Inspect(), testCase.result.Inspect(), result.Inspect())
+			t.Fail()
+		}
+	}
+}
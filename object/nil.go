@@ -22,8 +22,18 @@ var nilClassMethods = map[string]RubyMethod{}
 
 var nilMethods = map[string]RubyMethod{
 	"nil?": withArity(0, publicMethod(nilIsNil)),
+	"to_s": withArity(0, publicMethod(nilToS)),
+	"=~":   withArity(1, publicMethod(nilMatch)),
 }
 
 func nilIsNil(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return TRUE, nil
 }
+
+func nilToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return &String{Value: ""}, nil
+}
+
+func nilMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return NIL, nil
+}
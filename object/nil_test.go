@@ -20,3 +20,13 @@ func TestNilIsNil(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestNilToS(t *testing.T) {
+	context := &callContext{receiver: NIL}
+
+	result, err := nilToS(context)
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, &String{Value: ""})
+}
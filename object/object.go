@@ -30,4 +30,10 @@ func (o *Object) Class() RubyClass { return objectClass }
 
 var objectClassMethods = map[string]RubyMethod{}
 
-var objectMethods = map[string]RubyMethod{}
+var objectMethods = map[string]RubyMethod{
+	"===": withArity(1, publicMethod(objectCaseEqual)),
+}
+
+func objectCaseEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return Send(context, "==", args[0])
+}
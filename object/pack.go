@@ -0,0 +1,351 @@
+package object
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// integerDirectives maps the integer directives of pack templates to their
+// size in bytes and their byte order. A byte order of nil is the native one,
+// which can be changed with the `<` and `>` modifiers.
+var integerDirectives = map[byte]struct {
+	size  int
+	order binary.ByteOrder
+}{
+	'C': {1, nil}, 'c': {1, nil},
+	'S': {2, nil}, 's': {2, nil},
+	'L': {4, nil}, 'l': {4, nil},
+	'I': {4, nil}, 'i': {4, nil},
+	'Q': {8, nil}, 'q': {8, nil},
+	'J': {8, nil}, 'j': {8, nil},
+	'n': {2, binary.BigEndian}, 'N': {4, binary.BigEndian},
+	'v': {2, binary.LittleEndian}, 'V': {4, binary.LittleEndian},
+}
+
+// floatDirectives maps the float directives of pack templates to their size
+// in bytes and their byte order
+var floatDirectives = map[byte]struct {
+	size  int
+	order binary.ByteOrder
+}{
+	'D': {8, nil}, 'd': {8, nil},
+	'F': {4, nil}, 'f': {4, nil},
+	'E': {8, binary.LittleEndian}, 'e': {4, binary.LittleEndian},
+	'G': {8, binary.BigEndian}, 'g': {4, binary.BigEndian},
+}
+
+// pack packs args into a binary string according to template, as done by
+// Array#pack. Every directive may be followed by a count, or `*` to consume
+// all remaining arguments.
+func pack(template string, args []RubyObject) (string, error) {
+	var out []byte
+	next := 0
+	nextArg := func() (RubyObject, error) {
+		if next >= len(args) {
+			return nil, NewArgumentError("too few arguments")
+		}
+		next++
+		return args[next-1], nil
+	}
+	for i := 0; i < len(template); i++ {
+		directive := template[i]
+		switch directive {
+		case ' ', '\t', '\n', '\v', '\f', '\r':
+			continue
+		case '#':
+			for i < len(template) && template[i] != '\n' {
+				i++
+			}
+			continue
+		}
+		var order binary.ByteOrder = binary.LittleEndian
+		native := false
+		for i+1 < len(template) && strings.IndexByte("_!<>", template[i+1]) != -1 {
+			i++
+			switch template[i] {
+			case '_', '!':
+				native = true
+			case '<':
+				order = binary.LittleEndian
+			case '>':
+				order = binary.BigEndian
+			}
+		}
+		count, star, hasCount := 1, false, false
+		if i+1 < len(template) && template[i+1] == '*' {
+			i++
+			star, hasCount = true, true
+		} else if i+1 < len(template) && isDigitByte(template[i+1]) {
+			count = 0
+			for i+1 < len(template) && isDigitByte(template[i+1]) {
+				i++
+				count = count*10 + int(template[i]-'0')
+			}
+			hasCount = true
+		}
+		if integer, ok := integerDirectives[directive]; ok {
+			size := integer.size
+			if native && (directive == 'L' || directive == 'l') {
+				size = 8
+			}
+			if integer.order != nil {
+				order = integer.order
+			}
+			if star {
+				count = len(args) - next
+			}
+			for j := 0; j < count; j++ {
+				arg, err := nextArg()
+				if err != nil {
+					return "", err
+				}
+				value, err := packInteger(arg)
+				if err != nil {
+					return "", err
+				}
+				buf := make([]byte, 8)
+				if order == binary.BigEndian {
+					binary.BigEndian.PutUint64(buf, value)
+					buf = buf[8-size:]
+				} else {
+					binary.LittleEndian.PutUint64(buf, value)
+					buf = buf[:size]
+				}
+				out = append(out, buf...)
+			}
+			continue
+		}
+		if float, ok := floatDirectives[directive]; ok {
+			if float.order != nil {
+				order = float.order
+			}
+			if star {
+				count = len(args) - next
+			}
+			for j := 0; j < count; j++ {
+				arg, err := nextArg()
+				if err != nil {
+					return "", err
+				}
+				if !isNumeric(arg) {
+					return "", NewImplicitConversionTypeError(&Float{}, arg)
+				}
+				value, _ := formatFloatValue(arg)
+				if float.size == 4 {
+					buf := make([]byte, 4)
+					order.PutUint32(buf, math.Float32bits(float32(value)))
+					out = append(out, buf...)
+				} else {
+					buf := make([]byte, 8)
+					order.PutUint64(buf, math.Float64bits(value))
+					out = append(out, buf...)
+				}
+			}
+			continue
+		}
+		switch directive {
+		case 'a', 'A', 'Z':
+			arg, err := nextArg()
+			if err != nil {
+				return "", err
+			}
+			str, ok := arg.(*String)
+			if !ok {
+				return "", NewImplicitConversionTypeError(str, arg)
+			}
+			value := []byte(str.Value)
+			if star {
+				count = len(value)
+				if directive == 'Z' {
+					count++
+				}
+			}
+			pad := byte(0)
+			if directive == 'A' {
+				pad = ' '
+			}
+			for j := 0; j < count; j++ {
+				if j < len(value) {
+					out = append(out, value[j])
+				} else {
+					out = append(out, pad)
+				}
+			}
+		case 'B', 'b', 'H', 'h':
+			arg, err := nextArg()
+			if err != nil {
+				return "", err
+			}
+			str, ok := arg.(*String)
+			if !ok {
+				return "", NewImplicitConversionTypeError(str, arg)
+			}
+			if star {
+				count = len(str.Value)
+			}
+			if count > len(str.Value) {
+				count = len(str.Value)
+			}
+			out = append(out, packDigits(directive, str.Value[:count])...)
+		case 'U':
+			if star {
+				count = len(args) - next
+			}
+			for j := 0; j < count; j++ {
+				arg, err := nextArg()
+				if err != nil {
+					return "", err
+				}
+				value, err := packInteger(arg)
+				if err != nil {
+					return "", err
+				}
+				if int64(value) < 0 || value > utf8.MaxRune {
+					return "", NewRangeError("pack(U): value out of range")
+				}
+				buf := make([]byte, utf8.UTFMax)
+				out = append(out, buf[:utf8.EncodeRune(buf, rune(value))]...)
+			}
+		case 'w':
+			if star {
+				count = len(args) - next
+			}
+			for j := 0; j < count; j++ {
+				arg, err := nextArg()
+				if err != nil {
+					return "", err
+				}
+				integer, ok := arg.(*Integer)
+				if !ok {
+					return "", NewImplicitConversionTypeError(integer, arg)
+				}
+				if integer.BigInt().Sign() < 0 {
+					return "", NewArgumentError("can't compress negative numbers")
+				}
+				out = append(out, packBER(integer.BigInt())...)
+			}
+		case 'm':
+			arg, err := nextArg()
+			if err != nil {
+				return "", err
+			}
+			str, ok := arg.(*String)
+			if !ok {
+				return "", NewImplicitConversionTypeError(str, arg)
+			}
+			encoded := base64.StdEncoding.EncodeToString([]byte(str.Value))
+			if hasCount && !star && count == 0 {
+				out = append(out, encoded...)
+				break
+			}
+			lineLength := 60
+			if hasCount && !star && count >= 3 {
+				lineLength = count / 3 * 4
+			}
+			for len(encoded) > 0 {
+				n := lineLength
+				if n > len(encoded) {
+					n = len(encoded)
+				}
+				out = append(out, encoded[:n]...)
+				out = append(out, '\n')
+				encoded = encoded[n:]
+			}
+		case 'x':
+			if star {
+				count = 0
+			}
+			for j := 0; j < count; j++ {
+				out = append(out, 0)
+			}
+		case 'X':
+			if star {
+				count = 0
+			}
+			if count > len(out) {
+				return "", NewArgumentError("X outside of string")
+			}
+			out = out[:len(out)-count]
+		case '@':
+			if star {
+				count = len(out)
+			}
+			for len(out) < count {
+				out = append(out, 0)
+			}
+			out = out[:count]
+		default:
+			return "", NewArgumentError("unknown pack directive '%c' in '%s'", directive, template)
+		}
+	}
+	return string(out), nil
+}
+
+// packInteger returns the lower 64 bits of the two's complement of the
+// Integer or Float obj
+func packInteger(obj RubyObject) (uint64, error) {
+	switch obj := obj.(type) {
+	case *Integer:
+		if !obj.IsBig() {
+			return uint64(obj.Value), nil
+		}
+		modulus := new(big.Int).Lsh(big.NewInt(1), 64)
+		return new(big.Int).Mod(obj.BigInt(), modulus).Uint64(), nil
+	case *Float:
+		return uint64(int64(obj.Value)), nil
+	default:
+		return 0, NewImplicitConversionTypeError(&Integer{}, obj)
+	}
+}
+
+// packDigits packs a string of bits, for `B` and `b`, or of hex digits, for
+// `H` and `h`. Uppercase directives start with the most significant bit or
+// nibble.
+func packDigits(directive byte, digits string) []byte {
+	bits := 1
+	if directive == 'H' || directive == 'h' {
+		bits = 4
+	}
+	perByte := 8 / bits
+	out := make([]byte, (len(digits)+perByte-1)/perByte)
+	for i := 0; i < len(digits); i++ {
+		var value byte
+		c := digits[i]
+		if bits == 1 {
+			value = c & 1
+		} else if isDigitByte(c) {
+			value = c - '0'
+		} else {
+			value = (c&^0x20 - 'A' + 10) & 0xf
+		}
+		shift := uint((i % perByte) * bits)
+		if directive == 'B' || directive == 'H' {
+			shift = uint(8 - bits - (i%perByte)*bits)
+		}
+		out[i/perByte] |= value << shift
+	}
+	return out
+}
+
+// packBER returns value as BER compressed integer, seven bits per byte with
+// the high bit set on all but the last byte
+func packBER(value *big.Int) []byte {
+	if value.Sign() == 0 {
+		return []byte{0}
+	}
+	var out []byte
+	mask := big.NewInt(0x7f)
+	for value.Sign() > 0 {
+		b := byte(new(big.Int).And(value, mask).Uint64())
+		if len(out) > 0 {
+			b |= 0x80
+		}
+		out = append([]byte{b}, out...)
+		value = new(big.Int).Rsh(value, 7)
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package object
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPack(t *testing.T) {
+	overflow, _ := new(big.Int).SetString("-18446744073709551617", 10)
+
+	tests := []struct {
+		template string
+		args     []RubyObject
+		result   string
+		err      error
+	}{
+		{"C*", []RubyObject{NewInteger(65), NewInteger(66), NewInteger(323)}, "ABC", nil},
+		{"c2", []RubyObject{NewInteger(-1), NewInteger(1)}, "\xff\x01", nil},
+		{"S", []RubyObject{NewInteger(258)}, "\x02\x01", nil},
+		{"S>", []RubyObject{NewInteger(258)}, "\x01\x02", nil},
+		{"n N", []RubyObject{NewInteger(1), NewInteger(2)}, "\x00\x01\x00\x00\x00\x02", nil},
+		{"v V", []RubyObject{NewInteger(1), NewInteger(2)}, "\x01\x00\x02\x00\x00\x00", nil},
+		{"l_", []RubyObject{NewInteger(-2)}, "\xfe\xff\xff\xff\xff\xff\xff\xff", nil},
+		{"q", []RubyObject{NewBigInteger(overflow)}, "\xff\xff\xff\xff\xff\xff\xff\xff", nil},
+		{"C", []RubyObject{NewFloat(65.9)}, "A", nil},
+		{"a3 A3 Z*", []RubyObject{&String{Value: "x"}, &String{Value: "y"}, &String{Value: "z"}}, "x\x00\x00y  z\x00", nil},
+		{"a", []RubyObject{&String{Value: "abc"}}, "a", nil},
+		{"B8 b8", []RubyObject{&String{Value: "10000001"}, &String{Value: "10000001"}}, "\x81\x81", nil},
+		{"H* h2", []RubyObject{&String{Value: "616"}, &String{Value: "16"}}, "a`a", nil},
+		{"U*", []RubyObject{NewInteger(0x20ac), NewInteger(0x41)}, "€A", nil},
+		{"w", []RubyObject{NewInteger(300)}, "\x82\x2c", nil},
+		{"m", []RubyObject{&String{Value: "hello"}}, "aGVsbG8=\n", nil},
+		{"m0", []RubyObject{&String{Value: "hello"}}, "aGVsbG8=", nil},
+		{"e g", []RubyObject{NewFloat(1), NewInteger(1)}, "\x00\x00\x80\x3f\x3f\x80\x00\x00", nil},
+		{"G", []RubyObject{NewFloat(-2)}, "\xc0\x00\x00\x00\x00\x00\x00\x00", nil},
+		{"C x2 C", []RubyObject{NewInteger(1), NewInteger(2)}, "\x01\x00\x00\x02", nil},
+		{"a4 X2 @1", []RubyObject{&String{Value: "abcd"}}, "a", nil},
+		{"C # comment\n C", []RubyObject{NewInteger(1), NewInteger(2)}, "\x01\x02", nil},
+		{"C2", []RubyObject{NewInteger(1)}, "", NewArgumentError("too few arguments")},
+		{"C", []RubyObject{&String{Value: "a"}}, "", NewImplicitConversionTypeError(&Integer{}, &String{})},
+		{"a", []RubyObject{NewInteger(1)}, "", NewImplicitConversionTypeError(&String{}, &Integer{})},
+		{"U", []RubyObject{NewInteger(-1)}, "", NewRangeError("pack(U): value out of range")},
+		{"w", []RubyObject{NewInteger(-1)}, "", NewArgumentError("can't compress negative numbers")},
+		{"y", nil, "", NewArgumentError("unknown pack directive 'y' in 'y'")},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.template, func(t *testing.T) {
+			result, err := pack(testCase.template, testCase.args)
+
+			checkError(t, err, testCase.err)
+
+			if result != testCase.result {
+				t.Logf("Expected %q, got %q\n", testCase.result, result)
+				t.Fail()
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package object
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 
 	"github.com/goruby/goruby/ast"
+	"github.com/pkg/errors"
 )
 
 var procClass RubyClassObject = newClass(
@@ -36,6 +38,16 @@ type Proc struct {
 	Body                   *ast.BlockStatement
 	Env                    Environment
 	ArgumentCountMandatory bool
+	Frame                  Frame // the frame the body is evaluated in
+	// native is called instead of evaluating Body if set. It allows Go code
+	// to pass blocks to methods implemented in Ruby.
+	native func(args ...RubyObject) (RubyObject, error)
+}
+
+// newNativeProc returns a Proc which calls fn with the arguments it is
+// called with.
+func newNativeProc(fn func(args ...RubyObject) (RubyObject, error)) *Proc {
+	return &Proc{native: fn}
 }
 
 // Type returns proc_OBJ
@@ -43,6 +55,9 @@ func (p *Proc) Type() Type { return "" }
 
 // Inspect returns the proc body
 func (p *Proc) Inspect() string {
+	if p.native != nil {
+		return fmt.Sprintf("#<Proc:%p>", p)
+	}
 	var out bytes.Buffer
 	params := []string{}
 	for _, p := range p.Parameters {
@@ -59,33 +74,134 @@ func (p *Proc) Inspect() string {
 // Class returns procClass
 func (p *Proc) Class() RubyClass { return procClass }
 
-// Call implements the RubyMethod interface. It evaluates p.Body and returns its result
+// Call implements the RubyMethod interface. It evaluates p.Body and returns its result.
+//
+// A next within the body returns its value, a redo evaluates the body again
+// and a break is tagged with p and handed to the caller of the method the
+// block was given to.
 func (p *Proc) Call(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if p.native != nil {
+		return p.native(args...)
+	}
 	if p.ArgumentCountMandatory && len(args) != len(p.Parameters) {
 		return nil, NewWrongNumberOfArgumentsError(len(p.Parameters), len(args))
 	}
 	extendedEnv := p.extendProcEnv(args)
-	evaluated, err := context.Eval(p.Body, extendedEnv)
-	if err != nil {
-		return nil, err
+	for {
+		evaluated, err := context.Eval(frameBody(p.Body, p.Frame), extendedEnv)
+		if err == nil {
+			return evaluated, nil
+		}
+		jump, ok := errors.Cause(err).(*Jump)
+		if !ok || jump.Block != nil {
+			return nil, err
+		}
+		switch jump.JumpType {
+		case NextJump:
+			return jump.Value, nil
+		case RedoJump:
+			continue
+		case BreakJump:
+			jump.Block = p
+			return nil, jump
+		default:
+			return nil, err
+		}
 	}
-	return evaluated, nil
 }
 
 func (p *Proc) extendProcEnv(args []RubyObject) Environment {
 	env := NewEnclosedEnvironment(p.Env)
-	arguments := args
-	if len(args) < len(p.Parameters) {
-		for i := 0; i < (len(p.Parameters) - len(args)); i++ {
-			arguments = append(arguments, NIL)
+	bindBlockParameters(env, p.Parameters, args)
+	return env
+}
+
+// bindBlockParameters sets params to args within env. Missing arguments are
+// nil and surplus arguments are dropped. Destructuring parameters like
+// `(a, b)` bind their elements to the elements of their argument.
+func bindBlockParameters(env Environment, params []*ast.FunctionParameter, args []RubyObject) {
+	positional, rest, keywords := 0, -1, false
+	for i, param := range params {
+		switch param.Kind {
+		case ast.PositionalParameter:
+			positional++
+		case ast.RestParameter:
+			rest = i
+		case ast.KeywordParameter, ast.KeywordRestParameter:
+			keywords = true
 		}
 	}
-	for paramIdx, param := range p.Parameters {
-		env.Set(param.Name.Value, arguments[paramIdx])
+	// like in MRI a single array is spread over multiple block parameters
+	if len(args) == 1 && (positional > 1 || (positional > 0 && rest != -1)) {
+		if array, ok := args[0].(*Array); ok {
+			args = append([]RubyObject{}, array.Elements...)
+		}
+	}
+	keywordArgs := NewHash()
+	if keywords && len(args) > positional {
+		if hash, ok := args[len(args)-1].(*Hash); ok && hasSymbolKeys(hash) {
+			keywordArgs = hash.copy()
+			args = args[:len(args)-1]
+		}
+	}
+	// parameters after the rest parameter take the last arguments
+	pre, post := positional, 0
+	if rest != -1 {
+		pre = rest
+		post = positional - rest
+	}
+	restEnd := len(args) - post
+	if restEnd < pre {
+		restEnd = pre
+	}
+	preIdx, postIdx := 0, restEnd
+	for _, param := range params {
+		var value RubyObject = NIL
+		switch param.Kind {
+		case ast.PositionalParameter:
+			index := &preIdx
+			if preIdx >= pre {
+				index = &postIdx
+			}
+			if *index < len(args) {
+				value = args[*index]
+			}
+			*index++
+		case ast.RestParameter:
+			array := NewArray()
+			if restEnd > pre && pre < len(args) {
+				array.Elements = append(array.Elements, args[pre:restEnd]...)
+			}
+			value = array
+		case ast.KeywordParameter:
+			if pair, err := keywordArgs.remove(nil, NewSymbol(param.Name.Value)); err == nil && pair != nil {
+				value = pair.Value
+			}
+		case ast.KeywordRestParameter:
+			value = keywordArgs
+		}
+		if param.Elements != nil {
+			elements := []RubyObject{value}
+			if array, ok := value.(*Array); ok {
+				elements = array.Elements
+			}
+			bindBlockParameters(env, param.Elements, elements)
+			continue
+		}
+		if param.Name != nil {
+			env.Set(param.Name.Value, value)
+		}
 	}
-	return env
 }
 
 var procClassMethods = map[string]RubyMethod{}
 
-var procMethods = map[string]RubyMethod{}
+var procMethods = map[string]RubyMethod{
+	"call": publicMethod(procCall),
+	"===":  publicMethod(procCall),
+}
+
+func procCall(context CallContext, args ...RubyObject) (RubyObject, error) {
+	proc := context.Receiver().(*Proc)
+	return proc.Call(context, args...)
+}
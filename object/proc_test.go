@@ -147,4 +147,111 @@ func TestProcCall(t *testing.T) {
 
 		checkError(t, err, expected)
 	})
+	t.Run("array spread over parameters", func(t *testing.T) {
+		proc := &Proc{
+			Parameters: []*ast.FunctionParameter{
+				&ast.FunctionParameter{Name: &ast.Identifier{Value: "a"}},
+				&ast.FunctionParameter{Name: &ast.Identifier{Value: "b"}},
+			},
+			Body: &ast.BlockStatement{Statements: []ast.Statement{}},
+			Env:  NewEnvironment(),
+		}
+		eval := func(node ast.Node, env Environment) (RubyObject, error) {
+			a, _ := env.Get("a")
+			b, _ := env.Get("b")
+			return NewArray(b, a), nil
+		}
+		context := &callContext{
+			receiver: NIL,
+			env:      NewEnvironment(),
+			eval:     eval,
+		}
+
+		result, err := proc.Call(context, NewArray(NewInteger(1), NewInteger(2)))
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, NewArray(NewInteger(2), NewInteger(1)))
+	})
+	t.Run("rest parameter", func(t *testing.T) {
+		proc := &Proc{
+			Parameters: []*ast.FunctionParameter{
+				&ast.FunctionParameter{Name: &ast.Identifier{Value: "a"}},
+				&ast.FunctionParameter{Name: &ast.Identifier{Value: "b"}, Kind: ast.RestParameter},
+				&ast.FunctionParameter{Name: &ast.Identifier{Value: "c"}},
+			},
+			Body: &ast.BlockStatement{Statements: []ast.Statement{}},
+			Env:  NewEnvironment(),
+		}
+		eval := func(node ast.Node, env Environment) (RubyObject, error) {
+			a, _ := env.Get("a")
+			b, _ := env.Get("b")
+			c, _ := env.Get("c")
+			return NewArray(a, b, c), nil
+		}
+		context := &callContext{
+			receiver: NIL,
+			env:      NewEnvironment(),
+			eval:     eval,
+		}
+
+		result, err := proc.Call(context, NewInteger(1), NewInteger(2), NewInteger(3), NewInteger(4))
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, NewArray(NewInteger(1), NewArray(NewInteger(2), NewInteger(3)), NewInteger(4)))
+	})
+	t.Run("destructuring parameter", func(t *testing.T) {
+		proc := &Proc{
+			Parameters: []*ast.FunctionParameter{
+				&ast.FunctionParameter{Elements: []*ast.FunctionParameter{
+					&ast.FunctionParameter{Name: &ast.Identifier{Value: "a"}},
+					&ast.FunctionParameter{Name: &ast.Identifier{Value: "b"}},
+				}},
+				&ast.FunctionParameter{Name: &ast.Identifier{Value: "c"}},
+			},
+			Body: &ast.BlockStatement{Statements: []ast.Statement{}},
+			Env:  NewEnvironment(),
+		}
+		eval := func(node ast.Node, env Environment) (RubyObject, error) {
+			a, _ := env.Get("a")
+			b, _ := env.Get("b")
+			c, _ := env.Get("c")
+			return NewArray(a, b, c), nil
+		}
+		context := &callContext{
+			receiver: NIL,
+			env:      NewEnvironment(),
+			eval:     eval,
+		}
+
+		result, err := proc.Call(context, NewArray(NewInteger(1), NewInteger(2)), NewInteger(3))
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, NewArray(NewInteger(1), NewInteger(2), NewInteger(3)))
+	})
+}
+
+func TestProcCaseEqual(t *testing.T) {
+	proc := &Proc{
+		Parameters: []*ast.FunctionParameter{&ast.FunctionParameter{Name: &ast.Identifier{Value: "a"}}},
+		Body:       &ast.BlockStatement{Statements: []ast.Statement{}},
+		Env:        NewEnvironment(),
+	}
+	eval := func(node ast.Node, env Environment) (RubyObject, error) {
+		arg, _ := env.Get("a")
+		return arg, nil
+	}
+	context := &callContext{
+		receiver: proc,
+		env:      NewEnvironment(),
+		eval:     eval,
+	}
+
+	result, err := procCall(context, NewInteger(3))
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, NewInteger(3))
 }
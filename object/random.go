@@ -0,0 +1,206 @@
+package object
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+var randomClass RubyClassObject = newClass(
+	"Random",
+	objectClass,
+	randomMethods,
+	randomClassMethods,
+	func(RubyClassObject, ...RubyObject) (RubyObject, error) {
+		return NewRandom(newSeed()), nil
+	},
+)
+
+func init() {
+	classes.Set("Random", randomClass)
+}
+
+// defaultRandom is the generator used by Kernel#rand and by methods like
+// Array#shuffle if no generator is given. It is reseeded by Kernel#srand.
+var defaultRandom = NewRandom(newSeed())
+
+// NewRandom returns a new pseudo random number generator seeded with seed.
+// Generators with the same seed return the same sequence of numbers.
+func NewRandom(seed int64) *Random {
+	return &Random{Seed: seed, rand: rand.New(rand.NewSource(seed))}
+}
+
+func newSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+// A Random represents a Ruby Random
+type Random struct {
+	Seed int64
+	rand *rand.Rand
+}
+
+// Inspect returns the address of the generator
+func (r *Random) Inspect() string { return fmt.Sprintf("#<Random:%p>", r) }
+
+// Type returns RANDOM_OBJ
+func (r *Random) Type() Type { return RANDOM_OBJ }
+
+// Class returns randomClass
+func (r *Random) Class() RubyClass { return randomClass }
+
+func (r *Random) hashKey() hashKey {
+	h := fnv.New64a()
+	h.Write([]byte(fmt.Sprintf("%d", r.Seed)))
+	return hashKey{Type: r.Type(), Value: h.Sum64()}
+}
+
+// intn returns a number within [0, n)
+func (r *Random) intn(n int) int { return r.rand.Intn(n) }
+
+// value returns a random number limited by max as done by Random#rand. A
+// max of nil returns a Float within [0, 1), an Integer or Float n a number
+// within [0, n) and a Range a number it covers. If lenient is true, as for
+// Kernel#rand, a max of zero is treated like nil and negative numbers like
+// their absolute value.
+func (r *Random) value(max RubyObject, lenient bool) (RubyObject, error) {
+	switch max := max.(type) {
+	case *Integer:
+		n := max.Value
+		if lenient && n < 0 {
+			n = -n
+		}
+		if lenient && n == 0 {
+			return NewFloat(r.rand.Float64()), nil
+		}
+		if n <= 0 {
+			return nil, NewArgumentError("invalid argument - %s", max.Inspect())
+		}
+		return NewInteger(r.rand.Int63n(n)), nil
+	case *Float:
+		n := max.Value
+		if lenient && n < 0 {
+			n = -n
+		}
+		if lenient && n == 0 {
+			return NewFloat(r.rand.Float64()), nil
+		}
+		if n <= 0 {
+			return nil, NewArgumentError("invalid argument - %s", max.Inspect())
+		}
+		return NewFloat(r.rand.Float64() * n), nil
+	case *Range:
+		begin, beginIsInteger := max.Begin.(*Integer)
+		end, endIsInteger := max.End.(*Integer)
+		if beginIsInteger && endIsInteger {
+			n := end.Value - begin.Value
+			if !max.Exclusive {
+				n++
+			}
+			if n <= 0 {
+				return NIL, nil
+			}
+			return NewInteger(begin.Value + r.rand.Int63n(n)), nil
+		}
+		if !isNumeric(max.Begin) || !isNumeric(max.End) {
+			return nil, NewArgumentError("invalid argument - %s", max.Inspect())
+		}
+		first, _ := formatFloatValue(max.Begin)
+		last, _ := formatFloatValue(max.End)
+		if last < first || last == first && max.Exclusive {
+			return NIL, nil
+		}
+		return NewFloat(first + r.rand.Float64()*(last-first)), nil
+	default:
+		if max == NIL {
+			return NewFloat(r.rand.Float64()), nil
+		}
+		return nil, NewArgumentError("invalid argument - %s", max.Inspect())
+	}
+}
+
+var randomClassMethods = map[string]RubyMethod{
+	"rand":     publicMethod(randomClassRand),
+	"new_seed": withArity(0, publicMethod(randomNewSeed)),
+	"srand":    publicMethod(kernelSrand),
+}
+
+var randomMethods = map[string]RubyMethod{
+	"initialize": privateMethod(randomInitialize),
+	"rand":       publicMethod(randomRand),
+	"seed":       withArity(0, publicMethod(randomSeed)),
+}
+
+func randomClassRand(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return randomRand(&callContext{receiver: defaultRandom, env: context.Env(), eval: context.Eval}, args...)
+}
+
+func randomNewSeed(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return NewInteger(newSeed()), nil
+}
+
+func randomInitialize(context CallContext, args ...RubyObject) (RubyObject, error) {
+	self, _ := context.Receiver().(*Self)
+	switch len(args) {
+	case 0:
+		self.RubyObject = NewRandom(newSeed())
+	case 1:
+		seed, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(seed, args[0])
+		}
+		self.RubyObject = NewRandom(seed.Value)
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	return self, nil
+}
+
+func randomRand(context CallContext, args ...RubyObject) (RubyObject, error) {
+	random := context.Receiver().(*Random)
+	switch len(args) {
+	case 0:
+		return random.value(NIL, false)
+	case 1:
+		return random.value(args[0], false)
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+}
+
+func randomSeed(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return NewInteger(context.Receiver().(*Random).Seed), nil
+}
+
+// kernelRand returns a random number using the default generator. Unlike
+// Random#rand it accepts zero and negative limits.
+func kernelRand(context CallContext, args ...RubyObject) (RubyObject, error) {
+	switch len(args) {
+	case 0:
+		return defaultRandom.value(NIL, true)
+	case 1:
+		return defaultRandom.value(args[0], true)
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+}
+
+// kernelSrand reseeds the default generator and returns the previous seed
+func kernelSrand(context CallContext, args ...RubyObject) (RubyObject, error) {
+	seed := newSeed()
+	switch len(args) {
+	case 0:
+	case 1:
+		arg, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(arg, args[0])
+		}
+		seed = arg.Value
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	previous := defaultRandom.Seed
+	defaultRandom = NewRandom(seed)
+	return NewInteger(previous), nil
+}
@@ -0,0 +1,84 @@
+package object
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRandomValue(t *testing.T) {
+	tests := []struct {
+		max     RubyObject
+		lenient bool
+		check   func(RubyObject) bool
+		err     error
+	}{
+		{NIL, false, func(v RubyObject) bool { f, ok := v.(*Float); return ok && f.Value >= 0 && f.Value < 1 }, nil},
+		{NewInteger(3), false, func(v RubyObject) bool { i, ok := v.(*Integer); return ok && i.Value >= 0 && i.Value < 3 }, nil},
+		{NewFloat(0.5), false, func(v RubyObject) bool { f, ok := v.(*Float); return ok && f.Value >= 0 && f.Value < 0.5 }, nil},
+		{
+			&Range{Begin: NewInteger(5), End: NewInteger(6)}, false,
+			func(v RubyObject) bool { i, ok := v.(*Integer); return ok && i.Value >= 5 && i.Value <= 6 }, nil,
+		},
+		{&Range{Begin: NewInteger(5), End: NewInteger(5), Exclusive: true}, false, func(v RubyObject) bool { return v == NIL }, nil},
+		{
+			&Range{Begin: NewFloat(1), End: NewInteger(2)}, false,
+			func(v RubyObject) bool { f, ok := v.(*Float); return ok && f.Value >= 1 && f.Value <= 2 }, nil,
+		},
+		{NewInteger(-3), true, func(v RubyObject) bool { i, ok := v.(*Integer); return ok && i.Value >= 0 && i.Value < 3 }, nil},
+		{NewInteger(0), true, func(v RubyObject) bool { _, ok := v.(*Float); return ok }, nil},
+		{NewInteger(0), false, nil, NewArgumentError("invalid argument - 0")},
+		{NewFloat(-1), false, nil, NewArgumentError("invalid argument - -1.0")},
+		{&String{Value: "a"}, false, nil, NewArgumentError(`invalid argument - "a"`)},
+	}
+
+	for _, testCase := range tests {
+		random := NewRandom(1)
+
+		result, err := random.value(testCase.max, testCase.lenient)
+
+		checkError(t, err, testCase.err)
+
+		if testCase.err == nil && !testCase.check(result) {
+			t.Logf("Unexpected random value for %s: %s\n", testCase.max.Inspect(), result.Inspect())
+			t.Fail()
+		}
+	}
+}
+
+func TestRandomSeed(t *testing.T) {
+	t.Run("same seed", func(t *testing.T) {
+		first, second := NewRandom(42), NewRandom(42)
+
+		for i := 0; i < 5; i++ {
+			a, _ := first.value(NewInteger(math.MaxInt32), false)
+			b, _ := second.value(NewInteger(math.MaxInt32), false)
+			checkResult(t, a, b)
+		}
+	})
+	t.Run("initialize", func(t *testing.T) {
+		self := &Self{RubyObject: &Random{}}
+		context := &callContext{receiver: self}
+
+		_, err := randomInitialize(context, NewInteger(42))
+
+		checkError(t, err, nil)
+		if seed := self.RubyObject.(*Random).Seed; seed != 42 {
+			t.Logf("Expected seed to equal 42, got %d\n", seed)
+			t.Fail()
+		}
+	})
+	t.Run("srand", func(t *testing.T) {
+		context := &callContext{receiver: NIL}
+
+		_, err := kernelSrand(context, NewInteger(42))
+		checkError(t, err, nil)
+		first, _ := kernelRand(context, NewInteger(1000))
+
+		previous, err := kernelSrand(context, NewInteger(42))
+		checkError(t, err, nil)
+		second, _ := kernelRand(context, NewInteger(1000))
+
+		checkResult(t, previous, NewInteger(42))
+		checkResult(t, first, second)
+	})
+}
@@ -0,0 +1,661 @@
+package object
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var rangeClass RubyClassObject = newMixin(newClass(
+	"Range",
+	objectClass,
+	rangeMethods,
+	rangeClassMethods,
+	func(RubyClassObject, ...RubyObject) (RubyObject, error) {
+		return &Range{Begin: NIL, End: NIL}, nil
+	},
+), enumerableModule)
+
+func init() {
+	classes.Set("Range", rangeClass)
+}
+
+// errStopIteration ends the iteration over a range early
+var errStopIteration = errors.New("stop iteration")
+
+// NewRange returns a new range from begin to end, excluding end if exclusive
+// is true. Beginless and endless ranges have a begin or end of NIL. It
+// returns an ArgumentError if begin and end cannot be compared with each
+// other.
+func NewRange(context CallContext, begin, end RubyObject, exclusive bool) (*Range, error) {
+	if begin != NIL && end != NIL {
+		_, ok, err := compare(context, begin, end)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, NewArgumentError("bad value for range")
+		}
+	}
+	return &Range{Begin: begin, End: end, Exclusive: exclusive}, nil
+}
+
+// A Range represents an interval of values in Ruby
+type Range struct {
+	Begin     RubyObject
+	End       RubyObject
+	Exclusive bool
+}
+
+// Inspect returns the range in its literal notation, e.g. `1..5`
+func (r *Range) Inspect() string {
+	var begin, end string
+	if r.Begin != NIL || r.End == NIL {
+		begin = r.Begin.Inspect()
+	}
+	if r.End != NIL || r.Begin == NIL {
+		end = r.End.Inspect()
+	}
+	return begin + r.operator() + end
+}
+
+func (r *Range) operator() string {
+	if r.Exclusive {
+		return "..."
+	}
+	return ".."
+}
+
+// Type returns RANGE_OBJ
+func (r *Range) Type() Type { return RANGE_OBJ }
+
+// Class returns rangeClass
+func (r *Range) Class() RubyClass { return rangeClass }
+
+func (r *Range) hashKey() hashKey {
+	h := fnv.New64a()
+	h.Write(hash(r.Begin).bytes())
+	h.Write(hash(r.End).bytes())
+	h.Write([]byte(r.operator()))
+	return hashKey{Type: r.Type(), Value: h.Sum64()}
+}
+
+// each calls fn with every element of the range. Integers are counted up
+// while strings and any other objects are advanced by their succ method.
+func (r *Range) each(context CallContext, fn func(RubyObject) error) error {
+	switch begin := r.Begin.(type) {
+	case *Integer:
+		end, ok := r.End.(*Integer)
+		if begin.big != nil || ok && end.big != nil || !ok && r.End != NIL {
+			break
+		}
+		for i := begin.Value; !ok || i < end.Value || i == end.Value && !r.Exclusive; i++ {
+			if err := fn(NewInteger(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *String:
+		if end, ok := r.End.(*String); ok {
+			return stringUpto(begin.Value, end.Value, r.Exclusive, func(s string) error {
+				return fn(&String{Value: s})
+			})
+		}
+	}
+	if r.Begin == NIL || !respondTo(r.Begin, "succ") {
+		return NewTypeError("can't iterate from " + r.Begin.Class().Name())
+	}
+	current := r.Begin
+	for {
+		cmp := -1
+		if r.End != NIL {
+			var ok bool
+			var err error
+			cmp, ok, err = compare(context, current, r.End)
+			if err != nil {
+				return err
+			}
+			if !ok || cmp > 0 || cmp == 0 && r.Exclusive {
+				return nil
+			}
+		}
+		if err := fn(current); err != nil {
+			return err
+		}
+		if cmp == 0 {
+			return nil
+		}
+		next, err := Send(&callContext{receiver: current, env: context.Env(), eval: context.Eval}, "succ")
+		if err != nil {
+			return err
+		}
+		current = next
+	}
+}
+
+// stringUpto calls fn with every string from begin up to end, as done by
+// String#upto
+func stringUpto(begin, end string, exclusive bool, fn func(string) error) error {
+	n := strings.Compare(begin, end)
+	if n > 0 || exclusive && n == 0 {
+		return nil
+	}
+	afterEnd := successor(end)
+	for current := begin; current != afterEnd; {
+		var next string
+		hasNext := exclusive || current != end
+		if hasNext {
+			next = successor(current)
+		}
+		if err := fn(current); err != nil {
+			return err
+		}
+		if !hasNext {
+			return nil
+		}
+		current = next
+		if exclusive && current == end {
+			return nil
+		}
+		if len(current) > len(end) || len(current) == 0 {
+			return nil
+		}
+	}
+	return nil
+}
+
+// toArray returns all elements of the range
+func (r *Range) toArray(context CallContext) (*Array, error) {
+	if r.End == NIL {
+		return nil, NewRangeError("cannot convert endless range to an array")
+	}
+	array := NewArray()
+	err := r.each(context, func(element RubyObject) error {
+		array.Elements = append(array.Elements, element)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return array, nil
+}
+
+// covers reports whether obj lies between the begin and the end of the range
+func (r *Range) covers(context CallContext, obj RubyObject) (bool, error) {
+	if r.Begin != NIL {
+		cmp, ok, err := compare(context, r.Begin, obj)
+		if err != nil || !ok || cmp > 0 {
+			return false, err
+		}
+	}
+	if r.End != NIL {
+		cmp, ok, err := compare(context, obj, r.End)
+		if err != nil || !ok || cmp > 0 || cmp == 0 && r.Exclusive {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// indices resolves the range into the start index and the length of the
+// slice it selects within a sequence of length elements, as done when
+// slicing Arrays and Strings. It returns false if the range starts outside of
+// the sequence.
+func (r *Range) indices(length int) (int, int, bool, error) {
+	start, end := int64(0), int64(-1)
+	if r.Begin != NIL {
+		begin, ok := r.Begin.(*Integer)
+		if !ok {
+			return 0, 0, false, NewImplicitConversionTypeError(begin, r.Begin)
+		}
+		start = begin.Value
+	}
+	exclusive := r.Exclusive
+	if r.End != NIL {
+		last, ok := r.End.(*Integer)
+		if !ok {
+			return 0, 0, false, NewImplicitConversionTypeError(last, r.End)
+		}
+		end = last.Value
+	} else {
+		exclusive = false
+	}
+	if start < 0 {
+		start += int64(length)
+		if start < 0 {
+			return 0, 0, false, nil
+		}
+	}
+	if start > int64(length) {
+		return 0, 0, false, nil
+	}
+	if end < 0 {
+		end += int64(length)
+	}
+	if !exclusive {
+		end++
+	}
+	size := end - start
+	if size < 0 {
+		size = 0
+	}
+	if start+size > int64(length) {
+		size = int64(length) - start
+	}
+	return int(start), int(size), true, nil
+}
+
+// compare returns the result of `a <=> b` called within context. It returns
+// false if a and b are not comparable.
+func compare(context CallContext, a, b RubyObject) (int, bool, error) {
+	result, err := Send(&callContext{receiver: a, env: context.Env(), eval: context.Eval}, "<=>", b)
+	if err != nil {
+		return 0, false, err
+	}
+	cmp, ok := result.(*Integer)
+	if !ok {
+		return 0, false, nil
+	}
+	return cmp.BigInt().Sign(), true, nil
+}
+
+var rangeClassMethods = map[string]RubyMethod{}
+
+var rangeMethods = map[string]RubyMethod{
+	"initialize":   privateMethod(rangeInitialize),
+	"begin":        withArity(0, publicMethod(rangeBegin)),
+	"end":          withArity(0, publicMethod(rangeEnd)),
+	"first":        publicMethod(rangeFirst),
+	"last":         publicMethod(rangeLast),
+	"exclude_end?": withArity(0, publicMethod(rangeExcludeEnd)),
+	"each":         publicMethod(rangeEach),
+	"step":         publicMethod(rangeStep),
+	"to_a":         withArity(0, publicMethod(rangeToA)),
+	"entries":      withArity(0, publicMethod(rangeToA)),
+	"size":         withArity(0, publicMethod(rangeSize)),
+	"include?":     withArity(1, publicMethod(rangeInclude)),
+	"member?":      withArity(1, publicMethod(rangeInclude)),
+	"cover?":       withArity(1, publicMethod(rangeCover)),
+	"===":          withArity(1, publicMethod(rangeCover)),
+	"==":           withArity(1, publicMethod(rangeEqual)),
+	"eql?":         withArity(1, publicMethod(rangeEqual)),
+	"to_s":         withArity(0, publicMethod(rangeToS)),
+	"inspect":      withArity(0, publicMethod(rangeInspect)),
+}
+
+func rangeInitialize(context CallContext, args ...RubyObject) (RubyObject, error) {
+	self, _ := context.Receiver().(*Self)
+	if len(args) < 2 || len(args) > 3 {
+		return nil, NewWrongNumberOfArgumentsError(2, len(args))
+	}
+	exclusive := len(args) == 3 && args[2] != NIL && args[2] != FALSE
+	rng, err := NewRange(context, args[0], args[1], exclusive)
+	if err != nil {
+		return nil, err
+	}
+	self.RubyObject = rng
+	return self, nil
+}
+
+func rangeBegin(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver().(*Range).Begin, nil
+}
+
+func rangeEnd(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver().(*Range).End, nil
+}
+
+func rangeFirst(context CallContext, args ...RubyObject) (RubyObject, error) {
+	rng := context.Receiver().(*Range)
+	switch len(args) {
+	case 0:
+		if rng.Begin == NIL {
+			return nil, NewRangeError("cannot get the first element of beginless range")
+		}
+		return rng.Begin, nil
+	case 1:
+		count, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(count, args[0])
+		}
+		if count.Value < 0 {
+			return nil, NewArgumentError("negative array size (or size too big)")
+		}
+		first := NewArray()
+		if count.Value == 0 {
+			return first, nil
+		}
+		err := rng.each(context, func(element RubyObject) error {
+			first.Elements = append(first.Elements, element)
+			if int64(len(first.Elements)) == count.Value {
+				return errStopIteration
+			}
+			return nil
+		})
+		if err != nil && err != errStopIteration {
+			return nil, err
+		}
+		return first, nil
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+}
+
+func rangeLast(context CallContext, args ...RubyObject) (RubyObject, error) {
+	rng := context.Receiver().(*Range)
+	switch len(args) {
+	case 0:
+		if rng.End == NIL {
+			return nil, NewRangeError("cannot get the last element of endless range")
+		}
+		return rng.End, nil
+	case 1:
+		count, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(count, args[0])
+		}
+		if count.Value < 0 {
+			return nil, NewArgumentError("negative array size")
+		}
+		elements, err := rng.toArray(context)
+		if err != nil {
+			return nil, err
+		}
+		start := int64(len(elements.Elements)) - count.Value
+		if start < 0 {
+			start = 0
+		}
+		return NewArray(elements.Elements[start:]...), nil
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+}
+
+func rangeExcludeEnd(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return nativeBoolToBooleanObject(context.Receiver().(*Range).Exclusive), nil
+}
+
+func rangeEach(context CallContext, args ...RubyObject) (RubyObject, error) {
+	rng := context.Receiver().(*Range)
+	block, remainingArgs, ok := extractBlockFromArgs(args)
+	if !ok {
+		return nil, NewNoBlockGivenLocalJumpError()
+	}
+	if len(remainingArgs) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(remainingArgs))
+	}
+	err := rng.each(context, func(element RubyObject) error {
+		_, err := block.Call(context, element)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rng, nil
+}
+
+// rangeStep calls the block with every step-th element of the range. Without
+// a block the elements are returned as Array.
+func rangeStep(context CallContext, args ...RubyObject) (RubyObject, error) {
+	rng := context.Receiver().(*Range)
+	block, args, hasBlock := extractBlockFromArgs(args)
+	if len(args) > 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	var step RubyObject = NewInteger(1)
+	if len(args) == 1 {
+		step = args[0]
+	}
+	if !hasBlock && rng.End == NIL {
+		return nil, NewRangeError("cannot convert endless range to an array")
+	}
+	steps := NewArray()
+	yield := func(element RubyObject) error {
+		if !hasBlock {
+			steps.Elements = append(steps.Elements, element)
+			return nil
+		}
+		_, err := block.Call(context, element)
+		return err
+	}
+	var err error
+	if isNumeric(rng.Begin) && (rng.End == NIL || isNumeric(rng.End)) {
+		err = rng.numericStep(context, step, yield)
+	} else {
+		err = rng.step(context, step, yield)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !hasBlock {
+		return steps, nil
+	}
+	return rng, nil
+}
+
+// numericStep calls fn with begin, begin+step, begin+2*step, ... as long as
+// the value lies within the range
+func (r *Range) numericStep(context CallContext, step RubyObject, fn func(RubyObject) error) error {
+	if !isNumeric(step) {
+		return NewImplicitConversionTypeError(&Integer{}, step)
+	}
+	unit, _ := formatFloatValue(step)
+	if unit < 0 {
+		return NewArgumentError("step can't be negative")
+	}
+	if unit == 0 {
+		return NewArgumentError("step can't be 0")
+	}
+	_, beginIsFloat := r.Begin.(*Float)
+	_, endIsFloat := r.End.(*Float)
+	_, stepIsFloat := step.(*Float)
+	if !beginIsFloat && !endIsFloat && !stepIsFloat {
+		current := r.Begin
+		for {
+			if r.End != NIL {
+				cmp, _, err := compare(context, current, r.End)
+				if err != nil {
+					return err
+				}
+				if cmp > 0 || cmp == 0 && r.Exclusive {
+					return nil
+				}
+			}
+			if err := fn(current); err != nil {
+				return err
+			}
+			next, err := Send(&callContext{receiver: current, env: context.Env(), eval: context.Eval}, "+", step)
+			if err != nil {
+				return err
+			}
+			current = next
+		}
+	}
+	begin, _ := formatFloatValue(r.Begin)
+	if r.End == NIL {
+		for i := 0.0; ; i++ {
+			if err := fn(NewFloat(i*unit + begin)); err != nil {
+				return err
+			}
+		}
+	}
+	end, _ := formatFloatValue(r.End)
+	// like MRI the number of steps is corrected by the floating point error
+	n := (end - begin) / unit
+	floatErr := (math.Abs(begin) + math.Abs(end) + math.Abs(end-begin)) / math.Abs(unit) * (math.Nextafter(1, 2) - 1)
+	if r.Exclusive {
+		if n <= 0 {
+			return nil
+		}
+		if n < 1 {
+			n = 0
+		} else {
+			n = math.Floor(n - floatErr)
+		}
+	} else {
+		if n < 0 {
+			return nil
+		}
+		n = math.Floor(n + floatErr)
+	}
+	for i := 0.0; i <= n; i++ {
+		value := i*unit + begin
+		if end < value {
+			value = end
+		}
+		if err := fn(NewFloat(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// step calls fn with every step-th element of the range
+func (r *Range) step(context CallContext, step RubyObject, fn func(RubyObject) error) error {
+	unit, ok := step.(*Integer)
+	if !ok {
+		return NewImplicitConversionTypeError(unit, step)
+	}
+	if unit.Value < 0 {
+		return NewArgumentError("step can't be negative")
+	}
+	if unit.Value == 0 {
+		return NewArgumentError("step can't be 0")
+	}
+	var i int64
+	return r.each(context, func(element RubyObject) error {
+		i++
+		if (i-1)%unit.Value != 0 {
+			return nil
+		}
+		return fn(element)
+	})
+}
+
+func rangeToA(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver().(*Range).toArray(context)
+}
+
+// rangeSize returns the number of elements of ranges of numbers and nil
+// for any other range
+func rangeSize(context CallContext, args ...RubyObject) (RubyObject, error) {
+	rng := context.Receiver().(*Range)
+	begin, ok := rng.Begin.(*Integer)
+	if !ok {
+		if rng.Begin == NIL || isNumeric(rng.Begin) {
+			return nil, NewTypeError("can't iterate from " + rng.Begin.Class().Name())
+		}
+		return NIL, nil
+	}
+	if rng.End == NIL {
+		return NewFloat(math.Inf(1)), nil
+	}
+	if !isNumeric(rng.End) {
+		return NIL, nil
+	}
+	first, _ := formatFloatValue(begin)
+	last, _ := formatFloatValue(rng.End)
+	size := math.Floor(last - first)
+	if !rng.Exclusive || first+size < last {
+		size++
+	}
+	if size < 0 {
+		size = 0
+	}
+	return NewInteger(int64(size)), nil
+}
+
+// rangeInclude reports whether the argument is an element of the range.
+// Ranges of strings are iterated, any other range compares the argument with
+// its bounds.
+func rangeInclude(context CallContext, args ...RubyObject) (RubyObject, error) {
+	rng := context.Receiver().(*Range)
+	_, beginIsString := rng.Begin.(*String)
+	_, endIsString := rng.End.(*String)
+	if !beginIsString || !endIsString {
+		return rangeCover(context, args...)
+	}
+	str, ok := args[0].(*String)
+	if !ok {
+		return FALSE, nil
+	}
+	found := false
+	err := rng.each(context, func(element RubyObject) error {
+		if element.(*String).Value == str.Value {
+			found = true
+			return errStopIteration
+		}
+		return nil
+	})
+	if err != nil && err != errStopIteration {
+		return nil, err
+	}
+	return nativeBoolToBooleanObject(found), nil
+}
+
+func rangeCover(context CallContext, args ...RubyObject) (RubyObject, error) {
+	covered, err := context.Receiver().(*Range).covers(context, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return nativeBoolToBooleanObject(covered), nil
+}
+
+func rangeEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	rng := context.Receiver().(*Range)
+	other, ok := args[0].(*Range)
+	if !ok || rng.Exclusive != other.Exclusive {
+		return FALSE, nil
+	}
+	for _, bounds := range [][2]RubyObject{{rng.Begin, other.Begin}, {rng.End, other.End}} {
+		equal, err := Send(&callContext{receiver: bounds[0], env: context.Env(), eval: context.Eval}, "==", bounds[1])
+		if err != nil {
+			return nil, err
+		}
+		if equal != TRUE {
+			return FALSE, nil
+		}
+	}
+	return TRUE, nil
+}
+
+func rangeToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	rng := context.Receiver().(*Range)
+	begin, err := convertToString(context, rng.Begin)
+	if err != nil {
+		return nil, err
+	}
+	end, err := convertToString(context, rng.End)
+	if err != nil {
+		return nil, err
+	}
+	return &String{Value: begin + rng.operator() + end}, nil
+}
+
+func rangeInspect(context CallContext, args ...RubyObject) (RubyObject, error) {
+	rng := context.Receiver().(*Range)
+	var begin, end string
+	var err error
+	if rng.Begin != NIL || rng.End == NIL {
+		if begin, err = inspect(context, rng.Begin); err != nil {
+			return nil, err
+		}
+	}
+	if rng.End != NIL || rng.Begin == NIL {
+		if end, err = inspect(context, rng.End); err != nil {
+			return nil, err
+		}
+	}
+	return &String{Value: begin + rng.operator() + end}, nil
+}
+
+func isNumeric(obj RubyObject) bool {
+	switch obj.(type) {
+	case *Integer, *Float:
+		return true
+	default:
+		return false
+	}
+}
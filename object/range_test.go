@@ -0,0 +1,229 @@
+package object
+
+import (
+	"math"
+	"testing"
+
+	"github.com/goruby/goruby/ast"
+)
+
+func TestNewRange(t *testing.T) {
+	tests := []struct {
+		begin  RubyObject
+		end    RubyObject
+		result RubyObject
+		err    error
+	}{
+		{NewInteger(1), NewInteger(5), &Range{Begin: NewInteger(1), End: NewInteger(5)}, nil},
+		{NewInteger(1), NewFloat(2.5), &Range{Begin: NewInteger(1), End: NewFloat(2.5)}, nil},
+		{NIL, NewInteger(5), &Range{Begin: NIL, End: NewInteger(5)}, nil},
+		{NewInteger(1), NIL, &Range{Begin: NewInteger(1), End: NIL}, nil},
+		{NewInteger(1), &String{Value: "a"}, nil, NewArgumentError("bad value for range")},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.begin}
+
+		result, err := NewRange(context, testCase.begin, testCase.end, false)
+
+		checkError(t, err, testCase.err)
+
+		if testCase.err == nil {
+			checkResult(t, result, testCase.result)
+		}
+	}
+}
+
+func TestRangeInspect(t *testing.T) {
+	tests := []struct {
+		rng      *Range
+		expected string
+	}{
+		{&Range{Begin: NewInteger(1), End: NewInteger(5)}, "1..5"},
+		{&Range{Begin: NewInteger(1), End: NewInteger(5), Exclusive: true}, "1...5"},
+		{&Range{Begin: NIL, End: NewInteger(5)}, "..5"},
+		{&Range{Begin: NewInteger(1), End: NIL}, "1.."},
+		{&Range{Begin: NIL, End: NIL}, "nil..nil"},
+		{&Range{Begin: &String{Value: "a"}, End: &String{Value: "c"}}, `"a".."c"`},
+	}
+
+	for _, testCase := range tests {
+		actual := testCase.rng.Inspect()
+
+		if actual != testCase.expected {
+			t.Logf("Expected inspect to equal %q, got %q\n", testCase.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestRangeMethods(t *testing.T) {
+	integers := func(values ...int64) *Array {
+		array := NewArray()
+		for _, v := range values {
+			array.Elements = append(array.Elements, NewInteger(v))
+		}
+		return array
+	}
+	strings := func(values ...string) *Array {
+		array := NewArray()
+		for _, v := range values {
+			array.Elements = append(array.Elements, &String{Value: v})
+		}
+		return array
+	}
+	oneToFive := &Range{Begin: NewInteger(1), End: NewInteger(5)}
+	oneUntilFive := &Range{Begin: NewInteger(1), End: NewInteger(5), Exclusive: true}
+	endless := &Range{Begin: NewInteger(1), End: NIL}
+	beginless := &Range{Begin: NIL, End: NewInteger(5)}
+	letters := &Range{Begin: &String{Value: "a"}, End: &String{Value: "e"}}
+
+	tests := []struct {
+		receiver  *Range
+		method    string
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{oneToFive, "to_a", nil, integers(1, 2, 3, 4, 5), nil},
+		{oneUntilFive, "to_a", nil, integers(1, 2, 3, 4), nil},
+		{&Range{Begin: NewInteger(5), End: NewInteger(1)}, "to_a", nil, integers(), nil},
+		{&Range{Begin: NewInteger(1), End: NewFloat(2.5)}, "to_a", nil, integers(1, 2), nil},
+		{endless, "to_a", nil, nil, NewRangeError("cannot convert endless range to an array")},
+		{beginless, "to_a", nil, nil, NewTypeError("can't iterate from NilClass")},
+		{&Range{Begin: NewFloat(1), End: NewInteger(2)}, "to_a", nil, nil, NewTypeError("can't iterate from Float")},
+		{letters, "to_a", nil, strings("a", "b", "c", "d", "e"), nil},
+		{&Range{Begin: &String{Value: "az"}, End: &String{Value: "bb"}}, "to_a", nil, strings("az", "ba", "bb"), nil},
+		{&Range{Begin: &String{Value: "y"}, End: &String{Value: "ab"}}, "to_a", nil, strings(), nil},
+		{&Range{Begin: &String{Value: "a"}, End: &String{Value: "c"}, Exclusive: true}, "entries", nil, strings("a", "b"), nil},
+		{oneToFive, "first", nil, NewInteger(1), nil},
+		{oneToFive, "first", []RubyObject{NewInteger(2)}, integers(1, 2), nil},
+		{endless, "first", []RubyObject{NewInteger(3)}, integers(1, 2, 3), nil},
+		{beginless, "first", nil, nil, NewRangeError("cannot get the first element of beginless range")},
+		{oneUntilFive, "last", nil, NewInteger(5), nil},
+		{oneUntilFive, "last", []RubyObject{NewInteger(2)}, integers(3, 4), nil},
+		{endless, "last", nil, nil, NewRangeError("cannot get the last element of endless range")},
+		{oneToFive, "begin", nil, NewInteger(1), nil},
+		{endless, "end", nil, NIL, nil},
+		{oneUntilFive, "exclude_end?", nil, TRUE, nil},
+		{oneToFive, "size", nil, NewInteger(5), nil},
+		{oneUntilFive, "size", nil, NewInteger(4), nil},
+		{&Range{Begin: NewInteger(1), End: NewFloat(2.5)}, "size", nil, NewInteger(2), nil},
+		{&Range{Begin: NewInteger(5), End: NewInteger(1)}, "size", nil, NewInteger(0), nil},
+		{endless, "size", nil, NewFloat(math.Inf(1)), nil},
+		{letters, "size", nil, NIL, nil},
+		{oneToFive, "include?", []RubyObject{NewInteger(5)}, TRUE, nil},
+		{oneUntilFive, "include?", []RubyObject{NewInteger(5)}, FALSE, nil},
+		{oneToFive, "member?", []RubyObject{NewFloat(2.5)}, TRUE, nil},
+		{oneToFive, "===", []RubyObject{&String{Value: "a"}}, FALSE, nil},
+		{beginless, "===", []RubyObject{NewInteger(-100)}, TRUE, nil},
+		{endless, "cover?", []RubyObject{NewInteger(100)}, TRUE, nil},
+		{endless, "cover?", []RubyObject{NewInteger(0)}, FALSE, nil},
+		{letters, "include?", []RubyObject{&String{Value: "bb"}}, FALSE, nil},
+		{letters, "cover?", []RubyObject{&String{Value: "bb"}}, TRUE, nil},
+		{oneToFive, "step", []RubyObject{NewInteger(2)}, integers(1, 3, 5), nil},
+		{oneUntilFive, "step", []RubyObject{NewInteger(2)}, integers(1, 3), nil},
+		{
+			&Range{Begin: NewFloat(1), End: NewInteger(2)}, "step", []RubyObject{NewFloat(0.5)},
+			NewArray(NewFloat(1), NewFloat(1.5), NewFloat(2)), nil,
+		},
+		{letters, "step", []RubyObject{NewInteger(2)}, strings("a", "c", "e"), nil},
+		{oneToFive, "step", []RubyObject{NewInteger(0)}, nil, NewArgumentError("step can't be 0")},
+		{oneToFive, "step", []RubyObject{NewInteger(-1)}, nil, NewArgumentError("step can't be negative")},
+		{oneToFive, "==", []RubyObject{&Range{Begin: NewInteger(1), End: NewInteger(5)}}, TRUE, nil},
+		{oneToFive, "==", []RubyObject{oneUntilFive}, FALSE, nil},
+		{oneToFive, "==", []RubyObject{integers(1, 2, 3, 4, 5)}, FALSE, nil},
+		{oneUntilFive, "to_s", nil, &String{Value: "1...5"}, nil},
+		{letters, "to_s", nil, &String{Value: "a..e"}, nil},
+		{letters, "inspect", nil, &String{Value: `"a".."e"`}, nil},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.receiver, env: NewMainEnvironment()}
+
+		result, err := Send(context, testCase.method, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		if testCase.err == nil {
+			checkResult(t, result, testCase.result)
+		}
+	}
+}
+
+func TestRangeEach(t *testing.T) {
+	var elements []RubyObject
+	eval := func(node ast.Node, env Environment) (RubyObject, error) {
+		element, _ := env.Get("x")
+		elements = append(elements, element)
+		return NIL, nil
+	}
+	block := &Proc{
+		Parameters: []*ast.FunctionParameter{&ast.FunctionParameter{Name: &ast.Identifier{Value: "x"}}},
+		Body:       &ast.BlockStatement{Statements: []ast.Statement{}},
+		Env:        NewEnvironment(),
+	}
+
+	t.Run("each", func(t *testing.T) {
+		elements = nil
+		rng := &Range{Begin: NewInteger(1), End: NewInteger(3)}
+		context := &callContext{receiver: rng, env: NewEnvironment(), eval: eval}
+
+		result, err := rangeEach(context, block)
+
+		checkError(t, err, nil)
+		checkResult(t, result, rng)
+		checkResult(t, NewArray(elements...), NewArray(NewInteger(1), NewInteger(2), NewInteger(3)))
+	})
+	t.Run("each without block", func(t *testing.T) {
+		context := &callContext{receiver: &Range{Begin: NewInteger(1), End: NewInteger(3)}}
+
+		_, err := rangeEach(context)
+
+		checkError(t, err, NewNoBlockGivenLocalJumpError())
+	})
+	t.Run("step", func(t *testing.T) {
+		elements = nil
+		rng := &Range{Begin: NewInteger(1), End: NewInteger(10)}
+		context := &callContext{receiver: rng, env: NewEnvironment(), eval: eval}
+
+		result, err := rangeStep(context, NewInteger(4), block)
+
+		checkError(t, err, nil)
+		checkResult(t, result, rng)
+		checkResult(t, NewArray(elements...), NewArray(NewInteger(1), NewInteger(5), NewInteger(9)))
+	})
+}
+
+func TestRangeIndices(t *testing.T) {
+	tests := []struct {
+		rng    *Range
+		start  int
+		length int
+		ok     bool
+	}{
+		{&Range{Begin: NewInteger(1), End: NewInteger(2)}, 1, 2, true},
+		{&Range{Begin: NewInteger(1), End: NewInteger(2), Exclusive: true}, 1, 1, true},
+		{&Range{Begin: NewInteger(1), End: NewInteger(10)}, 1, 3, true},
+		{&Range{Begin: NewInteger(-2), End: NIL}, 2, 2, true},
+		{&Range{Begin: NIL, End: NewInteger(-2)}, 0, 3, true},
+		{&Range{Begin: NewInteger(4), End: NIL}, 4, 0, true},
+		{&Range{Begin: NewInteger(5), End: NIL}, 0, 0, false},
+		{&Range{Begin: NewInteger(-5), End: NIL}, 0, 0, false},
+		{&Range{Begin: NewInteger(3), End: NewInteger(1)}, 3, 0, true},
+	}
+
+	for _, testCase := range tests {
+		start, length, ok, err := testCase.rng.indices(4)
+
+		checkError(t, err, nil)
+
+		if start != testCase.start || length != testCase.length || ok != testCase.ok {
+			t.Logf(
+				"Expected indices of %s to equal (%d, %d, %t), got (%d, %d, %t)\n",
+				testCase.rng.Inspect(), testCase.start, testCase.length, testCase.ok, start, length, ok,
+			)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,1014 @@
+package object
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The option bits of a Regexp, as returned by Regexp#options
+const (
+	RegexpIgnoreCase = 1
+	RegexpExtended   = 2
+	RegexpMultiline  = 4
+)
+
+var regexpClass RubyClassObject = newClass(
+	"Regexp",
+	objectClass,
+	regexpMethods,
+	regexpClassMethods,
+	func(RubyClassObject, ...RubyObject) (RubyObject, error) {
+		return &Regexp{}, nil
+	},
+)
+
+var matchDataClass RubyClassObject = newClass(
+	"MatchData", objectClass, matchDataMethods, nil, notInstantiatable,
+)
+
+func init() {
+	classes.Set("Regexp", regexpClass)
+	classes.Set("MatchData", matchDataClass)
+	regexpEnv := regexpClass.(Environment)
+	regexpEnv.Set("IGNORECASE", NewInteger(RegexpIgnoreCase))
+	regexpEnv.Set("EXTENDED", NewInteger(RegexpExtended))
+	regexpEnv.Set("MULTILINE", NewInteger(RegexpMultiline))
+}
+
+// RegexpOptions returns the option bits for the flags following a regular
+// expression literal, e.g. `ix` for `/foo/ix`. Flags without a meaning to
+// the matching, like the encoding flags, are ignored.
+func RegexpOptions(flags string) int {
+	var options int
+	for _, flag := range flags {
+		switch flag {
+		case 'i':
+			options |= RegexpIgnoreCase
+		case 'x':
+			options |= RegexpExtended
+		case 'm':
+			options |= RegexpMultiline
+		}
+	}
+	return options
+}
+
+// NewRegexp returns a Regexp for the Ruby regular expression source. It
+// returns a RegexpError if source is invalid or uses a construct Go's
+// regexp package cannot match, like backreferences or lookarounds.
+func NewRegexp(source string, options int) (*Regexp, error) {
+	translated, endBeforeNewline, err := translateRegexp(source, options)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := regexp.Compile(translated)
+	if err != nil {
+		message := err.Error()
+		if syntaxErr, ok := err.(*syntax.Error); ok {
+			message = string(syntaxErr.Code)
+		}
+		return nil, NewRegexpError("%s: /%s/", message, source)
+	}
+	return &Regexp{Source: source, Options: options, regexp: compiled, endBeforeNewline: endBeforeNewline}, nil
+}
+
+// A Regexp represents a regular expression in Ruby
+type Regexp struct {
+	Source  string
+	Options int
+	regexp  *regexp.Regexp
+	// endBeforeNewline is set if the source uses `\Z`, which also matches
+	// before a newline ending the string
+	endBeforeNewline bool
+}
+
+// Inspect returns the regexp in its literal notation, e.g. `/foo/i`
+func (r *Regexp) Inspect() string {
+	var out strings.Builder
+	out.WriteByte('/')
+	for i := 0; i < len(r.Source); i++ {
+		switch r.Source[i] {
+		case '\\':
+			out.WriteByte('\\')
+			if i+1 < len(r.Source) {
+				i++
+				out.WriteByte(r.Source[i])
+			}
+		case '/':
+			out.WriteString(`\/`)
+		default:
+			out.WriteByte(r.Source[i])
+		}
+	}
+	out.WriteByte('/')
+	for _, option := range regexpOptionFlags {
+		if r.Options&option.bit != 0 {
+			out.WriteByte(option.flag)
+		}
+	}
+	return out.String()
+}
+
+// Type returns REGEXP_OBJ
+func (r *Regexp) Type() Type { return REGEXP_OBJ }
+
+// Class returns regexpClass
+func (r *Regexp) Class() RubyClass { return regexpClass }
+
+func (r *Regexp) hashKey() hashKey {
+	h := fnv.New64a()
+	h.Write([]byte(r.Source))
+	h.Write([]byte{byte(r.Options)})
+	return hashKey{Type: r.Type(), Value: h.Sum64()}
+}
+
+// match returns the byte offsets of the first match within s at or after
+// the byte offset from, followed by those of its groups, or nil
+func (r *Regexp) match(s string, from int) []int {
+	loc := r.find(s, from)
+	if !r.endBeforeNewline || !strings.HasSuffix(s, "\n") {
+		return loc
+	}
+	// RE2 has no lookahead to match `\Z` before the final newline, so the
+	// string is matched again without it
+	chomped := r.find(s[:len(s)-1], from)
+	if chomped != nil && (loc == nil || chomped[0] < loc[0]) {
+		return chomped
+	}
+	return loc
+}
+
+// find returns the first match of the translated regexp within s at or
+// after the byte offset from
+func (r *Regexp) find(s string, from int) []int {
+	for _, match := range r.regexp.FindAllStringSubmatchIndex(s, -1) {
+		if match[0] >= from {
+			return match
+		}
+	}
+	return nil
+}
+
+// names returns the names of the named groups in order of appearance
+func (r *Regexp) names() []string {
+	var names []string
+	for _, name := range r.regexp.SubexpNames() {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+var regexpOptionFlags = []struct {
+	bit  int
+	flag byte
+}{
+	{RegexpMultiline, 'm'},
+	{RegexpIgnoreCase, 'i'},
+	{RegexpExtended, 'x'},
+}
+
+// intervalPattern matches an interval quantifier like `{2}` or `{1,3}`
+var intervalPattern = regexp.MustCompile(`^\{(\d+|\d*,\d+|\d+,)\}`)
+
+// translateRegexp translates the Ruby regular expression source into the
+// RE2 syntax of Go's regexp package. Ruby's `^` and `$` always match at line
+// boundaries and its multiline option lets `.` match newlines, which maps
+// onto the Go flags `m` and `s`. Like in Ruby plain groups do not capture if
+// there are named groups. `\Z` is translated to `\z`, and reported so that
+// the caller can match it before a final newline. Constructs RE2 has no
+// equivalent for are reported as RegexpError.
+func translateRegexp(source string, options int) (string, bool, error) {
+	unsupported := func(construct string) (string, bool, error) {
+		return "", false, NewRegexpError("%s are not supported: /%s/", construct, source)
+	}
+	invalid := func(message string) (string, bool, error) {
+		return "", false, NewRegexpError("%s: /%s/", message, source)
+	}
+	var out strings.Builder
+	out.WriteString("(?m")
+	if options&RegexpIgnoreCase != 0 {
+		out.WriteByte('i')
+	}
+	if options&RegexpMultiline != 0 {
+		out.WriteByte('s')
+	}
+	out.WriteByte(')')
+	extended := options&RegexpExtended != 0
+	// groups holds the extended mode to restore at the end of each open
+	// group and the offset of the group within out
+	type group struct {
+		extended bool
+		start    int
+	}
+	var groups []group
+	// plainGroups holds the offsets after the opening parens of plain groups
+	var plainGroups []int
+	// atom is the offset of the last atom within out a quantifier applies to
+	atom := 0
+	named, endBeforeNewline := false, false
+	inClass := 0
+	for i := 0; i < len(source); i++ {
+		c := source[i]
+		var next byte
+		if i+1 < len(source) {
+			next = source[i+1]
+		}
+		if inClass == 0 && strings.IndexByte("*+?{|)", c) == -1 {
+			atom = out.Len()
+		}
+		switch {
+		case c == '{' && inClass == 0 && intervalPattern.MatchString(source[i:]):
+			interval := intervalPattern.FindString(source[i:])
+			i += len(interval) - 1
+			if !strings.HasPrefix(source[i+1:], "+") {
+				out.WriteString(interval)
+				break
+			}
+			// an interval followed by + is a repetition of the interval in
+			// Onigmo, which RE2 only accepts within a group
+			translated := out.String()
+			out.Reset()
+			out.WriteString(translated[:atom] + "(?:" + translated[atom:] + interval + ")")
+			for j, offset := range plainGroups {
+				if offset > atom {
+					plainGroups[j] += 3
+				}
+			}
+		case c == '\\':
+			if i+1 == len(source) {
+				return invalid("too short escape sequence")
+			}
+			i++
+			switch {
+			case '1' <= next && next <= '9' && inClass == 0:
+				return unsupported("backreferences")
+			case next == 'k' && inClass == 0:
+				return unsupported("backreferences")
+			case next == 'g' && inClass == 0:
+				return unsupported("subexpression calls")
+			case next == 'Z' && inClass == 0:
+				endBeforeNewline = true
+				out.WriteString(`\z`)
+			case next == 'G' || next == 'K' || next == 'R' || next == 'X':
+				return unsupported(fmt.Sprintf(`\%c escapes`, next))
+			case (next == 'p' || next == 'P') && strings.HasPrefix(source[i+1:], "{"):
+				end := strings.IndexByte(source[i+1:], '}')
+				if end == -1 {
+					return invalid("invalid character property name")
+				}
+				out.WriteString(`\` + source[i:i+end+2])
+				i += end + 1
+			case next == 'u':
+				codepoints, width := unicodeEscape(source[i:])
+				if codepoints == nil {
+					return invalid("invalid Unicode escape")
+				}
+				for _, codepoint := range codepoints {
+					out.WriteString(`\x{` + codepoint + `}`)
+				}
+				i += width
+			case next == 'h' && inClass > 0:
+				out.WriteString("0-9a-fA-F")
+			case next == 'h':
+				out.WriteString("[0-9a-fA-F]")
+			case next == 'H':
+				out.WriteString("[^0-9a-fA-F]")
+			case next >= utf8.RuneSelf:
+				// escaped multibyte characters stand for themselves
+				i--
+			default:
+				out.WriteByte('\\')
+				out.WriteByte(next)
+			}
+		case c == '[':
+			inClass++
+			out.WriteByte(c)
+			if next == '^' {
+				out.WriteByte(next)
+				i++
+				if i+1 < len(source) {
+					next = source[i+1]
+				}
+			}
+			if next == ']' {
+				// a leading bracket is part of the class
+				out.WriteString(`\]`)
+				i++
+			}
+		case c == ']' && inClass > 0:
+			inClass--
+			out.WriteByte(c)
+		case c == '&' && next == '&' && inClass > 0:
+			return unsupported("character class intersections")
+		case inClass > 0:
+			out.WriteByte(c)
+		case c == '(' && next == '?':
+			rest := source[i+2:]
+			switch {
+			case strings.HasPrefix(rest, "<=") || strings.HasPrefix(rest, "<!"):
+				return unsupported("lookbehind assertions")
+			case strings.HasPrefix(rest, "=") || strings.HasPrefix(rest, "!"):
+				return unsupported("lookahead assertions")
+			case strings.HasPrefix(rest, ">"):
+				return unsupported("atomic groups")
+			case strings.HasPrefix(rest, "~"):
+				return unsupported("absence operators")
+			case strings.HasPrefix(rest, "#"):
+				end := strings.IndexByte(rest, ')')
+				if end == -1 {
+					return invalid("end pattern in group")
+				}
+				i += end + 2
+			case strings.HasPrefix(rest, "<") || strings.HasPrefix(rest, "'"):
+				closer := map[byte]byte{'<': '>', '\'': '\''}[rest[0]]
+				end := strings.IndexByte(rest[1:], closer)
+				if end == -1 {
+					return invalid("invalid group name")
+				}
+				groups = append(groups, group{extended, out.Len()})
+				out.WriteString("(?P<" + rest[1:end+1] + ">")
+				named = true
+				i += end + 3
+			default:
+				end := strings.IndexAny(rest, ":)")
+				if end == -1 {
+					return invalid("end pattern in group")
+				}
+				flags := strings.SplitN(rest[:end], "-", 2)
+				if strings.Trim(rest[:end], "imx-") != "" || strings.Count(rest[:end], "-") > 1 {
+					return invalid("undefined group option")
+				}
+				if rest[end] == ':' {
+					groups = append(groups, group{extended, out.Len()})
+				}
+				if strings.Contains(flags[0], "x") {
+					extended = true
+				}
+				if len(flags) == 2 && strings.Contains(flags[1], "x") {
+					extended = false
+				}
+				// Go has no extended mode and calls Ruby's multiline mode s
+				goFlags := strings.NewReplacer("x", "", "m", "s").Replace(rest[:end])
+				goFlags = strings.TrimSuffix(goFlags, "-")
+				if goFlags != "" || rest[end] == ':' {
+					out.WriteString("(?" + goFlags + rest[end:end+1])
+				}
+				i += end + 2
+			}
+		case c == '(':
+			groups = append(groups, group{extended, out.Len()})
+			out.WriteByte(c)
+			plainGroups = append(plainGroups, out.Len())
+		case c == ')':
+			if len(groups) > 0 {
+				extended, atom = groups[len(groups)-1].extended, groups[len(groups)-1].start
+				groups = groups[:len(groups)-1]
+			}
+			out.WriteByte(c)
+		case extended && strings.IndexByte(" \t\n\r\f\v", c) != -1:
+		case extended && c == '#':
+			for i+1 < len(source) && source[i+1] != '\n' {
+				i++
+			}
+		case strings.IndexByte("*+?", c) != -1 && next == '+':
+			return unsupported("possessive quantifiers")
+		default:
+			out.WriteByte(c)
+		}
+	}
+	translated := out.String()
+	if !named {
+		return translated, endBeforeNewline, nil
+	}
+	var nonCapturing strings.Builder
+	start := 0
+	for _, offset := range plainGroups {
+		nonCapturing.WriteString(translated[start:offset])
+		nonCapturing.WriteString("?:")
+		start = offset
+	}
+	nonCapturing.WriteString(translated[start:])
+	return nonCapturing.String(), endBeforeNewline, nil
+}
+
+// unicodeEscape returns the hexadecimal codepoints of the Unicode escape
+// following `\` at the start of s, i.e. `u` followed by four hex digits or
+// by space separated codepoints within braces, and its width. It returns
+// nil if the escape is malformed.
+func unicodeEscape(s string) ([]string, int) {
+	isHex := func(s string) bool {
+		_, err := strconv.ParseUint(s, 16, 32)
+		return err == nil
+	}
+	if !strings.HasPrefix(s, "u{") {
+		if len(s) < 5 || !isHex(s[1:5]) {
+			return nil, 0
+		}
+		return []string{s[1:5]}, 4
+	}
+	end := strings.IndexByte(s, '}')
+	if end == -1 {
+		return nil, 0
+	}
+	codepoints := strings.Fields(s[2:end])
+	for _, codepoint := range codepoints {
+		if len(codepoint) > 6 || !isHex(codepoint) {
+			return nil, 0
+		}
+	}
+	if len(codepoints) == 0 {
+		return nil, 0
+	}
+	return codepoints, end
+}
+
+// regexpEscape escapes the regular expression metacharacters within s
+func regexpEscape(s string) string {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\n':
+			out.WriteString(`\n`)
+		case '\t':
+			out.WriteString(`\t`)
+		case '\r':
+			out.WriteString(`\r`)
+		case '\f':
+			out.WriteString(`\f`)
+		case '\v':
+			out.WriteString(`\v`)
+		case ' ':
+			out.WriteString(`\ `)
+		case '.', '*', '?', '+', '^', '$', '|', '(', ')', '[', ']', '{', '}', '\\', '/', '-':
+			out.WriteByte('\\')
+			out.WriteByte(c)
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+// matchTarget returns the string to match a regexp against, which is the
+// value of a String or the name of a Symbol. It returns false for nil.
+func matchTarget(obj RubyObject) (string, bool, error) {
+	switch obj := obj.(type) {
+	case *String:
+		return obj.Value, true, nil
+	case *Symbol:
+		return obj.Value, true, nil
+	case *nilObject:
+		return "", false, nil
+	default:
+		return "", false, NewImplicitConversionTypeError(&String{}, obj)
+	}
+}
+
+// characterOffset converts the character position pos within s into a byte
+// offset. Negative positions count from the end of s. It returns false if
+// pos is out of range.
+func characterOffset(s string, pos int) (int, bool) {
+	length := utf8.RuneCountInString(s)
+	if pos < 0 {
+		pos += length
+	}
+	if pos < 0 || pos > length {
+		return 0, false
+	}
+	offset := 0
+	for ; pos > 0; pos-- {
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		offset += size
+	}
+	return offset, true
+}
+
+// regexpSearch matches re against the string target starting at the
+// character position given as optional argument and sets the last match
+// `$~` accordingly. It returns nil if there is no match.
+func regexpSearch(context CallContext, re *Regexp, target RubyObject, args []RubyObject) (*MatchData, error) {
+	s, ok, err := matchTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	var match *MatchData
+	if ok {
+		from := 0
+		if len(args) > 0 {
+			pos, isInt := args[0].(*Integer)
+			if !isInt {
+				return nil, NewImplicitConversionTypeError(&Integer{}, args[0])
+			}
+			from, ok = characterOffset(s, int(pos.Value))
+		}
+		if ok {
+			if loc := re.match(s, from); loc != nil {
+				match = &MatchData{Regexp: re, Target: s, Offsets: loc}
+			}
+		}
+	}
+	setLastMatch(context, match)
+	return match, nil
+}
+
+// setLastMatch sets the last match `$~` to match, or nil if match is nil.
+// Like in MRI the last match is local to the current method and shared with
+// the blocks within it, so it is kept on the self of the current frame.
+func setLastMatch(context CallContext, match *MatchData) {
+	if context.Env() == nil {
+		return
+	}
+	if self, ok := frameSelf(context.Env()); ok {
+		self.LastMatch = match
+	}
+}
+
+// lastMatch returns the last match `$~` of the current frame of env
+func lastMatch(env Environment) RubyObject {
+	self, ok := frameSelf(env)
+	if !ok || self.LastMatch == nil {
+		return NIL
+	}
+	return self.LastMatch
+}
+
+func frameSelf(env Environment) (*Self, bool) {
+	self, _ := env.Get("self")
+	frame, ok := self.(*Self)
+	return frame, ok
+}
+
+// LastMatchReference returns the value of the global variables derived from
+// the last match, i.e. the last match `$~` itself, the match `$&`, the text
+// before and after it, $` and $', the last matched group `$+` and the
+// numbered groups `$1`, `$2` and so on. It returns false if name is not one
+// of them.
+func LastMatchReference(env Environment, name string) (RubyObject, bool) {
+	var group int
+	switch name {
+	case "$~":
+		return lastMatch(env), true
+	case "$&", "$`", "$'", "$+":
+	default:
+		n, err := strconv.Atoi(strings.TrimPrefix(name, "$"))
+		if err != nil || n < 1 {
+			return nil, false
+		}
+		group = n
+	}
+	match, ok := lastMatch(env).(*MatchData)
+	if !ok {
+		return NIL, true
+	}
+	switch name {
+	case "$`":
+		return &String{Value: match.Target[:match.Offsets[0]]}, true
+	case "$'":
+		return &String{Value: match.Target[match.Offsets[1]:]}, true
+	case "$+":
+		for group = match.size() - 1; group > 0 && match.Offsets[2*group] == -1; group-- {
+		}
+	}
+	return match.group(group), true
+}
+
+var regexpClassMethods = map[string]RubyMethod{
+	"escape":     withArity(1, publicMethod(regexpClassEscape)),
+	"quote":      withArity(1, publicMethod(regexpClassEscape)),
+	"union":      publicMethod(regexpClassUnion),
+	"last_match": publicMethod(regexpClassLastMatch),
+}
+
+var regexpMethods = map[string]RubyMethod{
+	"initialize": privateMethod(regexpInitialize),
+	"source":     withArity(0, publicMethod(regexpSource)),
+	"options":    withArity(0, publicMethod(regexpOptions)),
+	"casefold?":  withArity(0, publicMethod(regexpIsCasefold)),
+	"names":      withArity(0, publicMethod(regexpNames)),
+	"match":      publicMethod(regexpMatch),
+	"match?":     publicMethod(regexpIsMatch),
+	"=~":         withArity(1, publicMethod(regexpMatchOperator)),
+	"===":        withArity(1, publicMethod(regexpCaseEqual)),
+	"==":         withArity(1, publicMethod(regexpEqual)),
+	"eql?":       withArity(1, publicMethod(regexpEqual)),
+	"to_s":       withArity(0, publicMethod(regexpToS)),
+	"inspect":    withArity(0, publicMethod(regexpInspect)),
+}
+
+func regexpClassEscape(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s, ok, err := matchTarget(args[0])
+	if err != nil || !ok {
+		return nil, NewImplicitConversionTypeError(&String{}, args[0])
+	}
+	return &String{Value: regexpEscape(s)}, nil
+}
+
+func regexpClassUnion(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if len(args) == 1 {
+		if array, ok := args[0].(*Array); ok {
+			args = array.Elements
+		}
+	}
+	if len(args) == 0 {
+		// Ruby's never matching `(?!)` needs a lookahead RE2 does not have
+		return &Regexp{Source: "(?!)", regexp: regexp.MustCompile(`[^\x00-\x{10FFFF}]`)}, nil
+	}
+	if len(args) == 1 {
+		if re, ok := args[0].(*Regexp); ok {
+			return re, nil
+		}
+	}
+	patterns := make([]string, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case *Regexp:
+			patterns[i] = arg.toS()
+		case *String:
+			patterns[i] = regexpEscape(arg.Value)
+		default:
+			return nil, NewImplicitConversionTypeError(&String{}, arg)
+		}
+	}
+	return NewRegexp(strings.Join(patterns, "|"), 0)
+}
+
+func regexpClassLastMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if len(args) > 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	match, ok := lastMatch(context.Env()).(*MatchData)
+	if !ok {
+		return NIL, nil
+	}
+	if len(args) == 0 {
+		return match, nil
+	}
+	return matchDataIndex(&callContext{receiver: match, env: context.Env(), eval: context.Eval}, args[0])
+}
+
+func regexpInitialize(context CallContext, args ...RubyObject) (RubyObject, error) {
+	self, _ := context.Receiver().(*Self)
+	if len(args) < 1 || len(args) > 2 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	var source string
+	var options int
+	switch pattern := args[0].(type) {
+	case *Regexp:
+		source, options = pattern.Source, pattern.Options
+	case *String:
+		source = pattern.Value
+	default:
+		return nil, NewImplicitConversionTypeError(&String{}, pattern)
+	}
+	if len(args) == 2 {
+		switch option := args[1].(type) {
+		case *Integer:
+			options = int(option.Value)
+		case *String:
+			options = RegexpOptions(option.Value)
+			if strings.Trim(option.Value, "mix") != "" {
+				return nil, NewArgumentError("unknown regexp option: %s", option.Value)
+			}
+		default:
+			if option != NIL && option != FALSE {
+				options = RegexpIgnoreCase
+			}
+		}
+	}
+	re, err := NewRegexp(source, options)
+	if err != nil {
+		return nil, err
+	}
+	self.RubyObject = re
+	return self, nil
+}
+
+func regexpSource(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return &String{Value: context.Receiver().(*Regexp).Source}, nil
+}
+
+func regexpOptions(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return NewInteger(int64(context.Receiver().(*Regexp).Options)), nil
+}
+
+func regexpIsCasefold(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	return nativeBoolToBooleanObject(re.Options&RegexpIgnoreCase != 0), nil
+}
+
+func regexpNames(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return stringsToArray(context.Receiver().(*Regexp).names()), nil
+}
+
+func regexpMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	block, args, _ := extractBlockFromArgs(args)
+	if len(args) < 1 || len(args) > 2 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	match, err := regexpSearch(context, re, args[0], args[1:])
+	if err != nil {
+		return nil, err
+	}
+	if match == nil {
+		return NIL, nil
+	}
+	if block != nil {
+		return block.Call(context, match)
+	}
+	return match, nil
+}
+
+func regexpIsMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	if len(args) < 1 || len(args) > 2 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	s, ok, err := matchTarget(args[0])
+	if err != nil || !ok {
+		return FALSE, err
+	}
+	from := 0
+	if len(args) == 2 {
+		pos, isInt := args[1].(*Integer)
+		if !isInt {
+			return nil, NewImplicitConversionTypeError(&Integer{}, args[1])
+		}
+		if from, ok = characterOffset(s, int(pos.Value)); !ok {
+			return FALSE, nil
+		}
+	}
+	return nativeBoolToBooleanObject(re.match(s, from) != nil), nil
+}
+
+func regexpMatchOperator(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	match, err := regexpSearch(context, re, args[0], nil)
+	if err != nil {
+		return nil, err
+	}
+	if match == nil {
+		return NIL, nil
+	}
+	return NewInteger(int64(utf8.RuneCountInString(match.Target[:match.Offsets[0]]))), nil
+}
+
+func regexpCaseEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	switch args[0].(type) {
+	case *String, *Symbol:
+	default:
+		return FALSE, nil
+	}
+	match, err := regexpSearch(context, re, args[0], nil)
+	if err != nil {
+		return nil, err
+	}
+	return nativeBoolToBooleanObject(match != nil), nil
+}
+
+func regexpEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	other, ok := args[0].(*Regexp)
+	return nativeBoolToBooleanObject(ok && re.Source == other.Source && re.Options == other.Options), nil
+}
+
+// toS returns the regexp as group with its options, e.g. `(?i-mx:foo)`,
+// suitable to be embedded into another regexp
+func (r *Regexp) toS() string {
+	var on, off string
+	for _, option := range regexpOptionFlags {
+		if r.Options&option.bit != 0 {
+			on += string(option.flag)
+		} else {
+			off += string(option.flag)
+		}
+	}
+	if off != "" {
+		off = "-" + off
+	}
+	return "(?" + on + off + ":" + r.Source + ")"
+}
+
+func regexpToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return &String{Value: context.Receiver().(*Regexp).toS()}, nil
+}
+
+func regexpInspect(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return &String{Value: context.Receiver().(*Regexp).Inspect()}, nil
+}
+
+// A MatchData represents the result of matching a Regexp against a string.
+// Offsets holds the byte offsets of the match followed by those of its
+// groups, -1 for groups which did not participate in the match.
+type MatchData struct {
+	Regexp  *Regexp
+	Target  string
+	Offsets []int
+}
+
+// Inspect returns the match and its groups, e.g. `#<MatchData "ab" 1:"b">`
+func (m *MatchData) Inspect() string {
+	var out strings.Builder
+	out.WriteString("#<MatchData ")
+	out.WriteString(m.group(0).Inspect())
+	names := m.Regexp.regexp.SubexpNames()
+	for i := 1; i < m.size(); i++ {
+		name := names[i]
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		out.WriteString(" " + name + ":" + m.group(i).Inspect())
+	}
+	out.WriteByte('>')
+	return out.String()
+}
+
+// Type returns MATCH_DATA_OBJ
+func (m *MatchData) Type() Type { return MATCH_DATA_OBJ }
+
+// Class returns matchDataClass
+func (m *MatchData) Class() RubyClass { return matchDataClass }
+
+// size returns the number of groups including the whole match
+func (m *MatchData) size() int {
+	return len(m.Offsets) / 2
+}
+
+// group returns the text matched by the group n, nil if the group did not
+// participate in the match or does not exist
+func (m *MatchData) group(n int) RubyObject {
+	if n < 0 || n >= m.size() || m.Offsets[2*n] == -1 {
+		return NIL
+	}
+	return &String{Value: m.Target[m.Offsets[2*n]:m.Offsets[2*n+1]]}
+}
+
+// groups returns the texts matched by the groups from start on
+func (m *MatchData) groups(start int) *Array {
+	array := NewArray()
+	for i := start; i < m.size(); i++ {
+		array.Elements = append(array.Elements, m.group(i))
+	}
+	return array
+}
+
+// namedGroup returns the index of the last group named name which
+// participated in the match
+func (m *MatchData) namedGroup(name string) (int, bool) {
+	index, found := -1, false
+	for i, groupName := range m.Regexp.regexp.SubexpNames() {
+		if i > 0 && groupName == name {
+			found = true
+			if index == -1 || m.Offsets[2*i] != -1 {
+				index = i
+			}
+		}
+	}
+	return index, found
+}
+
+var matchDataMethods = map[string]RubyMethod{
+	"[]":             publicMethod(matchDataIndex),
+	"captures":       withArity(0, publicMethod(matchDataCaptures)),
+	"named_captures": withArity(0, publicMethod(matchDataNamedCaptures)),
+	"names":          withArity(0, publicMethod(matchDataNames)),
+	"to_a":           withArity(0, publicMethod(matchDataToA)),
+	"size":           withArity(0, publicMethod(matchDataSize)),
+	"length":         withArity(0, publicMethod(matchDataSize)),
+	"pre_match":      withArity(0, publicMethod(matchDataPreMatch)),
+	"post_match":     withArity(0, publicMethod(matchDataPostMatch)),
+	"begin":          withArity(1, publicMethod(matchDataBegin)),
+	"end":            withArity(1, publicMethod(matchDataEnd)),
+	"string":         withArity(0, publicMethod(matchDataString)),
+	"regexp":         withArity(0, publicMethod(matchDataRegexp)),
+	"to_s":           withArity(0, publicMethod(matchDataToS)),
+	"inspect":        withArity(0, publicMethod(matchDataInspect)),
+}
+
+// groupIndex returns the index of the group referenced by the Integer,
+// String or Symbol ref
+func (m *MatchData) groupIndex(ref RubyObject) (int, error) {
+	var name string
+	switch ref := ref.(type) {
+	case *Integer:
+		return int(ref.Value), nil
+	case *String:
+		name = ref.Value
+	case *Symbol:
+		name = ref.Value
+	default:
+		return 0, NewImplicitConversionTypeError(&Integer{}, ref)
+	}
+	index, ok := m.namedGroup(name)
+	if !ok {
+		return 0, NewIndexError("undefined group name reference: %s", name)
+	}
+	return index, nil
+}
+
+func matchDataIndex(context CallContext, args ...RubyObject) (RubyObject, error) {
+	match := context.Receiver().(*MatchData)
+	if len(args) != 1 {
+		return arrayIndex(&callContext{receiver: match.groups(0), env: context.Env(), eval: context.Eval}, args...)
+	}
+	if _, ok := args[0].(*Range); ok {
+		return arrayIndex(&callContext{receiver: match.groups(0), env: context.Env(), eval: context.Eval}, args...)
+	}
+	index, err := match.groupIndex(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 {
+		index += match.size()
+	}
+	return match.group(index), nil
+}
+
+func matchDataCaptures(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver().(*MatchData).groups(1), nil
+}
+
+func matchDataNamedCaptures(context CallContext, args ...RubyObject) (RubyObject, error) {
+	match := context.Receiver().(*MatchData)
+	captures := &Hash{}
+	for _, name := range match.Regexp.names() {
+		index, _ := match.namedGroup(name)
+		captures.Set(&String{Value: name}, match.group(index))
+	}
+	return captures, nil
+}
+
+func matchDataNames(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return stringsToArray(context.Receiver().(*MatchData).Regexp.names()), nil
+}
+
+func matchDataToA(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver().(*MatchData).groups(0), nil
+}
+
+func matchDataSize(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return NewInteger(int64(context.Receiver().(*MatchData).size())), nil
+}
+
+func matchDataPreMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	match := context.Receiver().(*MatchData)
+	return &String{Value: match.Target[:match.Offsets[0]]}, nil
+}
+
+func matchDataPostMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	match := context.Receiver().(*MatchData)
+	return &String{Value: match.Target[match.Offsets[1]:]}, nil
+}
+
+// groupOffset returns the character offset of the start, if end is false,
+// or the end of the group referenced by ref
+func groupOffset(match *MatchData, ref RubyObject, end bool) (RubyObject, error) {
+	index, err := match.groupIndex(ref)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= match.size() {
+		return nil, NewIndexError("index %d out of matches", index)
+	}
+	offset := match.Offsets[2*index]
+	if offset == -1 {
+		return NIL, nil
+	}
+	if end {
+		offset = match.Offsets[2*index+1]
+	}
+	return NewInteger(int64(utf8.RuneCountInString(match.Target[:offset]))), nil
+}
+
+func matchDataBegin(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return groupOffset(context.Receiver().(*MatchData), args[0], false)
+}
+
+func matchDataEnd(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return groupOffset(context.Receiver().(*MatchData), args[0], true)
+}
+
+func matchDataString(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return &String{Value: context.Receiver().(*MatchData).Target, frozen: true}, nil
+}
+
+func matchDataRegexp(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver().(*MatchData).Regexp, nil
+}
+
+func matchDataToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver().(*MatchData).group(0), nil
+}
+
+func matchDataInspect(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return &String{Value: context.Receiver().(*MatchData).Inspect()}, nil
+}
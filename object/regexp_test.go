@@ -0,0 +1,368 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/goruby/goruby/ast"
+)
+
+func mustRegexp(source string, options int) *Regexp {
+	re, err := NewRegexp(source, options)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
+func TestNewRegexp(t *testing.T) {
+	tests := []struct {
+		source string
+		err    error
+	}{
+		{`a+b`, nil},
+		{`(?<year>\d+)-(?<month>\d+)`, nil},
+		{`[\]\d]`, nil},
+		{`(a)\1`, NewRegexpError(`backreferences are not supported: /(a)\1/`)},
+		{`(?<a>x)\k<a>`, NewRegexpError(`backreferences are not supported: /(?<a>x)\k<a>/`)},
+		{`(?<=a)b`, NewRegexpError(`lookbehind assertions are not supported: /(?<=a)b/`)},
+		{`(?<!a)b`, NewRegexpError(`lookbehind assertions are not supported: /(?<!a)b/`)},
+		{`a(?=b)`, NewRegexpError(`lookahead assertions are not supported: /a(?=b)/`)},
+		{`a(?!b)`, NewRegexpError(`lookahead assertions are not supported: /a(?!b)/`)},
+		{`(?>a+)b`, NewRegexpError(`atomic groups are not supported: /(?>a+)b/`)},
+		{`a++`, NewRegexpError(`possessive quantifiers are not supported: /a++/`)},
+		{`a\G`, NewRegexpError(`\G escapes are not supported: /a\G/`)},
+		{`\p{L}+`, nil},
+		{`\u{61}+`, nil},
+		{`\d{2}+`, nil},
+		{`(ab){2}+`, nil},
+		{`a*+`, NewRegexpError(`possessive quantifiers are not supported: /a*+/`)},
+		{`\p{L`, NewRegexpError(`invalid character property name: /\p{L/`)},
+		{`\u{61`, NewRegexpError(`invalid Unicode escape: /\u{61/`)},
+		{`\u12`, NewRegexpError(`invalid Unicode escape: /\u12/`)},
+		{`[a-z&&[^aeiou]]`, NewRegexpError(`character class intersections are not supported: /[a-z&&[^aeiou]]/`)},
+		{`(a`, NewRegexpError(`missing closing ): /(a/`)},
+		{`a\`, NewRegexpError(`too short escape sequence: /a\/`)},
+		{`(?z)a`, NewRegexpError(`undefined group option: /(?z)a/`)},
+	}
+
+	for _, testCase := range tests {
+		_, err := NewRegexp(testCase.source, 0)
+
+		checkError(t, err, testCase.err)
+	}
+}
+
+func TestRegexpTranslation(t *testing.T) {
+	tests := []struct {
+		source  string
+		options int
+		input   string
+		match   RubyObject
+	}{
+		{`^b`, 0, "a\nb", &String{Value: "b"}},
+		{`a$`, 0, "a\nb", &String{Value: "a"}},
+		{`\Aa`, 0, "b\na", NIL},
+		{`a.b`, 0, "a\nb", NIL},
+		{`a.b`, RegexpMultiline, "a\nb", &String{Value: "a\nb"}},
+		{`A`, RegexpIgnoreCase, "bab", &String{Value: "a"}},
+		{`a b # comment`, RegexpExtended, "ab", &String{Value: "ab"}},
+		{`a[ ]b`, RegexpExtended, "a b", &String{Value: "a b"}},
+		{`\h+`, 0, "xff0z", &String{Value: "ff0"}},
+		{`[\h]+`, 0, "xA9z", &String{Value: "A9"}},
+		{`\H`, 0, "ax", &String{Value: "x"}},
+		{`(?i)a`, 0, "A", &String{Value: "A"}},
+		{`(?i:a)b`, 0, "ABAb", &String{Value: "Ab"}},
+		{`(?m:a.)b`, 0, "a\nb", &String{Value: "a\nb"}},
+		{`(?x: a )b`, 0, "ab", &String{Value: "ab"}},
+		{`(?-x: a ) b`, RegexpExtended, " a b", &String{Value: " a b"}},
+		{`(?:ab)+`, 0, "abab", &String{Value: "abab"}},
+		{`(?#comment)a`, 0, "a", &String{Value: "a"}},
+		{`[]a]+`, 0, "x]a]", &String{Value: "]a]"}},
+		{`[^]a]+`, 0, "]x", &String{Value: "x"}},
+		{`a\/b`, 0, "a/b", &String{Value: "a/b"}},
+		{`\p{L}+`, 0, "1äb2", &String{Value: "äb"}},
+		{`[\p{Lu}]+`, 0, "aBCd", &String{Value: "BC"}},
+		{`\u{61 62}+`, 0, "xabbab", &String{Value: "abb"}},
+		{`(ab){2}+`, 0, "abababab", &String{Value: "abababab"}},
+		{`a|b{2}+`, 0, "bbbb", &String{Value: "bbbb"}},
+		{`(?<n>a)(b){2}+`, 0, "abbbb", &String{Value: "abbbb"}},
+		{`\u00e4`, 0, "xä", &String{Value: "ä"}},
+		{`\d{2}+`, 0, "1234", &String{Value: "1234"}},
+		{`a\Z`, 0, "ba\n", &String{Value: "a"}},
+		{`a\Z`, 0, "ba", &String{Value: "a"}},
+		{`a\Z`, 0, "ba\n\n", NIL},
+		{`\n|a\Z`, 0, "a\n", &String{Value: "a"}},
+	}
+
+	for _, testCase := range tests {
+		re, err := NewRegexp(testCase.source, testCase.options)
+		checkError(t, err, nil)
+		if err != nil {
+			continue
+		}
+
+		var match RubyObject = NIL
+		if loc := re.match(testCase.input, 0); loc != nil {
+			match = &String{Value: testCase.input[loc[0]:loc[1]]}
+		}
+
+		checkResult(t, match, testCase.match)
+	}
+}
+
+func TestRegexpNamedGroups(t *testing.T) {
+	re := mustRegexp(`(?<a>x)(y)(?<b>z)`, 0)
+
+	match := &MatchData{Regexp: re, Target: "xyz", Offsets: re.match("xyz", 0)}
+
+	checkResult(t, match.groups(0), NewArray(&String{Value: "xyz"}, &String{Value: "x"}, &String{Value: "z"}))
+}
+
+func TestRegexpInspect(t *testing.T) {
+	tests := []struct {
+		regexp   *Regexp
+		expected string
+	}{
+		{mustRegexp(`a+b`, 0), `/a+b/`},
+		{mustRegexp(`a/b`, 0), `/a\/b/`},
+		{mustRegexp(`a\/b`, 0), `/a\/b/`},
+		{mustRegexp(`a`, RegexpIgnoreCase|RegexpExtended|RegexpMultiline), `/a/mix`},
+	}
+
+	for _, testCase := range tests {
+		actual := testCase.regexp.Inspect()
+
+		if actual != testCase.expected {
+			t.Logf("Expected inspect to equal %q, got %q\n", testCase.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestRegexpOptions(t *testing.T) {
+	tests := []struct {
+		flags   string
+		options int
+	}{
+		{"", 0},
+		{"i", RegexpIgnoreCase},
+		{"mix", RegexpIgnoreCase | RegexpExtended | RegexpMultiline},
+		{"on", 0},
+	}
+
+	for _, testCase := range tests {
+		options := RegexpOptions(testCase.flags)
+
+		if options != testCase.options {
+			t.Logf("Expected options of %q to equal %d, got %d\n", testCase.flags, testCase.options, options)
+			t.Fail()
+		}
+	}
+}
+
+func TestRegexpMethods(t *testing.T) {
+	re := mustRegexp(`l+`, 0)
+	named := mustRegexp(`(?<first>\w+) (?<last>\w+)`, 0)
+
+	tests := []struct {
+		receiver  *Regexp
+		method    string
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{re, "source", nil, &String{Value: "l+"}, nil},
+		{mustRegexp(`a`, RegexpIgnoreCase|RegexpMultiline), "options", nil, NewInteger(5), nil},
+		{mustRegexp(`a`, RegexpIgnoreCase), "to_s", nil, &String{Value: "(?i-mx:a)"}, nil},
+		{mustRegexp(`a`, RegexpIgnoreCase), "casefold?", nil, TRUE, nil},
+		{re, "inspect", nil, &String{Value: "/l+/"}, nil},
+		{re, "=~", []RubyObject{&String{Value: "héllo"}}, NewInteger(2), nil},
+		{re, "=~", []RubyObject{&String{Value: "abc"}}, NIL, nil},
+		{re, "=~", []RubyObject{NIL}, NIL, nil},
+		{re, "=~", []RubyObject{NewInteger(1)}, nil, NewImplicitConversionTypeError(&String{}, NewInteger(1))},
+		{re, "match?", []RubyObject{&String{Value: "hello"}}, TRUE, nil},
+		{re, "match?", []RubyObject{&String{Value: "hello"}, NewInteger(4)}, FALSE, nil},
+		{re, "match?", []RubyObject{NIL}, FALSE, nil},
+		{re, "match", []RubyObject{&String{Value: "abc"}}, NIL, nil},
+		{re, "===", []RubyObject{&String{Value: "hello"}}, TRUE, nil},
+		{re, "===", []RubyObject{NewSymbol("hello")}, TRUE, nil},
+		{re, "===", []RubyObject{NewInteger(1)}, FALSE, nil},
+		{re, "==", []RubyObject{mustRegexp(`l+`, 0)}, TRUE, nil},
+		{re, "==", []RubyObject{mustRegexp(`l+`, RegexpIgnoreCase)}, FALSE, nil},
+		{named, "names", nil, NewArray(&String{Value: "first"}, &String{Value: "last"}), nil},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: testCase.receiver, env: NewMainEnvironment()}
+
+		result, err := Send(context, testCase.method, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		if testCase.err == nil {
+			checkResult(t, result, testCase.result)
+		}
+	}
+}
+
+func TestRegexpClassMethods(t *testing.T) {
+	tests := []struct {
+		method    string
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{"escape", []RubyObject{&String{Value: "a.b*c d"}}, &String{Value: `a\.b\*c\ d`}, nil},
+		{"union", []RubyObject{&String{Value: "a."}, mustRegexp("b", RegexpIgnoreCase)}, mustRegexp(`a\.|(?i-mx:b)`, 0), nil},
+		{"union", []RubyObject{NewArray(&String{Value: "a"}, &String{Value: "b"})}, mustRegexp(`a|b`, 0), nil},
+		{"new", []RubyObject{&String{Value: "a+"}}, mustRegexp(`a+`, 0), nil},
+		{"new", []RubyObject{&String{Value: "a"}, TRUE}, mustRegexp(`a`, RegexpIgnoreCase), nil},
+		{"new", []RubyObject{&String{Value: "a"}, NewInteger(RegexpExtended)}, mustRegexp(`a`, RegexpExtended), nil},
+		{"new", []RubyObject{&String{Value: "a"}, &String{Value: "mi"}}, mustRegexp(`a`, RegexpIgnoreCase|RegexpMultiline), nil},
+		{"new", []RubyObject{&String{Value: "(a)\\1"}}, nil, NewRegexpError(`backreferences are not supported: /(a)\1/`)},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: regexpClass, env: NewMainEnvironment()}
+
+		result, err := Send(context, testCase.method, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		if testCase.err == nil {
+			if self, ok := result.(*Self); ok {
+				result = self.RubyObject
+			}
+			checkResult(t, result, testCase.result)
+		}
+	}
+}
+
+func TestRegexpLastMatch(t *testing.T) {
+	env := NewMainEnvironment()
+	context := &callContext{receiver: mustRegexp(`(\d)(x)?(\d)`, 0), env: env}
+
+	_, err := Send(context, "=~", &String{Value: "ab12cd"})
+	checkError(t, err, nil)
+
+	tests := []struct {
+		name   string
+		result RubyObject
+	}{
+		{"$&", &String{Value: "12"}},
+		{"$`", &String{Value: "ab"}},
+		{"$'", &String{Value: "cd"}},
+		{"$+", &String{Value: "2"}},
+		{"$1", &String{Value: "1"}},
+		{"$2", NIL},
+		{"$3", &String{Value: "2"}},
+		{"$4", NIL},
+	}
+
+	for _, testCase := range tests {
+		result, ok := LastMatchReference(env, testCase.name)
+
+		if !ok {
+			t.Logf("Expected %s to refer to the last match\n", testCase.name)
+			t.Fail()
+		}
+		checkResult(t, result, testCase.result)
+	}
+
+	t.Run("other globals", func(t *testing.T) {
+		for _, name := range []string{"$0", "$foo", "$:"} {
+			if _, ok := LastMatchReference(env, name); ok {
+				t.Logf("Expected %s not to refer to the last match\n", name)
+				t.Fail()
+			}
+		}
+	})
+	t.Run("after failed match", func(t *testing.T) {
+		_, err := Send(context, "=~", &String{Value: "abc"})
+		checkError(t, err, nil)
+
+		lastMatch, _ := LastMatchReference(env, "$~")
+		checkResult(t, lastMatch, NIL)
+		result, _ := LastMatchReference(env, "$1")
+		checkResult(t, result, NIL)
+	})
+}
+
+func TestMatchDataMethods(t *testing.T) {
+	re := mustRegexp(`(?<first>\w+) (?<last>\w+)(?<bang>!)?`, 0)
+	match := &MatchData{Regexp: re, Target: "hi john smith.", Offsets: re.match("hi john smith.", 0)}
+
+	tests := []struct {
+		method    string
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{"[]", []RubyObject{NewInteger(0)}, &String{Value: "hi john"}, nil},
+		{"[]", []RubyObject{NewInteger(-2)}, &String{Value: "john"}, nil},
+		{"[]", []RubyObject{NewInteger(3)}, NIL, nil},
+		{"[]", []RubyObject{NewInteger(5)}, NIL, nil},
+		{"[]", []RubyObject{&String{Value: "first"}}, &String{Value: "hi"}, nil},
+		{"[]", []RubyObject{NewSymbol("last")}, &String{Value: "john"}, nil},
+		{"[]", []RubyObject{NewSymbol("middle")}, nil, NewIndexError("undefined group name reference: middle")},
+		{"[]", []RubyObject{NewInteger(1), NewInteger(2)}, NewArray(&String{Value: "hi"}, &String{Value: "john"}), nil},
+		{"captures", nil, NewArray(&String{Value: "hi"}, &String{Value: "john"}, NIL), nil},
+		{"to_a", nil, NewArray(&String{Value: "hi john"}, &String{Value: "hi"}, &String{Value: "john"}, NIL), nil},
+		{"names", nil, NewArray(&String{Value: "first"}, &String{Value: "last"}, &String{Value: "bang"}), nil},
+		{"size", nil, NewInteger(4), nil},
+		{"pre_match", nil, &String{Value: ""}, nil},
+		{"post_match", nil, &String{Value: " smith."}, nil},
+		{"begin", []RubyObject{NewInteger(2)}, NewInteger(3), nil},
+		{"end", []RubyObject{NewSymbol("last")}, NewInteger(7), nil},
+		{"begin", []RubyObject{NewInteger(3)}, NIL, nil},
+		{"begin", []RubyObject{NewInteger(4)}, nil, NewIndexError("index 4 out of matches")},
+		{"to_s", nil, &String{Value: "hi john"}, nil},
+		{"inspect", nil, &String{Value: `#<MatchData "hi john" first:"hi" last:"john" bang:nil>`}, nil},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: match, env: NewMainEnvironment()}
+
+		result, err := Send(context, testCase.method, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		if testCase.err == nil {
+			checkResult(t, result, testCase.result)
+		}
+	}
+
+	t.Run("named_captures", func(t *testing.T) {
+		context := &callContext{receiver: match, env: NewMainEnvironment()}
+
+		result, err := matchDataNamedCaptures(context)
+
+		checkError(t, err, nil)
+		expected := &Hash{}
+		expected.Set(&String{Value: "first"}, &String{Value: "hi"})
+		expected.Set(&String{Value: "last"}, &String{Value: "john"})
+		expected.Set(&String{Value: "bang"}, NIL)
+		checkResult(t, result, expected)
+	})
+}
+
+func TestRegexpMatchWithBlock(t *testing.T) {
+	var yielded RubyObject
+	eval := func(node ast.Node, env Environment) (RubyObject, error) {
+		yielded, _ = env.Get("m")
+		return &String{Value: "block result"}, nil
+	}
+	block := &Proc{
+		Parameters: []*ast.FunctionParameter{&ast.FunctionParameter{Name: &ast.Identifier{Value: "m"}}},
+		Body:       &ast.BlockStatement{Statements: []ast.Statement{}},
+		Env:        NewEnvironment(),
+	}
+	re := mustRegexp(`b`, 0)
+	context := &callContext{receiver: re, env: NewMainEnvironment(), eval: eval}
+
+	result, err := regexpMatch(context, &String{Value: "abc"}, block)
+
+	checkError(t, err, nil)
+	checkResult(t, result, &String{Value: "block result"})
+	checkResult(t, yielded, &MatchData{Regexp: re, Target: "abc", Offsets: []int{1, 2}})
+}
@@ -21,7 +21,15 @@ const (
 	CLASS_INSTANCE_OBJ Type = "CLASS"
 	ARRAY_OBJ          Type = "ARRAY"
 	HASH_OBJ           Type = "HASH"
+	RANGE_OBJ          Type = "RANGE"
+	REGEXP_OBJ         Type = "REGEXP"
+	MATCH_DATA_OBJ     Type = "MATCH_DATA"
+	RANDOM_OBJ         Type = "RANDOM"
+	METHOD_OBJ         Type = "METHOD"
+	LAZY_OBJ           Type = "LAZY"
+	ENUMERATOR_OBJ     Type = "ENUMERATOR"
 	INTEGER_OBJ        Type = "INTEGER"
+	FLOAT_OBJ          Type = "FLOAT"
 	STRING_OBJ         Type = "STRING"
 	SYMBOL_OBJ         Type = "SYMBOL"
 	BOOLEAN_OBJ        Type = "BOOLEAN"
@@ -97,57 +105,124 @@ func (rv *ReturnValue) Class() RubyClass { return rv.Value.Class() }
 
 type functionParameters []*FunctionParameter
 
-func (f functionParameters) defaultParamCount() int {
-	count := 0
+// signature sorts the parameters the way MRI binds arguments to them:
+// mandatory parameters before optional ones, optional ones, the rest
+// parameter and the mandatory parameters after them. Keyword and block
+// parameters are bound independently from their position.
+type signature struct {
+	pre, optional, post []*FunctionParameter
+	rest                *FunctionParameter
+	keywords            []*FunctionParameter
+	keywordRest         *FunctionParameter
+	block               *FunctionParameter
+}
+
+func (f functionParameters) signature() signature {
+	var sig signature
 	for _, p := range f {
-		if p.Default != nil {
-			count++
+		switch p.Kind {
+		case ast.RestParameter:
+			sig.rest = p
+		case ast.KeywordParameter:
+			sig.keywords = append(sig.keywords, p)
+		case ast.KeywordRestParameter:
+			sig.keywordRest = p
+		case ast.BlockParameter:
+			sig.block = p
+		default:
+			switch {
+			case sig.rest != nil || (p.Default == nil && len(sig.optional) > 0):
+				sig.post = append(sig.post, p)
+			case p.Default != nil:
+				sig.optional = append(sig.optional, p)
+			default:
+				sig.pre = append(sig.pre, p)
+			}
 		}
 	}
-	return count
+	return sig
 }
-func (f functionParameters) mandatoryParams() []*FunctionParameter {
-	params := make([]*FunctionParameter, 0)
-	for _, p := range f {
+
+func (s signature) mandatoryCount() int { return len(s.pre) + len(s.post) }
+
+func (s signature) acceptsKeywords() bool { return len(s.keywords) > 0 || s.keywordRest != nil }
+
+func (s signature) requiredKeywords() []*FunctionParameter {
+	required := make([]*FunctionParameter, 0)
+	for _, p := range s.keywords {
 		if p.Default == nil {
-			params = append(params, p)
+			required = append(required, p)
 		}
 	}
-	return params
+	return required
 }
-func (f functionParameters) optionalParams() []*FunctionParameter {
-	params := make([]*FunctionParameter, 0)
-	for _, p := range f {
-		if p.Default != nil {
-			params = append(params, p)
-		}
+
+// arity returns the arity as reported by Method#arity: the number of
+// mandatory arguments, or its one's complement if arguments are optional
+func (s signature) arity() int {
+	required := s.mandatoryCount()
+	requiredKeywords := len(s.requiredKeywords())
+	if requiredKeywords > 0 {
+		required++
 	}
-	return params
+	if len(s.optional) > 0 || s.rest != nil || (requiredKeywords == 0 && s.acceptsKeywords()) {
+		return -required - 1
+	}
+	return required
 }
-func (f functionParameters) separateDefaultParams() ([]*FunctionParameter, []*FunctionParameter) {
-	mandatory, defaults := make([]*FunctionParameter, 0), make([]*FunctionParameter, 0)
-	for _, p := range f {
-		if p.Default != nil {
-			defaults = append(defaults, p)
-		} else {
-			mandatory = append(mandatory, p)
-		}
+
+// arityError returns the ArgumentError for a call with given positional
+// arguments, mentioning the required keywords like MRI does
+func (s signature) arityError(given int) *ArgumentError {
+	max := s.mandatoryCount() + len(s.optional)
+	if s.rest != nil {
+		max = -1
 	}
-	return mandatory, defaults
+	err := NewWrongNumberOfArgumentsRangeError(s.mandatoryCount(), max, given)
+	required := s.requiredKeywords()
+	if len(required) == 0 {
+		return err
+	}
+	names := make([]string, len(required))
+	for i, p := range required {
+		names[i] = p.Name
+	}
+	label := "required keyword"
+	if len(names) > 1 {
+		label += "s"
+	}
+	err.message = fmt.Sprintf("%s; %s: %s)", strings.TrimSuffix(err.message, ")"), label, strings.Join(names, ", "))
+	return err
 }
 
 // FunctionParameter represents a parameter within a function
 type FunctionParameter struct {
-	Name    string
-	Default RubyObject
+	Name    string         // empty for anonymous rest parameters
+	Default ast.Expression // evaluated on every call missing the argument
+	Kind    ast.ParameterKind
 }
 
 func (f *FunctionParameter) String() string {
 	var out bytes.Buffer
+	switch f.Kind {
+	case ast.RestParameter:
+		out.WriteString("*")
+	case ast.KeywordRestParameter:
+		out.WriteString("**")
+	case ast.BlockParameter:
+		out.WriteString("&")
+	}
 	out.WriteString(f.Name)
-	if f.Default != nil {
+	switch {
+	case f.Kind == ast.KeywordParameter:
+		out.WriteString(":")
+		if f.Default != nil {
+			out.WriteString(" ")
+			out.WriteString(f.Default.String())
+		}
+	case f.Default != nil:
 		out.WriteString(" = ")
-		out.WriteString(f.Default.Inspect())
+		out.WriteString(f.Default.String())
 	}
 	return out.String()
 }
@@ -158,6 +233,7 @@ type Function struct {
 	Body             *ast.BlockStatement
 	Env              Environment
 	MethodVisibility MethodVisibility
+	Frame            Frame // the frame the body is evaluated in
 }
 
 // String returns the function literal
@@ -179,18 +255,31 @@ func (f *Function) String() string {
 // Call implements the RubyMethod interface. It evaluates f.Body and returns its result
 func (f *Function) Call(context CallContext, args ...RubyObject) (RubyObject, error) {
 	block, arguments, _ := extractBlockFromArgs(args)
-	defaultParams := functionParameters(f.Parameters).defaultParamCount()
-	if len(arguments) < len(f.Parameters)-defaultParams || len(arguments) > len(f.Parameters) {
-		return nil, NewWrongNumberOfArgumentsError(len(f.Parameters), len(arguments))
-	}
-	params, err := f.populateParameters(arguments)
+	params, defaults, err := f.populateParameters(arguments, block)
 	if err != nil {
 		return nil, err
 	}
 	contextSelf, _ := context.Env().Get("self")
-	contextSelfObject := contextSelf.(*Self)
-	extendedEnv := f.extendFunctionEnv(contextSelfObject, params, block)
-	evaluated, err := context.Eval(f.Body, extendedEnv)
+	self, _ := contextSelf.(*Self)
+	// methods sent to another object run with that object as self
+	switch receiver := context.Receiver().(type) {
+	case nil:
+	case *Self:
+		self = receiver
+	default:
+		if self == nil || self.RubyObject != receiver {
+			self = &Self{RubyObject: receiver, Name: receiver.Inspect()}
+		}
+	}
+	extendedEnv := f.extendFunctionEnv(self, params, block)
+	for _, param := range defaults {
+		value, err := context.Eval(param.Default, extendedEnv)
+		if err != nil {
+			return nil, err
+		}
+		extendedEnv.Set(param.Name, value)
+	}
+	evaluated, err := context.Eval(frameBody(f.Body, f.Frame), extendedEnv)
 	if err != nil {
 		return nil, err
 	}
@@ -202,40 +291,137 @@ func (f *Function) Visibility() MethodVisibility {
 	return f.MethodVisibility
 }
 
-func (f *Function) populateParameters(args []RubyObject) (map[string]RubyObject, error) {
-	if len(args) > len(f.Parameters) {
-		return nil, NewWrongNumberOfArgumentsError(len(f.Parameters), len(args))
-	}
+// populateParameters binds args and block to the parameters of f. Like in
+// MRI a trailing Hash is taken as keyword arguments if f accepts keywords.
+// Unless f has a keyword rest parameter collecting any key, all its keys
+// must be Symbols. The parameters not given an argument are returned in
+// order, as their defaults must be evaluated once the others are bound;
+// until then they are nil.
+func (f *Function) populateParameters(args []RubyObject, block *Proc) (map[string]RubyObject, []*FunctionParameter, error) {
+	sig := functionParameters(f.Parameters).signature()
 	params := make(map[string]RubyObject)
+	var defaults []*FunctionParameter
 
-	mandatory, defaults := functionParameters(f.Parameters).separateDefaultParams()
+	var keywords *Hash
+	if sig.acceptsKeywords() && len(args) > 0 {
+		if hash, ok := args[len(args)-1].(*Hash); ok && (sig.keywordRest != nil || hasSymbolKeys(hash)) {
+			keywords = hash
+			args = args[:len(args)-1]
+		}
+	}
 
-	if len(args) < len(mandatory)-len(defaults) || len(args) > len(f.Parameters) {
-		return nil, NewWrongNumberOfArgumentsError(len(f.Parameters), len(args))
+	mandatory := sig.mandatoryCount()
+	if len(args) < mandatory || (sig.rest == nil && len(args) > mandatory+len(sig.optional)) {
+		return nil, nil, sig.arityError(len(args))
 	}
 
-	if len(args) == len(f.Parameters) {
-		for paramIdx, param := range f.Parameters {
-			params[param.Name] = args[paramIdx]
+	for i, param := range sig.pre {
+		params[param.Name] = args[i]
+	}
+	for i, param := range sig.post {
+		params[param.Name] = args[len(args)-len(sig.post)+i]
+	}
+	remaining := args[len(sig.pre) : len(args)-len(sig.post)]
+	for i, param := range sig.optional {
+		if i < len(remaining) {
+			params[param.Name] = remaining[i]
+			continue
+		}
+		params[param.Name] = NIL
+		defaults = append(defaults, param)
+	}
+	if sig.rest != nil {
+		rest := NewArray()
+		if len(remaining) > len(sig.optional) {
+			rest.Elements = append(rest.Elements, remaining[len(sig.optional):]...)
 		}
-		return params, nil
+		if sig.rest.Name != "" {
+			params[sig.rest.Name] = rest
+		}
+	}
+
+	keywordDefaults, err := sig.populateKeywords(params, keywords)
+	if err != nil {
+		return nil, nil, err
 	}
+	defaults = append(defaults, keywordDefaults...)
 
-	parameters := append(mandatory, defaults...)
+	if sig.block != nil {
+		if block != nil {
+			params[sig.block.Name] = block
+		} else {
+			params[sig.block.Name] = NIL
+		}
+	}
+	return params, defaults, nil
+}
 
-	for paramIdx, param := range parameters {
-		if paramIdx >= len(args) {
-			params[param.Name] = param.Default
+// populateKeywords binds the pairs of keywords to the keyword parameters and
+// returns the keyword parameters which are left to their defaults
+func (s signature) populateKeywords(params map[string]RubyObject, keywords *Hash) ([]*FunctionParameter, error) {
+	if !s.acceptsKeywords() {
+		return nil, nil
+	}
+	unknown := NewHash()
+	if keywords != nil {
+		for _, pair := range keywords.pairs {
+			unknown.Set(pair.Key, pair.Value)
+		}
+	}
+	var missing []string
+	var defaults []*FunctionParameter
+	for _, param := range s.keywords {
+		key := NewSymbol(param.Name)
+		if value, ok := unknown.Get(key); ok {
+			params[param.Name] = value
+			unknown.remove(nil, key)
+			continue
+		}
+		if param.Default == nil {
+			missing = append(missing, key.Inspect())
 			continue
 		}
-		params[param.Name] = args[paramIdx]
+		params[param.Name] = NIL
+		defaults = append(defaults, param)
+	}
+	if len(missing) == 1 {
+		return nil, NewArgumentError("missing keyword: %s", missing[0])
+	}
+	if len(missing) > 1 {
+		return nil, NewArgumentError("missing keywords: %s", strings.Join(missing, ", "))
+	}
+	if s.keywordRest != nil {
+		if s.keywordRest.Name != "" {
+			params[s.keywordRest.Name] = unknown
+		}
+		return defaults, nil
+	}
+	if unknown.Len() == 0 {
+		return defaults, nil
+	}
+	names := make([]string, unknown.Len())
+	for i, key := range unknown.Keys() {
+		names[i] = key.Inspect()
+	}
+	if len(names) == 1 {
+		return nil, NewArgumentError("unknown keyword: %s", names[0])
+	}
+	return nil, NewArgumentError("unknown keywords: %s", strings.Join(names, ", "))
+}
+
+// hasSymbolKeys reports whether all keys of hash are Symbols
+func hasSymbolKeys(hash *Hash) bool {
+	for _, key := range hash.Keys() {
+		if _, ok := key.(*Symbol); !ok {
+			return false
+		}
 	}
-	return params, nil
+	return true
 }
 
 func (f *Function) extendFunctionEnv(context *Self, params map[string]RubyObject, block *Proc) Environment {
 	// encapsulate the block within a new self, but with the same object
-	funcSelf := &Self{RubyObject: context.RubyObject, Name: context.Name, Block: block}
+	funcSelf := &Self{RubyObject: context.RubyObject, Name: context.Name, Block: block, Method: f}
 	env := NewEnclosedEnvironment(f.Env)
 	env.Set("self", funcSelf)
 	for k, v := range params {
@@ -255,9 +441,11 @@ func (f *Function) unwrapReturnValue(obj RubyObject) RubyObject {
 // the RubyObject and is just meant to indicate that the given object is
 // self in the given context.
 type Self struct {
-	RubyObject        // The encapsuled object acting as self
-	Block      *Proc  // the block given to the current execution binding
-	Name       string // The name of self in this context
+	RubyObject            // The encapsuled object acting as self
+	Block      *Proc      // the block given to the current execution binding
+	Name       string     // The name of self in this context
+	Method     *Function  // the method executed in the current binding, if any
+	LastMatch  *MatchData // the last match `$~` of the current binding, if any
 }
 
 // Type returns SELF
@@ -109,7 +109,7 @@ func TestFunctionCall(t *testing.T) {
 		}
 
 		{
-			expected := &Self{RubyObject: &Integer{Value: 42}, Name: "context self"}
+			expected := &Self{RubyObject: &Integer{Value: 42}, Name: "context self", Method: function}
 			actual, _ := evalEnv.Get("self")
 			if !reflect.DeepEqual(expected, actual) {
 				t.Logf("Expected Eval env self to equal\n%+v\n\tgot\n%+v\n", expected, actual)
@@ -121,6 +121,33 @@ func TestFunctionCall(t *testing.T) {
 			}
 		}
 	})
+	t.Run("uses the receiver as self for CallContext#Eval", func(t *testing.T) {
+		contextEnv := NewEnvironment()
+		contextEnv.Set("self", &Self{RubyObject: &Integer{Value: 42}, Name: "context self"})
+		var evalEnv Environment
+		context := &callContext{
+			env:      contextEnv,
+			receiver: &String{Value: "receiver"},
+			eval: func(node ast.Node, env Environment) (RubyObject, error) {
+				evalEnv = env
+				return nil, nil
+			},
+		}
+
+		function := &Function{
+			Parameters: []*FunctionParameter{},
+			Env:        NewEnvironment(),
+		}
+
+		mustCall(function.Call(context))
+
+		expected := &Self{RubyObject: &String{Value: "receiver"}, Name: `"receiver"`, Method: function}
+		actual, _ := evalEnv.Get("self")
+		if !reflect.DeepEqual(expected, actual) {
+			t.Logf("Expected Eval env self to equal\n%+v\n\tgot\n%+v\n", expected, actual)
+			t.Fail()
+		}
+	})
 	t.Run("puts the Call args into the env for CallContext#Eval", func(t *testing.T) {
 		contextEnv := NewEnvironment()
 		contextEnv.Set("self", &Self{RubyObject: &Integer{Value: 42}, Name: "context self"})
@@ -128,6 +155,9 @@ func TestFunctionCall(t *testing.T) {
 		context := &callContext{
 			env: contextEnv,
 			eval: func(node ast.Node, env Environment) (RubyObject, error) {
+				if literal, ok := node.(*ast.IntegerLiteral); ok {
+					return NewInteger(literal.Value), nil
+				}
 				evalEnv = env
 				return nil, nil
 			},
@@ -173,10 +203,9 @@ func TestFunctionCall(t *testing.T) {
 			}
 		})
 		t.Run("with default params", func(t *testing.T) {
-			t.Skip()
 			function := &Function{
 				Parameters: []*FunctionParameter{
-					&FunctionParameter{Name: "foo", Default: &Integer{Value: 12}},
+					&FunctionParameter{Name: "foo", Default: &ast.IntegerLiteral{Value: 12}},
 					&FunctionParameter{Name: "bar"},
 					&FunctionParameter{Name: "qux"},
 				},
@@ -228,6 +257,97 @@ func TestFunctionCall(t *testing.T) {
 			}
 		})
 	})
+	t.Run("binds rest, keyword and block params", func(t *testing.T) {
+		var evalEnv Environment
+		context := &callContext{
+			env: NewMainEnvironment(),
+			eval: func(node ast.Node, env Environment) (RubyObject, error) {
+				if literal, ok := node.(*ast.IntegerLiteral); ok {
+					return NewInteger(literal.Value), nil
+				}
+				evalEnv = env
+				return nil, nil
+			},
+		}
+
+		function := &Function{
+			Parameters: []*FunctionParameter{
+				&FunctionParameter{Name: "a"},
+				&FunctionParameter{Name: "b", Kind: ast.RestParameter},
+				&FunctionParameter{Name: "c", Kind: ast.KeywordParameter},
+				&FunctionParameter{Name: "d", Default: &ast.IntegerLiteral{Value: 4}, Kind: ast.KeywordParameter},
+				&FunctionParameter{Name: "e", Kind: ast.KeywordRestParameter},
+				&FunctionParameter{Name: "f", Kind: ast.BlockParameter},
+			},
+		}
+		keywords := NewHash()
+		keywords.Set(NewSymbol("c"), NewInteger(3))
+		keywords.Set(NewSymbol("x"), NewInteger(5))
+		block := &Proc{}
+
+		mustCall(function.Call(context, NewInteger(1), NewInteger(2), keywords, block))
+
+		rest := NewHash()
+		rest.Set(NewSymbol("x"), NewInteger(5))
+		expected := map[string]RubyObject{
+			"a": NewInteger(1),
+			"b": NewArray(NewInteger(2)),
+			"c": NewInteger(3),
+			"d": NewInteger(4),
+			"e": rest,
+			"f": block,
+		}
+		for name, value := range expected {
+			actual, _ := evalEnv.Get(name)
+			if !reflect.DeepEqual(value, actual) {
+				t.Logf("Expected %q to equal\n%v\n\tgot\n%v\n", name, value, actual)
+				t.Fail()
+			}
+		}
+	})
+	t.Run("returns MRI like argument errors", func(t *testing.T) {
+		context := &callContext{
+			env:  NewMainEnvironment(),
+			eval: func(ast.Node, Environment) (RubyObject, error) { return nil, nil },
+		}
+		keywords := NewHash()
+		keywords.Set(NewSymbol("x"), NIL)
+
+		tests := []struct {
+			parameters []*FunctionParameter
+			args       []RubyObject
+			err        error
+		}{
+			{
+				[]*FunctionParameter{{Name: "a"}, {Name: "b", Default: &ast.Nil{}}},
+				[]RubyObject{},
+				NewWrongNumberOfArgumentsRangeError(1, 2, 0),
+			},
+			{
+				[]*FunctionParameter{{Name: "a"}, {Name: "b", Kind: ast.RestParameter}},
+				[]RubyObject{},
+				NewWrongNumberOfArgumentsRangeError(1, -1, 0),
+			},
+			{
+				[]*FunctionParameter{{Name: "a", Kind: ast.KeywordParameter}},
+				[]RubyObject{},
+				NewArgumentError("missing keyword: :a"),
+			},
+			{
+				[]*FunctionParameter{{Name: "a", Default: &ast.Nil{}, Kind: ast.KeywordParameter}},
+				[]RubyObject{keywords},
+				NewArgumentError("unknown keyword: :x"),
+			},
+		}
+
+		for _, testCase := range tests {
+			function := &Function{Parameters: testCase.parameters}
+
+			_, err := function.Call(context, testCase.args...)
+
+			checkError(t, err, testCase.err)
+		}
+	})
 	t.Run("returns the object returned by CallContext#Eval", func(t *testing.T) {
 		t.Run("vanilla object", func(t *testing.T) {
 			context := &callContext{
@@ -352,7 +472,7 @@ func TestFunctionCall(t *testing.T) {
 
 		t.Run("with default arguments", func(t *testing.T) {
 			function.Parameters = []*FunctionParameter{
-				&FunctionParameter{Name: "x", Default: TRUE},
+				&FunctionParameter{Name: "x", Default: &ast.Boolean{Value: true}},
 				&FunctionParameter{Name: "y"},
 			}
 
@@ -25,13 +25,51 @@ func Send(context CallContext, method string, args ...RubyObject) (RubyObject, e
 	}
 
 	methodMissingArgs := append(
-		[]RubyObject{&Symbol{method}},
+		[]RubyObject{NewSymbol(method)},
 		args...,
 	)
 
 	return methodMissing(context, methodMissingArgs...)
 }
 
+// SendSuper sends the method current to the implementation found above the
+// class defining current within the ancestry tree of the receiver, as the
+// Ruby keyword `super` does.
+func SendSuper(context CallContext, current *Function, args ...RubyObject) (RubyObject, error) {
+	receiver := context.Receiver()
+	class := receiver.Class()
+	var method string
+	for class != nil && method == "" {
+		for name, fn := range class.Methods().GetAll() {
+			if fn == RubyMethod(current) {
+				method = name
+				break
+			}
+		}
+		class = class.SuperClass()
+	}
+	if method == "" {
+		return nil, errors.WithStack(NewRuntimeError("super called outside of method"))
+	}
+	for ; class != nil; class = class.SuperClass() {
+		if fn, ok := class.Methods().Get(method); ok {
+			return fn.Call(context, args...)
+		}
+	}
+	return nil, errors.WithStack(NewNoSuperMethodError(receiver, method))
+}
+
+// respondTo reports whether obj has a method called method within its
+// ancestry tree, regardless of its visibility
+func respondTo(obj RubyObject, method string) bool {
+	for class := obj.Class(); class != nil; class = class.SuperClass() {
+		if _, ok := class.Methods().Get(method); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // AddMethod adds a method to a given object. It returns the object with the modified method set
 func AddMethod(context RubyObject, methodName string, method *Function) RubyObject {
 	objectToExtend := context
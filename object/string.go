@@ -3,6 +3,12 @@ package object
 import (
 	"fmt"
 	"hash/fnv"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 var stringClass RubyClassObject = newClass(
@@ -19,13 +25,15 @@ func init() {
 	classes.Set("String", stringClass)
 }
 
-// String represents a string in Ruby
+// String represents a string in Ruby. Its Value is changed in place by the
+// mutating methods like `<<` or `upcase!`, unless the string is frozen.
 type String struct {
-	Value string
+	Value  string
+	frozen bool
 }
 
-// Inspect returns the Value
-func (s *String) Inspect() string { return s.Value }
+// Inspect returns the Value quoted and escaped the way MRI does
+func (s *String) Inspect() string { return quote(s.Value) }
 
 // Type returns STRING_OBJ
 func (s *String) Type() Type { return STRING_OBJ }
@@ -40,8 +48,63 @@ func (s *String) hashKey() hashKey {
 	return hashKey{Type: s.Type(), Value: h.Sum64()}
 }
 
+// checkFrozen returns a FrozenError if s must not be modified
+func (s *String) checkFrozen() error {
+	if s.frozen {
+		return NewFrozenError(s)
+	}
+	return nil
+}
+
+var inspectEscapes = map[rune]string{
+	'"':  `\"`,
+	'\\': `\\`,
+	'\n': `\n`,
+	'\t': `\t`,
+	'\r': `\r`,
+	'\f': `\f`,
+	'\v': `\v`,
+	'\a': `\a`,
+	'\b': `\b`,
+	0x1b: `\e`,
+	0x7f: `\x7F`,
+}
+
+// quote returns s within double quotes, with quotes, backslashes, the
+// beginning of interpolations and unprintable characters escaped
+func quote(s string) string {
+	var out strings.Builder
+	out.WriteByte('"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch escape, ok := inspectEscapes[r]; {
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&out, "\\x%02X", s[i])
+		case ok:
+			out.WriteString(escape)
+		case r == '#' && i+1 < len(s) && strings.IndexByte("{$@", s[i+1]) != -1:
+			out.WriteString(`\#`)
+		case !unicode.IsPrint(r) && r < 0x10000:
+			fmt.Fprintf(&out, "\\u%04X", r)
+		case !unicode.IsPrint(r):
+			fmt.Fprintf(&out, "\\u{%X}", r)
+		default:
+			out.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	out.WriteByte('"')
+	return out.String()
+}
+
 func stringify(obj RubyObject) (string, error) {
-	stringObj, err := Send(NewCallContext(nil, obj), "to_s")
+	return convertToString(NewCallContext(nil, obj), obj)
+}
+
+// convertToString returns the value of the String obj.to_s returns when
+// called within context
+func convertToString(context CallContext, obj RubyObject) (string, error) {
+	stringObj, err := Send(&callContext{receiver: obj, env: context.Env(), eval: context.Eval}, "to_s")
 	if err != nil {
 		return "", NewTypeError(
 			fmt.Sprintf(
@@ -67,9 +130,76 @@ func stringify(obj RubyObject) (string, error) {
 var stringClassMethods = map[string]RubyMethod{}
 
 var stringMethods = map[string]RubyMethod{
-	"initialize": privateMethod(stringInitialize),
-	"to_s":       withArity(0, publicMethod(stringToS)),
-	"+":          withArity(1, publicMethod(stringAdd)),
+	"initialize":  privateMethod(stringInitialize),
+	"to_s":        withArity(0, publicMethod(stringToS)),
+	"to_str":      withArity(0, publicMethod(stringToS)),
+	"to_i":        publicMethod(stringToI),
+	"to_f":        withArity(0, publicMethod(stringToF)),
+	"to_sym":      withArity(0, publicMethod(stringToSym)),
+	"intern":      withArity(0, publicMethod(stringToSym)),
+	"+":           withArity(1, publicMethod(stringAdd)),
+	"*":           withArity(1, publicMethod(stringMul)),
+	"%":           withArity(1, publicMethod(stringFormat)),
+	"==":          withArity(1, publicMethod(stringEqual)),
+	"eql?":        withArity(1, publicMethod(stringEqual)),
+	"<=>":         withArity(1, publicMethod(stringSpaceship)),
+	"<<":          withArity(1, publicMethod(stringAppend)),
+	"concat":      publicMethod(stringConcat),
+	"length":      withArity(0, publicMethod(stringLength)),
+	"size":        withArity(0, publicMethod(stringLength)),
+	"empty?":      withArity(0, publicMethod(stringIsEmpty)),
+	"[]":          publicMethod(stringIndex),
+	"slice":       publicMethod(stringIndex),
+	"index":       publicMethod(stringIndexOf),
+	"rindex":      publicMethod(stringRindexOf),
+	"include?":    withArity(1, publicMethod(stringInclude)),
+	"start_with?": publicMethod(stringStartWith),
+	"end_with?":   publicMethod(stringEndWith),
+	"split":       publicMethod(stringSplit),
+	"scan":        publicMethod(stringScan),
+	"=~":          withArity(1, publicMethod(stringMatchOperator)),
+	"match":       publicMethod(stringMatch),
+	"match?":      publicMethod(stringIsMatch),
+	"chars":       withArity(0, publicMethod(stringChars)),
+	"bytes":       withArity(0, publicMethod(stringBytes)),
+	"lines":       withArity(0, publicMethod(stringLines)),
+	"each_char":   publicMethod(stringEachChar),
+	"center":      publicMethod(stringCenter),
+	"ljust":       publicMethod(stringLjust),
+	"rjust":       publicMethod(stringRjust),
+	"sub":         publicMethod(stringSub),
+	"sub!":        publicMethod(stringSubBang),
+	"gsub":        publicMethod(stringGsub),
+	"gsub!":       publicMethod(stringGsubBang),
+	"tr":          withArity(2, publicMethod(stringTr)),
+	"tr!":         withArity(2, publicMethod(stringTrBang)),
+	"upcase":      stringTransformation(strings.ToUpper),
+	"upcase!":     stringMutation(strings.ToUpper),
+	"downcase":    stringTransformation(strings.ToLower),
+	"downcase!":   stringMutation(strings.ToLower),
+	"capitalize":  stringTransformation(capitalize),
+	"capitalize!": stringMutation(capitalize),
+	"swapcase":    stringTransformation(swapcase),
+	"swapcase!":   stringMutation(swapcase),
+	"strip":       stringTransformation(strip),
+	"strip!":      stringMutation(strip),
+	"lstrip":      stringTransformation(lstrip),
+	"lstrip!":     stringMutation(lstrip),
+	"rstrip":      stringTransformation(rstrip),
+	"rstrip!":     stringMutation(rstrip),
+	"chomp":       stringTransformation(chomp),
+	"chomp!":      stringMutation(chomp),
+	"chop":        stringTransformation(chop),
+	"chop!":       stringMutation(chop),
+	"reverse":     stringTransformation(reverse),
+	"reverse!":    stringMutation(reverse),
+	"succ":        stringTransformation(successor),
+	"succ!":       stringMutation(successor),
+	"next":        stringTransformation(successor),
+	"next!":       stringMutation(successor),
+	"freeze":      withArity(0, publicMethod(stringFreeze)),
+	"frozen?":     withArity(0, publicMethod(stringIsFrozen)),
+	"dup":         withArity(0, publicMethod(stringDup)),
 }
 
 func stringInitialize(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -90,9 +220,200 @@ func stringInitialize(context CallContext, args ...RubyObject) (RubyObject, erro
 	}
 }
 
+// stringTransformation returns a method returning a copy of the receiver
+// transformed by fn
+func stringTransformation(fn func(string) string) RubyMethod {
+	return withArity(0, publicMethod(func(context CallContext, args ...RubyObject) (RubyObject, error) {
+		s := context.Receiver().(*String)
+		return &String{Value: fn(s.Value)}, nil
+	}))
+}
+
+// stringMutation returns a method transforming the receiver in place by fn.
+// Like in MRI the method returns nil if nothing changed.
+func stringMutation(fn func(string) string) RubyMethod {
+	return withArity(0, publicMethod(func(context CallContext, args ...RubyObject) (RubyObject, error) {
+		s := context.Receiver().(*String)
+		return s.replace(fn(s.Value))
+	}))
+}
+
+// replace sets the value of s to value. It returns nil if the value did not
+// change, s otherwise.
+func (s *String) replace(value string) (RubyObject, error) {
+	if err := s.checkFrozen(); err != nil {
+		return nil, err
+	}
+	if value == s.Value {
+		return NIL, nil
+	}
+	s.Value = value
+	return s, nil
+}
+
+const asciiWhitespace = " \t\n\v\f\r"
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	first, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(first)) + strings.ToLower(s[size:])
+}
+
+func swapcase(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsUpper(r) {
+			return unicode.ToLower(r)
+		}
+		return unicode.ToUpper(r)
+	}, s)
+}
+
+func strip(s string) string  { return lstrip(rstrip(s)) }
+func lstrip(s string) string { return strings.TrimLeft(s, asciiWhitespace) }
+func rstrip(s string) string { return strings.TrimRight(s, asciiWhitespace+"\x00") }
+
+func chomp(s string) string {
+	switch {
+	case strings.HasSuffix(s, "\r\n"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "\n"), strings.HasSuffix(s, "\r"):
+		return s[:len(s)-1]
+	default:
+		return s
+	}
+}
+
+func chop(s string) string {
+	if strings.HasSuffix(s, "\r\n") {
+		return s[:len(s)-2]
+	}
+	_, size := utf8.DecodeLastRuneInString(s)
+	return s[:len(s)-size]
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// successor returns the successor of s as defined by String#succ. The
+// rightmost alphanumeric character is incremented, carrying over to the
+// alphanumerics on its left, e.g. "az" becomes "ba" and "zz" becomes "aaa".
+// Strings without alphanumerics increment their rightmost character.
+func successor(s string) string {
+	b := []byte(s)
+	last := -1
+	for i := len(b) - 1; i >= 0; i-- {
+		if isAlphanumericByte(b[i]) {
+			last = i
+			break
+		}
+	}
+	if last == -1 {
+		for i := len(b) - 1; i >= 0; i-- {
+			b[i]++
+			if b[i] != 0 {
+				return string(b)
+			}
+		}
+		if len(b) == 0 {
+			return ""
+		}
+		return "\x01" + string(b)
+	}
+	var carry byte
+	for i := last; i >= 0; i-- {
+		if !isAlphanumericByte(b[i]) {
+			continue
+		}
+		switch b[i] {
+		case 'z':
+			b[i], carry = 'a', 'a'
+		case 'Z':
+			b[i], carry = 'A', 'A'
+		case '9':
+			b[i], carry = '0', '1'
+		default:
+			b[i]++
+			return string(b)
+		}
+		last = i
+	}
+	return string(b[:last]) + string(carry) + string(b[last:])
+}
+
+func isAlphanumericByte(c byte) bool {
+	return isDigitByte(c) || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
 func stringToS(context CallContext, args ...RubyObject) (RubyObject, error) {
 	str := context.Receiver().(*String)
-	return &String{str.Value}, nil
+	return &String{Value: str.Value}, nil
+}
+
+var leadingFloat = regexp.MustCompile(`^[+-]?\d+(_\d+)*(\.\d+(_\d+)*)?([eE][+-]?\d+)?`)
+
+func stringToI(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	base := int64(10)
+	switch len(args) {
+	case 0:
+	case 1:
+		b, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(&Integer{}, args[0])
+		}
+		base = b.Value
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	if base < 2 || base > 36 {
+		return nil, NewArgumentError("invalid radix %d", base)
+	}
+	str := strings.TrimLeft(s.Value, asciiWhitespace)
+	sign := ""
+	if str != "" && (str[0] == '-' || str[0] == '+') {
+		sign, str = str[:1], str[1:]
+	}
+	prefixes := map[int64]string{2: "0b", 8: "0o", 16: "0x"}
+	if prefix, ok := prefixes[base]; ok && len(str) > 2 && strings.EqualFold(str[:2], prefix) {
+		str = str[2:]
+	}
+	var digits strings.Builder
+	for i, r := range str {
+		if r == '_' && i > 0 && i+1 < len(str) && str[i-1] != '_' {
+			continue
+		}
+		if !unicode.IsDigit(r) && !unicode.IsLetter(r) || r > unicode.MaxASCII {
+			break
+		}
+		if d, _ := strconv.ParseInt(string(r), 36, 64); d >= base {
+			break
+		}
+		digits.WriteRune(r)
+	}
+	value, ok := new(big.Int).SetString(sign+digits.String(), int(base))
+	if !ok {
+		return NewInteger(0), nil
+	}
+	return NewBigInteger(value), nil
+}
+
+func stringToF(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	literal := leadingFloat.FindString(strings.TrimLeft(s.Value, asciiWhitespace))
+	value, _ := strconv.ParseFloat(strings.Replace(literal, "_", "", -1), 64)
+	return NewFloat(value), nil
+}
+
+func stringToSym(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	return NewSymbol(s.Value), nil
 }
 
 func stringAdd(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -101,5 +422,882 @@ func stringAdd(context CallContext, args ...RubyObject) (RubyObject, error) {
 	if !ok {
 		return nil, NewImplicitConversionTypeError(add, args[0])
 	}
-	return &String{s.Value + add.Value}, nil
+	return &String{Value: s.Value + add.Value}, nil
+}
+
+func stringMul(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	times, ok := args[0].(*Integer)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(times, args[0])
+	}
+	if times.Value < 0 {
+		return nil, NewArgumentError("negative argument")
+	}
+	return &String{Value: strings.Repeat(s.Value, int(times.Value))}, nil
+}
+
+func stringFormat(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	var formatted string
+	var err error
+	switch arg := args[0].(type) {
+	case *Array:
+		formatted, err = format(s.Value, arg.Elements, nil)
+	case *Hash:
+		formatted, err = format(s.Value, []RubyObject{arg}, arg)
+	default:
+		formatted, err = format(s.Value, args, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &String{Value: formatted}, nil
+}
+
+func stringEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	other, ok := args[0].(*String)
+	if !ok || s.Value != other.Value {
+		return FALSE, nil
+	}
+	return TRUE, nil
+}
+
+func stringSpaceship(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	other, ok := args[0].(*String)
+	if !ok {
+		return NIL, nil
+	}
+	return NewInteger(int64(strings.Compare(s.Value, other.Value))), nil
+}
+
+func stringAppend(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	if err := s.checkFrozen(); err != nil {
+		return nil, err
+	}
+	switch arg := args[0].(type) {
+	case *String:
+		s.Value += arg.Value
+	case *Integer:
+		if arg.IsBig() || arg.Value < 0 || !utf8.ValidRune(rune(arg.Value)) {
+			return nil, NewRangeError("%s out of char range", arg.Inspect())
+		}
+		s.Value += string(rune(arg.Value))
+	default:
+		return nil, NewImplicitConversionTypeError(&String{}, arg)
+	}
+	return s, nil
+}
+
+func stringConcat(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	if err := s.checkFrozen(); err != nil {
+		return nil, err
+	}
+	var appended strings.Builder
+	for _, arg := range args {
+		str, ok := arg.(*String)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(&String{}, arg)
+		}
+		appended.WriteString(str.Value)
+	}
+	s.Value += appended.String()
+	return s, nil
+}
+
+func stringLength(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	return NewInteger(int64(utf8.RuneCountInString(s.Value))), nil
+}
+
+func stringIsEmpty(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	return nativeBoolToBooleanObject(s.Value == ""), nil
+}
+
+func stringIndex(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	runes := []rune(s.Value)
+	switch len(args) {
+	case 1:
+		switch arg := args[0].(type) {
+		case *Integer:
+			index, ok := normalizeIndex(arg.Value, len(runes))
+			if !ok || index == len(runes) {
+				return NIL, nil
+			}
+			return &String{Value: string(runes[index])}, nil
+		case *String:
+			if !strings.Contains(s.Value, arg.Value) {
+				return NIL, nil
+			}
+			return &String{Value: arg.Value}, nil
+		case *Range:
+			start, length, ok, err := arg.indices(len(runes))
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return NIL, nil
+			}
+			return &String{Value: string(runes[start : start+length])}, nil
+		case *Regexp:
+			return stringIndexRegexp(context, arg, NewInteger(0))
+		default:
+			return nil, NewImplicitConversionTypeError(&Integer{}, arg)
+		}
+	case 2:
+		if re, ok := args[0].(*Regexp); ok {
+			return stringIndexRegexp(context, re, args[1])
+		}
+		start, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(start, args[0])
+		}
+		length, ok := args[1].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(length, args[1])
+		}
+		index, ok := normalizeIndex(start.Value, len(runes))
+		if !ok || length.Value < 0 {
+			return NIL, nil
+		}
+		end := len(runes)
+		if length.Value < int64(end-index) {
+			end = index + int(length.Value)
+		}
+		return &String{Value: string(runes[index:end])}, nil
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+}
+
+// stringIndexRegexp returns the group referenced by ref of the first match
+// of re within the receiver and sets the last match `$~` accordingly
+func stringIndexRegexp(context CallContext, re *Regexp, ref RubyObject) (RubyObject, error) {
+	match, err := regexpSearch(context, re, context.Receiver(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if match == nil {
+		return NIL, nil
+	}
+	return matchDataIndex(&callContext{receiver: match, env: context.Env(), eval: context.Eval}, ref)
+}
+
+// normalizeIndex resolves the possibly negative index into a sequence of
+// length elements. It returns false if index lies outside of the sequence,
+// which may be extended by one element at its end.
+func normalizeIndex(index int64, length int) (int, bool) {
+	if index < 0 {
+		index += int64(length)
+	}
+	if index < 0 || index > int64(length) {
+		return 0, false
+	}
+	return int(index), true
+}
+
+// stringSearchArguments returns the substring and the rune offset given to
+// String#index and String#rindex
+func stringSearchArguments(args []RubyObject, defaultOffset int, length int) (string, int, bool, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return "", 0, false, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	sub, ok := args[0].(*String)
+	if !ok {
+		return "", 0, false, NewImplicitConversionTypeError(&String{}, args[0])
+	}
+	if len(args) == 1 {
+		return sub.Value, defaultOffset, true, nil
+	}
+	offset, ok := args[1].(*Integer)
+	if !ok {
+		return "", 0, false, NewImplicitConversionTypeError(offset, args[1])
+	}
+	index, ok := normalizeIndex(offset.Value, length)
+	if !ok && offset.Value > 0 {
+		return sub.Value, length, true, nil
+	}
+	return sub.Value, index, ok, nil
+}
+
+func stringIndexOf(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	runes := []rune(s.Value)
+	sub, offset, ok, err := stringSearchArguments(args, 0, len(runes))
+	if err != nil {
+		return nil, err
+	}
+	if !ok || offset > len(runes) {
+		return NIL, nil
+	}
+	index := strings.Index(string(runes[offset:]), sub)
+	if index == -1 {
+		return NIL, nil
+	}
+	return NewInteger(int64(offset + utf8.RuneCountInString(string(runes[offset:])[:index]))), nil
+}
+
+func stringRindexOf(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	runes := []rune(s.Value)
+	sub, offset, ok, err := stringSearchArguments(args, len(runes), len(runes))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return NIL, nil
+	}
+	end := offset + utf8.RuneCountInString(sub)
+	if end > len(runes) {
+		end = len(runes)
+	}
+	index := strings.LastIndex(string(runes[:end]), sub)
+	if index == -1 {
+		return NIL, nil
+	}
+	return NewInteger(int64(utf8.RuneCountInString(s.Value[:index]))), nil
+}
+
+func stringInclude(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	sub, ok := args[0].(*String)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(sub, args[0])
+	}
+	return nativeBoolToBooleanObject(strings.Contains(s.Value, sub.Value)), nil
+}
+
+func stringStartWith(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	for _, arg := range args {
+		prefix, ok := arg.(*String)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(prefix, arg)
+		}
+		if strings.HasPrefix(s.Value, prefix.Value) {
+			return TRUE, nil
+		}
+	}
+	return FALSE, nil
+}
+
+func stringEndWith(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	for _, arg := range args {
+		suffix, ok := arg.(*String)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(suffix, arg)
+		}
+		if strings.HasSuffix(s.Value, suffix.Value) {
+			return TRUE, nil
+		}
+	}
+	return FALSE, nil
+}
+
+func stringSplit(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	if len(args) > 2 {
+		return nil, NewWrongNumberOfArgumentsError(2, len(args))
+	}
+	limit := 0
+	if len(args) == 2 {
+		l, ok := args[1].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(l, args[1])
+		}
+		limit = int(l.Value)
+	}
+	n := -1
+	if limit > 0 {
+		n = limit
+	}
+	var fields []string
+	var pattern RubyObject = NIL
+	if len(args) > 0 {
+		pattern = args[0]
+	}
+	switch pattern := pattern.(type) {
+	case *nilObject:
+		fields = splitWhitespace(s.Value, n)
+	case *String:
+		switch pattern.Value {
+		case " ":
+			fields = splitWhitespace(s.Value, n)
+		case "":
+			fields = splitChars(s.Value, n)
+		default:
+			fields = strings.SplitN(s.Value, pattern.Value, n)
+		}
+	case *Regexp:
+		fields = splitRegexp(s.Value, pattern, n)
+	default:
+		return nil, NewWrongArgumentTypeError(&String{}, pattern)
+	}
+	if s.Value == "" {
+		fields = nil
+	}
+	if limit == 0 {
+		for len(fields) > 0 && fields[len(fields)-1] == "" {
+			fields = fields[:len(fields)-1]
+		}
+	}
+	return stringsToArray(fields), nil
+}
+
+// splitRegexp splits s at the matches of re into at most n fields if n is
+// positive. The texts matched by groups are added as fields of their own.
+// An empty match splits off a single character, but not at the start of a
+// field.
+func splitRegexp(s string, re *Regexp, n int) []string {
+	if n == 1 {
+		return []string{s}
+	}
+	var fields []string
+	start, pos, splits := 0, 0, 0
+	for pos <= len(s) {
+		loc := re.match(s, pos)
+		if loc == nil {
+			break
+		}
+		if loc[0] == loc[1] {
+			if loc[0] >= len(s) {
+				break
+			}
+			if loc[0] == start {
+				_, size := utf8.DecodeRuneInString(s[loc[0]:])
+				pos = loc[0] + size
+				continue
+			}
+		}
+		fields = append(fields, s[start:loc[0]])
+		for i := 2; i < len(loc); i += 2 {
+			if loc[i] != -1 {
+				fields = append(fields, s[loc[i]:loc[i+1]])
+			}
+		}
+		start, pos = loc[1], loc[1]
+		splits++
+		if n > 0 && splits >= n-1 {
+			break
+		}
+	}
+	return append(fields, s[start:])
+}
+
+// splitWhitespace splits s at runs of whitespace, ignoring leading
+// whitespace, into at most n fields if n is positive
+func splitWhitespace(s string, n int) []string {
+	var fields []string
+	s = lstrip(s)
+	for s != "" {
+		if len(fields) == n-1 {
+			return append(fields, s)
+		}
+		end := strings.IndexAny(s, asciiWhitespace)
+		if end == -1 {
+			return append(fields, s)
+		}
+		fields = append(fields, s[:end])
+		s = lstrip(s[end:])
+		if s == "" {
+			fields = append(fields, "")
+		}
+	}
+	return fields
+}
+
+// splitChars splits s into its characters, with the last field holding the
+// remainder if n is positive
+func splitChars(s string, n int) []string {
+	var fields []string
+	for s != "" {
+		if len(fields) == n-1 {
+			return append(fields, s)
+		}
+		_, size := utf8.DecodeRuneInString(s)
+		fields = append(fields, s[:size])
+		s = s[size:]
+	}
+	return fields
+}
+
+func stringsToArray(values []string) *Array {
+	array := NewArray()
+	for _, value := range values {
+		array.Elements = append(array.Elements, &String{Value: value})
+	}
+	return array
+}
+
+func stringChars(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	return stringsToArray(splitChars(s.Value, -1)), nil
+}
+
+func stringBytes(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	array := NewArray()
+	for i := 0; i < len(s.Value); i++ {
+		array.Elements = append(array.Elements, NewInteger(int64(s.Value[i])))
+	}
+	return array, nil
+}
+
+func stringLines(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	var lines []string
+	for rest := s.Value; rest != ""; {
+		end := strings.IndexByte(rest, '\n') + 1
+		if end == 0 {
+			end = len(rest)
+		}
+		lines = append(lines, rest[:end])
+		rest = rest[end:]
+	}
+	return stringsToArray(lines), nil
+}
+
+func stringEachChar(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	block, remainingArgs, ok := extractBlockFromArgs(args)
+	if len(remainingArgs) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(remainingArgs))
+	}
+	if !ok {
+		return newEnumerator(context, "each_char"), nil
+	}
+	for _, char := range splitChars(s.Value, -1) {
+		if _, err := block.Call(context, &String{Value: char}); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// stringJustification returns the width and the padding given to
+// String#center, String#ljust and String#rjust
+func stringJustification(args []RubyObject) (int, []rune, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return 0, nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	width, ok := args[0].(*Integer)
+	if !ok {
+		return 0, nil, NewImplicitConversionTypeError(width, args[0])
+	}
+	pad := " "
+	if len(args) == 2 {
+		padStr, ok := args[1].(*String)
+		if !ok {
+			return 0, nil, NewImplicitConversionTypeError(padStr, args[1])
+		}
+		if padStr.Value == "" {
+			return 0, nil, NewArgumentError("zero width padding")
+		}
+		pad = padStr.Value
+	}
+	return int(width.Value), []rune(pad), nil
+}
+
+// padding returns n characters taken from pad, repeating it as necessary
+func padding(pad []rune, n int) string {
+	out := make([]rune, n)
+	for i := range out {
+		out[i] = pad[i%len(pad)]
+	}
+	return string(out)
+}
+
+func stringCenter(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	width, pad, err := stringJustification(args)
+	if err != nil {
+		return nil, err
+	}
+	missing := width - utf8.RuneCountInString(s.Value)
+	if missing <= 0 {
+		return &String{Value: s.Value}, nil
+	}
+	left := missing / 2
+	return &String{Value: padding(pad, left) + s.Value + padding(pad, missing-left)}, nil
+}
+
+func stringLjust(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	width, pad, err := stringJustification(args)
+	if err != nil {
+		return nil, err
+	}
+	missing := width - utf8.RuneCountInString(s.Value)
+	if missing <= 0 {
+		return &String{Value: s.Value}, nil
+	}
+	return &String{Value: s.Value + padding(pad, missing)}, nil
+}
+
+func stringRjust(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	width, pad, err := stringJustification(args)
+	if err != nil {
+		return nil, err
+	}
+	missing := width - utf8.RuneCountInString(s.Value)
+	if missing <= 0 {
+		return &String{Value: s.Value}, nil
+	}
+	return &String{Value: padding(pad, missing) + s.Value}, nil
+}
+
+// stringMatcher returns a function finding the next match of pattern within
+// a string at or after the byte offset from. The match is returned as the
+// byte offsets of the match followed by those of its groups, or nil.
+func stringMatcher(pattern RubyObject) (func(s string, from int) []int, error) {
+	if re, ok := pattern.(*Regexp); ok {
+		return re.match, nil
+	}
+	str, ok := pattern.(*String)
+	if !ok {
+		return nil, NewWrongArgumentTypeError(&String{}, pattern)
+	}
+	return func(s string, from int) []int {
+		index := strings.Index(s[from:], str.Value)
+		if index == -1 {
+			return nil
+		}
+		return []int{from + index, from + index + len(str.Value)}
+	}, nil
+}
+
+// expandReplacement replaces the references to the match `\0` and `\&` and
+// to the groups `\1` to `\9` within replacement with the matched text
+func expandReplacement(replacement string, s string, match []int) string {
+	if !strings.Contains(replacement, "\\") {
+		return replacement
+	}
+	var out strings.Builder
+	for i := 0; i < len(replacement); i++ {
+		c := replacement[i]
+		if c != '\\' || i+1 == len(replacement) {
+			out.WriteByte(c)
+			continue
+		}
+		i++
+		switch ref := replacement[i]; {
+		case ref == '&':
+			out.WriteString(s[match[0]:match[1]])
+		case '0' <= ref && ref <= '9':
+			group := int(ref - '0')
+			if 2*group+1 < len(match) && match[2*group] != -1 {
+				out.WriteString(s[match[2*group]:match[2*group+1]])
+			}
+		case ref == '\\':
+			out.WriteByte('\\')
+		default:
+			out.WriteByte('\\')
+			out.WriteByte(ref)
+		}
+	}
+	return out.String()
+}
+
+// substitute replaces the first or, if global is true, every match of the
+// pattern given in args within s. The replacement is either given in args
+// or computed by the block within args from the matched text.
+func substitute(context CallContext, s string, global bool, args []RubyObject) (string, error) {
+	block, args, _ := extractBlockFromArgs(args)
+	if len(args) < 1 || len(args) > 2 || len(args) == 1 && block == nil {
+		return "", NewWrongNumberOfArgumentsError(2, len(args))
+	}
+	var replacement *String
+	if len(args) == 2 {
+		var ok bool
+		replacement, ok = args[1].(*String)
+		if !ok {
+			return "", NewImplicitConversionTypeError(&String{}, args[1])
+		}
+	}
+	match, err := stringMatcher(args[0])
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	pos := 0
+	for pos <= len(s) {
+		loc := match(s, pos)
+		if loc == nil {
+			break
+		}
+		out.WriteString(s[pos:loc[0]])
+		if replacement != nil {
+			out.WriteString(expandReplacement(replacement.Value, s, loc))
+		} else {
+			if re, ok := args[0].(*Regexp); ok {
+				setLastMatch(context, &MatchData{Regexp: re, Target: s, Offsets: loc})
+			}
+			result, err := block.Call(context, &String{Value: s[loc[0]:loc[1]]})
+			if err != nil {
+				return "", err
+			}
+			str, err := stringify(result)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(str)
+		}
+		pos = loc[1]
+		if loc[0] == loc[1] {
+			// step over one character to not match the empty string again
+			if pos == len(s) {
+				pos++
+				break
+			}
+			_, size := utf8.DecodeRuneInString(s[pos:])
+			out.WriteString(s[pos : pos+size])
+			pos += size
+		}
+		if !global {
+			break
+		}
+	}
+	if pos <= len(s) {
+		out.WriteString(s[pos:])
+	}
+	return out.String(), nil
+}
+
+// stringRegexp returns pattern as Regexp, converting a String into a Regexp
+// matching it literally
+func stringRegexp(pattern RubyObject) (*Regexp, error) {
+	switch pattern := pattern.(type) {
+	case *Regexp:
+		return pattern, nil
+	case *String:
+		return NewRegexp(regexpEscape(pattern.Value), 0)
+	default:
+		return nil, NewWrongArgumentTypeError(&Regexp{}, pattern)
+	}
+}
+
+func stringScan(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	block, args, _ := extractBlockFromArgs(args)
+	if len(args) != 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	match, err := stringMatcher(args[0])
+	if err != nil {
+		return nil, err
+	}
+	re, isRegexp := args[0].(*Regexp)
+	results := NewArray()
+	for pos := 0; pos <= len(s.Value); {
+		loc := match(s.Value, pos)
+		if loc == nil {
+			break
+		}
+		var result RubyObject = &String{Value: s.Value[loc[0]:loc[1]]}
+		if isRegexp {
+			matchData := &MatchData{Regexp: re, Target: s.Value, Offsets: loc}
+			if matchData.size() > 1 {
+				result = matchData.groups(1)
+			}
+			setLastMatch(context, matchData)
+		}
+		if block != nil {
+			if _, err := block.Call(context, result); err != nil {
+				return nil, err
+			}
+		} else {
+			results.Elements = append(results.Elements, result)
+		}
+		pos = loc[1]
+		if loc[0] == loc[1] {
+			if pos == len(s.Value) {
+				break
+			}
+			_, size := utf8.DecodeRuneInString(s.Value[pos:])
+			pos += size
+		}
+	}
+	if block != nil {
+		return s, nil
+	}
+	return results, nil
+}
+
+func stringMatchOperator(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if pattern, ok := args[0].(*String); ok {
+		return nil, NewWrongArgumentTypeError(&Regexp{}, pattern)
+	}
+	return Send(&callContext{receiver: args[0], env: context.Env(), eval: context.Eval}, "=~", context.Receiver())
+}
+
+func stringMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if len(args) == 0 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	if _, isBlock := args[0].(*Proc); isBlock {
+		return nil, NewWrongNumberOfArgumentsError(1, 0)
+	}
+	re, err := stringRegexp(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return regexpMatch(&callContext{receiver: re, env: context.Env(), eval: context.Eval}, append([]RubyObject{context.Receiver()}, args[1:]...)...)
+}
+
+func stringIsMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	re, err := stringRegexp(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return regexpIsMatch(&callContext{receiver: re, env: context.Env(), eval: context.Eval}, append([]RubyObject{context.Receiver()}, args[1:]...)...)
+}
+
+func stringSub(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	value, err := substitute(context, s.Value, false, args)
+	if err != nil {
+		return nil, err
+	}
+	return &String{Value: value}, nil
+}
+
+func stringSubBang(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	value, err := substitute(context, s.Value, false, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.replace(value)
+}
+
+func stringGsub(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	value, err := substitute(context, s.Value, true, args)
+	if err != nil {
+		return nil, err
+	}
+	return &String{Value: value}, nil
+}
+
+func stringGsubBang(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	value, err := substitute(context, s.Value, true, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.replace(value)
+}
+
+// expandCharacterSet expands the ranges like `a-z` within the character set
+// of String#tr. A leading `^` negates the set.
+func expandCharacterSet(set string) ([]rune, bool) {
+	runes := []rune(set)
+	negated := len(runes) > 1 && runes[0] == '^'
+	if negated {
+		runes = runes[1:]
+	}
+	var expanded []rune
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '\\' && i+1 < len(runes) {
+			i++
+			expanded = append(expanded, runes[i])
+			continue
+		}
+		if i+2 < len(runes) && runes[i+1] == '-' {
+			for r := runes[i]; r <= runes[i+2]; r++ {
+				expanded = append(expanded, r)
+			}
+			i += 2
+			continue
+		}
+		expanded = append(expanded, runes[i])
+	}
+	return expanded, negated
+}
+
+// translate replaces the characters of s within the set from by the
+// corresponding ones within the set to, deleting them if to is empty
+func translate(s string, from, to string) string {
+	fromSet, negated := expandCharacterSet(from)
+	toSet, _ := expandCharacterSet(to)
+	return strings.Map(func(r rune) rune {
+		index := -1
+		for i, f := range fromSet {
+			if f == r {
+				index = i
+			}
+		}
+		if negated {
+			if index != -1 {
+				return r
+			}
+			index = len(toSet) - 1
+		} else if index == -1 {
+			return r
+		}
+		if len(toSet) == 0 {
+			return -1
+		}
+		if index >= len(toSet) {
+			index = len(toSet) - 1
+		}
+		return toSet[index]
+	}, s)
+}
+
+func stringTrArguments(args []RubyObject) (string, string, error) {
+	from, ok := args[0].(*String)
+	if !ok {
+		return "", "", NewImplicitConversionTypeError(from, args[0])
+	}
+	to, ok := args[1].(*String)
+	if !ok {
+		return "", "", NewImplicitConversionTypeError(to, args[1])
+	}
+	return from.Value, to.Value, nil
+}
+
+func stringTr(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	from, to, err := stringTrArguments(args)
+	if err != nil {
+		return nil, err
+	}
+	return &String{Value: translate(s.Value, from, to)}, nil
+}
+
+func stringTrBang(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	from, to, err := stringTrArguments(args)
+	if err != nil {
+		return nil, err
+	}
+	return s.replace(translate(s.Value, from, to))
+}
+
+func stringFreeze(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	s.frozen = true
+	return s, nil
+}
+
+func stringIsFrozen(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	return nativeBoolToBooleanObject(s.frozen), nil
+}
+
+func stringDup(context CallContext, args ...RubyObject) (RubyObject, error) {
+	s := context.Receiver().(*String)
+	return &String{Value: s.Value}, nil
 }
@@ -1,6 +1,11 @@
 package object
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"github.com/goruby/goruby/ast"
+)
 
 func TestString_hashKey(t *testing.T) {
 	hello1 := &String{Value: "Hello World"}
@@ -89,3 +94,348 @@ func TestStringAdd(t *testing.T) {
 		checkResult(t, result, testCase.result)
 	}
 }
+
+func TestStringEqual(t *testing.T) {
+	tests := []struct {
+		argument RubyObject
+		result   RubyObject
+	}{
+		{&String{Value: "foo"}, TRUE},
+		{&String{Value: "bar"}, FALSE},
+		{&Symbol{Value: "foo"}, FALSE},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: &String{Value: "foo"}}
+
+		result, err := stringEqual(context, testCase.argument)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestStringInspect(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"foo", `"foo"`},
+		{`a"b\`, `"a\"b\\"`},
+		{"\t\n\r\x1b", `"\t\n\r\e"`},
+		{"#{x} #$y #@z #", `"\#{x} \#$y \#@z #"`},
+		{"\x01\x7f", `"\u0001\x7F"`},
+		{"\xff", `"\xFF"`},
+		{"héllo", `"héllo"`},
+	}
+
+	for _, testCase := range tests {
+		actual := (&String{Value: testCase.value}).Inspect()
+
+		if actual != testCase.expected {
+			t.Logf("Expected inspect to equal %s, got %s\n", testCase.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestStringMethods(t *testing.T) {
+	tests := []struct {
+		receiver  string
+		method    string
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{"héllo", "length", nil, NewInteger(5), nil},
+		{"", "empty?", nil, TRUE, nil},
+		{"héllo", "upcase", nil, &String{Value: "HÉLLO"}, nil},
+		{"HeLLo", "downcase", nil, &String{Value: "hello"}, nil},
+		{"hELLO wORLD", "capitalize", nil, &String{Value: "Hello world"}, nil},
+		{"HeLLo", "swapcase", nil, &String{Value: "hEllO"}, nil},
+		{" \t foo \n", "strip", nil, &String{Value: "foo"}, nil},
+		{" foo ", "lstrip", nil, &String{Value: "foo "}, nil},
+		{" foo \x00", "rstrip", nil, &String{Value: " foo"}, nil},
+		{"foo\r\n", "chomp", nil, &String{Value: "foo"}, nil},
+		{"foo", "chop", nil, &String{Value: "fo"}, nil},
+		{"héllo", "reverse", nil, &String{Value: "olléh"}, nil},
+		{"az", "succ", nil, &String{Value: "ba"}, nil},
+		{"zz99", "succ", nil, &String{Value: "aaa00"}, nil},
+		{"a-9", "succ", nil, &String{Value: "b-0"}, nil},
+		{"Zz", "next", nil, &String{Value: "AAa"}, nil},
+		{"***", "succ", nil, &String{Value: "**+"}, nil},
+		{"", "succ", nil, &String{Value: ""}, nil},
+		{"ab", "*", []RubyObject{NewInteger(3)}, &String{Value: "ababab"}, nil},
+		{"ab", "*", []RubyObject{NewInteger(-1)}, nil, NewArgumentError("negative argument")},
+		{"%d-%s", "%", []RubyObject{NewArray(NewInteger(1), &String{Value: "a"})}, &String{Value: "1-a"}, nil},
+		{"%05.1f", "%", []RubyObject{NewFloat(3.14)}, &String{Value: "003.1"}, nil},
+		{"a", "<=>", []RubyObject{&String{Value: "b"}}, NewInteger(-1), nil},
+		{"b", "<=>", []RubyObject{&String{Value: "a"}}, NewInteger(1), nil},
+		{"a", "<=>", []RubyObject{&String{Value: "a"}}, NewInteger(0), nil},
+		{"a", "<=>", []RubyObject{NewInteger(1)}, NIL, nil},
+		{"a", "eql?", []RubyObject{&String{Value: "a"}}, TRUE, nil},
+		{"a", "eql?", []RubyObject{&Symbol{Value: "a"}}, FALSE, nil},
+		{"hello", "[]", []RubyObject{NewInteger(1)}, &String{Value: "e"}, nil},
+		{"hello", "[]", []RubyObject{NewInteger(-1)}, &String{Value: "o"}, nil},
+		{"hello", "[]", []RubyObject{NewInteger(5)}, NIL, nil},
+		{"hello", "[]", []RubyObject{NewInteger(1), NewInteger(3)}, &String{Value: "ell"}, nil},
+		{"hello", "[]", []RubyObject{NewInteger(-3), NewInteger(10)}, &String{Value: "llo"}, nil},
+		{"hello", "[]", []RubyObject{NewInteger(5), NewInteger(1)}, &String{Value: ""}, nil},
+		{"hello", "[]", []RubyObject{NewInteger(6), NewInteger(1)}, NIL, nil},
+		{"hello", "[]", []RubyObject{&String{Value: "ll"}}, &String{Value: "ll"}, nil},
+		{"hello", "[]", []RubyObject{&String{Value: "x"}}, NIL, nil},
+		{"héllo", "[]", []RubyObject{&Range{Begin: NewInteger(1), End: NewInteger(3)}}, &String{Value: "éll"}, nil},
+		{"hello", "[]", []RubyObject{&Range{Begin: NewInteger(1), End: NewInteger(-1), Exclusive: true}}, &String{Value: "ell"}, nil},
+		{"hello", "[]", []RubyObject{&Range{Begin: NewInteger(-2), End: NIL}}, &String{Value: "lo"}, nil},
+		{"hello", "[]", []RubyObject{&Range{Begin: NIL, End: NewInteger(1)}}, &String{Value: "he"}, nil},
+		{"hello", "[]", []RubyObject{&Range{Begin: NewInteger(6), End: NIL}}, NIL, nil},
+		{"ab12", "[]", []RubyObject{mustRegexp(`\d+`, 0)}, &String{Value: "12"}, nil},
+		{"ab12", "[]", []RubyObject{mustRegexp(`x`, 0)}, NIL, nil},
+		{"ab12", "[]", []RubyObject{mustRegexp(`([a-z]+)(\d+)`, 0), NewInteger(2)}, &String{Value: "12"}, nil},
+		{"ab12", "[]", []RubyObject{mustRegexp(`(?<num>\d+)`, 0), &String{Value: "num"}}, &String{Value: "12"}, nil},
+		{"ab12", "[]", []RubyObject{mustRegexp(`(\d)`, 0), NewInteger(3)}, NIL, nil},
+		{"ab12", "slice", []RubyObject{mustRegexp(`[a-z]+`, 0)}, &String{Value: "ab"}, nil},
+		{"héllo", "index", []RubyObject{&String{Value: "l"}}, NewInteger(2), nil},
+		{"hello", "index", []RubyObject{&String{Value: "l"}, NewInteger(3)}, NewInteger(3), nil},
+		{"hello", "index", []RubyObject{&String{Value: "x"}}, NIL, nil},
+		{"héllo", "rindex", []RubyObject{&String{Value: "l"}}, NewInteger(3), nil},
+		{"hello", "rindex", []RubyObject{&String{Value: "l"}, NewInteger(2)}, NewInteger(2), nil},
+		{"hello", "include?", []RubyObject{&String{Value: "ell"}}, TRUE, nil},
+		{"hello", "include?", []RubyObject{NewInteger(1)}, nil, NewImplicitConversionTypeError(&String{}, NewInteger(1))},
+		{"hello", "start_with?", []RubyObject{&String{Value: "x"}, &String{Value: "he"}}, TRUE, nil},
+		{"hello", "end_with?", []RubyObject{&String{Value: "he"}}, FALSE, nil},
+		{"a b", "split", nil, NewArray(&String{Value: "a"}, &String{Value: "b"}), nil},
+		{" a  b ", "split", []RubyObject{&String{Value: " "}}, NewArray(&String{Value: "a"}, &String{Value: "b"}), nil},
+		{"a,b,,", "split", []RubyObject{&String{Value: ","}}, NewArray(&String{Value: "a"}, &String{Value: "b"}), nil},
+		{"a,b,,", "split", []RubyObject{&String{Value: ","}, NewInteger(-1)}, NewArray(&String{Value: "a"}, &String{Value: "b"}, &String{Value: ""}, &String{Value: ""}), nil},
+		{"a b c", "split", []RubyObject{NIL, NewInteger(2)}, NewArray(&String{Value: "a"}, &String{Value: "b c"}), nil},
+		{"abc", "split", []RubyObject{&String{Value: ""}}, NewArray(&String{Value: "a"}, &String{Value: "b"}, &String{Value: "c"}), nil},
+		{"", "split", []RubyObject{&String{Value: ","}}, NewArray(), nil},
+		{"hé", "chars", nil, NewArray(&String{Value: "h"}, &String{Value: "é"}), nil},
+		{"ab", "bytes", nil, NewArray(NewInteger(97), NewInteger(98)), nil},
+		{"a\nb", "lines", nil, NewArray(&String{Value: "a\n"}, &String{Value: "b"}), nil},
+		{" -12_3abc", "to_i", nil, NewInteger(-123), nil},
+		{"0x1f", "to_i", []RubyObject{NewInteger(16)}, NewInteger(31), nil},
+		{"z", "to_i", []RubyObject{NewInteger(36)}, NewInteger(35), nil},
+		{"abc", "to_i", nil, NewInteger(0), nil},
+		{"1", "to_i", []RubyObject{NewInteger(1)}, nil, NewArgumentError("invalid radix 1")},
+		{"1_0.5e1x", "to_f", nil, NewFloat(105), nil},
+		{"x", "to_f", nil, NewFloat(0), nil},
+		{"foo", "to_sym", nil, &Symbol{Value: "foo"}, nil},
+		{"abc", "center", []RubyObject{NewInteger(8), &String{Value: "12"}}, &String{Value: "12abc121"}, nil},
+		{"abc", "ljust", []RubyObject{NewInteger(5)}, &String{Value: "abc  "}, nil},
+		{"abc", "rjust", []RubyObject{NewInteger(2)}, &String{Value: "abc"}, nil},
+		{"abc", "rjust", []RubyObject{NewInteger(5), &String{Value: ""}}, nil, NewArgumentError("zero width padding")},
+		{"hello", "sub", []RubyObject{&String{Value: "l"}, &String{Value: "L"}}, &String{Value: "heLlo"}, nil},
+		{"hello", "gsub", []RubyObject{&String{Value: "l"}, &String{Value: "<\\0>"}}, &String{Value: "he<l><l>o"}, nil},
+		{"abc", "gsub", []RubyObject{&String{Value: ""}, &String{Value: "-"}}, &String{Value: "-a-b-c-"}, nil},
+		{"hello", "gsub", []RubyObject{&String{Value: "l"}}, nil, NewWrongNumberOfArgumentsError(2, 1)},
+		{"a1b22", "gsub", []RubyObject{mustRegexp(`\d`, 0), &String{Value: "#"}}, &String{Value: "a#b##"}, nil},
+		{"abc", "gsub", []RubyObject{mustRegexp(`x*`, 0), &String{Value: "-"}}, &String{Value: "-a-b-c-"}, nil},
+		{"john smith", "sub", []RubyObject{mustRegexp(`(\w+) (\w+)`, 0), &String{Value: `\2 \1`}}, &String{Value: "smith john"}, nil},
+		{"hello", "sub", []RubyObject{NewInteger(1), &String{Value: "x"}}, nil, NewWrongArgumentTypeError(&String{}, NewInteger(1))},
+		{"a, b,c", "split", []RubyObject{mustRegexp(`,\s*`, 0)}, NewArray(&String{Value: "a"}, &String{Value: "b"}, &String{Value: "c"}), nil},
+		{"abc", "split", []RubyObject{mustRegexp(``, 0)}, NewArray(&String{Value: "a"}, &String{Value: "b"}, &String{Value: "c"}), nil},
+		{"a b", "split", []RubyObject{mustRegexp(`\s*`, 0)}, NewArray(&String{Value: "a"}, &String{Value: "b"}), nil},
+		{",a", "split", []RubyObject{mustRegexp(`,`, 0)}, NewArray(&String{Value: ""}, &String{Value: "a"}), nil},
+		{"a1b2c", "split", []RubyObject{mustRegexp(`(\d)`, 0)}, NewArray(&String{Value: "a"}, &String{Value: "1"}, &String{Value: "b"}, &String{Value: "2"}, &String{Value: "c"}), nil},
+		{"a1b2c", "split", []RubyObject{mustRegexp(`\d`, 0), NewInteger(2)}, NewArray(&String{Value: "a"}, &String{Value: "b2c"}), nil},
+		{"a1b22", "scan", []RubyObject{mustRegexp(`\d+`, 0)}, NewArray(&String{Value: "1"}, &String{Value: "22"}), nil},
+		{"a1b22", "scan", []RubyObject{mustRegexp(`([a-z])(\d+)`, 0)}, NewArray(NewArray(&String{Value: "a"}, &String{Value: "1"}), NewArray(&String{Value: "b"}, &String{Value: "22"})), nil},
+		{"hello", "scan", []RubyObject{&String{Value: "l"}}, NewArray(&String{Value: "l"}, &String{Value: "l"}), nil},
+		{"héllo", "=~", []RubyObject{mustRegexp(`l+`, 0)}, NewInteger(2), nil},
+		{"hello", "=~", []RubyObject{&String{Value: "l"}}, nil, NewWrongArgumentTypeError(&Regexp{}, &String{Value: "l"})},
+		{"hello", "match?", []RubyObject{&String{Value: "ll"}}, TRUE, nil},
+		{"hello", "match?", []RubyObject{mustRegexp(`L`, RegexpIgnoreCase)}, TRUE, nil},
+		{"hello", "match", []RubyObject{mustRegexp(`x`, 0)}, NIL, nil},
+		{"hello", "match", []RubyObject{NewInteger(1)}, nil, NewWrongArgumentTypeError(&Regexp{}, NewInteger(1))},
+		{"hello", "tr", []RubyObject{&String{Value: "a-y"}, &String{Value: "b-z"}}, &String{Value: "ifmmp"}, nil},
+		{"hello", "tr", []RubyObject{&String{Value: "el"}, &String{Value: "x"}}, &String{Value: "hxxxo"}, nil},
+		{"hello", "tr", []RubyObject{&String{Value: "^l"}, &String{Value: "*"}}, &String{Value: "**ll*"}, nil},
+		{"hello", "tr", []RubyObject{&String{Value: "l"}, &String{Value: ""}}, &String{Value: "heo"}, nil},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.receiver+"."+testCase.method, func(t *testing.T) {
+			context := &callContext{receiver: &String{Value: testCase.receiver}}
+
+			result, err := stringMethods[testCase.method].Call(context, testCase.arguments...)
+
+			checkError(t, err, testCase.err)
+
+			checkResult(t, result, testCase.result)
+		})
+	}
+}
+
+func TestStringMutations(t *testing.T) {
+	tests := []struct {
+		receiver  string
+		method    string
+		arguments []RubyObject
+		changed   bool
+		value     string
+	}{
+		{"foo", "upcase!", nil, true, "FOO"},
+		{"FOO", "upcase!", nil, false, "FOO"},
+		{"FOO", "downcase!", nil, true, "foo"},
+		{"foo", "capitalize!", nil, true, "Foo"},
+		{"Foo", "swapcase!", nil, true, "fOO"},
+		{" foo ", "strip!", nil, true, "foo"},
+		{"foo", "strip!", nil, false, "foo"},
+		{" foo", "lstrip!", nil, true, "foo"},
+		{"foo ", "rstrip!", nil, true, "foo"},
+		{"foo\n", "chomp!", nil, true, "foo"},
+		{"foo", "chop!", nil, true, "fo"},
+		{"foo", "reverse!", nil, true, "oof"},
+		{"foo", "sub!", []RubyObject{&String{Value: "o"}, &String{Value: "0"}}, true, "f0o"},
+		{"foo", "gsub!", []RubyObject{&String{Value: "o"}, &String{Value: "0"}}, true, "f00"},
+		{"foo", "gsub!", []RubyObject{&String{Value: "x"}, &String{Value: "0"}}, false, "foo"},
+		{"foo", "tr!", []RubyObject{&String{Value: "o"}, &String{Value: "0"}}, true, "f00"},
+		{"foo", "<<", []RubyObject{&String{Value: "bar"}}, true, "foobar"},
+		{"foo", "<<", []RubyObject{NewInteger(0x263a)}, true, "foo\u263a"},
+		{"foo", "concat", []RubyObject{&String{Value: "bar"}, &String{Value: "baz"}}, true, "foobarbaz"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.receiver+"."+testCase.method, func(t *testing.T) {
+			str := &String{Value: testCase.receiver}
+			context := &callContext{receiver: str}
+
+			result, err := stringMethods[testCase.method].Call(context, testCase.arguments...)
+
+			checkError(t, err, nil)
+
+			if testCase.changed {
+				checkResult(t, result, str)
+			} else {
+				checkResult(t, result, NIL)
+			}
+			if str.Value != testCase.value {
+				t.Logf("Expected receiver to equal %q, got %q\n", testCase.value, str.Value)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestStringFreeze(t *testing.T) {
+	str := &String{Value: "foo"}
+	context := &callContext{receiver: str}
+
+	result, err := stringIsFrozen(context)
+	checkError(t, err, nil)
+	checkResult(t, result, FALSE)
+
+	result, err = stringFreeze(context)
+	checkError(t, err, nil)
+	checkResult(t, result, str)
+
+	result, err = stringIsFrozen(context)
+	checkError(t, err, nil)
+	checkResult(t, result, TRUE)
+
+	for _, method := range []string{"upcase!", "<<", "gsub!"} {
+		args := []RubyObject{&String{Value: "o"}, &String{Value: "0"}}
+		if method == "upcase!" {
+			args = nil
+		} else if method == "<<" {
+			args = args[:1]
+		}
+
+		_, err := stringMethods[method].Call(context, args...)
+
+		checkError(t, err, NewFrozenError(str))
+	}
+	if str.Value != "foo" {
+		t.Logf("Expected frozen string to stay unchanged, got %q\n", str.Value)
+		t.Fail()
+	}
+
+	result, err = stringDup(context)
+	checkError(t, err, nil)
+	checkResult(t, result, &String{Value: "foo"})
+}
+
+func TestStringBlockMethods(t *testing.T) {
+	eval := func(node ast.Node, env Environment) (RubyObject, error) {
+		arg, _ := env.Get("c")
+		return &String{Value: strings.ToUpper(arg.(*String).Value)}, nil
+	}
+	block := &Proc{
+		Parameters: []*ast.FunctionParameter{&ast.FunctionParameter{Name: &ast.Identifier{Value: "c"}}},
+		Body:       &ast.BlockStatement{Statements: []ast.Statement{}},
+		Env:        NewEnvironment(),
+	}
+
+	t.Run("gsub", func(t *testing.T) {
+		context := &callContext{receiver: &String{Value: "hello"}, env: NewEnvironment(), eval: eval}
+
+		result, err := stringGsub(context, &String{Value: "l"}, block)
+
+		checkError(t, err, nil)
+		checkResult(t, result, &String{Value: "heLLo"})
+	})
+	t.Run("gsub with regexp", func(t *testing.T) {
+		var groups []RubyObject
+		eval := func(node ast.Node, env Environment) (RubyObject, error) {
+			group, _ := LastMatchReference(env, "$1")
+			groups = append(groups, group)
+			return &String{Value: "#"}, nil
+		}
+		env := NewMainEnvironment()
+		context := &callContext{receiver: &String{Value: "a1b2"}, env: env, eval: eval}
+		block := &Proc{Body: &ast.BlockStatement{}, Env: env}
+
+		result, err := stringGsub(context, mustRegexp(`[a-z](\d)`, 0), block)
+
+		checkError(t, err, nil)
+		checkResult(t, result, &String{Value: "##"})
+		checkResult(t, NewArray(groups...), NewArray(&String{Value: "1"}, &String{Value: "2"}))
+	})
+	t.Run("scan", func(t *testing.T) {
+		var matches []RubyObject
+		eval := func(node ast.Node, env Environment) (RubyObject, error) {
+			match, _ := env.Get("c")
+			matches = append(matches, match)
+			return NIL, nil
+		}
+		str := &String{Value: "a1b2"}
+		context := &callContext{receiver: str, env: NewEnvironment(), eval: eval}
+
+		result, err := stringScan(context, mustRegexp(`\d`, 0), block)
+
+		checkError(t, err, nil)
+		checkResult(t, result, str)
+		checkResult(t, NewArray(matches...), NewArray(&String{Value: "1"}, &String{Value: "2"}))
+	})
+	t.Run("each_char", func(t *testing.T) {
+		var chars []string
+		eval := func(node ast.Node, env Environment) (RubyObject, error) {
+			arg, _ := env.Get("c")
+			chars = append(chars, arg.(*String).Value)
+			return NIL, nil
+		}
+		str := &String{Value: "hé"}
+		context := &callContext{receiver: str, env: NewEnvironment(), eval: eval}
+
+		result, err := stringEachChar(context, block)
+
+		checkError(t, err, nil)
+		checkResult(t, result, str)
+		if strings.Join(chars, ",") != "h,é" {
+			t.Logf("Expected block to be called with each char, got %q\n", chars)
+			t.Fail()
+		}
+	})
+	t.Run("each_char without block", func(t *testing.T) {
+		str := &String{Value: "hé"}
+		context := &callContext{receiver: str}
+
+		result, err := stringEachChar(context)
+
+		checkError(t, err, nil)
+		checkResult(t, result, &Enumerator{Receiver: str, Method: "each_char"})
+	})
+}
@@ -1,6 +1,13 @@
 package object
 
-import "hash/fnv"
+import (
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/goruby/goruby/ast"
+)
 
 var symbolClass RubyClassObject = newClass(
 	"Symbol",
@@ -21,8 +28,41 @@ type Symbol struct {
 	Value string
 }
 
-// Inspect returns the value of the symbol
-func (s *Symbol) Inspect() string { return ":" + s.Value }
+// symbols is the symbol table holding every symbol created via NewSymbol in
+// order of creation
+var symbols = struct {
+	sync.Mutex
+	table map[string]*Symbol
+	all   []*Symbol
+}{table: make(map[string]*Symbol)}
+
+// NewSymbol returns the symbol for name. Symbols are unique, i.e. NewSymbol
+// returns the same instance for the same name.
+func NewSymbol(name string) *Symbol {
+	symbols.Lock()
+	defer symbols.Unlock()
+	if sym, ok := symbols.table[name]; ok {
+		return sym
+	}
+	sym := &Symbol{Value: name}
+	symbols.table[name] = sym
+	symbols.all = append(symbols.all, sym)
+	return sym
+}
+
+// plainSymbol matches the symbol names which need no quotes within a symbol
+// literal, i.e. identifiers, variable names and operators
+var plainSymbol = regexp.MustCompile(
+	`^(?:[\p{L}_][\p{L}\p{N}_]*[?!=]?|@@?[\p{L}_][\p{L}\p{N}_]*|\$[\p{L}_][\p{L}\p{N}_]*|\[\]=?|[-+]@|\*\*|<=>|===?|=~|!=|!~|<<|>>|<=|>=|[-+*/%<>!~^&|])$`,
+)
+
+// Inspect returns the value of the symbol as symbol literal
+func (s *Symbol) Inspect() string {
+	if plainSymbol.MatchString(s.Value) {
+		return ":" + s.Value
+	}
+	return ":" + quote(s.Value)
+}
 
 // Type returns SYMBOL_OBJ
 func (s *Symbol) Type() Type { return SYMBOL_OBJ }
@@ -36,10 +76,33 @@ func (s *Symbol) hashKey() hashKey {
 	return hashKey{Type: s.Type(), Value: h.Sum64()}
 }
 
-var symbolClassMethods = map[string]RubyMethod{}
+var symbolClassMethods = map[string]RubyMethod{
+	"all_symbols": withArity(0, publicMethod(symbolAllSymbols)),
+}
 
 var symbolMethods = map[string]RubyMethod{
-	"to_s": withArity(0, publicMethod(symbolToS)),
+	"to_s":     withArity(0, publicMethod(symbolToS)),
+	"id2name":  withArity(0, publicMethod(symbolToS)),
+	"to_sym":   withArity(0, publicMethod(symbolToSym)),
+	"to_proc":  withArity(0, publicMethod(symbolToProc)),
+	"==":       withArity(1, publicMethod(symbolEqual)),
+	"<=>":      withArity(1, publicMethod(symbolSpaceship)),
+	"length":   withArity(0, publicMethod(symbolLength)),
+	"size":     withArity(0, publicMethod(symbolLength)),
+	"upcase":   symbolTransformation(strings.ToUpper),
+	"downcase": symbolTransformation(strings.ToLower),
+	"succ":     symbolTransformation(successor),
+	"next":     symbolTransformation(successor),
+}
+
+func symbolAllSymbols(context CallContext, args ...RubyObject) (RubyObject, error) {
+	symbols.Lock()
+	defer symbols.Unlock()
+	all := make([]RubyObject, len(symbols.all))
+	for i, sym := range symbols.all {
+		all[i] = sym
+	}
+	return NewArray(all...), nil
 }
 
 func symbolToS(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -48,3 +111,57 @@ func symbolToS(context CallContext, args ...RubyObject) (RubyObject, error) {
 	}
 	return nil, nil
 }
+
+func symbolEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	sym := context.Receiver().(*Symbol)
+	other, ok := args[0].(*Symbol)
+	if !ok || sym.Value != other.Value {
+		return FALSE, nil
+	}
+	return TRUE, nil
+}
+
+func symbolToSym(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver(), nil
+}
+
+// symbolToProc returns a proc calling the method named by the receiver on its
+// argument, i.e. `:upcase.to_proc` behaves like `{ |receiver| receiver.upcase }`
+func symbolToProc(context CallContext, args ...RubyObject) (RubyObject, error) {
+	sym := context.Receiver().(*Symbol)
+	receiver := &ast.Identifier{Value: "receiver"}
+	call := &ast.ContextCallExpression{
+		Context:  receiver,
+		Function: &ast.Identifier{Value: sym.Value},
+	}
+	return &Proc{
+		Parameters: []*ast.FunctionParameter{{Name: receiver}},
+		Body: &ast.BlockStatement{
+			Statements: []ast.Statement{&ast.ExpressionStatement{Expression: call}},
+		},
+		Env: NewEnvironment(),
+	}, nil
+}
+
+func symbolSpaceship(context CallContext, args ...RubyObject) (RubyObject, error) {
+	sym := context.Receiver().(*Symbol)
+	other, ok := args[0].(*Symbol)
+	if !ok {
+		return NIL, nil
+	}
+	return NewInteger(int64(strings.Compare(sym.Value, other.Value))), nil
+}
+
+func symbolLength(context CallContext, args ...RubyObject) (RubyObject, error) {
+	sym := context.Receiver().(*Symbol)
+	return NewInteger(int64(len([]rune(sym.Value)))), nil
+}
+
+// symbolTransformation returns a method returning the symbol named by the
+// result of fn applied to the name of the receiver
+func symbolTransformation(fn func(string) string) RubyMethod {
+	return withArity(0, publicMethod(func(context CallContext, args ...RubyObject) (RubyObject, error) {
+		sym := context.Receiver().(*Symbol)
+		return NewSymbol(fn(sym.Value)), nil
+	}))
+}
@@ -1,6 +1,10 @@
 package object
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/goruby/goruby/ast"
+)
 
 func TestSymbol_hashKey(t *testing.T) {
 	hello1 := &Symbol{Value: "Hello World"}
@@ -34,3 +38,150 @@ func TestSymbolToS(t *testing.T) {
 
 	checkResult(t, result, expected)
 }
+
+func TestSymbolEqual(t *testing.T) {
+	tests := []struct {
+		argument RubyObject
+		result   RubyObject
+	}{
+		{&Symbol{Value: "foo"}, TRUE},
+		{&Symbol{Value: "bar"}, FALSE},
+		{&String{Value: "foo"}, FALSE},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: &Symbol{Value: "foo"}}
+
+		result, err := symbolEqual(context, testCase.argument)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestSymbolInspect(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"foo", ":foo"},
+		{"foo?", ":foo?"},
+		{"foo=", ":foo="},
+		{"@iv", ":@iv"},
+		{"$global", ":$global"},
+		{"Const", ":Const"},
+		{"foo bar", `:"foo bar"`},
+		{"", `:""`},
+	}
+
+	for _, testCase := range tests {
+		actual := (&Symbol{Value: testCase.value}).Inspect()
+
+		if actual != testCase.expected {
+			t.Logf("Expected inspect to equal %q, got %q\n", testCase.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestNewSymbol(t *testing.T) {
+	foo := NewSymbol("foo")
+
+	if NewSymbol("foo") != foo {
+		t.Logf("Expected NewSymbol to return the same instance for the same name")
+		t.Fail()
+	}
+
+	if NewSymbol("bar") == foo {
+		t.Logf("Expected NewSymbol to return different instances for different names")
+		t.Fail()
+	}
+
+	context := &callContext{receiver: symbolClass}
+
+	result, err := symbolAllSymbols(context)
+
+	checkError(t, err, nil)
+
+	var found bool
+	for _, sym := range result.(*Array).Elements {
+		found = found || sym == foo
+	}
+	if !found {
+		t.Logf("Expected all_symbols to contain %s, got %s", foo.Inspect(), result.Inspect())
+		t.Fail()
+	}
+}
+
+func TestSymbolMethods(t *testing.T) {
+	tests := []struct {
+		receiver  string
+		method    string
+		arguments []RubyObject
+		result    RubyObject
+	}{
+		{"foo", "length", nil, NewInteger(3)},
+		{"héllo", "size", nil, NewInteger(5)},
+		{"foo", "upcase", nil, NewSymbol("FOO")},
+		{"FOO", "downcase", nil, NewSymbol("foo")},
+		{"az", "succ", nil, NewSymbol("ba")},
+		{"a9", "next", nil, NewSymbol("b0")},
+		{"a", "<=>", []RubyObject{NewSymbol("b")}, NewInteger(-1)},
+		{"b", "<=>", []RubyObject{NewSymbol("a")}, NewInteger(1)},
+		{"a", "<=>", []RubyObject{NewSymbol("a")}, NewInteger(0)},
+		{"a", "<=>", []RubyObject{&String{Value: "a"}}, NIL},
+		{"foo", "to_sym", nil, NewSymbol("foo")},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewSymbol(testCase.receiver)}
+
+		result, err := Send(context, testCase.method, testCase.arguments...)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestSymbolToProc(t *testing.T) {
+	context := &callContext{receiver: NewSymbol("upcase")}
+
+	result, err := symbolToProc(context)
+
+	checkError(t, err, nil)
+
+	proc, ok := result.(*Proc)
+	if !ok {
+		t.Logf("Expected result to be a *Proc, got %T", result)
+		t.FailNow()
+	}
+
+	expected := "do |receiver| \nreceiver.upcase()\nend"
+	if proc.Inspect() != expected {
+		t.Logf("Expected proc to equal %q, got %q", expected, proc.Inspect())
+		t.Fail()
+	}
+
+	var evaluated ast.Node
+	evalContext := &callContext{
+		receiver: NewSymbol("upcase"),
+		eval: func(node ast.Node, env Environment) (RubyObject, error) {
+			evaluated = node
+			receiver, _ := env.Get("receiver")
+			return receiver, nil
+		},
+	}
+
+	result, err = proc.Call(evalContext, &String{Value: "foo"})
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, &String{Value: "foo"})
+
+	if evaluated != proc.Body {
+		t.Logf("Expected the proc body to be evaluated, got %v", evaluated)
+		t.Fail()
+	}
+}
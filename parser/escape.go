@@ -0,0 +1,176 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var simpleEscapes = map[byte]byte{
+	'a': '\a',
+	'b': '\b',
+	't': '\t',
+	'n': '\n',
+	'v': '\v',
+	'f': '\f',
+	'r': '\r',
+	'e': 0x1b,
+	's': ' ',
+}
+
+// unescapeQuoted returns s with the escape sequences valid within single
+// quoted strings replaced, i.e. escaped backslashes and escaped delimiters
+// given by quotes, e.g. `\'`
+func unescapeQuoted(s string, quotes string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '\\' || strings.IndexByte(quotes, s[i+1]) != -1) {
+			i++
+		}
+		out.WriteByte(s[i])
+	}
+	return out.String()
+}
+
+// readEscape decodes the escape sequence within a double quoted string
+// starting at s[i], which is the character following the backslash. It
+// returns the decoded characters and the index of the first character after
+// the escape sequence.
+func readEscape(s string, i int) (string, int, error) {
+	if i >= len(s) {
+		return "", i, fmt.Errorf("invalid escape character syntax")
+	}
+	c := s[i]
+	if r, ok := simpleEscapes[c]; ok {
+		return string([]byte{r}), i + 1, nil
+	}
+	switch c {
+	case '\n':
+		return "", i + 1, nil
+	case '0', '1', '2', '3', '4', '5', '6', '7':
+		end := i + 1
+		for end < len(s) && end < i+3 && isOctalDigit(s[end]) {
+			end++
+		}
+		value, _ := strconv.ParseUint(s[i:end], 8, 16)
+		return string([]byte{byte(value)}), end, nil
+	case 'x':
+		end := i + 1
+		for end < len(s) && end < i+3 && isHexDigit(s[end]) {
+			end++
+		}
+		if end == i+1 {
+			return "", i, fmt.Errorf("invalid hex escape")
+		}
+		value, _ := strconv.ParseUint(s[i+1:end], 16, 8)
+		return string([]byte{byte(value)}), end, nil
+	case 'u':
+		return readUnicodeEscape(s, i+1)
+	case 'c', 'C', 'M':
+		b, next, err := readControlOrMeta(s, i)
+		return string([]byte{b}), next, err
+	default:
+		_, size := utf8.DecodeRuneInString(s[i:])
+		return s[i : i+size], i + size, nil
+	}
+}
+
+// readUnicodeEscape decodes `\unnnn` and `\u{nnnn ...}` with s[i] being the
+// character following the `u`
+func readUnicodeEscape(s string, i int) (string, int, error) {
+	if i < len(s) && s[i] == '{' {
+		end := strings.IndexByte(s[i:], '}')
+		if end == -1 {
+			return "", i, fmt.Errorf("unterminated Unicode escape")
+		}
+		codepoints := strings.Fields(s[i+1 : i+end])
+		if len(codepoints) == 0 {
+			return "", i, fmt.Errorf("invalid Unicode escape")
+		}
+		var out strings.Builder
+		for _, codepoint := range codepoints {
+			if len(codepoint) > 6 {
+				return "", i, fmt.Errorf("invalid Unicode escape")
+			}
+			value, err := strconv.ParseUint(codepoint, 16, 32)
+			if err != nil || !utf8.ValidRune(rune(value)) {
+				return "", i, fmt.Errorf("invalid Unicode escape")
+			}
+			out.WriteRune(rune(value))
+		}
+		return out.String(), i + end + 1, nil
+	}
+	if i+4 > len(s) {
+		return "", i, fmt.Errorf("invalid Unicode escape")
+	}
+	value, err := strconv.ParseUint(s[i:i+4], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(value)) {
+		return "", i, fmt.Errorf("invalid Unicode escape")
+	}
+	return string(rune(value)), i + 4, nil
+}
+
+// readControlOrMeta decodes the control and meta escapes `\cx`, `\C-x` and
+// `\M-x` with s[i] being the character following the backslash. They can be
+// combined, e.g. `\M-\C-x`.
+func readControlOrMeta(s string, i int) (byte, int, error) {
+	var meta bool
+	switch {
+	case s[i] == 'c':
+		i++
+	case strings.HasPrefix(s[i:], "C-"):
+		i += 2
+	case strings.HasPrefix(s[i:], "M-"):
+		meta = true
+		i += 2
+	default:
+		return 0, i, fmt.Errorf("invalid escape character syntax")
+	}
+	if i >= len(s) {
+		return 0, i, fmt.Errorf("invalid escape character syntax")
+	}
+	b, next := s[i], i+1
+	if b == '\\' {
+		if next >= len(s) {
+			return 0, i, fmt.Errorf("invalid escape character syntax")
+		}
+		var err error
+		switch s[next] {
+		case 'c', 'C', 'M':
+			b, next, err = readControlOrMeta(s, next)
+		default:
+			var escaped string
+			escaped, next, err = readEscape(s, next)
+			if err == nil && len(escaped) != 1 {
+				err = fmt.Errorf("invalid escape character syntax")
+			}
+			if err == nil {
+				b = escaped[0]
+			}
+		}
+		if err != nil {
+			return 0, i, err
+		}
+	} else if b >= utf8.RuneSelf {
+		return 0, i, fmt.Errorf("invalid escape character syntax")
+	}
+	if meta {
+		return b | 0x80, next, nil
+	}
+	if b == '?' {
+		return 0x7f, next, nil
+	}
+	return b & 0x9f, next, nil
+}
+
+func isOctalDigit(c byte) bool {
+	return '0' <= c && c <= '7'
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
@@ -0,0 +1,99 @@
+package parser
+
+import "testing"
+
+func TestReadEscape(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		next     int
+	}{
+		{`a`, "\a", 1},
+		{`b`, "\b", 1},
+		{`t`, "\t", 1},
+		{`n`, "\n", 1},
+		{`v`, "\v", 1},
+		{`f`, "\f", 1},
+		{`r`, "\r", 1},
+		{`e`, "\x1b", 1},
+		{`s`, " ", 1},
+		{`\`, `\`, 1},
+		{`"`, `"`, 1},
+		{"\n", "", 1},
+		{`101x`, "A", 3},
+		{`0`, "\x00", 1},
+		{`18`, "\x01", 1},
+		{`x41x`, "A", 3},
+		{`x9`, "\t", 2},
+		{`u00e9`, "é", 5},
+		{`u{e9 1F600}`, "é😀", 11},
+		{`cA`, "\x01", 2},
+		{`C-a`, "\x01", 3},
+		{`c?`, "\x7f", 2},
+		{`C-?`, "\x7f", 3},
+		{`M-a`, "\xe1", 3},
+		{`M-\C-a`, "\x81", 6},
+		{`M-\ca`, "\x81", 5},
+		{`c\M-a`, "\x81", 5},
+		{`ä`, "ä", 2},
+	}
+
+	for _, tt := range tests {
+		actual, next, err := readEscape(tt.input, 0)
+		if err != nil {
+			t.Errorf("Expected no error for %q, got %v", tt.input, err)
+			continue
+		}
+
+		if actual != tt.expected {
+			t.Errorf("Expected %q to decode to %q, got %q", tt.input, tt.expected, actual)
+		}
+		if next != tt.next {
+			t.Errorf("Expected %q to end at %d, got %d", tt.input, tt.next, next)
+		}
+	}
+
+	t.Run("invalid escapes", func(t *testing.T) {
+		tests := []string{
+			``,
+			`xg`,
+			`u12`,
+			`u{}`,
+			`u{110000}`,
+			`u{12`,
+			`C`,
+			`M-`,
+			`cä`,
+		}
+
+		for _, input := range tests {
+			_, _, err := readEscape(input, 0)
+			if err == nil {
+				t.Errorf("Expected error for %q, got nil", input)
+			}
+		}
+	})
+}
+
+func TestUnescapeQuoted(t *testing.T) {
+	tests := []struct {
+		input    string
+		quotes   string
+		expected string
+	}{
+		{`foo`, `'`, `foo`},
+		{`a\'b`, `'`, `a'b`},
+		{`a\\b`, `'`, `a\b`},
+		{`a\nb`, `'`, `a\nb`},
+		{`a\`, `'`, `a\`},
+		{`a\)\(\'`, `()`, `a)(\'`},
+	}
+
+	for _, tt := range tests {
+		actual := unescapeQuoted(tt.input, tt.quotes)
+
+		if actual != tt.expected {
+			t.Errorf("Expected %q to unescape to %q, got %q", tt.input, tt.expected, actual)
+		}
+	}
+}
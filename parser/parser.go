@@ -3,8 +3,10 @@ package parser
 import (
 	"fmt"
 	gotoken "go/token"
+	"math/big"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/goruby/goruby/ast"
 	"github.com/goruby/goruby/lexer"
@@ -21,6 +23,7 @@ const (
 	precIfUnless    // modifier-if, modifier-unless
 	precAssignment  // x = 5
 	precTenary      // ?, :
+	precRange       // .., ...
 	precLogicalOr   // ||
 	precLogicalAnd  // &&
 	precEquals      // ==, !=, <=>
@@ -31,6 +34,7 @@ const (
 	precSum         // + or -
 	precProduct     // *, /, %
 	precPrefix      // -X or !X
+	precPower       // **
 	precCallArg     // func x
 	precCall        // foo.myFunction(X)
 	precIndex       // array[index]
@@ -43,8 +47,14 @@ const (
 var precedences = map[token.Type]int{
 	token.IF:         precIfUnless,
 	token.UNLESS:     precIfUnless,
+	token.WHILE:      precIfUnless,
+	token.UNTIL:      precIfUnless,
+	token.RESCUE:     precIfUnless,
 	token.EQ:         precEquals,
 	token.NOTEQ:      precEquals,
+	token.CASEEQ:     precEquals,
+	token.MATCH:      precEquals,
+	token.NOTMATCH:   precEquals,
 	token.SPACESHIP:  precEquals,
 	token.LSHIFT:     precShift,
 	token.QMARK:      precTenary,
@@ -58,6 +68,7 @@ var precedences = map[token.Type]int{
 	token.SLASH:      precProduct,
 	token.ASTERISK:   precProduct,
 	token.MODULO:     precProduct,
+	token.POW:        precPower,
 	token.ASSIGN:     precAssignment,
 	token.ADDASSIGN:  precAssignment,
 	token.SUBASSIGN:  precAssignment,
@@ -66,12 +77,22 @@ var precedences = map[token.Type]int{
 	token.MODASSIGN:  precAssignment,
 	token.LPAREN:     precCall,
 	token.DOT:        precCall,
+	token.DOT2:       precRange,
+	token.DOT3:       precRange,
 	token.IDENT:      precCallArg,
 	token.CONST:      precCallArg,
 	token.GLOBAL:     precCallArg,
 	token.INT:        precCallArg,
+	token.FLOAT:      precCallArg,
 	token.STRING:     precCallArg,
+	token.HEREDOC:    precCallArg,
+	token.SYMBOL:     precCallArg,
+	token.WORDS:      precCallArg,
+	token.REGEX:      precCallArg,
 	token.SELF:       precCallArg,
+	token.NIL:        precCallArg,
+	token.TRUE:       precCallArg,
+	token.FALSE:      precCallArg,
 	token.LBRACKET:   precIndex,
 	token.LBRACE:     precBlockBraces,
 	token.DO:         precBlockDo,
@@ -89,6 +110,11 @@ var precedences = map[token.Type]int{
 
 var tokensNotPossibleInCallArgs = []token.Type{
 	token.ASSIGN,
+	token.ADDASSIGN,
+	token.SUBASSIGN,
+	token.MULASSIGN,
+	token.DIVASSIGN,
+	token.MODASSIGN,
 	token.LT,
 	token.LTE,
 	token.GT,
@@ -97,11 +123,21 @@ var tokensNotPossibleInCallArgs = []token.Type{
 	token.LSHIFT,
 	token.EQ,
 	token.NOTEQ,
+	token.CASEEQ,
+	token.MATCH,
+	token.NOTMATCH,
 	token.IF,
 	token.UNLESS,
+	token.WHILE,
+	token.UNTIL,
+	token.RESCUE,
 	token.COLON,
 	token.RBRACKET,
+	token.RPAREN,
 	token.COMMA,
+	token.THEN,
+	token.WHEN,
+	token.IN,
 }
 
 type (
@@ -118,6 +154,7 @@ var defaultExpressionTerminators = []token.Type{
 // AST describing the parsed program.
 type parser struct {
 	file   *gotoken.File
+	src    string
 	l      *lexer.Lexer
 	errors []error
 
@@ -138,7 +175,8 @@ type parser struct {
 func (p *parser) init(fset *gotoken.FileSet, filename string, src []byte, mode Mode) {
 	p.file = fset.AddFile(filename, -1, len(src))
 
-	p.l = lexer.New(string(src))
+	p.src = string(src)
+	p.l = lexer.NewWithFile(p.file, p.src)
 	p.errors = []error{}
 
 	p.mode = mode
@@ -149,7 +187,13 @@ func (p *parser) init(fset *gotoken.FileSet, filename string, src []byte, mode M
 	p.registerPrefix(token.CONST, p.parseIdentifier)
 	p.registerPrefix(token.AT, p.parseInstanceVariable)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
+	p.registerPrefix(token.ILLEGAL, p.parseIllegal)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.HEREDOC, p.parseHeredoc)
+	p.registerPrefix(token.SYMBOL, p.parsePercentSymbol)
+	p.registerPrefix(token.WORDS, p.parseWords)
+	p.registerPrefix(token.REGEX, p.parseRegexLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
@@ -158,6 +202,12 @@ func (p *parser) init(fset *gotoken.FileSet, filename string, src []byte, mode M
 	p.registerPrefix(token.IF, p.parseIfExpression)
 	p.registerPrefix(token.UNLESS, p.parseIfExpression)
 	p.registerPrefix(token.WHILE, p.parseLoopExpression)
+	p.registerPrefix(token.UNTIL, p.parseLoopExpression)
+	p.registerPrefix(token.BREAK, p.parseBreak)
+	p.registerPrefix(token.NEXT, p.parseNext)
+	p.registerPrefix(token.REDO, p.parseRedo)
+	p.registerPrefix(token.RETRY, p.parseRetry)
+	p.registerPrefix(token.CASE, p.parseCaseExpression)
 	p.registerPrefix(token.DEF, p.parseFunctionLiteral)
 	p.registerPrefix(token.SYMBEG, p.parseSymbolLiteral)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
@@ -171,18 +221,24 @@ func (p *parser) init(fset *gotoken.FileSet, filename string, src []byte, mode M
 	p.registerPrefix(token.GLOBAL, p.parseGlobal)
 	p.registerPrefix(token.KEYWORD__FILE__, p.parseKeyword__FILE__)
 	p.registerPrefix(token.BEGIN, p.parseExceptionHandlingBlock)
-	p.registerPrefix(token.CAPTURE, p.parseBlockCapture)
+	p.registerPrefix(token.CAPTURE, p.parseBlockArgument)
+	p.registerPrefix(token.DOT2, p.parseBeginlessRange)
+	p.registerPrefix(token.DOT3, p.parseBeginlessRange)
 
 	p.infixParseFns = make(map[token.Type]infixParseFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
-	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfix(token.ASTERISK, p.parseSplatArgumentOrInfixExpression)
 	p.registerInfix(token.MODULO, p.parseInfixExpression)
+	p.registerInfix(token.POW, p.parseSplatArgumentOrInfixExpression)
 	p.registerInfix(token.AND, p.parseInfixExpression)
 	p.registerInfix(token.PIPE, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NOTEQ, p.parseInfixExpression)
+	p.registerInfix(token.CASEEQ, p.parseInfixExpression)
+	p.registerInfix(token.MATCH, p.parseInfixExpression)
+	p.registerInfix(token.NOTMATCH, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
 	p.registerInfix(token.LTE, p.parseInfixExpression)
@@ -199,19 +255,32 @@ func (p *parser) init(fset *gotoken.FileSet, filename string, src []byte, mode M
 	p.registerInfix(token.MODASSIGN, p.parseAssignmentOperator)
 	p.registerInfix(token.IF, p.parseModifierConditionalExpression)
 	p.registerInfix(token.UNLESS, p.parseModifierConditionalExpression)
+	p.registerInfix(token.WHILE, p.parseModifierLoopExpression)
+	p.registerInfix(token.UNTIL, p.parseModifierLoopExpression)
+	p.registerInfix(token.RESCUE, p.parseModifierRescueExpression)
 	p.registerInfix(token.QMARK, p.parseTenaryIfExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpressionWithParens)
 	p.registerInfix(token.IDENT, p.parseCallArgument)
 	p.registerInfix(token.CONST, p.parseCallArgument)
 	p.registerInfix(token.GLOBAL, p.parseCallArgument)
 	p.registerInfix(token.INT, p.parseCallArgument)
+	p.registerInfix(token.FLOAT, p.parseCallArgument)
 	p.registerInfix(token.STRING, p.parseCallArgument)
+	p.registerInfix(token.HEREDOC, p.parseCallArgument)
+	p.registerInfix(token.SYMBOL, p.parseCallArgument)
+	p.registerInfix(token.WORDS, p.parseCallArgument)
+	p.registerInfix(token.REGEX, p.parseCallArgument)
 	p.registerInfix(token.SYMBEG, p.parseCallArgument)
 	p.registerInfix(token.CAPTURE, p.parseCallArgument)
 	p.registerInfix(token.SELF, p.parseCallArgument)
+	p.registerInfix(token.NIL, p.parseCallArgument)
+	p.registerInfix(token.TRUE, p.parseCallArgument)
+	p.registerInfix(token.FALSE, p.parseCallArgument)
 	p.registerInfix(token.LBRACE, p.parseCallBlock)
 	p.registerInfix(token.DO, p.parseCallBlock)
 	p.registerInfix(token.DOT, p.parseMethodCall)
+	p.registerInfix(token.DOT2, p.parseRange)
+	p.registerInfix(token.DOT3, p.parseRange)
 	p.registerInfix(token.COMMA, p.parseExpressions)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
 	p.registerInfix(token.SCOPE, p.parseScopedIdentifierExpression)
@@ -273,10 +342,9 @@ func (p *parser) nextToken() {
 		}
 	}
 	p.curToken = p.peekToken
-	p.pos = gotoken.Pos(p.curToken.Pos)
+	p.pos = p.position(p.curToken.Pos)
 	p.lastLine += p.curToken.Literal
 	if p.curToken.Type == token.NEWLINE {
-		p.file.AddLine(int(p.pos))
 		p.lastLine = ""
 	}
 	if p.l.HasNext() {
@@ -286,6 +354,14 @@ func (p *parser) nextToken() {
 	}
 }
 
+// position converts the token offset into a gotoken.Pos within p.file
+func (p *parser) position(offset int) gotoken.Pos {
+	if offset < 0 || offset > p.file.Size() {
+		return gotoken.NoPos
+	}
+	return p.file.Pos(offset)
+}
+
 // Errors returns all errors which happened during the parsing of the input.
 func (p *parser) Errors() []error {
 	return p.errors
@@ -311,13 +387,24 @@ func (p *parser) expectError(t ...token.Type) {
 	p.errors = append(p.errors, errors.WithStack(err))
 }
 
+// parseIllegal records the error message the lexer emitted as token.ILLEGAL
+func (p *parser) parseIllegal() ast.Expression {
+	msg := p.curToken.Literal
+	epos := p.file.Position(p.pos)
+	if epos.Filename != "" || epos.IsValid() {
+		msg = epos.String() + ": " + msg
+	}
+	p.errors = append(p.errors, errors.New(msg))
+	return nil
+}
+
 func (p *parser) noPrefixParseFnError(t token.Type) {
 	msg := fmt.Sprintf("no prefix parse function for type %s found", t)
 	epos := p.file.Position(p.pos)
 	if epos.Filename != "" || epos.IsValid() {
 		msg = epos.String() + ": " + msg
 	}
-	p.errors = append(p.errors, errors.Errorf(msg))
+	p.errors = append(p.errors, errors.New(msg))
 }
 
 // ParseProgram returns the parsed program AST and all errors which occured
@@ -351,12 +438,7 @@ func (p *parser) parseStatement() ast.Statement {
 	}
 	switch p.curToken.Type {
 	case token.ILLEGAL:
-		msg := p.curToken.Literal
-		epos := p.file.Position(p.pos)
-		if epos.Filename != "" || epos.IsValid() {
-			msg = epos.String() + ": " + msg
-		}
-		p.errors = append(p.errors, fmt.Errorf(msg))
+		p.parseIllegal()
 		return nil
 	case token.EOF:
 		p.expectError(token.NEWLINE)
@@ -419,7 +501,7 @@ func (p *parser) parseExpressionStatement() *ast.ExpressionStatement {
 		defer un(trace(p, "parseExpressionStatement"))
 	}
 	stmt := &ast.ExpressionStatement{Token: p.curToken}
-	stmt.Expression = p.parseExpression(precLowest)
+	stmt.Expression = p.parseAssignmentSide()
 	if p.peekTokenOneOf(token.SEMICOLON, token.NEWLINE) {
 		p.nextToken()
 	}
@@ -435,7 +517,12 @@ func (p *parser) parseExpression(precedence int) ast.Expression {
 		p.noPrefixParseFnError(p.curToken.Type)
 		return nil
 	}
-	leftExp := prefix()
+	return p.parseInfixExpressions(prefix(), precedence)
+}
+
+// parseInfixExpressions parses the infix operators following leftExp as
+// long as they bind stronger than precedence
+func (p *parser) parseInfixExpressions(leftExp ast.Expression, precedence int) ast.Expression {
 	for precedence < p.peekPrecedence() {
 		if leftExp == nil {
 			return nil // fail early and stop parsing
@@ -480,16 +567,11 @@ func (p *parser) parseExceptionHandlingBlock() ast.Expression {
 		defer un(trace(p, "parseExceptionHandlingBlock"))
 	}
 	block := &ast.ExceptionHandlingBlock{BeginToken: p.curToken}
-	if !p.accept(token.NEWLINE) {
+	if !p.acceptOneOf(token.NEWLINE, token.SEMICOLON) {
 		return nil
 	}
-	block.TryBody = p.parseBlockStatement(token.END, token.RESCUE)
-	block.Rescues = []*ast.RescueBlock{}
-	for p.peekTokenIs(token.RESCUE) {
-		p.accept(token.RESCUE)
-		rescue := p.parseRescueBlock()
-		block.Rescues = append(block.Rescues, rescue)
-	}
+	block.TryBody = p.parseBlockStatement(exceptionHandlingTerminators...)
+	block.Rescues, block.Else, block.Ensure = p.parseExceptionHandlingClauses()
 	if !p.accept(token.END) {
 		return nil
 	}
@@ -497,6 +579,47 @@ func (p *parser) parseExceptionHandlingBlock() ast.Expression {
 	return block
 }
 
+// exceptionHandlingTerminators are the tokens which end a begin or def body
+// and the clauses rescuing its exceptions
+var exceptionHandlingTerminators = []token.Type{
+	token.RESCUE,
+	token.ELSE,
+	token.ENSURE,
+}
+
+// parseExceptionHandlingClauses parses the rescue clauses and the optional
+// else and ensure clause following a begin or def body
+func (p *parser) parseExceptionHandlingClauses() ([]*ast.RescueBlock, *ast.BlockStatement, *ast.BlockStatement) {
+	if p.trace {
+		defer un(trace(p, "parseExceptionHandlingClauses"))
+	}
+	rescues := []*ast.RescueBlock{}
+	for p.peekTokenIs(token.RESCUE) {
+		p.accept(token.RESCUE)
+		rescue := p.parseRescueBlock()
+		if rescue == nil {
+			return rescues, nil, nil
+		}
+		rescues = append(rescues, rescue)
+	}
+	var elseBody, ensure *ast.BlockStatement
+	if p.peekTokenIs(token.ELSE) {
+		p.accept(token.ELSE)
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.accept(token.SEMICOLON)
+		}
+		elseBody = p.parseBlockStatement(token.ENSURE)
+	}
+	if p.peekTokenIs(token.ENSURE) {
+		p.accept(token.ENSURE)
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.accept(token.SEMICOLON)
+		}
+		ensure = p.parseBlockStatement()
+	}
+	return rescues, elseBody, ensure
+}
+
 func (p *parser) parseRescueBlock() *ast.RescueBlock {
 	if p.trace {
 		defer un(trace(p, "parseRescueBlock"))
@@ -520,10 +643,34 @@ func (p *parser) parseRescueBlock() *ast.RescueBlock {
 		}
 		block.Exception = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 	}
-	if !p.accept(token.NEWLINE) {
+	if !p.acceptOneOf(token.NEWLINE, token.SEMICOLON, token.THEN) {
 		return nil
 	}
-	block.Body = p.parseBlockStatement(token.END)
+	block.Body = p.parseBlockStatement(exceptionHandlingTerminators...)
+	return block
+}
+
+func (p *parser) parseModifierRescueExpression(left ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseModifierRescueExpression"))
+	}
+	block := &ast.ExceptionHandlingBlock{
+		BeginToken: p.curToken,
+		TryBody: &ast.BlockStatement{
+			Statements: []ast.Statement{
+				&ast.ExpressionStatement{Expression: left},
+			},
+		},
+	}
+	rescue := &ast.RescueBlock{Token: p.curToken}
+	p.nextToken()
+	fallback := p.parseExpression(precIfUnless)
+	rescue.Body = &ast.BlockStatement{
+		Statements: []ast.Statement{
+			&ast.ExpressionStatement{Expression: fallback},
+		},
+	}
+	block.Rescues = []*ast.RescueBlock{rescue}
 	return block
 }
 
@@ -532,15 +679,8 @@ func (p *parser) parseExpressions(left ast.Expression) ast.Expression {
 		defer un(trace(p, "parseExpressions"))
 	}
 	p.nextToken()
-	elements := []ast.Expression{left}
-	next := p.parseExpression(precAssignment)
-	elements = append(elements, next)
-	for p.peekTokenIs(token.COMMA) {
-		p.consume(token.COMMA)
-		next = p.parseExpression(precAssignment)
-		elements = append(elements, next)
-	}
-	return ast.ExpressionList(elements)
+	elements := p.parseListElement([]ast.Expression{left})
+	return ast.ExpressionList(p.parseListElements(elements))
 }
 
 func (p *parser) parseBlockCapture() ast.Expression {
@@ -555,6 +695,41 @@ func (p *parser) parseBlockCapture() ast.Expression {
 	return capture
 }
 
+// parseSplat parses a splat or double splat, which are only valid as call
+// argument, array element or target of a multiple assignment
+func (p *parser) parseSplat() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseSplat"))
+	}
+	splat := &ast.Splat{Token: p.curToken}
+	// anonymous splats only appear on the left side of assignments
+	if p.peekTokenOneOf(token.COMMA, token.ASSIGN, token.RPAREN) {
+		return splat
+	}
+	p.nextToken()
+	splat.Value = p.parseExpression(precAssignment)
+	if splat.Value == nil {
+		return nil
+	}
+	return splat
+}
+
+func (p *parser) parseBlockArgument() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseBlockArgument"))
+	}
+	if !p.peekTokenIs(token.SYMBEG) {
+		return p.parseBlockCapture()
+	}
+	capture := &ast.BlockCapture{Token: p.curToken}
+	p.nextToken()
+	capture.Value = p.parseSymbolLiteral()
+	if capture.Value == nil {
+		return nil
+	}
+	return capture
+}
+
 func (p *parser) parseAssignmentOperator(left ast.Expression) ast.Expression {
 	if p.trace {
 		defer un(trace(p, "parseAssignmentOperator"))
@@ -572,9 +747,11 @@ func (p *parser) parseAssignmentOperator(left ast.Expression) ast.Expression {
 		Left:  left,
 	}
 	p.nextToken()
-	newInf.Right = p.parseExpression(precLowest)
-	assign.Right = newInf
-	return assign
+	return p.hoistModifier(p.parseExpression(precLowest), func(right ast.Expression) ast.Expression {
+		newInf.Right = right
+		assign.Right = newInf
+		return assign
+	})
 }
 
 func (p *parser) parseAssignment(left ast.Expression) ast.Expression {
@@ -582,12 +759,20 @@ func (p *parser) parseAssignment(left ast.Expression) ast.Expression {
 		defer un(trace(p, "parseAssignment"))
 	}
 
-	switch left.(type) {
+	switch left := left.(type) {
 	case *ast.Identifier:
 	case *ast.Global:
 	case *ast.IndexExpression:
 	case *ast.InstanceVariable:
 	case ast.ExpressionList:
+	case *ast.Splat:
+	case *ast.ContextCallExpression:
+		// only attribute accesses like `foo.bar` can be assigned to, calling
+		// the setter `bar=`
+		if left.Context == nil || left.Arguments != nil || left.Block != nil {
+			p.expectError(token.EOF)
+			return nil
+		}
 	case *ast.Keyword__FILE__:
 		epos := p.file.Position(p.pos)
 		msg := fmt.Errorf("%s: Can't assign to __FILE__", epos.String())
@@ -603,29 +788,49 @@ func (p *parser) parseAssignment(left ast.Expression) ast.Expression {
 		Left:  left,
 	}
 	p.nextToken()
-	expr := p.parseExpression(precLowest)
-	right, ok := expr.(*ast.ConditionalExpression)
-	if !ok {
-		assign.Right = expr
+	return p.hoistModifier(p.parseAssignmentSide(), func(right ast.Expression) ast.Expression {
+		assign.Right = right
 		return assign
+	})
+}
+
+// parseAssignmentSide parses an expression which may also be a side of a
+// multiple assignment starting with a splat, e.g. `*a, b = 1, *c`
+func (p *parser) parseAssignmentSide() ast.Expression {
+	if p.currentTokenIs(token.ASTERISK) {
+		return p.parseInfixExpressions(p.parseSplat(), precLowest)
+	}
+	return p.parseExpression(precLowest)
+}
+
+// hoistModifier lifts a trailing if, unless, while or until modifier out of
+// the right hand side of an assignment so that it applies to the whole
+// assignment. assign gets the unwrapped right hand side and returns the
+// assignment expression.
+func (p *parser) hoistModifier(right ast.Expression, assign func(ast.Expression) ast.Expression) ast.Expression {
+	var body *ast.BlockStatement
+	switch modifier := right.(type) {
+	case *ast.ConditionalExpression:
+		if !modifier.Token.Type.IsKeyword() || modifier.EndToken.Type != token.ILLEGAL {
+			return assign(right)
+		}
+		body = modifier.Consequence
+	case *ast.LoopExpression:
+		if !modifier.IsModifier() {
+			return assign(right)
+		}
+		body = modifier.Block
+		modifier.PostCondition = false
+	default:
+		return assign(right)
 	}
-	expStmt, ok := right.Consequence.Statements[0].(*ast.ExpressionStatement)
+	expStmt, ok := body.Statements[0].(*ast.ExpressionStatement)
 	if !ok {
 		p.errors = append(p.errors, fmt.Errorf("malformed AST in assignment"))
 		return nil
 	}
-	assign.Right = expStmt.Expression
-	cond := &ast.ConditionalExpression{
-		Token:     right.Token,
-		Condition: right.Condition,
-		Consequence: &ast.BlockStatement{
-			Statements: []ast.Statement{
-				&ast.ExpressionStatement{Expression: assign},
-			},
-		},
-	}
-
-	return cond
+	body.Statements[0] = &ast.ExpressionStatement{Expression: assign(expStmt.Expression)}
+	return right
 }
 
 func (p *parser) parseInstanceVariable() ast.Expression {
@@ -725,193 +930,855 @@ func (p *parser) parseIntegerLiteral() ast.Expression {
 		defer un(trace(p, "parseIntegerLiteral"))
 	}
 	lit := &ast.IntegerLiteral{Token: p.curToken}
-	value, err := strconv.ParseInt(integerLiteralReplacer.Replace(p.curToken.Literal), 0, 64)
+	literal := integerLiteralReplacer.Replace(p.curToken.Literal)
+	if strings.HasPrefix(strings.ToLower(literal), "0d") {
+		// strconv does not know about the explicit decimal prefix
+		literal = literal[2:]
+	}
+	value, err := strconv.ParseInt(literal, 0, 64)
 	if err != nil {
-		msg := fmt.Errorf("could not parse %q as integer", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
-		return nil
+		bigValue, ok := new(big.Int).SetString(literal, 0)
+		if !ok {
+			msg := fmt.Errorf("could not parse %q as integer", p.curToken.Literal)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		lit.BigValue = bigValue
+		return lit
 	}
 	lit.Value = value
 	return lit
 }
 
-func (p *parser) parseStringLiteral() ast.Expression {
+func (p *parser) parseFloatLiteral() ast.Expression {
 	if p.trace {
-		defer un(trace(p, "parseStringLiteral"))
+		defer un(trace(p, "parseFloatLiteral"))
 	}
-	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
-}
-
-func (p *parser) parseSymbolLiteral() ast.Expression {
-	if p.trace {
-		defer un(trace(p, "parseSymbolLiteral"))
+	lit := &ast.FloatLiteral{Token: p.curToken}
+	value, err := strconv.ParseFloat(integerLiteralReplacer.Replace(p.curToken.Literal), 64)
+	// out of range literals are ±Infinity or 0, as in MRI
+	if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+		err = nil
 	}
-	symbol := &ast.SymbolLiteral{Token: p.curToken}
-	if !p.acceptOneOf(token.IDENT, token.STRING) {
+	if err != nil {
+		msg := fmt.Errorf("could not parse %q as float", p.curToken.Literal)
+		p.errors = append(p.errors, msg)
 		return nil
 	}
-	val := p.parseExpression(precHighest)
-	symbol.Value = val
-	return symbol
+	lit.Value = value
+	return lit
 }
 
-func (p *parser) parseArrayLiteral() ast.Expression {
+func (p *parser) parseStringLiteral() ast.Expression {
 	if p.trace {
-		defer un(trace(p, "parseArrayLiteral"))
+		defer un(trace(p, "parseStringLiteral"))
 	}
-	array := &ast.ArrayLiteral{Token: p.curToken}
-
-	p.nextToken()
-	array.Elements = p.parseExpressionList(token.RBRACKET)
-	array.Rbracket = p.curToken
-	return array
-}
-
-func (p *parser) parseBoolean() ast.Expression {
-	if p.trace {
-		defer un(trace(p, "parseBoolean"))
+	str := p.parseSingleStringLiteral()
+	// adjacent string literals are joined, character literals are not
+	for str != nil && !p.isCharacterLiteral(p.curToken) && p.peekTokenIs(token.STRING) && !p.isCharacterLiteral(p.peekToken) {
+		p.nextToken()
+		next := p.parseSingleStringLiteral()
+		if next == nil {
+			return nil
+		}
+		str = p.concatenateStrings(str, next)
 	}
-	return &ast.Boolean{Token: p.curToken, Value: p.currentTokenIs(token.TRUE)}
+	return str
 }
 
-func (p *parser) parseHash() ast.Expression {
-	hash := &ast.HashLiteral{Token: p.curToken, Map: make(map[ast.Expression]ast.Expression)}
-	if p.trace {
-		defer un(trace(p, "parseHash"))
-	}
-	p.nextToken()
+func (p *parser) isCharacterLiteral(tok token.Token) bool {
+	return tok.Type == token.STRING && strings.HasPrefix(tok.Literal, "?")
+}
 
-	if p.currentTokenIs(token.RBRACE) {
-		hash.Rbrace = p.curToken
-		return hash
+// concatenateStrings joins the adjacent string literals left and right into
+// one literal spanning both within the source
+func (p *parser) concatenateStrings(left, right ast.Expression) ast.Expression {
+	tok := token.NewToken(token.STRING, p.src[left.Pos():right.End()], left.Pos())
+	leftLit, leftOk := left.(*ast.StringLiteral)
+	rightLit, rightOk := right.(*ast.StringLiteral)
+	if leftOk && rightOk {
+		return &ast.StringLiteral{Token: tok, Value: leftLit.Value + rightLit.Value}
 	}
-
-	k, v, ok := p.parseKeyValue()
-	if !ok {
-		return nil
+	var parts []ast.Node
+	for _, str := range []ast.Expression{left, right} {
+		switch str := str.(type) {
+		case *ast.InterpolatedStringLiteral:
+			parts = append(parts, str.Parts...)
+		case *ast.StringLiteral:
+			if str.Value != "" {
+				parts = append(parts, str)
+			}
+		}
 	}
-	hash.Map[k] = v
+	return &ast.InterpolatedStringLiteral{Token: tok, Parts: parts}
+}
 
-	for p.peekTokenIs(token.COMMA) {
-		p.consume(token.COMMA)
-		k, v, ok := p.parseKeyValue()
-		if !ok {
+// parseSingleStringLiteral parses the string literal at the current token
+func (p *parser) parseSingleStringLiteral() ast.Expression {
+	lit := &ast.StringLiteral{Token: p.curToken}
+	literal := p.curToken.Literal
+	switch {
+	case strings.HasPrefix(literal, "?\\"):
+		value, next, err := readEscape(literal, 2)
+		if err == nil && next != len(literal) {
+			err = fmt.Errorf("invalid character syntax")
+		}
+		if err != nil {
+			p.stringError(p.curToken.Pos, err)
 			return nil
 		}
-		hash.Map[k] = v
+		lit.Value = value
+	case strings.HasPrefix(literal, "?"):
+		lit.Value = literal[1:]
+	case strings.HasPrefix(literal, "'"):
+		lit.Value = unescapeQuoted(literal[1:len(literal)-1], "'")
+	case strings.HasPrefix(literal, "%"):
+		return p.parsePercentString()
+	case len(literal) >= 2:
+		return p.parseDoubleQuotedString()
 	}
+	return lit
+}
 
-	if !p.accept(token.RBRACE) {
-		return nil
-	}
-	hash.Rbrace = p.curToken
-	return hash
+// parseDoubleQuotedString decodes the escape sequences within the current
+// double quoted string token and parses interpolated code. It returns an
+// *ast.StringLiteral if the string does not contain any interpolation.
+func (p *parser) parseDoubleQuotedString() ast.Expression {
+	tok := p.curToken
+	return p.parseStringContent(tok, tok.Literal[1:len(tok.Literal)-1], tok.Pos+1, nil)
 }
 
-func (p *parser) parseKeyValue() (ast.Expression, ast.Expression, bool) {
-	key := p.parseExpression(precAssignment)
-	if !p.consume(token.HASHROCKET) {
-		return nil, nil, false
+// parseStringContent decodes the escape sequences within content and parses
+// interpolated code. offset is the position of content within the source.
+// skip maps indices within content to the index to continue at, which is
+// used to strip the indentation of heredocs.
+func (p *parser) parseStringContent(tok token.Token, content string, offset int, skip map[int]int) ast.Expression {
+	words, ok := p.parseStringWords(tok, content, offset, skip, false)
+	if !ok {
+		return nil
 	}
-	val := p.parseExpression(precAssignment)
-	return key, val, true
+	if len(words) == 0 {
+		return &ast.StringLiteral{Token: tok, Value: ""}
+	}
+	return words[0]
 }
 
-func (p *parser) parseBlock() ast.Expression {
-	if p.trace {
-		defer un(trace(p, "parseBlock"))
+// parseStringWords is like parseStringContent but splits content at
+// whitespace into several words if split is true, as for `%W()`. It returns
+// false if any escape sequence or interpolation is invalid.
+func (p *parser) parseStringWords(tok token.Token, content string, offset int, skip map[int]int, split bool) ([]ast.Expression, bool) {
+	var words []ast.Expression
+	var parts []ast.Node
+	var segment strings.Builder
+	segmentStart, wordStart, inWord := 0, 0, false
+	flushSegment := func(end int) {
+		if segment.Len() == 0 {
+			return
+		}
+		parts = append(parts, &ast.StringLiteral{
+			Token: token.NewToken(token.STRING, content[segmentStart:end], offset+segmentStart),
+			Value: segment.String(),
+		})
+		segment.Reset()
+	}
+	finishWord := func(end int) {
+		flushSegment(end)
+		wordToken := tok
+		if split {
+			wordToken = token.NewToken(token.STRING, content[wordStart:end], offset+wordStart)
+		}
+		var value strings.Builder
+		for _, part := range parts {
+			lit, ok := part.(*ast.StringLiteral)
+			if !ok {
+				words = append(words, &ast.InterpolatedStringLiteral{Token: wordToken, Parts: parts})
+				parts, inWord = nil, false
+				return
+			}
+			value.WriteString(lit.Value)
+		}
+		words = append(words, &ast.StringLiteral{Token: wordToken, Value: value.String()})
+		parts, inWord = nil, false
+	}
+	for i := 0; i < len(content); {
+		if next, ok := skip[i]; ok && next > i {
+			flushSegment(i)
+			i = next
+			segmentStart = i
+			continue
+		}
+		if split && unicode.IsSpace(rune(content[i])) {
+			if inWord {
+				finishWord(i)
+			}
+			i++
+			segmentStart, wordStart = i, i
+			continue
+		}
+		inWord = true
+		switch {
+		case content[i] == '\\':
+			decoded, next, err := readEscape(content, i+1)
+			if err != nil {
+				p.stringError(offset+i, err)
+				return nil, false
+			}
+			segment.WriteString(decoded)
+			i = next
+		case strings.HasPrefix(content[i:], "#{"):
+			flushSegment(i)
+			body := p.parseInterpolation(offset + i + 1)
+			if body == nil {
+				return nil, false
+			}
+			parts = append(parts, body)
+			i = body.EndToken.Pos + len(body.EndToken.Literal) - offset
+			segmentStart = i
+		default:
+			segment.WriteByte(content[i])
+			i++
+		}
 	}
-	block := &ast.BlockExpression{Token: p.curToken}
-	if p.peekTokenIs(token.PIPE) {
-		block.Parameters = p.parseParameters(token.PIPE, token.PIPE)
+	if inWord || !split {
+		finishWord(len(content))
 	}
+	return words, true
+}
 
-	if p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
-		p.acceptOneOf(token.NEWLINE, token.SEMICOLON)
+// parsePercentString parses the percent string literals `%q()`, `%Q()` and
+// `%()`
+func (p *parser) parsePercentString() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parsePercentString"))
 	}
-
-	endToken := token.RBRACE
-	if block.Token.Type == token.DO {
-		endToken = token.END
+	tok := p.curToken
+	kind, content, offset := percentLiteralContent(tok)
+	if kind == 'q' {
+		return &ast.StringLiteral{Token: tok, Value: unescapeQuoted(content, percentDelimiters(tok))}
 	}
-
-	block.Body = p.parseBlockStatement(endToken)
-	p.nextToken()
-	block.EndToken = p.curToken
-	return block
+	return p.parseStringContent(tok, content, offset, nil)
 }
 
-func (p *parser) parsePrefixExpression() ast.Expression {
+// parsePercentSymbol parses the percent symbol literal `%s()`
+func (p *parser) parsePercentSymbol() ast.Expression {
 	if p.trace {
-		defer un(trace(p, "parsePrefixExpression"))
-	}
-	expression := &ast.PrefixExpression{
-		Token:    p.curToken,
-		Operator: p.curToken.Literal,
+		defer un(trace(p, "parsePercentSymbol"))
 	}
-	p.nextToken()
-	expression.Right = p.parseExpression(precPrefix)
-	return expression
+	tok := p.curToken
+	_, content, _ := percentLiteralContent(tok)
+	value := &ast.StringLiteral{Token: tok, Value: unescapeQuoted(content, percentDelimiters(tok))}
+	return &ast.SymbolLiteral{Token: tok, Value: value}
 }
 
-func (p *parser) parseInfixExpression(left ast.Expression) ast.Expression {
+// parseWords parses the word and symbol array literals `%w()`, `%W()`,
+// `%i()` and `%I()` into an *ast.ArrayLiteral
+func (p *parser) parseWords() ast.Expression {
 	if p.trace {
-		defer un(trace(p, "parseInfixExpression"))
+		defer un(trace(p, "parseWords"))
+	}
+	tok := p.curToken
+	kind, content, offset := percentLiteralContent(tok)
+	array := &ast.ArrayLiteral{
+		Token:    tok,
+		Rbracket: token.NewToken(token.RBRACKET, tok.Literal[len(tok.Literal)-1:], tok.Pos+len(tok.Literal)-1),
+		Elements: []ast.Expression{},
+	}
+	var words []ast.Expression
+	if kind == 'W' || kind == 'I' {
+		var ok bool
+		words, ok = p.parseStringWords(tok, content, offset, nil, true)
+		if !ok {
+			return nil
+		}
+	} else {
+		words = splitWords(content, offset, percentDelimiters(tok))
 	}
-	expression := &ast.InfixExpression{
-		Token:    p.curToken,
-		Operator: p.curToken.Literal,
-		Left:     left,
+	for _, word := range words {
+		if kind == 'i' || kind == 'I' {
+			word = &ast.SymbolLiteral{Token: token.NewToken(token.SYMBEG, "", word.Pos()), Value: word}
+		}
+		array.Elements = append(array.Elements, word)
 	}
-	precedence := p.curPrecedence()
-	p.nextToken()
-	expression.Right = p.parseExpression(precedence)
-	return expression
+	return array
 }
 
-func (p *parser) parseIndexExpression(left ast.Expression) ast.Expression {
+// parseRegexLiteral parses the regular expression literals `/regex/` and
+// `%r{regex}` including their flags
+func (p *parser) parseRegexLiteral() ast.Expression {
 	if p.trace {
-		defer un(trace(p, "parseIndexExpression"))
+		defer un(trace(p, "parseRegexLiteral"))
 	}
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
-
-	p.nextToken()
-	exp.Index = p.parseExpression(precLowest)
-	if elist, ok := exp.Index.(ast.ExpressionList); ok {
-		exp.Index = elist[0]
-		exp.Length = elist[1]
+	tok := p.curToken
+	var content string
+	var offset int
+	if strings.HasPrefix(tok.Literal, "%") {
+		_, content, offset = percentLiteralContent(tok)
+	} else {
+		content, offset = tok.Literal[1:strings.LastIndexByte(tok.Literal, '/')], tok.Pos+1
 	}
-
-	if !p.accept(token.RBRACKET) {
+	value, ok := p.parseRegexContent(tok, content, offset)
+	if !ok {
 		return nil
 	}
-	return exp
+	flags := tok.Literal[offset-tok.Pos+len(content)+1:]
+	return &ast.RegexLiteral{Token: tok, Value: value, Flags: flags}
 }
 
-func (p *parser) parseGroupedExpression() ast.Expression {
-	if p.trace {
-		defer un(trace(p, "parseGroupedExpression"))
+// parseRegexContent parses the pattern of a regular expression literal into
+// an *ast.StringLiteral or, if it contains interpolated code, an
+// *ast.InterpolatedStringLiteral. Unlike within strings, escape sequences
+// are kept as written for the regular expression engine to interpret.
+func (p *parser) parseRegexContent(tok token.Token, content string, offset int) (ast.Expression, bool) {
+	var parts []ast.Node
+	segmentStart := 0
+	flushSegment := func(end int) {
+		if end <= segmentStart {
+			return
+		}
+		parts = append(parts, &ast.StringLiteral{
+			Token: token.NewToken(token.STRING, content[segmentStart:end], offset+segmentStart),
+			Value: content[segmentStart:end],
+		})
 	}
-	p.nextToken()
-	exp := p.parseExpression(precLowest)
-	if !p.accept(token.RPAREN) {
-		return nil
+	for i := 0; i < len(content); {
+		switch {
+		case content[i] == '\\':
+			i += 2
+		case strings.HasPrefix(content[i:], "#{"):
+			flushSegment(i)
+			body := p.parseInterpolation(offset + i + 1)
+			if body == nil {
+				return nil, false
+			}
+			parts = append(parts, body)
+			i = body.EndToken.Pos + len(body.EndToken.Literal) - offset
+			segmentStart = i
+		default:
+			i++
+		}
 	}
-	return exp
+	flushSegment(len(content))
+	switch {
+	case len(parts) == 0:
+		return &ast.StringLiteral{Token: tok, Value: ""}, true
+	case len(parts) == 1:
+		if lit, ok := parts[0].(*ast.StringLiteral); ok {
+			return &ast.StringLiteral{Token: tok, Value: lit.Value}, true
+		}
+	}
+	return &ast.InterpolatedStringLiteral{Token: tok, Parts: parts}, true
 }
 
-func (p *parser) parseIfExpression() ast.Expression {
-	if p.trace {
-		defer un(trace(p, "parseIfExpression"))
+var percentLiteralClosers = map[byte]byte{
+	'(': ')',
+	'[': ']',
+	'{': '}',
+	'<': '>',
+}
+
+// percentLiteralContent returns the type character, the content between the
+// delimiters and the position of the content within the source of the
+// percent literal tok
+func percentLiteralContent(tok token.Token) (byte, string, int) {
+	literal := tok.Literal
+	kind, start := byte('Q'), 1
+	if strings.IndexByte("qQwWiIsr", literal[1]) != -1 {
+		kind, start = literal[1], 2
 	}
-	expression := &ast.ConditionalExpression{Token: p.curToken}
-	p.nextToken()
-	expression.Condition = p.parseExpression(precLowest)
-	if p.peekTokenIs(token.THEN) {
-		p.accept(token.THEN)
+	closer, ok := percentLiteralClosers[literal[start]]
+	if !ok {
+		closer = literal[start]
+	}
+	end := strings.LastIndexByte(literal, closer)
+	return kind, literal[start+1 : end], tok.Pos + start + 1
+}
+
+// percentDelimiters returns the opening and closing delimiter of the percent
+// literal tok
+func percentDelimiters(tok token.Token) string {
+	_, content, offset := percentLiteralContent(tok)
+	start := offset - tok.Pos - 1
+	return tok.Literal[start:start+1] + tok.Literal[start+len(content)+1:start+len(content)+2]
+}
+
+// splitWords splits the content of a non interpolating word array literal at
+// unescaped whitespace. Backslashes only escape whitespace, backslashes and
+// the delimiters.
+func splitWords(content string, offset int, delimiters string) []ast.Expression {
+	var words []ast.Expression
+	var word strings.Builder
+	wordStart, inWord := 0, false
+	finishWord := func(end int) {
+		words = append(words, &ast.StringLiteral{
+			Token: token.NewToken(token.STRING, content[wordStart:end], offset+wordStart),
+			Value: word.String(),
+		})
+		word.Reset()
+		inWord = false
+	}
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if unicode.IsSpace(rune(c)) {
+			if inWord {
+				finishWord(i)
+			}
+			wordStart = i + 1
+			continue
+		}
+		inWord = true
+		if c == '\\' && i+1 < len(content) {
+			next := content[i+1]
+			if unicode.IsSpace(rune(next)) || next == '\\' || strings.IndexByte(delimiters, next) != -1 {
+				c = next
+				i++
+			}
+		}
+		word.WriteByte(c)
+	}
+	if inWord {
+		finishWord(len(content))
 	}
+	return words
+}
 
-	if !p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
+// parseHeredoc parses a heredoc, i.e. the token.HEREDOC holding the
+// identifier and the following token.STRING holding the body
+func (p *parser) parseHeredoc() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseHeredoc"))
+	}
+	tok := p.curToken
+	if !p.accept(token.STRING) {
+		return nil
+	}
+	body := p.curToken
+	id := strings.TrimLeft(tok.Literal[2:], "-")
+	var skip map[int]int
+	if strings.HasPrefix(id, "~") {
+		id = id[1:]
+		skip = heredocIndentation(body.Literal)
+	}
+	if !strings.HasPrefix(id, "'") {
+		return p.parseStringContent(tok, body.Literal, body.Pos, skip)
+	}
+	var value strings.Builder
+	for i := 0; i < len(body.Literal); i++ {
+		if next, ok := skip[i]; ok && next > i {
+			i = next - 1
+			continue
+		}
+		value.WriteByte(body.Literal[i])
+	}
+	return &ast.StringLiteral{Token: tok, Value: value.String()}
+}
+
+// heredocIndentation returns the indentation to strip from the lines of a
+// squiggly heredoc body as map from line start to the index after the
+// indentation. The indentation stripped is the one of the least indented
+// line, ignoring lines consisting of whitespace only. Tabs count as up to
+// eight spaces, as in MRI.
+func heredocIndentation(body string) map[int]int {
+	var lineStarts []int
+	for i := 0; i < len(body); i++ {
+		if i == 0 || body[i-1] == '\n' {
+			lineStarts = append(lineStarts, i)
+		}
+	}
+	indentation := -1
+	for _, start := range lineStarts {
+		width := 0
+		i := start
+		for ; i < len(body) && (body[i] == ' ' || body[i] == '\t'); i++ {
+			width = indentWidth(width, body[i])
+		}
+		if i == len(body) || body[i] == '\n' || body[i] == '\r' {
+			continue
+		}
+		if indentation == -1 || width < indentation {
+			indentation = width
+		}
+	}
+	skip := make(map[int]int)
+	for _, start := range lineStarts {
+		width := 0
+		i := start
+		for ; i < len(body) && (body[i] == ' ' || body[i] == '\t'); i++ {
+			next := indentWidth(width, body[i])
+			if next > indentation {
+				break
+			}
+			width = next
+		}
+		skip[start] = i
+	}
+	return skip
+}
+
+// indentWidth returns the indentation width after adding c to width
+func indentWidth(width int, c byte) int {
+	if c == '\t' {
+		return (width/8 + 1) * 8
+	}
+	return width + 1
+}
+
+// parseInterpolation parses the code of an interpolation within a string
+// literal. offset points to the opening brace of the interpolation within
+// the source. The returned block spans from the `#` before the opening brace
+// to the closing brace.
+func (p *parser) parseInterpolation(offset int) *ast.BlockStatement {
+	if p.trace {
+		defer un(trace(p, "parseInterpolation"))
+	}
+	l, curToken, peekToken, pos, lastLine := p.l, p.curToken, p.peekToken, p.pos, p.lastLine
+	defer func() {
+		p.l, p.curToken, p.peekToken, p.pos, p.lastLine = l, curToken, peekToken, pos, lastLine
+	}()
+	p.l = lexer.NewAt(p.file, p.src, offset)
+	p.nextToken()
+	p.nextToken()
+	errCount := len(p.errors)
+	body := p.parseBlockStatement(token.RBRACE)
+	if !p.accept(token.RBRACE) || len(p.errors) != errCount {
+		return nil
+	}
+	body.Token = token.NewToken(token.LBRACE, "#{", offset-1)
+	body.EndToken = p.curToken
+	return body
+}
+
+// stringError records err found at offset within a string literal
+func (p *parser) stringError(offset int, err error) {
+	msg := err.Error()
+	epos := p.file.Position(p.position(offset))
+	if epos.Filename != "" || epos.IsValid() {
+		msg = epos.String() + ": " + msg
+	}
+	p.errors = append(p.errors, errors.New(msg))
+}
+
+func (p *parser) parseSymbolLiteral() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseSymbolLiteral"))
+	}
+	symbol := &ast.SymbolLiteral{Token: p.curToken}
+	if !p.acceptOneOf(token.IDENT, token.STRING) {
+		return nil
+	}
+	val := p.parseExpression(precHighest)
+	symbol.Value = val
+	return symbol
+}
+
+func (p *parser) parseArrayLiteral() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseArrayLiteral"))
+	}
+	array := &ast.ArrayLiteral{Token: p.curToken}
+
+	p.nextToken()
+	array.Elements = p.parseExpressionList(token.RBRACKET)
+	array.Rbracket = p.curToken
+	return array
+}
+
+func (p *parser) parseRange(low ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseRange"))
+	}
+	return p.parseRangeEnd(low, precRange)
+}
+
+func (p *parser) parseBeginlessRange() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseBeginlessRange"))
+	}
+	return p.parseRangeEnd(nil, precRange)
+}
+
+// parseRangeEnd parses the upper bound of the range starting at low with the
+// given precedence. The range is endless if there is no upper bound, e.g.
+// `(1..)`.
+func (p *parser) parseRangeEnd(low ast.Expression, precedence int) ast.Expression {
+	rng := &ast.RangeLiteral{Token: p.curToken, Low: low}
+	_, isExpressionStart := p.prefixParseFns[p.peekToken.Type]
+	if !isExpressionStart || p.peekTokenOneOf(token.IF, token.UNLESS, token.WHILE, token.UNTIL) {
+		if low == nil {
+			p.noPrefixParseFnError(p.peekToken.Type)
+			return nil
+		}
+		return rng
+	}
+	p.nextToken()
+	rng.High = p.parseExpression(precedence)
+	if rng.High == nil {
+		return nil
+	}
+	return rng
+}
+
+func (p *parser) parseBoolean() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseBoolean"))
+	}
+	return &ast.Boolean{Token: p.curToken, Value: p.currentTokenIs(token.TRUE)}
+}
+
+func (p *parser) parseHash() ast.Expression {
+	hash := &ast.HashLiteral{Token: p.curToken}
+	if p.trace {
+		defer un(trace(p, "parseHash"))
+	}
+	p.nextToken()
+
+	// pairs may span multiple lines and end with a trailing comma
+	for {
+		for p.currentTokenIs(token.NEWLINE) {
+			p.nextToken()
+		}
+		if p.currentTokenIs(token.RBRACE) {
+			break
+		}
+		k, v, ok := p.parseKeyValue()
+		if !ok {
+			return nil
+		}
+		hash.Keys = append(hash.Keys, k)
+		hash.Values = append(hash.Values, v)
+		for p.peekTokenIs(token.NEWLINE) {
+			p.nextToken()
+		}
+		if !p.peekTokenIs(token.COMMA) {
+			if !p.accept(token.RBRACE) {
+				return nil
+			}
+			break
+		}
+		p.consume(token.COMMA)
+	}
+
+	hash.Rbrace = p.curToken
+	return hash
+}
+
+func (p *parser) parseKeyValue() (ast.Expression, ast.Expression, bool) {
+	if p.isHashLabel() {
+		return p.parseHashLabel()
+	}
+	key := p.parseExpression(precAssignment)
+	if !p.consume(token.HASHROCKET) {
+		return nil, nil, false
+	}
+	val := p.parseExpression(precAssignment)
+	return key, val, true
+}
+
+// isHashLabel reports whether the current token is the label of a
+// `key: value` pair, i.e. an identifier, constant, keyword or string
+// immediately followed by a colon
+func (p *parser) isHashLabel() bool {
+	if !p.currentTokenOneOf(token.IDENT, token.CONST, token.STRING) && !p.curToken.IsKeyword() {
+		return false
+	}
+	if !p.peekTokenOneOf(token.COLON, token.SYMBEG) {
+		return false
+	}
+	return p.peekToken.Pos == p.curToken.Pos+len(p.curToken.Literal)
+}
+
+// parseHashLabel parses a `key: value` pair, with key as Symbol. The value
+// can be omitted for identifiers, e.g. `{x:}`, in which case it is the local
+// variable or method named like the key.
+func (p *parser) parseHashLabel() (ast.Expression, ast.Expression, bool) {
+	if p.trace {
+		defer un(trace(p, "parseHashLabel"))
+	}
+	label := p.curToken
+	key := &ast.SymbolLiteral{Token: label}
+	if p.currentTokenIs(token.STRING) {
+		key.Value = p.parseExpression(precHighest)
+	} else {
+		key.Value = &ast.Identifier{Token: label, Value: label.Literal}
+	}
+	p.nextToken()
+	if p.peekTokenOneOf(token.COMMA, token.RBRACE, token.RPAREN, token.RBRACKET, token.NEWLINE, token.SEMICOLON, token.EOF) {
+		if label.Type != token.IDENT {
+			p.peekError(p.curToken.Type)
+			return nil, nil, false
+		}
+		return key, &ast.Identifier{Token: label, Value: label.Literal}, true
+	}
+	p.nextToken()
+	val := p.parseExpression(precAssignment)
+	return key, val, true
+}
+
+// parseListElement parses the element of a comma separated list starting at
+// the current token and appends it to list. Pairs given as `key: value` or
+// `key => value` are collected into a trailing Hash without braces.
+func (p *parser) parseListElement(list []ast.Expression) []ast.Expression {
+	// a trailing comma continues the list on the next line
+	for p.currentTokenIs(token.NEWLINE) {
+		p.nextToken()
+	}
+	if p.isHashLabel() {
+		key, val, ok := p.parseHashLabel()
+		if !ok {
+			return append(list, nil)
+		}
+		return appendHashPair(list, key, val)
+	}
+	if p.currentTokenOneOf(token.ASTERISK, token.POW) {
+		return append(list, p.parseSplat())
+	}
+	element := p.parseExpression(precAssignment)
+	if element == nil || !p.peekTokenIs(token.HASHROCKET) {
+		return append(list, element)
+	}
+	return p.parseListPair(list, element)
+}
+
+// parseListPair parses the value for key in a list of call arguments and
+// appends the pair to list
+func (p *parser) parseListPair(list []ast.Expression, key ast.Expression) []ast.Expression {
+	p.consume(token.HASHROCKET)
+	val := p.parseExpression(precAssignment)
+	return appendHashPair(list, key, val)
+}
+
+// parseListElements parses the remaining comma separated elements of a list
+// and appends them to list
+func (p *parser) parseListElements(list []ast.Expression) []ast.Expression {
+	for p.peekTokenIs(token.COMMA) {
+		p.consume(token.COMMA)
+		list = p.parseListElement(list)
+	}
+	return list
+}
+
+// appendHashPair adds the pair to the Hash without braces at the end of list,
+// which is added if there is none yet
+func appendHashPair(list []ast.Expression, key, val ast.Expression) []ast.Expression {
+	if len(list) > 0 {
+		if hash, ok := list[len(list)-1].(*ast.HashLiteral); ok && hash.Token.Type == token.ILLEGAL {
+			hash.Keys = append(hash.Keys, key)
+			hash.Values = append(hash.Values, val)
+			return list
+		}
+	}
+	hash := &ast.HashLiteral{Keys: []ast.Expression{key}, Values: []ast.Expression{val}}
+	return append(list, hash)
+}
+
+func (p *parser) parseBlock() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseBlock"))
+	}
+	block := &ast.BlockExpression{Token: p.curToken}
+	if p.peekTokenIs(token.PIPE) {
+		block.Parameters = p.parseParameters(token.PIPE, token.PIPE)
+	}
+
+	if p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
+		p.acceptOneOf(token.NEWLINE, token.SEMICOLON)
+	}
+
+	endToken := token.RBRACE
+	if block.Token.Type == token.DO {
+		endToken = token.END
+	}
+
+	block.Body = p.parseBlockStatement(endToken)
+	p.nextToken()
+	block.EndToken = p.curToken
+	return block
+}
+
+func (p *parser) parsePrefixExpression() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parsePrefixExpression"))
+	}
+	expression := &ast.PrefixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+	}
+	p.nextToken()
+	expression.Right = p.parseExpression(precPrefix)
+	return expression
+}
+
+func (p *parser) parseInfixExpression(left ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseInfixExpression"))
+	}
+	expression := &ast.InfixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+		Left:     left,
+	}
+	precedence := p.curPrecedence()
+	p.nextToken()
+	expression.Right = p.parseExpression(precedence)
+	return expression
+}
+
+func (p *parser) parseRightAssociativeInfixExpression(left ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseRightAssociativeInfixExpression"))
+	}
+	expression := &ast.InfixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+		Left:     left,
+	}
+	precedence := p.curPrecedence()
+	p.nextToken()
+	expression.Right = p.parseExpression(precedence - 1)
+	return expression
+}
+
+func (p *parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseIndexExpression"))
+	}
+	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+
+	p.nextToken()
+	exp.Index = p.parseExpression(precLowest)
+	if elist, ok := exp.Index.(ast.ExpressionList); ok {
+		exp.Index = elist[0]
+		exp.Length = elist[1]
+	}
+
+	if !p.accept(token.RBRACKET) {
+		return nil
+	}
+	exp.Rbracket = p.curToken
+	return exp
+}
+
+func (p *parser) parseGroupedExpression() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseGroupedExpression"))
+	}
+	p.nextToken()
+	exp := p.parseExpression(precLowest)
+	if !p.accept(token.RPAREN) {
+		return nil
+	}
+	return exp
+}
+
+func (p *parser) parseIfExpression() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseIfExpression"))
+	}
+	expression := &ast.ConditionalExpression{Token: p.curToken}
+	p.nextToken()
+	expression.Condition = p.parseExpression(precLowest)
+	if p.peekTokenIs(token.THEN) {
+		p.accept(token.THEN)
+	} else if !p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
 		msg := fmt.Sprintf(
 			"could not parse if expression: unexpected token %s: '%s'",
 			p.peekToken.Type,
@@ -919,81 +1786,589 @@ func (p *parser) parseIfExpression() ast.Expression {
 		)
 		err := errors.Wrap(
 			&unexpectedTokenError{
-				expectedTokens: []token.Type{token.NEWLINE, token.SEMICOLON},
+				expectedTokens: []token.Type{token.THEN, token.NEWLINE, token.SEMICOLON},
 				actualToken:    p.peekToken.Type,
 			},
 			msg,
 		)
 		p.errors = append(p.errors, err)
 		return nil
+	} else {
+		p.acceptOneOf(token.NEWLINE, token.SEMICOLON)
+	}
+	consequence := p.parseBlockStatement(token.ELSE, token.ELSIF)
+	expression.Consequence = consequence
+	if expression.Token.Type != token.UNLESS && p.peekTokenIs(token.ELSIF) {
+		p.accept(token.ELSIF)
+		elsifToken := p.curToken
+		elsif, ok := p.parseIfExpression().(*ast.ConditionalExpression)
+		if !ok {
+			return nil
+		}
+		expression.Alternative = &ast.BlockStatement{
+			Token: elsifToken,
+			Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: elsifToken, Expression: elsif},
+			},
+		}
+		expression.EndToken = elsif.EndToken
+		return expression
+	}
+	if p.peekTokenIs(token.ELSE) {
+		p.accept(token.ELSE)
+		if p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
+			p.nextToken()
+		}
+		expression.Alternative = p.parseBlockStatement()
+	}
+	p.accept(token.END)
+	expression.EndToken = p.curToken
+	return expression
+}
+
+func (p *parser) parseTenaryIfExpression(condition ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseTenaryIfExpression"))
+	}
+	expression := &ast.ConditionalExpression{Token: p.curToken}
+	p.nextToken()
+	expression.Condition = condition
+	expression.Consequence = &ast.BlockStatement{
+		Statements: []ast.Statement{
+			&ast.ExpressionStatement{
+				Expression: p.parseExpression(precTenary),
+			},
+		},
+	}
+	p.consume(token.COLON)
+	expression.Alternative = &ast.BlockStatement{
+		Statements: []ast.Statement{
+			&ast.ExpressionStatement{
+				Expression: p.parseExpression(precLowest),
+			},
+		},
+	}
+	return expression
+}
+
+func (p *parser) parseModifierConditionalExpression(left ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseModifierConditionalExpression"))
+	}
+	expression := &ast.ConditionalExpression{Token: p.curToken}
+	p.nextToken()
+	expression.Condition = p.parseExpression(precLowest)
+
+	expression.Consequence = &ast.BlockStatement{
+		Statements: []ast.Statement{
+			&ast.ExpressionStatement{Expression: left},
+		},
+	}
+	return expression
+}
+
+func (p *parser) parseLoopExpression() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseLoopExpression"))
+	}
+	loop := &ast.LoopExpression{Token: p.curToken}
+	p.nextToken()
+	loop.Condition = p.parseExpression(precBlockDo)
+	if p.peekTokenOneOf(token.DO, token.SEMICOLON) {
+		p.acceptOneOf(token.DO, token.SEMICOLON)
+	}
+	loop.Block = p.parseBlockStatement(token.END)
+	if !p.accept(token.END) {
+		return nil
+	}
+	loop.EndToken = p.curToken
+	return loop
+}
+
+func (p *parser) parseModifierLoopExpression(left ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseModifierLoopExpression"))
+	}
+	loop := &ast.LoopExpression{Token: p.curToken}
+	p.nextToken()
+	loop.Condition = p.parseExpression(precIfUnless)
+	if block, ok := left.(*ast.ExceptionHandlingBlock); ok && !block.IsModifier() {
+		loop.PostCondition = true
+	}
+	loop.Block = &ast.BlockStatement{
+		Statements: []ast.Statement{
+			&ast.ExpressionStatement{Expression: left},
+		},
+	}
+	return loop
+}
+
+// loopControlTerminators are the tokens which end a break or next without
+// a value
+var loopControlTerminators = []token.Type{
+	token.NEWLINE,
+	token.SEMICOLON,
+	token.EOF,
+	token.END,
+	token.RBRACE,
+	token.IF,
+	token.UNLESS,
+	token.WHILE,
+	token.UNTIL,
+}
+
+func (p *parser) parseBreak() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseBreak"))
+	}
+	brk := &ast.BreakExpression{Token: p.curToken}
+	brk.Value = p.parseLoopControlValue()
+	return brk
+}
+
+func (p *parser) parseNext() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseNext"))
+	}
+	next := &ast.NextExpression{Token: p.curToken}
+	next.Value = p.parseLoopControlValue()
+	return next
+}
+
+func (p *parser) parseLoopControlValue() ast.Expression {
+	if p.peekTokenOneOf(loopControlTerminators...) {
+		return nil
+	}
+	p.nextToken()
+	valToken := p.curToken
+	value := p.parseExpression(precIfUnless)
+	if list, ok := value.(ast.ExpressionList); ok {
+		return &ast.ArrayLiteral{Token: valToken, Elements: list, Rbracket: p.curToken}
+	}
+	return value
+}
+
+func (p *parser) parseRedo() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseRedo"))
+	}
+	return &ast.RedoExpression{Token: p.curToken}
+}
+
+func (p *parser) parseRetry() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseRetry"))
+	}
+	return &ast.RetryExpression{Token: p.curToken}
+}
+
+func (p *parser) parseCaseExpression() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseCaseExpression"))
+	}
+	caseToken := p.curToken
+	var subject ast.Expression
+	if !p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
+		p.nextToken()
+		subject = p.parseExpression(precLowest)
+		if subject == nil {
+			return nil
+		}
+	}
+	for p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
+		p.nextToken()
+	}
+	if subject != nil && p.peekTokenIs(token.IN) {
+		return p.parseCaseMatchExpression(caseToken, subject)
+	}
+	expression := &ast.CaseExpression{Token: caseToken, Subject: subject}
+	if !p.peekTokenIs(token.WHEN) {
+		p.peekError(token.WHEN, token.IN)
+		return nil
+	}
+	for p.peekTokenIs(token.WHEN) {
+		p.nextToken()
+		when := &ast.WhenClause{Token: p.curToken}
+		p.nextToken()
+		values := p.parseExpression(precLowest)
+		if values == nil {
+			return nil
+		}
+		if list, ok := values.(ast.ExpressionList); ok {
+			when.Values = list
+		} else {
+			when.Values = []ast.Expression{values}
+		}
+		if !p.acceptClauseBodyStart() {
+			return nil
+		}
+		when.Body = p.parseBlockStatement(token.WHEN, token.ELSE)
+		expression.Whens = append(expression.Whens, when)
+	}
+	if p.peekTokenIs(token.ELSE) {
+		p.nextToken()
+		expression.Else = p.parseBlockStatement()
+	}
+	if !p.accept(token.END) {
+		return nil
+	}
+	expression.EndToken = p.curToken
+	return expression
+}
+
+func (p *parser) parseCaseMatchExpression(caseToken token.Token, subject ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseCaseMatchExpression"))
+	}
+	expression := &ast.CaseMatchExpression{Token: caseToken, Subject: subject}
+	for p.peekTokenIs(token.IN) {
+		p.nextToken()
+		in := &ast.InClause{Token: p.curToken}
+		p.nextToken()
+		in.Pattern = p.parseTopLevelPattern()
+		if in.Pattern == nil {
+			return nil
+		}
+		if p.peekTokenOneOf(token.IF, token.UNLESS) {
+			p.nextToken()
+			in.GuardToken = p.curToken
+			p.nextToken()
+			in.Guard = p.parseExpression(precIfUnless)
+			if in.Guard == nil {
+				return nil
+			}
+		}
+		if !p.acceptClauseBodyStart() {
+			return nil
+		}
+		in.Body = p.parseBlockStatement(token.IN, token.ELSE)
+		expression.Ins = append(expression.Ins, in)
 	}
-	p.acceptOneOf(token.NEWLINE, token.SEMICOLON)
-	consequence := p.parseBlockStatement(token.ELSE)
-	expression.Consequence = consequence
 	if p.peekTokenIs(token.ELSE) {
-		p.accept(token.ELSE)
-		p.accept(token.NEWLINE)
-		expression.Alternative = p.parseBlockStatement()
+		p.nextToken()
+		expression.Else = p.parseBlockStatement()
+	}
+	if !p.accept(token.END) {
+		return nil
 	}
-	p.accept(token.END)
 	expression.EndToken = p.curToken
 	return expression
 }
 
-func (p *parser) parseTenaryIfExpression(condition ast.Expression) ast.Expression {
-	if p.trace {
-		defer un(trace(p, "parseTenaryIfExpression"))
+// acceptClauseBodyStart moves past the `then`, newline or semicolon
+// separating a when or in clause from its body
+func (p *parser) acceptClauseBodyStart() bool {
+	if !p.peekTokenOneOf(token.THEN, token.NEWLINE, token.SEMICOLON) {
+		p.peekError(token.THEN, token.NEWLINE, token.SEMICOLON)
+		return false
 	}
-	expression := &ast.ConditionalExpression{Token: p.curToken}
 	p.nextToken()
-	expression.Condition = condition
-	expression.Consequence = &ast.BlockStatement{
-		Statements: []ast.Statement{
-			&ast.ExpressionStatement{
-				Expression: p.parseExpression(precTenary),
-			},
-		},
+	return true
+}
+
+// patternTerminators are the tokens which end a pattern without brackets
+var patternTerminators = []token.Type{
+	token.THEN,
+	token.NEWLINE,
+	token.SEMICOLON,
+	token.IF,
+	token.UNLESS,
+}
+
+// parseTopLevelPattern parses the pattern of an in clause, where array and
+// hash patterns may omit their brackets
+func (p *parser) parseTopLevelPattern() ast.Pattern {
+	if p.trace {
+		defer un(trace(p, "parseTopLevelPattern"))
 	}
-	p.consume(token.COLON)
-	expression.Alternative = &ast.BlockStatement{
-		Statements: []ast.Statement{
-			&ast.ExpressionStatement{
-				Expression: p.parseExpression(precLowest),
-			},
-		},
+	if p.isPatternLabel() || p.currentTokenIs(token.POW) {
+		pattern := &ast.HashPattern{}
+		if !p.parseHashPatternElements(pattern, patternTerminators...) {
+			return nil
+		}
+		return pattern
 	}
-	return expression
+	elements, ok := p.parsePatternElements()
+	if !ok {
+		return nil
+	}
+	if _, isSplat := elements[0].(*ast.SplatPattern); len(elements) == 1 && !isSplat {
+		return elements[0]
+	}
+	return p.newArrayPattern(token.Token{}, nil, elements, token.Token{})
 }
 
-func (p *parser) parseModifierConditionalExpression(left ast.Expression) ast.Expression {
-	if p.trace {
-		defer un(trace(p, "parseModifierConditionalExpression"))
+// isPatternLabel reports whether the current token is a hash pattern key,
+// i.e. `key:`
+func (p *parser) isPatternLabel() bool {
+	return p.currentTokenIs(token.IDENT) && p.peekTokenOneOf(token.COLON, token.SYMBEG)
+}
+
+func (p *parser) parsePatternElements() ([]ast.Pattern, bool) {
+	var elements []ast.Pattern
+	for {
+		var element ast.Pattern
+		if p.currentTokenIs(token.ASTERISK) {
+			element = p.parseSplatPattern()
+		} else {
+			element = p.parsePattern()
+		}
+		if element == nil {
+			return nil, false
+		}
+		elements = append(elements, element)
+		if !p.peekTokenIs(token.COMMA) {
+			return elements, true
+		}
+		p.nextToken()
+		p.nextToken()
 	}
-	expression := &ast.ConditionalExpression{Token: p.curToken}
-	p.nextToken()
-	expression.Condition = p.parseExpression(precLowest)
+}
 
-	expression.Consequence = &ast.BlockStatement{
-		Statements: []ast.Statement{
-			&ast.ExpressionStatement{Expression: left},
-		},
+func (p *parser) newArrayPattern(open token.Token, constant ast.Expression, elements []ast.Pattern, close token.Token) ast.Pattern {
+	var splats []int
+	for i, e := range elements {
+		if _, ok := e.(*ast.SplatPattern); ok {
+			splats = append(splats, i)
+		}
+	}
+	switch {
+	case len(splats) < 2:
+		return &ast.ArrayPattern{Token: open, Constant: constant, Elements: elements, Rbracket: close}
+	case len(splats) == 2 && splats[0] == 0 && splats[1] == len(elements)-1 && len(elements) > 2:
+		return &ast.FindPattern{
+			Token:    open,
+			Constant: constant,
+			Pre:      elements[0].(*ast.SplatPattern),
+			Elements: elements[1 : len(elements)-1],
+			Post:     elements[len(elements)-1].(*ast.SplatPattern),
+			Rbracket: close,
+		}
+	default:
+		epos := p.file.Position(p.pos)
+		msg := fmt.Errorf("%s: multiple splats in array pattern", epos.String())
+		p.errors = append(p.errors, msg)
+		return nil
 	}
-	return expression
 }
 
-func (p *parser) parseLoopExpression() ast.Expression {
+func (p *parser) parsePattern() ast.Pattern {
 	if p.trace {
-		defer un(trace(p, "parseLoopExpression"))
+		defer un(trace(p, "parsePattern"))
+	}
+	pattern := p.parsePrimaryPattern()
+	if pattern == nil {
+		return nil
+	}
+	if p.peekTokenIs(token.PIPE) {
+		alternative := &ast.AlternativePattern{Alternatives: []ast.Pattern{pattern}}
+		for p.peekTokenIs(token.PIPE) {
+			p.nextToken()
+			p.nextToken()
+			next := p.parsePrimaryPattern()
+			if next == nil {
+				return nil
+			}
+			alternative.Alternatives = append(alternative.Alternatives, next)
+		}
+		pattern = alternative
+	}
+	if p.peekTokenIs(token.HASHROCKET) {
+		p.nextToken()
+		if !p.accept(token.IDENT) {
+			return nil
+		}
+		name := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		pattern = &ast.CapturePattern{Pattern: pattern, Name: name}
+	}
+	return pattern
+}
+
+func (p *parser) parsePrimaryPattern() ast.Pattern {
+	switch p.curToken.Type {
+	case token.LBRACKET:
+		return p.parseArrayPattern(nil)
+	case token.LBRACE:
+		return p.parseHashPattern(nil)
+	case token.CARET:
+		return p.parsePinPattern()
+	case token.IDENT:
+		return &ast.VariablePattern{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+	case token.CONST:
+		constant := p.parsePatternConstant()
+		if constant == nil {
+			return nil
+		}
+		if p.peekTokenOneOf(token.LPAREN, token.LBRACKET) {
+			p.nextToken()
+			return p.parseArrayPattern(constant)
+		}
+		return &ast.ValuePattern{Value: constant}
+	case token.LPAREN:
+		p.nextToken()
+		pattern := p.parsePattern()
+		if pattern == nil || !p.accept(token.RPAREN) {
+			return nil
+		}
+		return pattern
+	case token.DOT2, token.DOT3:
+		value := p.parseRangeEnd(nil, precOr)
+		if value == nil {
+			return nil
+		}
+		return &ast.ValuePattern{Value: value}
+	default:
+		value := p.parseExpression(precOr)
+		if value == nil {
+			return nil
+		}
+		if p.peekTokenOneOf(token.DOT2, token.DOT3) {
+			p.nextToken()
+			value = p.parseRangeEnd(value, precOr)
+			if value == nil {
+				return nil
+			}
+		}
+		return &ast.ValuePattern{Value: value}
+	}
+}
+
+func (p *parser) parsePatternConstant() ast.Expression {
+	constant := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if !p.peekTokenIs(token.SCOPE) {
+		return constant
 	}
-	loop := &ast.LoopExpression{Token: p.curToken}
 	p.nextToken()
-	loop.Condition = p.parseExpression(precBlockDo)
-	if p.peekTokenIs(token.DO) {
-		p.accept(token.DO)
+	scoped := &ast.ScopedIdentifier{Token: p.curToken, Outer: constant}
+	if !p.accept(token.CONST) {
+		return nil
 	}
-	loop.Block = p.parseBlockStatement(token.END)
+	scoped.Inner = p.parsePatternConstant()
+	if scoped.Inner == nil {
+		return nil
+	}
+	return scoped
+}
+
+func (p *parser) parseSplatPattern() ast.Pattern {
+	splat := &ast.SplatPattern{Token: p.curToken}
+	if p.peekTokenIs(token.IDENT) {
+		p.nextToken()
+		splat.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+	return splat
+}
+
+func (p *parser) parsePinPattern() ast.Pattern {
+	pin := &ast.PinPattern{Token: p.curToken}
 	p.nextToken()
-	return loop
+	if p.currentTokenIs(token.LPAREN) {
+		p.nextToken()
+		pin.Value = p.parseExpression(precLowest)
+		if pin.Value == nil || !p.accept(token.RPAREN) {
+			return nil
+		}
+		pin.Rparen = p.curToken
+		return pin
+	}
+	if !p.currentTokenOneOf(token.IDENT, token.AT, token.GLOBAL) {
+		p.expectError(token.IDENT, token.AT, token.GLOBAL, token.LPAREN)
+		return nil
+	}
+	pin.Value = p.parseExpression(precHighest)
+	if pin.Value == nil {
+		return nil
+	}
+	return pin
+}
+
+// parseArrayPattern parses an array or find pattern within brackets. With
+// a constant, the pattern may use parens as well, which also allows for a
+// hash pattern, i.e. `Const(key: value)`.
+func (p *parser) parseArrayPattern(constant ast.Expression) ast.Pattern {
+	open := p.curToken
+	end := token.RBRACKET
+	if open.Type == token.LPAREN {
+		end = token.RPAREN
+	}
+	var elements []ast.Pattern
+	if !p.peekTokenIs(end) {
+		p.nextToken()
+		if end == token.RPAREN && (p.isPatternLabel() || p.currentTokenIs(token.POW)) {
+			pattern := &ast.HashPattern{Token: open, Constant: constant}
+			if !p.parseHashPatternElements(pattern, end) || !p.accept(end) {
+				return nil
+			}
+			pattern.Rbrace = p.curToken
+			return pattern
+		}
+		var ok bool
+		elements, ok = p.parsePatternElements()
+		if !ok {
+			return nil
+		}
+	}
+	if !p.accept(end) {
+		return nil
+	}
+	return p.newArrayPattern(open, constant, elements, p.curToken)
+}
+
+func (p *parser) parseHashPattern(constant ast.Expression) ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.curToken, Constant: constant}
+	if !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		if !p.parseHashPatternElements(pattern, token.RBRACE) {
+			return nil
+		}
+	}
+	if !p.accept(token.RBRACE) {
+		return nil
+	}
+	pattern.Rbrace = p.curToken
+	return pattern
+}
+
+// parseHashPatternElements parses the keys and rest of a hash pattern up to
+// but not including one of the end tokens
+func (p *parser) parseHashPatternElements(pattern *ast.HashPattern, end ...token.Type) bool {
+	valueTerminators := append([]token.Type{token.COMMA}, end...)
+	for {
+		if p.currentTokenIs(token.POW) {
+			rest := &ast.SplatPattern{Token: p.curToken}
+			if !p.acceptOneOf(token.IDENT, token.NIL) {
+				return false
+			}
+			if p.currentTokenIs(token.NIL) {
+				pattern.NoRest = true
+			} else {
+				rest.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+				pattern.Rest = rest
+			}
+			return true
+		}
+		if !p.isPatternLabel() {
+			p.expectError(token.IDENT, token.POW)
+			return false
+		}
+		key := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		p.nextToken()
+		var value ast.Pattern
+		if !p.peekTokenOneOf(valueTerminators...) {
+			p.nextToken()
+			value = p.parsePattern()
+			if value == nil {
+				return false
+			}
+		}
+		pattern.Keys = append(pattern.Keys, key)
+		pattern.Values = append(pattern.Values, value)
+		if !p.peekTokenIs(token.COMMA) {
+			return true
+		}
+		p.nextToken()
+		p.nextToken()
+	}
 }
 
 func (p *parser) parseModule() ast.Expression {
@@ -1093,13 +2468,8 @@ func (p *parser) parseFunctionLiteral() ast.Expression {
 	if !p.acceptOneOf(token.NEWLINE, token.SEMICOLON) {
 		return nil
 	}
-	lit.Body = p.parseBlockStatement(token.END, token.RESCUE)
-	lit.Rescues = []*ast.RescueBlock{}
-	for p.peekTokenIs(token.RESCUE) {
-		p.accept(token.RESCUE)
-		rescue := p.parseRescueBlock()
-		lit.Rescues = append(lit.Rescues, rescue)
-	}
+	lit.Body = p.parseBlockStatement(exceptionHandlingTerminators...)
+	lit.Rescues, lit.Else, lit.Ensure = p.parseExceptionHandlingClauses()
 	if !p.accept(token.END) {
 		return nil
 	}
@@ -1155,38 +2525,20 @@ func (p *parser) parseParameters(startToken, endToken token.Type) []*ast.Functio
 		return identifiers
 	}
 
-	if p.peekTokenIs(token.ASTERISK) {
-		p.accept(token.ASTERISK)
-	}
-	if p.peekTokenOneOf(token.CAPTURE, token.AND) {
-		p.acceptOneOf(token.CAPTURE, token.AND)
-		return identifiers
-	}
-	p.accept(token.IDENT)
-
-	ident := &ast.FunctionParameter{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
-	if p.peekTokenIs(token.ASSIGN) {
-		p.consume(token.ASSIGN)
-		ident.Default = p.parseExpression(precAssignment)
-	}
-	identifiers = append(identifiers, ident)
-
-	for p.peekTokenIs(token.COMMA) {
-		p.accept(token.COMMA)
-		if p.peekTokenIs(token.ASTERISK) {
-			p.accept(token.ASTERISK)
-		}
-		if p.peekTokenOneOf(token.CAPTURE, token.AND) {
+	for {
+		if endToken != token.PIPE && p.peekTokenOneOf(token.CAPTURE, token.AND) {
 			p.acceptOneOf(token.CAPTURE, token.AND)
 			return identifiers
 		}
-		p.accept(token.IDENT)
-		ident := &ast.FunctionParameter{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
-		if p.peekTokenIs(token.ASSIGN) {
-			p.consume(token.ASSIGN)
-			ident.Default = p.parseExpression(precPrefix)
+		ident := p.parseParameter(endToken)
+		if ident == nil {
+			return nil
 		}
 		identifiers = append(identifiers, ident)
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.accept(token.COMMA)
 	}
 
 	if !hasDelimiters && p.peekTokenIs(endToken) {
@@ -1201,6 +2553,92 @@ func (p *parser) parseParameters(startToken, endToken token.Type) []*ast.Functio
 	return identifiers
 }
 
+// parseParameter parses a single parameter of a parameter list terminated
+// by endToken. A `&block` parameter is only parsed here within block
+// parameters, function literals store it as CapturedBlock.
+func (p *parser) parseParameter(endToken token.Type) *ast.FunctionParameter {
+	if p.trace {
+		defer un(trace(p, "parseParameter"))
+	}
+	param := &ast.FunctionParameter{}
+	switch {
+	case endToken == token.PIPE && p.peekTokenIs(token.LPAREN):
+		return p.parseDestructuringParameter()
+	case p.peekTokenIs(token.ASTERISK):
+		p.accept(token.ASTERISK)
+		param.Token, param.Kind = p.curToken, ast.RestParameter
+	case p.peekTokenIs(token.POW):
+		p.accept(token.POW)
+		param.Token, param.Kind = p.curToken, ast.KeywordRestParameter
+	case p.peekTokenOneOf(token.CAPTURE, token.AND):
+		p.acceptOneOf(token.CAPTURE, token.AND)
+		param.Token, param.Kind = p.curToken, ast.BlockParameter
+	}
+	if param.Kind != ast.PositionalParameter && param.Kind != ast.BlockParameter && !p.peekTokenIs(token.IDENT) {
+		return param
+	}
+	if !p.accept(token.IDENT) {
+		return nil
+	}
+	param.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if param.Kind != ast.PositionalParameter {
+		return param
+	}
+	// blocks parameters end with a '|' which must not be parsed as infix operator
+	precedence := precAssignment
+	if endToken == token.PIPE {
+		precedence = precOr
+	}
+	if p.peekTokenOneOf(token.COLON, token.SYMBEG) && p.peekToken.Pos == p.curToken.Pos+len(p.curToken.Literal) {
+		p.nextToken()
+		param.Kind = ast.KeywordParameter
+		if p.peekTokenOneOf(token.COMMA, endToken, token.NEWLINE, token.SEMICOLON) {
+			return param
+		}
+		p.nextToken()
+		param.Default = p.parseExpression(precedence)
+		return param
+	}
+	if p.peekTokenIs(token.ASSIGN) {
+		p.consume(token.ASSIGN)
+		param.Default = p.parseExpression(precedence)
+	}
+	return param
+}
+
+// parseDestructuringParameter parses a block parameter like `(a, *b)` which
+// spreads its argument over the nested parameters
+func (p *parser) parseDestructuringParameter() *ast.FunctionParameter {
+	if p.trace {
+		defer un(trace(p, "parseDestructuringParameter"))
+	}
+	p.accept(token.LPAREN)
+	param := &ast.FunctionParameter{Token: p.curToken, Elements: []*ast.FunctionParameter{}}
+	for {
+		var element *ast.FunctionParameter
+		if p.peekTokenIs(token.LPAREN) {
+			element = p.parseDestructuringParameter()
+		} else if p.peekTokenIs(token.ASTERISK) {
+			element = p.parseParameter(token.RPAREN)
+		} else if p.accept(token.IDENT) {
+			element = &ast.FunctionParameter{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+		}
+		if element == nil {
+			return nil
+		}
+		param.Elements = append(param.Elements, element)
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.accept(token.COMMA)
+	}
+	if !p.accept(token.RPAREN) {
+		return nil
+	}
+	param.Rparen = p.curToken
+	return param
+}
+
 func (p *parser) parseBlockStatement(t ...token.Type) *ast.BlockStatement {
 	if p.trace {
 		defer un(trace(p, "parseBlockStatement"))
@@ -1237,7 +2675,7 @@ func (p *parser) parseMethodCall(context ast.Expression) ast.Expression {
 
 	p.nextToken()
 
-	if !p.currentTokenOneOf(token.IDENT, token.CLASS) && !p.curToken.Type.IsOperator() {
+	if !p.currentTokenIs(token.IDENT) && !p.curToken.IsKeyword() && !p.curToken.Type.IsOperator() {
 		p.expectError(token.IDENT, token.CLASS)
 		return nil
 	}
@@ -1254,6 +2692,9 @@ func (p *parser) parseMethodCall(context ast.Expression) ast.Expression {
 		p.accept(token.LPAREN)
 		p.nextToken()
 		contextCallExpression.Arguments = p.parseExpressionList(token.RPAREN)
+		if p.currentTokenIs(token.RPAREN) {
+			contextCallExpression.Rparen = p.curToken
+		}
 		if p.peekTokenOneOf(token.LBRACE, token.DO) {
 			p.acceptOneOf(token.LBRACE, token.DO)
 			contextCallExpression.Block = p.parseBlock().(*ast.BlockExpression)
@@ -1264,6 +2705,9 @@ func (p *parser) parseMethodCall(context ast.Expression) ast.Expression {
 	if p.peekTokenOneOf(append(tokensNotPossibleInCallArgs, token.RBRACE)...) {
 		return contextCallExpression
 	}
+	if p.peekTokenOneOf(token.ASTERISK, token.POW) && !p.isSplatArgument(p.curToken.Pos+len(p.curToken.Literal), p.peekToken) {
+		return contextCallExpression
+	}
 
 	p.nextToken()
 
@@ -1307,6 +2751,9 @@ func (p *parser) parseContextCallExpression(context ast.Expression) ast.Expressi
 		p.accept(token.LPAREN)
 		p.nextToken()
 		contextCallExpression.Arguments = p.parseExpressionList(token.RPAREN)
+		if p.currentTokenIs(token.RPAREN) {
+			contextCallExpression.Rparen = p.curToken
+		}
 		if p.peekTokenOneOf(token.LBRACE, token.DO) {
 			p.acceptOneOf(token.LBRACE, token.DO)
 			contextCallExpression.Block = p.parseBlock().(*ast.BlockExpression)
@@ -1317,6 +2764,9 @@ func (p *parser) parseContextCallExpression(context ast.Expression) ast.Expressi
 	if p.peekTokenOneOf(append(tokensNotPossibleInCallArgs, token.RBRACE)...) {
 		return contextCallExpression
 	}
+	if p.peekTokenOneOf(token.ASTERISK, token.POW) && !p.isSplatArgument(p.curToken.Pos+len(p.curToken.Literal), p.peekToken) {
+		return contextCallExpression
+	}
 
 	p.nextToken()
 	contextCallExpression.Arguments = p.parseCallArguments(
@@ -1351,6 +2801,30 @@ func (p *parser) parseCallArgument(function ast.Expression) ast.Expression {
 	return exp
 }
 
+// parseSplatArgumentOrInfixExpression parses `foo *bar` as call of foo with
+// a splat argument and any other `*` or `**` as binary operator
+func (p *parser) parseSplatArgumentOrInfixExpression(left ast.Expression) ast.Expression {
+	if _, ok := left.(*ast.Identifier); ok && p.isSplatArgument(left.End(), p.curToken) {
+		return p.parseCallArgument(left)
+	}
+	if p.currentTokenIs(token.POW) {
+		return p.parseRightAssociativeInfixExpression(left)
+	}
+	return p.parseInfixExpression(left)
+}
+
+// isSplatArgument reports whether the operator tok following a method name
+// ending at offset end starts a splat argument. Like MRI it requires
+// whitespace before the operator and none after it, i.e. `foo *bar` passes a
+// splat whereas `foo * bar` and `foo*bar` multiply.
+func (p *parser) isSplatArgument(end int, tok token.Token) bool {
+	if tok.Type != token.ASTERISK && tok.Type != token.POW {
+		return false
+	}
+	next := tok.Pos + len(tok.Literal)
+	return tok.Pos > end && next < len(p.src) && !unicode.IsSpace(rune(p.src[next]))
+}
+
 func (p *parser) parseCallBlock(function ast.Expression) ast.Expression {
 	if p.trace {
 		defer un(trace(p, "parseCallBlock"))
@@ -1389,6 +2863,9 @@ func (p *parser) parseCallExpressionWithParens(function ast.Expression) ast.Expr
 	exp := &ast.ContextCallExpression{Token: p.curToken, Function: ident}
 	p.nextToken()
 	exp.Arguments = p.parseExpressionList(token.RPAREN)
+	if p.currentTokenIs(token.RPAREN) {
+		exp.Rparen = p.curToken
+	}
 	if p.peekTokenOneOf(token.LBRACE, token.DO) {
 		p.acceptOneOf(token.LBRACE, token.DO)
 		exp.Block = p.parseBlock().(*ast.BlockExpression)
@@ -1405,12 +2882,7 @@ func (p *parser) parseCallArguments(end ...token.Type) []ast.Expression {
 		return list
 	}
 
-	list = append(list, p.parseExpression(precAssignment))
-
-	for p.peekTokenIs(token.COMMA) {
-		p.consume(token.COMMA)
-		list = append(list, p.parseExpression(precAssignment))
-	}
+	list = p.parseListElements(p.parseListElement(list))
 
 	if p.peekTokenOneOf(end...) {
 		p.acceptOneOf(end...)
@@ -1428,6 +2900,14 @@ func (p *parser) parseExpressionList(end ...token.Type) []ast.Expression {
 		return list
 	}
 
+	if p.isHashLabel() || p.currentTokenOneOf(token.ASTERISK, token.POW) {
+		list = p.parseListElements(p.parseListElement(list))
+		if p.peekTokenOneOf(end...) {
+			p.acceptOneOf(end...)
+		}
+		return list
+	}
+
 	next := p.parseExpression(precIfUnless)
 	if elist, ok := next.(ast.ExpressionList); ok {
 		if p.peekTokenOneOf(end...) {
@@ -1435,7 +2915,11 @@ func (p *parser) parseExpressionList(end ...token.Type) []ast.Expression {
 		}
 		return elist
 	}
-	list = append(list, next)
+	if next != nil && p.peekTokenIs(token.HASHROCKET) {
+		list = p.parseListElements(p.parseListPair(list, next))
+	} else {
+		list = append(list, next)
+	}
 
 	if p.peekTokenOneOf(end...) {
 		p.acceptOneOf(end...)
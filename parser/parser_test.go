@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	gotoken "go/token"
+	"math"
 	"os"
 	"reflect"
 	"strconv"
@@ -123,6 +124,19 @@ func TestBlockCapture(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:  "symbol as block argument",
+			input: "foo.map(&:upcase)",
+			result: &ast.ContextCallExpression{
+				Context:  &ast.Identifier{Value: "foo"},
+				Function: &ast.Identifier{Value: "map"},
+				Arguments: []ast.Expression{
+					&ast.BlockCapture{
+						Value: &ast.SymbolLiteral{Value: &ast.Identifier{Value: "upcase"}},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -163,6 +177,12 @@ func TestAssignment(t *testing.T) {
 			leftType:  reflect.TypeOf(&ast.Identifier{}),
 			rightType: reflect.TypeOf(&ast.IntegerLiteral{}),
 		},
+		{
+			name:      "attribute",
+			input:     `x.foo = 3`,
+			leftType:  reflect.TypeOf(&ast.ContextCallExpression{}),
+			rightType: reflect.TypeOf(&ast.IntegerLiteral{}),
+		},
 		{
 			name:      "method call with block on rhs",
 			input:     `x = foo { |x| }`,
@@ -236,6 +256,12 @@ func TestAssignmentOperator(t *testing.T) {
 			leftType:      reflect.TypeOf(&ast.Identifier{}),
 			rightOperator: "-",
 		},
+		{
+			name:          "+= on attribute",
+			input:         `x.foo += 3`,
+			leftType:      reflect.TypeOf(&ast.ContextCallExpression{}),
+			rightOperator: "+",
+		},
 	}
 
 	for _, tt := range tests {
@@ -406,10 +432,13 @@ func TestVariableExpression(t *testing.T) {
 	})
 }
 
-func TestWhileExpression(t *testing.T) {
+func TestLoopExpression(t *testing.T) {
 	tests := []struct {
-		name  string
-		input string
+		name          string
+		input         string
+		negated       bool
+		modifier      bool
+		postCondition bool
 	}{
 		{
 			name: "with explicit do",
@@ -425,6 +454,34 @@ func TestWhileExpression(t *testing.T) {
 				x += x
 			end`,
 		},
+		{
+			name: "until",
+			input: `
+			until x < y
+				x += x
+			end`,
+			negated: true,
+		},
+		{
+			name:     "while modifier",
+			input:    "x += x while x < y",
+			modifier: true,
+		},
+		{
+			name:     "until modifier",
+			input:    "x += x until x < y",
+			negated:  true,
+			modifier: true,
+		},
+		{
+			name: "begin end while",
+			input: `
+			begin
+				x += x
+			end while x < y`,
+			modifier:      true,
+			postCondition: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -458,10 +515,207 @@ func TestWhileExpression(t *testing.T) {
 					stmt.Expression,
 				)
 			}
+
+			if exp.IsNegated() != tt.negated {
+				t.Logf("Expected negated to be %t, got %t\n", tt.negated, exp.IsNegated())
+				t.Fail()
+			}
+			if exp.IsModifier() != tt.modifier {
+				t.Logf("Expected modifier to be %t, got %t\n", tt.modifier, exp.IsModifier())
+				t.Fail()
+			}
+			if exp.PostCondition != tt.postCondition {
+				t.Logf("Expected post condition to be %t, got %t\n", tt.postCondition, exp.PostCondition)
+				t.Fail()
+			}
+			if len(exp.Block.Statements) != 1 {
+				t.Logf("Expected loop body to have 1 statement, got %d\n", len(exp.Block.Statements))
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestLoopControlExpressions(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      string
+		expectedValue string
+	}{
+		{"break", "*ast.BreakExpression", ""},
+		{"break 3", "*ast.BreakExpression", "3"},
+		{"break 1, 2", "*ast.BreakExpression", "[1, 2]"},
+		{"next", "*ast.NextExpression", ""},
+		{"next x + 2", "*ast.NextExpression", "(x + 2)"},
+		{"redo", "*ast.RedoExpression", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
+
+			stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+			if !ok {
+				t.Fatalf(
+					"program.Statements[0] is not ast.ExpressionStatement. got=%T",
+					program.Statements[0],
+				)
+			}
+
+			actual := fmt.Sprintf("%T", stmt.Expression)
+			if actual != tt.expected {
+				t.Fatalf("Expected expression to be %s, got %s", tt.expected, actual)
+			}
+
+			var value ast.Expression
+			switch exp := stmt.Expression.(type) {
+			case *ast.BreakExpression:
+				value = exp.Value
+			case *ast.NextExpression:
+				value = exp.Value
+			}
+			var actualValue string
+			if value != nil {
+				actualValue = value.String()
+			}
+			if actualValue != tt.expectedValue {
+				t.Logf("Expected value to equal %q, got %q\n", tt.expectedValue, actualValue)
+				t.Fail()
+			}
 		})
 	}
 }
 
+func TestCaseExpression(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedSubject string
+		expectedWhens   [][]string
+		expectElse      bool
+	}{
+		{"case x\nwhen 1 then 2\nend", "x", [][]string{{"1"}}, false},
+		{"case x\nwhen 1, 2\n  3\nwhen String; 4\nelse\n  5\nend", "x", [][]string{{"1", "2"}, {"String"}}, true},
+		{"case x.size when 1 then 2 end", "x.size()", [][]string{{"1"}}, false},
+		{"case\nwhen x > 1 then 2\nend", "", [][]string{{"(x > 1)"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
+
+			stmt := program.Statements[0].(*ast.ExpressionStatement)
+			caseExp, ok := stmt.Expression.(*ast.CaseExpression)
+			if !ok {
+				t.Fatalf("Expected expression to be *ast.CaseExpression, got %T", stmt.Expression)
+			}
+
+			var subject string
+			if caseExp.Subject != nil {
+				subject = caseExp.Subject.String()
+			}
+			if subject != tt.expectedSubject {
+				t.Logf("Expected subject to equal %q, got %q\n", tt.expectedSubject, subject)
+				t.Fail()
+			}
+
+			var whens [][]string
+			for _, when := range caseExp.Whens {
+				var values []string
+				for _, v := range when.Values {
+					values = append(values, v.String())
+				}
+				whens = append(whens, values)
+			}
+			if !reflect.DeepEqual(whens, tt.expectedWhens) {
+				t.Logf("Expected when values to equal %v, got %v\n", tt.expectedWhens, whens)
+				t.Fail()
+			}
+
+			if (caseExp.Else != nil) != tt.expectElse {
+				t.Logf("Expected else to be present: %t, got %v\n", tt.expectElse, caseExp.Else)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestCaseMatchExpression(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    string
+		expectedPattern string
+		expectedGuard   string
+	}{
+		{"case x\nin 1 then 2\nend", "*ast.ValuePattern", "1", ""},
+		{"case x\nin y then 2\nend", "*ast.VariablePattern", "y", ""},
+		{"case x\nin ^y then 2\nend", "*ast.PinPattern", "^y", ""},
+		{"case x\nin ^(y + 1) then 2\nend", "*ast.PinPattern", "^((y + 1))", ""},
+		{"case x\nin Integer | String => y\n2\nend", "*ast.CapturePattern", "Integer | String => y", ""},
+		{"case x\nin A::B then 2\nend", "*ast.ValuePattern", "A::B", ""},
+		{"case x\nin [1, *rest] then 2\nend", "*ast.ArrayPattern", "[1, *rest]", ""},
+		{"case x\nin 1, *, 2 then 2\nend", "*ast.ArrayPattern", "1, *, 2", ""},
+		{"case x\nin Point(a, b) then 2\nend", "*ast.ArrayPattern", "Point(a, b)", ""},
+		{"case x\nin [*, 1, *post] then 2\nend", "*ast.FindPattern", "[*, 1, *post]", ""},
+		{"case x\nin {name: String, age:} then 2\nend", "*ast.HashPattern", "{name: String, age:}", ""},
+		{"case x\nin name:, **rest then 2\nend", "*ast.HashPattern", "name:, **rest", ""},
+		{"case x\nin {a: 1, **nil} then 2\nend", "*ast.HashPattern", "{a: 1, **nil}", ""},
+		{"case x\nin Point(x:) then 2\nend", "*ast.HashPattern", "Point(x:)", ""},
+		{"case x\nin {} then 2\nend", "*ast.HashPattern", "{}", ""},
+		{"case x\nin y if y > 2 then 2\nend", "*ast.VariablePattern", "y", "(y > 2)"},
+		{"case x\nin [y] unless y\n2\nend", "*ast.ArrayPattern", "[y]", "y"},
+		{"case x\nin 1..5 then 2\nend", "*ast.ValuePattern", "(1..5)", ""},
+		{"case x\nin ..5 then 2\nend", "*ast.ValuePattern", "(..5)", ""},
+		{"case x\nin 1... then 2\nend", "*ast.ValuePattern", "(1...)", ""},
+		{"case x\nin 1..2 | 5..6 then 2\nend", "*ast.AlternativePattern", "(1..2) | (5..6)", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
+
+			stmt := program.Statements[0].(*ast.ExpressionStatement)
+			caseExp, ok := stmt.Expression.(*ast.CaseMatchExpression)
+			if !ok {
+				t.Fatalf("Expected expression to be *ast.CaseMatchExpression, got %T", stmt.Expression)
+			}
+			if len(caseExp.Ins) != 1 {
+				t.Fatalf("Expected one in clause, got %d", len(caseExp.Ins))
+			}
+			in := caseExp.Ins[0]
+
+			actualType := fmt.Sprintf("%T", in.Pattern)
+			if actualType != tt.expectedType {
+				t.Logf("Expected pattern to be %s, got %s\n", tt.expectedType, actualType)
+				t.Fail()
+			}
+			if in.Pattern.String() != tt.expectedPattern {
+				t.Logf("Expected pattern to equal %q, got %q\n", tt.expectedPattern, in.Pattern.String())
+				t.Fail()
+			}
+			var guard string
+			if in.Guard != nil {
+				guard = in.Guard.String()
+			}
+			if guard != tt.expectedGuard {
+				t.Logf("Expected guard to equal %q, got %q\n", tt.expectedGuard, guard)
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("multiple splats", func(t *testing.T) {
+		_, err := parseSource("case x\nin [*a, 1, *b, 2] then 2\nend")
+
+		if err == nil {
+			t.Logf("Expected parser error, got nil")
+			t.Fail()
+		}
+	})
+}
+
 func TestGlobalAssignment(t *testing.T) {
 	input := "$foo = 3"
 
@@ -542,6 +796,31 @@ func TestParseMultiAssignment(t *testing.T) {
 			variables: []string{"(x[0])", "@y", "$z", "A"},
 			values:    []string{"3", "4", "5", "6"},
 		},
+		{
+			input:     "x, *y = 3, 4, 5;",
+			variables: []string{"x", "*y"},
+			values:    []string{"3", "4", "5"},
+		},
+		{
+			input:     "*x, y = list;",
+			variables: []string{"*x", "y"},
+			values:    []string{"list"},
+		},
+		{
+			input:     "x, * = 3, *list;",
+			variables: []string{"x", "*"},
+			values:    []string{"3", "*list"},
+		},
+		{
+			input:     "(x, y), z = 3, 4;",
+			variables: []string{"x, y", "z"},
+			values:    []string{"3", "4"},
+		},
+		{
+			input:     "x, (y, *z) = 3, 4;",
+			variables: []string{"x", "y, *z"},
+			values:    []string{"3", "4"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -614,9 +893,11 @@ func TestExceptionHandling(t *testing.T) {
 		body      string
 	}
 	tests := []struct {
-		input   string
-		body    string
-		rescues []rescue
+		input    string
+		body     string
+		rescues  []rescue
+		elseBody string
+		ensure   string
 	}{
 		{
 			input: `
@@ -688,6 +969,76 @@ end
 			body:    "2",
 			rescues: []rescue{{classes: []string{"Error"}, body: "3", exception: "e"}},
 		},
+		{
+			input: `
+begin
+	2
+rescue ArgumentError
+	3
+rescue TypeError, NameError => e
+	4
+rescue
+	5
+end
+`,
+			body: "2",
+			rescues: []rescue{
+				{classes: []string{"ArgumentError"}, body: "3"},
+				{classes: []string{"TypeError", "NameError"}, body: "4", exception: "e"},
+				{body: "5"},
+			},
+		},
+		{
+			input: `
+begin
+	2
+rescue
+	3
+else
+	4
+ensure
+	5
+end
+`,
+			body:     "2",
+			rescues:  []rescue{{body: "3"}},
+			elseBody: "4",
+			ensure:   "5",
+		},
+		{
+			input: `
+begin
+	2
+ensure
+	5
+end
+`,
+			body:   "2",
+			ensure: "5",
+		},
+		{
+			input:    "begin; 2; rescue Error then 3; else; 4; ensure; 5; end",
+			body:     "2",
+			rescues:  []rescue{{classes: []string{"Error"}, body: "3"}},
+			elseBody: "4",
+			ensure:   "5",
+		},
+		{
+			input: `
+begin
+	2
+rescue
+	retry
+end
+`,
+			body:    "2",
+			rescues: []rescue{{body: "retry"}},
+		},
+		{
+			input:   "foo rescue 3",
+			body:    "foo",
+			rescues: []rescue{{body: "3"}},
+		},
 	}
 
 	for _, tt := range tests {
@@ -740,6 +1091,22 @@ end
 			t.Logf("Expected rescues to equal\n%s\n\tgot\n%s\n", tt.rescues, rescues)
 			t.Fail()
 		}
+
+		var elseBody, ensure string
+		if begin.Else != nil {
+			elseBody = begin.Else.String()
+		}
+		if begin.Ensure != nil {
+			ensure = begin.Ensure.String()
+		}
+		if elseBody != tt.elseBody {
+			t.Logf("Expected Else to equal\n%s\n\tgot\n%s\n", tt.elseBody, elseBody)
+			t.Fail()
+		}
+		if ensure != tt.ensure {
+			t.Logf("Expected Ensure to equal\n%s\n\tgot\n%s\n", tt.ensure, ensure)
+			t.Fail()
+		}
 	}
 }
 
@@ -1066,7 +1433,7 @@ func TestKeyword__FILE__(t *testing.T) {
 
 		_, err := parseSource(input)
 
-		expected := "1:9: Can't assign to __FILE__"
+		expected := "1:10: Can't assign to __FILE__"
 
 		parserErrors := err.errors
 		if len(parserErrors) != 1 {
@@ -1175,6 +1542,96 @@ func TestIntegerLiteralExpression(t *testing.T) {
 			literal.TokenLiteral(),
 		)
 	}
+
+	t.Run("exceeding int64", func(t *testing.T) {
+		input := "18_446_744_073_709_551_616"
+
+		program, err := parseSource(input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+		if !ok {
+			t.Fatalf("expression not *ast.IntegerLiteral. got=%T", stmt.Expression)
+		}
+		if literal.BigValue == nil {
+			t.Fatalf("expression.BigValue not set")
+		}
+		if literal.String() != "18446744073709551616" {
+			t.Errorf("expression.String not %s. got=%s", "18446744073709551616", literal.String())
+		}
+	})
+	t.Run("radix prefixes", func(t *testing.T) {
+		tests := []string{
+			"0xaa", "0XAA", "0252", "0o252", "0O252",
+			"0b1010_1010", "0B10101010", "0d170", "0D1_70",
+		}
+
+		for _, input := range tests {
+			program, err := parseSource(input)
+			checkParserErrors(t, err)
+
+			stmt := program.Statements[0].(*ast.ExpressionStatement)
+			literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+			if !ok {
+				t.Fatalf("expression not *ast.IntegerLiteral. got=%T", stmt.Expression)
+			}
+			if literal.Value != 170 {
+				t.Errorf("expression.Value for %q not %d. got=%d", input, 170, literal.Value)
+			}
+		}
+	})
+	t.Run("invalid digits", func(t *testing.T) {
+		_, err := parseSource("x = 0b102")
+
+		if err == nil {
+			t.Fatalf("Expected parser error, got nil")
+		}
+		if !strings.Contains(err.Error(), "Invalid binary digit: '2'") {
+			t.Errorf("Expected error to mention the invalid digit, got %q", err.Error())
+		}
+	})
+}
+
+func TestFloatLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"12.34", 12.34},
+		{"1234e-2", 12.34},
+		{"1.234E1", 12.34},
+		{"1_000.5", 1000.5},
+		{"2e3", 2000},
+		{"1.0e400", math.Inf(1)},
+		{"1.0e-400", 0},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf(
+				"program.Statements[0] is not ast.ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		literal, ok := stmt.Expression.(*ast.FloatLiteral)
+		if !ok {
+			t.Fatalf("expression not *ast.FloatLiteral. got=%T", stmt.Expression)
+		}
+		if literal.Value != tt.expected {
+			t.Errorf("expression.Value not %f. got=%f", tt.expected, literal.Value)
+		}
+		if literal.TokenLiteral() != tt.input {
+			t.Errorf(
+				"expression.TokenLiteral not %s. got=%s", tt.input,
+				literal.TokenLiteral(),
+			)
+		}
+	}
 }
 
 func TestParsingPrefixExpressions(t *testing.T) {
@@ -1454,6 +1911,18 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"-a * b",
 			"((-a) * b)",
 		},
+		{
+			"-a ** b",
+			"(-(a ** b))",
+		},
+		{
+			"a ** b ** c",
+			"(a ** (b ** c))",
+		},
+		{
+			"a * b ** c",
+			"(a * (b ** c))",
+		},
 		{
 			"!-a",
 			"(!(-a))",
@@ -1586,15 +2055,43 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			program, err := parseSource(tt.input)
-			checkParserErrors(t, err)
-
-			actual := program.String()
-			if actual != tt.expected {
+		{
+			"a.b * c",
+			"(a.b() * c)",
+		},
+		{
+			"a.b ** c",
+			"(a.b() ** c)",
+		},
+		{
+			"a.b*c",
+			"(a.b() * c)",
+		},
+		{
+			"a.b *c",
+			"a.b(*c)",
+		},
+		{
+			"a.b **c",
+			"a.b(**c)",
+		},
+		{
+			"a * b",
+			"(a * b)",
+		},
+		{
+			"a *b",
+			"a(*b)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
+
+			actual := program.String()
+			if actual != tt.expected {
 				t.Errorf("expected=%q, got=%q", tt.expected, actual)
 			}
 		})
@@ -2179,6 +2676,72 @@ func TestConditionalExpressionWithAlternative(t *testing.T) {
 	})
 }
 
+func TestConditionalExpressionWithElsif(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		elsifs   []string
+	}{
+		{
+			"if x\n  1\nelsif y\n  2\nend",
+			"ifx 1 elsify 2 end",
+			[]string{"3:1"},
+		},
+		{
+			"if x\n  1\nelsif y\n  2\nelsif z\n  3\nelse\n  4\nend",
+			"ifx 1 elsify 2 elsifz 3else 4 end",
+			[]string{"3:1", "5:1"},
+		},
+		{
+			"if x then 1 elsif y then 2 else 3 end",
+			"ifx 1 elsify 2else 3 end",
+			[]string{"1:13"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
+
+			stmt := program.Statements[0].(*ast.ExpressionStatement)
+			exp, ok := stmt.Expression.(*ast.ConditionalExpression)
+			if !ok {
+				t.Fatalf("stmt.Expression is not ast.ConditionalExpression. got=%T", stmt.Expression)
+			}
+
+			if exp.String() != tt.expected {
+				t.Logf("Expected expression to equal %q, got %q\n", tt.expected, exp.String())
+				t.Fail()
+			}
+
+			var elsifs []string
+			end := ast.Position(program.File, exp.End()).String()
+			for elsif, ok := exp.Elsif(); ok; elsif, ok = elsif.Elsif() {
+				elsifs = append(elsifs, ast.Position(program.File, elsif.Pos()).String())
+				elsifEnd := ast.Position(program.File, elsif.End()).String()
+				if elsifEnd != end {
+					t.Logf("Expected elsif to end at %s, got %s\n", end, elsifEnd)
+					t.Fail()
+				}
+			}
+			if !reflect.DeepEqual(elsifs, tt.elsifs) {
+				t.Logf("Expected elsifs to start at %v, got %v\n", tt.elsifs, elsifs)
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("unless with elsif", func(t *testing.T) {
+		_, err := parseSource("unless x\n  1\nelsif y\n  2\nend")
+
+		if err == nil {
+			t.Logf("Expected parser error, got nil")
+			t.Fail()
+		}
+	})
+}
+
 func TestFunctionLiteralParsing(t *testing.T) {
 	type funcParam struct {
 		name         string
@@ -2456,6 +3019,45 @@ func TestFunctionLiteralParsing(t *testing.T) {
 			)
 		}
 	})
+	t.Run("test function else and ensure block", func(t *testing.T) {
+		input := `
+			def foo
+				3
+			rescue ArgumentError
+				4
+			else
+				5
+			ensure
+				6
+			end
+		`
+
+		program, err := parseSource(input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		function, ok := stmt.Expression.(*ast.FunctionLiteral)
+		if !ok {
+			t.Fatalf(
+				"stmt.Expression is not %T. got=%T",
+				function,
+				stmt.Expression,
+			)
+		}
+
+		if len(function.Rescues) != 1 {
+			t.Logf("Expected 1 rescue block, got %d\n", len(function.Rescues))
+			t.Fail()
+		}
+		if function.Else == nil || function.Else.String() != "5" {
+			t.Logf("Expected Else to equal 5, got %v\n", function.Else)
+			t.Fail()
+		}
+		if function.Ensure == nil || function.Ensure.String() != "6" {
+			t.Logf("Expected Ensure to equal 6, got %v\n", function.Ensure)
+			t.Fail()
+		}
+	})
 }
 
 func TestBlockExpressionParsing(t *testing.T) {
@@ -2612,6 +3214,7 @@ func TestFunctionParameterParsing(t *testing.T) {
 	type funcParam struct {
 		name         string
 		defaultValue interface{}
+		kind         ast.ParameterKind
 	}
 	tests := []struct {
 		desc           string
@@ -2651,12 +3254,50 @@ func TestFunctionParameterParsing(t *testing.T) {
 		{
 			desc:           "multiple params last array splat with parens",
 			input:          "def fn(x, y, *z); end",
-			expectedParams: []funcParam{{name: "x"}, {name: "y"}, {name: "z"}},
+			expectedParams: []funcParam{{name: "x"}, {name: "y"}, {name: "z", kind: ast.RestParameter}},
 		},
 		{
 			desc:           "one param array splat with parens",
 			input:          "def fn(*x); end",
-			expectedParams: []funcParam{{name: "x"}},
+			expectedParams: []funcParam{{name: "x", kind: ast.RestParameter}},
+		},
+		{
+			desc:           "anonymous array splat with parens",
+			input:          "def fn(x, *); end",
+			expectedParams: []funcParam{{name: "x"}, {kind: ast.RestParameter}},
+		},
+		{
+			desc:           "params after array splat with parens",
+			input:          "def fn(x, *y, z); end",
+			expectedParams: []funcParam{{name: "x"}, {name: "y", kind: ast.RestParameter}, {name: "z"}},
+		},
+		{
+			desc:  "keyword params with parens",
+			input: "def fn(x, y: 3, z:); end",
+			expectedParams: []funcParam{
+				{name: "x"},
+				{name: "y", defaultValue: 3, kind: ast.KeywordParameter},
+				{name: "z", kind: ast.KeywordParameter},
+			},
+		},
+		{
+			desc:  "keyword params without parens",
+			input: "def fn x, y:, z: 2\nend",
+			expectedParams: []funcParam{
+				{name: "x"},
+				{name: "y", kind: ast.KeywordParameter},
+				{name: "z", defaultValue: 2, kind: ast.KeywordParameter},
+			},
+		},
+		{
+			desc:  "keyword splat with parens",
+			input: "def fn(x, *y, z: 1, **opts, &blk); end",
+			expectedParams: []funcParam{
+				{name: "x"},
+				{name: "y", kind: ast.RestParameter},
+				{name: "z", defaultValue: 1, kind: ast.KeywordParameter},
+				{name: "opts", kind: ast.KeywordRestParameter},
+			},
 		},
 		{
 			desc:           "multiple params last block capture with parens",
@@ -2687,6 +3328,15 @@ func TestFunctionParameterParsing(t *testing.T) {
 			}
 
 			for i, ident := range tt.expectedParams {
+				if function.Parameters[i].Kind != ident.kind {
+					t.Errorf("param %d kind wrong. want %d, got=%d\n", i, ident.kind, function.Parameters[i].Kind)
+				}
+				if ident.name == "" {
+					if function.Parameters[i].Name != nil {
+						t.Errorf("param %d name wrong. want nil, got=%v\n", i, function.Parameters[i].Name)
+					}
+					continue
+				}
 				testLiteralExpression(t, function.Parameters[i].Name, ident.name)
 				testLiteralExpression(t, function.Parameters[i].Default, ident.defaultValue)
 			}
@@ -2698,6 +3348,7 @@ func TestBlockParameterParsing(t *testing.T) {
 	type funcParam struct {
 		name         string
 		defaultValue interface{}
+		kind         ast.ParameterKind
 	}
 	tests := []struct {
 		desc           string
@@ -2774,6 +3425,17 @@ func TestBlockParameterParsing(t *testing.T) {
 			input:          "method do |x, y, z = 4|; end",
 			expectedParams: []funcParam{{name: "x"}, {name: "y"}, {name: "z", defaultValue: 4}},
 		},
+		{
+			desc:  "brace block params with splats, keywords and block",
+			input: "method { |x, *y, z: 1, **w, &v| }",
+			expectedParams: []funcParam{
+				{name: "x"},
+				{name: "y", kind: ast.RestParameter},
+				{name: "z", defaultValue: 1, kind: ast.KeywordParameter},
+				{name: "w", kind: ast.KeywordRestParameter},
+				{name: "v", kind: ast.BlockParameter},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -2807,6 +3469,9 @@ func TestBlockParameterParsing(t *testing.T) {
 			}
 
 			for i, ident := range tt.expectedParams {
+				if block.Parameters[i].Kind != ident.kind {
+					t.Errorf("param %d kind wrong. want %d, got=%d\n", i, ident.kind, block.Parameters[i].Kind)
+				}
 				testLiteralExpression(t, block.Parameters[i].Name, ident.name)
 				testLiteralExpression(t, block.Parameters[i].Default, ident.defaultValue)
 			}
@@ -2814,6 +3479,41 @@ func TestBlockParameterParsing(t *testing.T) {
 	}
 }
 
+func TestDestructuringBlockParameters(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedParams []string
+	}{
+		{"method { |(x, y)| }", []string{"(x, y)"}},
+		{"method { |(x, y), z| }", []string{"(x, y)", "z"}},
+		{"method do |x, (y, (z, *w))|; end", []string{"x", "(y, (z, *w))"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
+
+			stmt := program.Statements[0].(*ast.ExpressionStatement)
+			call, ok := stmt.Expression.(*ast.ContextCallExpression)
+			if !ok {
+				t.Fatalf("stmt.Expression is not *ast.ContextCallExpression. got=%T", stmt.Expression)
+			}
+			if call.Block == nil {
+				t.Fatalf("Expected block not to be nil")
+			}
+
+			actual := make([]string, len(call.Block.Parameters))
+			for i, param := range call.Block.Parameters {
+				actual[i] = param.String()
+			}
+			if !reflect.DeepEqual(tt.expectedParams, actual) {
+				t.Errorf("Expected parameters to equal %q, got %q", tt.expectedParams, actual)
+			}
+		})
+	}
+}
+
 func TestCallExpressionParsing(t *testing.T) {
 	testCases := []struct {
 		desc        string
@@ -2999,6 +3699,46 @@ func TestCallExpressionParameterParsing(t *testing.T) {
 			expectedIdent: "add",
 			expectedArgs:  []string{":foo"},
 		},
+		{
+			input:         "add 1, b: 2, c: 3;",
+			expectedIdent: "add",
+			expectedArgs:  []string{"1", `{":b" => "2", ":c" => "3"}`},
+		},
+		{
+			input:         `add(1, "k" => 2, :z => 3);`,
+			expectedIdent: "add",
+			expectedArgs:  []string{"1", `{"k" => "2", ":z" => "3"}`},
+		},
+		{
+			input:         "add(b:, c: 2);",
+			expectedIdent: "add",
+			expectedArgs:  []string{`{":b" => "b", ":c" => "2"}`},
+		},
+		{
+			input:         "add({a: 1}, b: 2);",
+			expectedIdent: "add",
+			expectedArgs:  []string{`{":a" => "1"}`, `{":b" => "2"}`},
+		},
+		{
+			input:         "add 1,\n  b: 2;",
+			expectedIdent: "add",
+			expectedArgs:  []string{"1", `{":b" => "2"}`},
+		},
+		{
+			input:         "add(*list, 1);",
+			expectedIdent: "add",
+			expectedArgs:  []string{"*list", "1"},
+		},
+		{
+			input:         "add 1, *list.first(2);",
+			expectedIdent: "add",
+			expectedArgs:  []string{"1", "*list.first(2)"},
+		},
+		{
+			input:         "add(*list, **opts, c: 3);",
+			expectedIdent: "add",
+			expectedArgs:  []string{"*list", "**opts", `{":c" => "3"}`},
+		},
 	}
 
 	for _, tt := range tests {
@@ -3837,6 +4577,235 @@ func TestStringLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestInterpolatedStringLiteralExpression(t *testing.T) {
+	input := `"a#{x}b#{y; 1 + 2}"`
+
+	program, err := parseSource(input)
+	checkParserErrors(t, err)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	literal, ok := stmt.Expression.(*ast.InterpolatedStringLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.InterpolatedStringLiteral. got=%T", stmt.Expression)
+	}
+
+	expected := []string{"a", "x", "b", "y(1 + 2)"}
+	if len(literal.Parts) != len(expected) {
+		t.Fatalf("Expected %d parts, got %d", len(expected), len(literal.Parts))
+	}
+	for i, part := range literal.Parts {
+		if part.String() != expected[i] {
+			t.Errorf("Expected part %d to equal %q, got %q", i, expected[i], part.String())
+		}
+	}
+	if _, ok := literal.Parts[0].(*ast.StringLiteral); !ok {
+		t.Errorf("Expected part 0 to be *ast.StringLiteral, got %T", literal.Parts[0])
+	}
+	if _, ok := literal.Parts[1].(*ast.BlockStatement); !ok {
+		t.Errorf("Expected part 1 to be *ast.BlockStatement, got %T", literal.Parts[1])
+	}
+
+	t.Run("escapes only", func(t *testing.T) {
+		program, err := parseSource(`"a\tb\u00e9"`)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.StringLiteral)
+		if !ok {
+			t.Fatalf("exp not *ast.StringLiteral. got=%T", stmt.Expression)
+		}
+		if literal.Value != "a\tb\u00e9" {
+			t.Errorf("literal.Value not %q. got=%q", "a\tb\u00e9", literal.Value)
+		}
+	})
+	t.Run("invalid interpolation", func(t *testing.T) {
+		_, err := parseSource(`"a#{1 +}"`)
+
+		if err == nil {
+			t.Fatalf("Expected parser error, got nil")
+		}
+	})
+	t.Run("invalid escape", func(t *testing.T) {
+		_, err := parseSource(`"\xg"`)
+
+		if err == nil {
+			t.Fatalf("Expected parser error, got nil")
+		}
+		if !strings.Contains(err.Error(), "1:2: invalid hex escape") {
+			t.Errorf("Expected error to contain the position and the escape error, got %q", err.Error())
+		}
+	})
+}
+
+func TestAdjacentStringLiterals(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"double and single quoted", `"foo" 'bar'`, "foobar"},
+		{"interpolation", `"a#{x}" "b" "#{y}c"`, "a#{x}b#{y}c"},
+		{"line continuation", "\"foo\" \\\n  \"bar\"", "foobar"},
+		{"call argument", `foo "a" "b", "c"`, "foo(ab, c)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
+
+			if len(program.Statements) != 1 {
+				t.Fatalf("Expected 1 statement, got %d", len(program.Statements))
+			}
+			if program.String() != tt.expected {
+				t.Errorf("program.String not %q. got=%q", tt.expected, program.String())
+			}
+		})
+	}
+
+	t.Run("interpolated parts", func(t *testing.T) {
+		program, err := parseSource(`"a#{x}" "b"`)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.InterpolatedStringLiteral)
+		if !ok {
+			t.Fatalf("exp not *ast.InterpolatedStringLiteral. got=%T", stmt.Expression)
+		}
+		if len(literal.Parts) != 3 {
+			t.Errorf("Expected 3 parts, got %d", len(literal.Parts))
+		}
+	})
+}
+
+func TestCharacterLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`?a`, "a"},
+		{`?\n`, "\n"},
+		{`?\s`, " "},
+		{`?\\`, "\\"},
+		{`?\x41`, "A"},
+		{`?\101`, "A"},
+		{`?\u00e9`, "\u00e9"},
+		{`?\u{1F600}`, "\U0001F600"},
+		{`?\C-a`, "\x01"},
+		{`?\M-\C-a`, "\x81"},
+		{`?\c?`, "\x7f"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
+
+			stmt := program.Statements[0].(*ast.ExpressionStatement)
+			literal, ok := stmt.Expression.(*ast.StringLiteral)
+			if !ok {
+				t.Fatalf("exp not *ast.StringLiteral. got=%T", stmt.Expression)
+			}
+			if literal.Value != tt.expected {
+				t.Errorf("literal.Value not %q. got=%q", tt.expected, literal.Value)
+			}
+		})
+	}
+
+	t.Run("invalid escape", func(t *testing.T) {
+		_, err := parseSource(`?\u{110000}`)
+
+		if err == nil {
+			t.Fatalf("Expected parser error, got nil")
+		}
+	})
+}
+
+func TestPercentLiterals(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"q", `%q(a (b) \) c \n)`, `a (b) ) c \n`},
+		{"Q", `%Q{a\tb}`, "a\tb"},
+		{"Q interpolation", `%Q{a #{x}}`, `a #{x}`},
+		{"bare", `%|a\|b|`, `a|b`},
+		{"w", `%w(a b\ c  d)`, `[a, b c, d]`},
+		{"W", "%W[a#{x} \\t b]", "[a#{x}, \t, b]"},
+		{"i", `%i(a b)`, `[:a, :b]`},
+		{"I", `%I(a#{x})`, `[:a#{x}]`},
+		{"s", `%s(a b)`, `:a b`},
+		{"argument", `foo %w(a b)`, `foo([a, b])`},
+		{"modulo", `foo % 2`, `(foo % 2)`},
+		{"r", `%r{a/b}i`, `/a/b/i`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
+
+			if program.String() != tt.expected {
+				t.Errorf("program.String not %q. got=%q", tt.expected, program.String())
+			}
+		})
+	}
+}
+
+func TestHeredoc(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain", "<<EOS\n  foo\\tbar\nEOS\n", "  foo\tbar\n"},
+		{"indented terminator", "<<-EOS\n  foo\n  EOS\n", "  foo\n"},
+		{"squiggly", "<<~EOS\n    foo\n      bar\n\n  EOS\n", "foo\n  bar\n\n"},
+		{"squiggly with tabs", "<<~EOS\n\tfoo\n        bar\nEOS\n", "foo\nbar\n"},
+		{"single quoted", "<<~'EOS'\n  foo\\t\n  EOS\n", "foo\\t\n"},
+		{"double quoted", "<<\"EOS\"\nfoo\\t\nEOS\n", "foo\t\n"},
+		{"empty", "<<EOS\nEOS\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
+
+			stmt := program.Statements[0].(*ast.ExpressionStatement)
+			literal, ok := stmt.Expression.(*ast.StringLiteral)
+			if !ok {
+				t.Fatalf("exp not *ast.StringLiteral. got=%T", stmt.Expression)
+			}
+			if literal.Value != tt.expected {
+				t.Errorf("literal.Value not %q. got=%q", tt.expected, literal.Value)
+			}
+		})
+	}
+
+	t.Run("interpolation", func(t *testing.T) {
+		program, err := parseSource("foo <<~EOS, 3\n  a #{x}\n   b\nEOS\n")
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		call, ok := stmt.Expression.(*ast.ContextCallExpression)
+		if !ok {
+			t.Fatalf("exp not *ast.ContextCallExpression. got=%T", stmt.Expression)
+		}
+		if len(call.Arguments) != 2 {
+			t.Fatalf("Expected 2 arguments, got %d", len(call.Arguments))
+		}
+		literal, ok := call.Arguments[0].(*ast.InterpolatedStringLiteral)
+		if !ok {
+			t.Fatalf("exp not *ast.InterpolatedStringLiteral. got=%T", call.Arguments[0])
+		}
+		if literal.String() != "a #{x}\n b\n" {
+			t.Errorf("literal.String not %q. got=%q", "a #{x}\n b\n", literal.String())
+		}
+	})
+}
+
 func TestSymbolExpression(t *testing.T) {
 	tests := []struct {
 		input string
@@ -3897,6 +4866,112 @@ func TestParsingArrayLiterals(t *testing.T) {
 	testHashLiteral(t, array.Elements[3], map[string]string{"foo": "2"})
 }
 
+func TestRangeLiterals(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  string
+		exclusive bool
+	}{
+		{"1..5", "(1..5)", false},
+		{"1...5", "(1...5)", true},
+		{"a + 1..b * 2", "((a + 1)..(b * 2))", false},
+		{"1..", "(1..)", false},
+		{"..5", "(..5)", false},
+		{"...5", "(...5)", true},
+		{"'a'..'z'", "(a..z)", false},
+		{"a || b..c", "((a || b)..c)", false},
+		{"1.0..2", "(1.0..2)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := parseExpression(tt.input)
+			checkParserErrors(t, err)
+
+			rng, ok := expr.(*ast.RangeLiteral)
+			if !ok {
+				t.Fatalf("Expected expression to be *ast.RangeLiteral, got %T", expr)
+			}
+			if rng.String() != tt.expected {
+				t.Errorf("Expected range to equal %q, got %q", tt.expected, rng.String())
+			}
+			if rng.IsExclusive() != tt.exclusive {
+				t.Errorf("Expected range exclusiveness to be %t", tt.exclusive)
+			}
+		})
+	}
+
+	t.Run("as method arguments", func(t *testing.T) {
+		program, err := parseSource("foo(1..2, ..3)\n[1, 2][0..]")
+		checkParserErrors(t, err)
+
+		expected := "foo((1..2), (..3))\n([1, 2][(0..)])"
+		if program.String() != expected {
+			t.Errorf("Expected program to equal %q, got %q", expected, program.String())
+		}
+	})
+	t.Run("without bounds", func(t *testing.T) {
+		_, err := parseExpression("..")
+
+		if err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+	})
+}
+
+func TestRegexLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		pattern  string
+		flags    string
+		expected string
+	}{
+		{`/ab+c/`, `ab+c`, "", `/ab+c/`},
+		{`/a\d\/b/ix`, `a\d\/b`, "ix", `/a\d\/b/ix`},
+		{`/a#{x}b/`, `a#{x}b`, "", `/a#{x}b/`},
+		{`%r{\s+}m`, `\s+`, "m", `/\s+/m`},
+		{`%r(a#{"(b)"})`, `a#{(b)}`, "", `/a#{(b)}/`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := parseExpression(tt.input)
+			checkParserErrors(t, err)
+
+			regex, ok := expr.(*ast.RegexLiteral)
+			if !ok {
+				t.Fatalf("Expected expression to be *ast.RegexLiteral, got %T", expr)
+			}
+			if regex.Value.String() != tt.pattern {
+				t.Errorf("Expected pattern to equal %q, got %q", tt.pattern, regex.Value.String())
+			}
+			if regex.Flags != tt.flags {
+				t.Errorf("Expected flags to equal %q, got %q", tt.flags, regex.Flags)
+			}
+			if regex.String() != tt.expected {
+				t.Errorf("Expected regex to equal %q, got %q", tt.expected, regex.String())
+			}
+		})
+	}
+
+	t.Run("match operators", func(t *testing.T) {
+		program, err := parseSource("x =~ /a/\nx !~ /b/ && y\nputs /c/\nz / 2 / 3")
+		checkParserErrors(t, err)
+
+		expected := "(x =~ /a/)\n((x !~ /b/) && y)\nputs(/c/)\n((z / 2) / 3)"
+		if program.String() != expected {
+			t.Errorf("Expected program to equal %q, got %q", expected, program.String())
+		}
+	})
+	t.Run("invalid interpolation", func(t *testing.T) {
+		_, err := parseSource(`/a#{)}/`)
+
+		if err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+	})
+}
+
 func TestParsingIndexExpressions(t *testing.T) {
 	t.Run("one arg as index", func(t *testing.T) {
 		input := "myArray[1 + 1]"
@@ -4072,6 +5147,18 @@ func TestParseHash(t *testing.T) {
 			input:   `{"foo" => 42, "bar" => "baz"}`,
 			hashMap: map[string]string{"foo": "42", "bar": "baz"},
 		},
+		{
+			input:   `{foo: 42, "bar": "baz", Qux: 1, if: 2}`,
+			hashMap: map[string]string{":foo": "42", ":bar": "baz", ":Qux": "1", ":if": "2"},
+		},
+		{
+			input:   `{foo:, bar: 1}`,
+			hashMap: map[string]string{":foo": "foo", ":bar": "1"},
+		},
+		{
+			input:   "{\n  foo: 42,\n  :bar => 1,\n}",
+			hashMap: map[string]string{":foo": "42", ":bar": "1"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -4086,6 +5173,23 @@ func TestParseHash(t *testing.T) {
 
 		testHashLiteral(t, stmt.Expression, tt.hashMap)
 	}
+
+	t.Run("keeps the key order", func(t *testing.T) {
+		program, err := parseSource(`{3 => 1, 1 => 2, 2 => 3}`)
+		checkParserErrors(t, err)
+
+		hash := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.HashLiteral)
+		var keys []string
+		for _, key := range hash.Keys {
+			keys = append(keys, key.String())
+		}
+
+		expected := []string{"3", "1", "2"}
+		if !reflect.DeepEqual(expected, keys) {
+			t.Logf("Expected keys to equal %q, got %q\n", expected, keys)
+			t.Fail()
+		}
+	})
 }
 
 func testExpression(t *testing.T, exp ast.Expression, expected interface{}) bool {
@@ -4339,8 +5443,8 @@ func testHashLiteral(t *testing.T, expr ast.Expression, value map[string]string)
 		return false
 	}
 	hashMap := make(map[string]string)
-	for k, v := range hash.Map {
-		hashMap[k.String()] = v.String()
+	for i, k := range hash.Keys {
+		hashMap[k.String()] = hash.Values[i].String()
 	}
 
 	if !reflect.DeepEqual(hashMap, value) {
@@ -4350,6 +5454,111 @@ func testHashLiteral(t *testing.T, expr ast.Expression, value map[string]string)
 	return true
 }
 
+func TestNodePositions(t *testing.T) {
+	tests := []struct {
+		input string
+		start string
+		end   string
+	}{
+		{"\n  foo", "2:3", "2:6"},
+		{`"foo"`, "1:1", "1:6"},
+		{`'foo bar'`, "1:1", "1:10"},
+		{"?a", "1:1", "1:3"},
+		{"\"a\" 'b'", "1:1", "1:8"},
+		{"\"a\" \\\n  \"#{b}\"", "1:1", "2:9"},
+		{"1_000", "1:1", "1:6"},
+		{"x = 3 + 42", "1:1", "1:11"},
+		{"[1, 2]", "1:1", "1:7"},
+		{"1..20", "1:1", "1:6"},
+		{"...5", "1:1", "1:5"},
+		{"1..", "1:1", "1:4"},
+		{"foo(&:bar)", "1:1", "1:11"},
+		{"{a => 2}", "1:1", "1:9"},
+		{"foo[1]", "1:1", "1:7"},
+		{"foo.bar(1, 2)", "1:1", "1:14"},
+		{"foo.bar 1, 2", "1:1", "1:13"},
+		{"bar(1) { |x| x }", "1:1", "1:17"},
+		{"if x\n  3\nend", "1:1", "3:4"},
+		{"if x\n  3\nelsif y\n  4\nend", "1:1", "5:4"},
+		{"3 if x", "1:1", "1:7"},
+		{"x ? 1 : 2", "1:1", "1:10"},
+		{"while x\n  3\nend", "1:1", "3:4"},
+		{"def foo\n  3\nend", "1:1", "3:4"},
+		{"class Foo\nend", "1:1", "2:4"},
+		{"begin\n  3\nrescue\n  4\nend", "1:1", "5:4"},
+		{"foo rescue 42", "1:1", "1:14"},
+		{"case x\nwhen 1 then 2\nend", "1:1", "3:4"},
+		{"case x\nin [a, *] then a\nend", "1:1", "3:4"},
+		{"*a, b = c", "1:1", "1:10"},
+		{"foo(*bar, **baz)", "1:1", "1:17"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
+
+			stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+			if !ok {
+				t.Fatalf(
+					"program.Statements[0] is not ast.ExpressionStatement. got=%T",
+					program.Statements[0],
+				)
+			}
+
+			start := ast.Position(program.File, stmt.Pos())
+			actualStart := fmt.Sprintf("%d:%d", start.Line, start.Column)
+			if actualStart != tt.start {
+				t.Logf("Expected node to start at %s, got %s\n", tt.start, actualStart)
+				t.Fail()
+			}
+			end := ast.Position(program.File, stmt.End())
+			actualEnd := fmt.Sprintf("%d:%d", end.Line, end.Column)
+			if actualEnd != tt.end {
+				t.Logf("Expected node to end at %s, got %s\n", tt.end, actualEnd)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestInterpolationPositions(t *testing.T) {
+	tests := []struct {
+		input string
+		spans []string
+	}{
+		{`"a#{x}b"`, []string{"1:3-1:7"}},
+		{`"#{foo(1)} #{y}"`, []string{"1:2-1:11", "1:12-1:16"}},
+		{"\"a\n#{b}\"", []string{"2:1-2:5"}},
+		{"%Q{#{x}}", []string{"1:4-1:8"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
+
+			stmt := program.Statements[0].(*ast.ExpressionStatement)
+			literal, ok := stmt.Expression.(*ast.InterpolatedStringLiteral)
+			if !ok {
+				t.Fatalf("Expected *ast.InterpolatedStringLiteral, got %T", stmt.Expression)
+			}
+			var spans []string
+			for _, part := range literal.Parts {
+				if _, ok := part.(*ast.BlockStatement); !ok {
+					continue
+				}
+				start, end := ast.Span(program.File, part)
+				spans = append(spans, fmt.Sprintf("%d:%d-%d:%d", start.Line, start.Column, end.Line, end.Column))
+			}
+			if !reflect.DeepEqual(spans, tt.spans) {
+				t.Logf("Expected interpolations to span %v, got %v\n", tt.spans, spans)
+				t.Fail()
+			}
+		})
+	}
+}
+
 func parseSource(src string, modes ...Mode) (*ast.Program, *Errors) {
 	mode := parseMode
 	for _, m := range modes {
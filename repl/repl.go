@@ -8,7 +8,6 @@ import (
 	"github.com/goruby/goruby/interpreter"
 	"github.com/goruby/goruby/object"
 	"github.com/goruby/goruby/parser"
-	"github.com/pkg/errors"
 )
 
 // Input defines the input interface for the repl
@@ -92,7 +91,7 @@ func (b *bufferedInterpreter) interpretLine(line string, out io.Writer) {
 			b.buffer += "\n"
 			return
 		}
-		fmt.Fprintf(out, "%s\n", errors.Cause(err).Error())
+		fmt.Fprintf(out, "%s\n", object.FullMessage(err))
 		b.buffer = ""
 		return
 	}
@@ -19,7 +19,12 @@ const (
 	CONST
 	GLOBAL
 	INT
+	FLOAT
 	STRING
+	HEREDOC // <<ID, followed by a STRING holding the body
+	SYMBOL  // %s()
+	WORDS   // %w(), %W(), %i(), %I()
+	REGEX   // /regex/, %r{}
 	literal_end
 
 	// Operators
@@ -37,19 +42,24 @@ const (
 	MINUS      // -
 	BANG       // !
 	ASTERISK   // *
+	POW        // **
 	SLASH      // /
 	MODULO     // %
 	AND        // &
 	LOGICALAND // &&
 	PIPE       // |
 	LOGICALOR  // ||
+	CARET      // ^
 
 	LT        // <
 	LTE       // <=
 	GT        // >
 	GTE       // >=
 	EQ        // ==
+	CASEEQ    // ===
 	NOTEQ     // !=
+	MATCH     // =~
+	NOTMATCH  // !~
 	SPACESHIP // <=>
 	LSHIFT    // <<
 	operator_end
@@ -65,6 +75,8 @@ const (
 
 	CAPTURE  // &
 	DOT      // .
+	DOT2     // ..
+	DOT3     // ...
 	COLON    // :
 	LPAREN   // (
 	RPAREN   // )
@@ -87,6 +99,7 @@ const (
 	IF
 	THEN
 	ELSE
+	ELSIF
 	UNLESS
 	TRUE
 	FALSE
@@ -98,7 +111,16 @@ const (
 	YIELD
 	BEGIN
 	RESCUE
+	ENSURE
+	RETRY
 	WHILE
+	UNTIL
+	BREAK
+	NEXT
+	REDO
+	CASE
+	WHEN
+	IN
 	KEYWORD__FILE__
 	keyword_end
 )
@@ -111,8 +133,14 @@ var tokens = [...]string{
 	CONST:  "CONST",
 	GLOBAL: "GLOBAL",
 	INT:    "INT",
+	FLOAT:  "FLOAT",
 	STRING: "STRING",
 
+	HEREDOC: "HEREDOC",
+	SYMBOL:  "SYMBOL",
+	WORDS:   "WORDS",
+	REGEX:   "REGEX",
+
 	ASSIGN:    "=",
 	ADDASSIGN: "+=",
 	SUBASSIGN: "-=",
@@ -124,19 +152,24 @@ var tokens = [...]string{
 	MINUS:      "-",
 	BANG:       "!",
 	ASTERISK:   "*",
+	POW:        "**",
 	SLASH:      "/",
 	MODULO:     "%",
 	AND:        "&",
 	CAPTURE:    "&",
 	LOGICALAND: "&&",
 	LOGICALOR:  "||",
+	CARET:      "^",
 
 	LT:        "<",
 	LTE:       "<=",
 	GT:        ">",
 	GTE:       ">=",
 	EQ:        "==",
+	CASEEQ:    "===",
 	NOTEQ:     "!=",
+	MATCH:     "=~",
+	NOTMATCH:  "!~",
 	SPACESHIP: "<=>",
 	LSHIFT:    "<<",
 
@@ -146,6 +179,8 @@ var tokens = [...]string{
 	HASH:      "#",
 
 	DOT:      ".",
+	DOT2:     "..",
+	DOT3:     "...",
 	COLON:    ":",
 	LPAREN:   "(",
 	RPAREN:   ")",
@@ -169,6 +204,7 @@ var tokens = [...]string{
 	IF:              "if",
 	THEN:            "then",
 	ELSE:            "else",
+	ELSIF:           "elsif",
 	TRUE:            "true",
 	FALSE:           "false",
 	RETURN:          "return",
@@ -179,7 +215,16 @@ var tokens = [...]string{
 	YIELD:           "yield",
 	BEGIN:           "begin",
 	RESCUE:          "rescue",
+	ENSURE:          "ensure",
+	RETRY:           "retry",
 	WHILE:           "while",
+	UNTIL:           "until",
+	BREAK:           "break",
+	NEXT:            "next",
+	REDO:            "redo",
+	CASE:            "case",
+	WHEN:            "when",
+	IN:              "in",
 	KEYWORD__FILE__: "__FILE__",
 }
 